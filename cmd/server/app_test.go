@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-api-template/internal/constants"
+	"go-api-template/internal/controller"
+	"go-api-template/internal/middleware"
+	"go-api-template/internal/model"
+	"go-api-template/internal/repository"
+	"go-api-template/internal/service"
+	"go-api-template/pkg/cache"
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/lifecycle"
+	"go-api-template/pkg/logger/logtest"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+)
+
+// testApp 完整路由（与 InitializeApp 相同的中间件和路由），不需要 MySQL、Redis
+// 配置取 config/config.yaml，数据库为内存 SQLite，缓存为进程内存；每个测试单独创建，数据互不影响
+type testApp struct {
+	*webtest.Server
+
+	Config    *config.Config
+	DB        *gorm.DB
+	Cache     *cache.CacheFacade
+	Lifecycle *lifecycle.Manager
+	DemoSvc   *service.DemoService
+	DemoCtrl  *controller.DemoController
+	MW        *middleware.Middleware
+	Checks    []web.HealthCheck
+	Logs      *observer.ObservedLogs
+}
+
+// newTestApp 创建测试应用，configure 在创建组件前修改配置（可为 nil）
+func newTestApp(t *testing.T, configure func(cfg *config.Config)) *testApp {
+	t.Helper()
+
+	logs := logtest.New(t)
+	cfg, err := config.LoadConfig("../../config/config.yaml")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Server.Mode = gin.TestMode
+	cfg.Cache.Driver = string(cache.DriverMemory)
+	cfg.Audit.File = filepath.Join(t.TempDir(), "audit.log")
+	if configure != nil {
+		configure(cfg)
+	}
+
+	// webtest.New 记录当前的 JSON 编码选项并在测试结束时恢复，需在 provideRouter 之前调用
+	s := webtest.New(t, nil)
+	prevLimits := web.SetJSONLimits(web.JSONLimits{})
+	t.Cleanup(func() {
+		web.SetJSONLimits(prevLimits)
+		model.SetUTC(false)
+	})
+
+	lc := lifecycle.NewManager(cfg)
+	t.Cleanup(lc.Shutdown)
+
+	db := dbtest.Open(t, &model.Demo{}, &model.App{})
+	cacheFacade, err := provideCache(cfg, nil)
+	if err != nil {
+		t.Fatalf("create cache: %v", err)
+	}
+	auditor, err := provideAudit(cfg, db, lc)
+	if err != nil {
+		t.Fatalf("create audit logger: %v", err)
+	}
+	appSecrets, err := provideAppSecrets(cfg, db, cacheFacade)
+	if err != nil {
+		t.Fatalf("create app secrets: %v", err)
+	}
+	apiKeys, err := provideAPIKeys(cfg, db, cacheFacade)
+	if err != nil {
+		t.Fatalf("create api keys: %v", err)
+	}
+
+	demoSvc := service.NewDemoService(repository.NewDemoRepository(db), database.NewTxManager(db), cacheFacade, auditor, cfg)
+	demoCtrl := controller.NewDemoController(demoSvc, cfg)
+	mw := middleware.NewMiddleware(cfg, appSecrets, provideSequenceStore(nil), apiKeys, nil)
+	checks := provideHealthChecks(cfg, db, nil, cacheFacade)
+
+	router, err := provideRouter(cfg, demoCtrl, mw, checks)
+	if err != nil {
+		t.Fatalf("create router: %v", err)
+	}
+	s.Engine = router
+
+	return &testApp{
+		Server:    s,
+		Config:    cfg,
+		DB:        db,
+		Cache:     cacheFacade,
+		Lifecycle: lc,
+		DemoSvc:   demoSvc,
+		DemoCtrl:  demoCtrl,
+		MW:        mw,
+		Checks:    checks,
+		Logs:      logs,
+	}
+}
+
+// initApp 使用测试应用的组件执行 provideApp（启动检查、缓存预热、启动后台任务）
+func (a *testApp) initApp(t *testing.T) (*App, error) {
+	t.Helper()
+
+	sched, err := provideScheduler(a.Config, a.DB, a.DemoSvc, a.Lifecycle)
+	if err != nil {
+		t.Fatalf("create scheduler: %v", err)
+	}
+	app, _, err := provideApp(a.Config, a.DemoCtrl, a.DemoSvc, a.MW, a.Checks, a.Lifecycle, sched, web.NewStreamTracker(), nil)
+	return app, err
+}
+
+func TestCacheWarmUpRunsOnceDuringInitialization(t *testing.T) {
+	a := newTestApp(t, func(cfg *config.Config) {
+		cfg.Cache.WarmUp = true
+		cfg.Scheduler.Enabled = false
+	})
+	demo := model.Demo{Title: "hot", Content: "warm"}
+	if err := a.DB.Create(&demo).Error; err != nil {
+		t.Fatalf("seed demo: %v", err)
+	}
+
+	if _, err := a.initApp(t); err != nil {
+		t.Fatalf("provideApp: %v", err)
+	}
+
+	if n := a.Logs.FilterMessage("cache warm-up finished").Len(); n != 1 {
+		t.Fatalf("warm-up ran %d times, want 1", n)
+	}
+	if !a.Cache.Has(context.Background(), fmt.Sprintf(constants.CacheKeyDemo, demo.ID)) {
+		t.Fatal("demo not cached after warm-up")
+	}
+}
+
+func TestCacheWarmUpDisabled(t *testing.T) {
+	a := newTestApp(t, func(cfg *config.Config) {
+		cfg.Cache.WarmUp = false
+		cfg.Scheduler.Enabled = false
+	})
+	if _, err := a.initApp(t); err != nil {
+		t.Fatalf("provideApp: %v", err)
+	}
+	if n := a.Logs.FilterMessage("cache warm-up finished").Len(); n != 0 {
+		t.Fatalf("warm-up ran %d times, want 0", n)
+	}
+}
+
+// scrapeMetric 抓取 /metrics，返回 series（含 label）的值，未导出时为 0
+func (a *testApp) scrapeMetric(t *testing.T, series string) float64 {
+	t.Helper()
+
+	w := a.Do(http.MethodGet, a.Config.Metrics.Path, nil)
+	a.AssertStatus(w, http.StatusOK)
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		if name, value, ok := strings.Cut(line, " "); ok && name == series {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatalf("parse %q: %v", line, err)
+			}
+			return v
+		}
+	}
+	return 0
+}
+
+func TestReadyIncludesCacheCheck(t *testing.T) {
+	a := newTestApp(t, nil)
+
+	w := a.Do(http.MethodGet, "/ready", nil)
+	a.AssertCode(w, http.StatusOK)
+	var data struct {
+		Checks []web.HealthResult `json:"checks"`
+	}
+	a.DecodeData(w, &data)
+	for _, c := range data.Checks {
+		if c.Name == "cache" {
+			if c.Status != web.HealthUp {
+				t.Fatalf("cache check = %+v, want up", c)
+			}
+			return
+		}
+	}
+	t.Fatalf("cache check not registered: %+v", data.Checks)
+}
+
+// 数据库迁移版本与代码期望的版本不一致时 /ready 返回 503
+func TestReadyFailsOnSchemaVersionMismatch(t *testing.T) {
+	a := newTestApp(t, nil)
+	a.DB.Exec("CREATE TABLE " + database.DefaultMigrationTable + " (version bigint NOT NULL, dirty boolean NOT NULL)")
+	a.DB.Exec("INSERT INTO " + database.DefaultMigrationTable + " (version, dirty) VALUES (1, false)")
+
+	// 与 provideHealthChecks 在 model.SchemaVersion 大于 0 时注册的检查相同，期望版本为 2
+	checks := append(a.Checks, web.HealthCheck{
+		Name:  "schema",
+		Check: database.SchemaVersionCheck(a.DB, a.Config.Database.MigrationTable, 2),
+	})
+	router, err := provideRouter(a.Config, a.DemoCtrl, a.MW, checks)
+	if err != nil {
+		t.Fatalf("create router: %v", err)
+	}
+	a.Engine = router
+
+	w := a.Do(http.MethodGet, "/ready", nil)
+	a.AssertStatus(w, http.StatusServiceUnavailable)
+	if !strings.Contains(w.Body.String(), "数据库迁移版本 1 与期望版本 2 不一致") {
+		t.Fatalf("body = %s, want the schema version error", w.Body.String())
+	}
+	if err := checkStartup(a.Config, checks); err == nil {
+		t.Fatal("startup check passed with a mismatched schema version")
+	}
+
+	a.DB.Exec("UPDATE " + database.DefaultMigrationTable + " SET version = 2")
+	a.AssertStatus(a.Do(http.MethodGet, "/ready", nil), http.StatusOK)
+}
+
+// failingCheck 返回一个总是失败的依赖检查
+func failingCheck(name string) web.HealthCheck {
+	return web.HealthCheck{Name: name, Check: func(context.Context) error {
+		return errors.New(name + " unreachable")
+	}}
+}
+
+func TestStartupFailsFastWhenCriticalCheckFails(t *testing.T) {
+	a := newTestApp(t, func(cfg *config.Config) {
+		cfg.Server.StartupCheck.Enabled = true
+		cfg.Scheduler.Enabled = false
+	})
+	a.Checks = append(a.Checks, failingCheck("payment"))
+
+	// 启动失败时已注册的关闭钩子需要执行（资源已打开，但调用方拿不到 cleanup）
+	closed := false
+	a.Lifecycle.OnShutdown("test-resource", func(context.Context) error {
+		closed = true
+		return nil
+	})
+
+	app, err := a.initApp(t)
+	if err == nil || !strings.Contains(err.Error(), "payment") {
+		t.Fatalf("provideApp err = %v, want critical dependency payment", err)
+	}
+	if app != nil {
+		t.Fatal("app returned despite failed startup check")
+	}
+	if !closed {
+		t.Fatal("shutdown hooks not run after failed startup check")
+	}
+	if a.Logs.FilterMessage("startup check failed").Len() != 1 {
+		t.Fatalf("want one startup check failure logged, got %v", a.Logs.FilterMessage("startup check failed").All())
+	}
+	if a.Logs.FilterMessage("startup check passed").Len() != 2 {
+		t.Fatal("passing checks (database, cache) not logged")
+	}
+}
+
+func TestStartupContinuesWhenOptionalCheckFails(t *testing.T) {
+	a := newTestApp(t, func(cfg *config.Config) {
+		cfg.Server.StartupCheck.Enabled = true
+		cfg.Server.StartupCheck.Optional = []string{"payment"}
+		cfg.Scheduler.Enabled = false
+	})
+	a.Checks = append(a.Checks, failingCheck("payment"))
+
+	if _, err := a.initApp(t); err != nil {
+		t.Fatalf("provideApp: %v", err)
+	}
+	if a.Logs.FilterMessage("startup check failed, optional dependency unavailable").Len() != 1 {
+		t.Fatal("optional check failure not logged as warning")
+	}
+}
+
+// loadTestConfig 读取 config/config.yaml，开启连接池指标（覆盖 provideDB、provideRedis 注册的全部钩子）
+func loadTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	cfg, err := config.LoadConfig("../../config/config.yaml")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Metrics.Enabled = true
+	cfg.Metrics.PoolSampleInterval = 1
+	return cfg
+}
+
+// 反复启动、关闭：provideDB 注册的钩子关闭连接池，不留下打开的连接
+// MySQL 连接由 setupDB 之前的 database.NewMySQLDB 打开，这里用 SQLite 代替
+func TestShutdownClosesDatabasePool(t *testing.T) {
+	logtest.New(t)
+
+	for i := 0; i < 5; i++ {
+		cfg := loadTestConfig(t)
+		cfg.Database.QueryCache = false
+		lc := lifecycle.NewManager(cfg)
+		db := dbtest.Open(t)
+		if err := setupDB(cfg, lc, nil, db); err != nil {
+			t.Fatalf("setupDB: %v", err)
+		}
+
+		// 并发查询，使连接池中打开多个连接
+		var wg sync.WaitGroup
+		for j := 0; j < 4; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var n int
+				_ = db.Raw("SELECT 1").Scan(&n).Error
+			}()
+		}
+		wg.Wait()
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sqlDB.Stats().OpenConnections == 0 {
+			t.Fatal("expected open connections before shutdown")
+		}
+
+		lc.Shutdown()
+
+		if n := sqlDB.Stats().OpenConnections; n != 0 {
+			t.Fatalf("round %d: %d connections still open after shutdown", i, n)
+		}
+		if err := database.Ping(context.Background(), db); err == nil {
+			t.Fatalf("round %d: ping succeeded after shutdown", i)
+		}
+	}
+}
+
+// 反复启动、关闭：provideRedis 注册的钩子关闭客户端，连接池清空
+func TestShutdownClosesRedisPool(t *testing.T) {
+	logtest.New(t)
+	mr := miniredis.RunT(t)
+	port, err := strconv.Atoi(mr.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		cfg := loadTestConfig(t)
+		cfg.Cache.Driver = string(cache.DriverRedis)
+		cfg.Redis.Host, cfg.Redis.Port = mr.Host(), port
+		lc := lifecycle.NewManager(cfg)
+
+		client, err := provideRedis(cfg, lc)
+		if err != nil {
+			t.Fatalf("provideRedis: %v", err)
+		}
+		if err := client.Set(context.Background(), "k", "v", 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+		if client.PoolStats().TotalConns == 0 {
+			t.Fatal("expected open connections before shutdown")
+		}
+
+		lc.Shutdown()
+
+		if err := client.Ping(context.Background()).Err(); err == nil {
+			t.Fatalf("round %d: ping succeeded after shutdown", i)
+		}
+		if n := client.PoolStats().TotalConns; n != 0 {
+			t.Fatalf("round %d: %d connections still open after shutdown", i, n)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"go-api-template/internal/constants"
+	"go-api-template/internal/model"
+	"go-api-template/pkg/config"
+)
+
+func TestDeleteDemoWritesAuditRecord(t *testing.T) {
+	app := newTestApp(t, func(cfg *config.Config) { cfg.Audit.Enabled = true })
+	demo := app.createDemo(t, "audited", "before delete")
+
+	app.Header.Set(constants.HeaderRequestID, "req-audit-delete")
+	app.AssertStatus(app.Do(http.MethodDelete, fmt.Sprintf("/api/v1/demos/%d", demo.ID), nil), http.StatusOK)
+
+	data, err := os.ReadFile(app.Config.Audit.File)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d audit records, want 1\n%s", len(lines), data)
+	}
+
+	var rec struct {
+		RequestID string     `json:"request_id"`
+		Actor     string     `json:"actor"`
+		Action    string     `json:"action"`
+		Resource  string     `json:"resource"`
+		Before    model.Demo `json:"before"`
+		After     *struct{}  `json:"after"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("decode audit record %s: %v", lines[0], err)
+	}
+	if rec.Action != "demo.delete" || rec.Resource != fmt.Sprintf("demo:%d", demo.ID) {
+		t.Fatalf("action/resource = %q/%q", rec.Action, rec.Resource)
+	}
+	if rec.RequestID != "req-audit-delete" || rec.Actor != "anonymous" {
+		t.Fatalf("request_id/actor = %q/%q", rec.RequestID, rec.Actor)
+	}
+	if rec.Before.ID != demo.ID || rec.Before.Content != "before delete" || rec.After != nil {
+		t.Fatalf("before = %+v, after = %v", rec.Before, rec.After)
+	}
+}
+
+func TestDeleteMissingDemoWritesNoAuditRecord(t *testing.T) {
+	app := newTestApp(t, func(cfg *config.Config) { cfg.Audit.Enabled = true })
+
+	app.AssertStatus(app.Do(http.MethodDelete, "/api/v1/demos/404", nil), http.StatusNotFound)
+	if data, err := os.ReadFile(app.Config.Audit.File); err != nil || len(data) != 0 {
+		t.Fatalf("audit log = %q, %v, want empty", data, err)
+	}
+}
@@ -0,0 +1,557 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/constants"
+	"go-api-template/internal/model"
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// createDemo 通过接口创建 Demo 并返回响应中的数据
+func (a *testApp) createDemo(t *testing.T, title, content string) model.Demo {
+	t.Helper()
+
+	w := a.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": title, "content": content})
+	a.AssertStatus(w, http.StatusCreated)
+	var demo model.Demo
+	a.DecodeData(w, &demo)
+	return demo
+}
+
+// getDemo 通过接口查询 Demo
+func (a *testApp) getDemo(t *testing.T, id uint) model.Demo {
+	t.Helper()
+
+	w := a.Do(http.MethodGet, fmt.Sprintf("/api/v1/demos/%d", id), nil)
+	a.AssertCode(w, http.StatusOK)
+	var demo model.Demo
+	a.DecodeData(w, &demo)
+	return demo
+}
+
+func TestPatchDemoAbsentEmptyNull(t *testing.T) {
+	app := newTestApp(t, nil)
+	demo := app.createDemo(t, "patch", "original")
+	path := fmt.Sprintf("/api/v1/demos/%d", demo.ID)
+
+	// 未出现的字段保持不变
+	app.AssertCode(app.Do(http.MethodPatch, path, json.RawMessage(`{}`)), http.StatusOK)
+	if got := app.getDemo(t, demo.ID).Content; got != "original" {
+		t.Fatalf("after {}: content = %q, want %q", got, "original")
+	}
+
+	// 空字符串清空内容
+	app.AssertCode(app.Do(http.MethodPatch, path, json.RawMessage(`{"content":""}`)), http.StatusOK)
+	if got := app.getDemo(t, demo.ID).Content; got != "" {
+		t.Fatalf(`after {"content":""}: content = %q, want empty`, got)
+	}
+
+	// null 不是合法值
+	app.AssertCode(app.Do(http.MethodPatch, path, json.RawMessage(`{"content":null}`)), http.StatusBadRequest)
+}
+
+func TestServiceLogIncludesRequestID(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.Header.Set(constants.HeaderRequestID, "req-service-log")
+	app.createDemo(t, "logged", "")
+
+	entries := app.Logs.FilterMessage("demo created successfully").All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d service log entries, want 1", len(entries))
+	}
+	if got := entries[0].ContextMap()["request_id"]; got != "req-service-log" {
+		t.Fatalf("request_id = %v, want %q", got, "req-service-log")
+	}
+}
+
+func TestListDemosCacheControl(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	w := app.Do(http.MethodGet, "/api/v1/demos", nil)
+	app.AssertCode(w, http.StatusOK)
+	if got := w.Header().Get("Cache-Control"); got != "public, max-age=30" {
+		t.Fatalf("Cache-Control = %q, want %q", got, "public, max-age=30")
+	}
+
+	// 参数错误的响应不能被缓存
+	w = app.Do(http.MethodGet, "/api/v1/demos?sort=unknown", nil)
+	app.AssertCode(w, http.StatusBadRequest)
+	if got := w.Header().Get("Cache-Control"); got != "" {
+		t.Fatalf("400 Cache-Control = %q, want none", got)
+	}
+}
+
+func TestCreateDemoIncrementsCounter(t *testing.T) {
+	app := newTestApp(t, nil)
+	before := app.scrapeMetric(t, "demo_created_total")
+
+	app.createDemo(t, "counted", "")
+	app.createDemo(t, "counted again", "")
+
+	if got := app.scrapeMetric(t, "demo_created_total"); got != before+2 {
+		t.Fatalf("demo_created_total = %v, want %v", got, before+2)
+	}
+}
+
+func TestExportDemosNDJSON(t *testing.T) {
+	app := newTestApp(t, nil)
+	titles := []string{"first", "second", "third"}
+	for _, title := range titles {
+		app.createDemo(t, title, "")
+	}
+
+	w := app.Do(http.MethodGet, "/api/v1/demos/export.ndjson", nil)
+	app.AssertStatus(w, http.StatusOK)
+	if got := w.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Fatalf("Content-Type = %q, want application/x-ndjson", got)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	if len(lines) != len(titles) {
+		t.Fatalf("got %d lines, want %d\nbody: %s", len(lines), len(titles), w.Body.String())
+	}
+	for i, line := range lines {
+		var demo model.Demo
+		if err := json.Unmarshal([]byte(line), &demo); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		if demo.Title != titles[i] {
+			t.Fatalf("line %d: title = %q, want %q", i+1, demo.Title, titles[i])
+		}
+	}
+}
+
+func TestCreateDemoFromForm(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := app.Do(http.MethodPost, "/api/v1/demos", "title=from+form&content=posted&status=1")
+	app.AssertStatus(w, http.StatusCreated)
+	var demo model.Demo
+	app.DecodeData(w, &demo)
+	if demo.Title != "from form" || demo.Content != "posted" || demo.Status != model.DemoStatusEnabled {
+		t.Fatalf("created %+v", demo)
+	}
+}
+
+func TestCreateDemoValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  map[string]interface{}
+		field string
+		rule  string
+	}{
+		{"missing title", map[string]interface{}{"content": "x"}, "title", "required"},
+		{"title too long", map[string]interface{}{"title": strings.Repeat("t", 201)}, "title", "max"},
+		{"invalid status", map[string]interface{}{"title": "status", "status": 2}, "status", "oneof"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, nil)
+			w := app.Do(http.MethodPost, "/api/v1/demos", tt.body)
+			app.AssertCode(w, http.StatusBadRequest)
+
+			var data struct {
+				Errors []web.FieldError `json:"errors"`
+			}
+			app.DecodeData(w, &data)
+			if len(data.Errors) != 1 || data.Errors[0].Field != tt.field || data.Errors[0].Rule != tt.rule {
+				t.Fatalf("errors = %+v, want %s/%s", data.Errors, tt.field, tt.rule)
+			}
+		})
+	}
+
+	// 200 个字符（与 varchar(200) 一致）可以创建
+	app := newTestApp(t, nil)
+	app.createDemo(t, strings.Repeat("字", 200), "")
+}
+
+func TestCreateDuplicateTitleConflict(t *testing.T) {
+	app := newTestApp(t, nil)
+	first := app.createDemo(t, "unique title", "")
+
+	w := app.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": "unique title"})
+	app.AssertStatus(w, http.StatusConflict)
+	app.AssertMessage(w, "demo title already exists")
+
+	// 更新为已存在的标题同样冲突
+	second := app.createDemo(t, "other title", "")
+	w = app.Do(http.MethodPut, fmt.Sprintf("/api/v1/demos/%d", second.ID), map[string]interface{}{"title": first.Title, "status": 1})
+	app.AssertStatus(w, http.StatusConflict)
+}
+
+func TestResponseFieldNamingCamel(t *testing.T) {
+	app := newTestApp(t, func(cfg *config.Config) { cfg.Response.FieldNaming = web.FieldNamingCamel })
+
+	w := app.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": "camel"})
+	app.AssertStatus(w, http.StatusCreated)
+	var data map[string]interface{}
+	app.DecodeData(w, &data)
+	if _, ok := data["createdAt"]; !ok {
+		t.Fatalf("createdAt missing: %v", data)
+	}
+	if _, ok := data["created_at"]; ok {
+		t.Fatalf("created_at present with camel naming: %v", data)
+	}
+}
+
+func TestHeadListDemos(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.createDemo(t, "head", "")
+
+	get := app.Do(http.MethodGet, "/api/v1/demos", nil)
+	app.AssertStatus(get, http.StatusOK)
+
+	head := app.Do(http.MethodHead, "/api/v1/demos", nil)
+	if head.Code != http.StatusOK {
+		t.Fatalf("HEAD status = %d, want 200", head.Code)
+	}
+	if head.Body.Len() != 0 {
+		t.Fatalf("HEAD body = %q, want empty", head.Body.String())
+	}
+	if got, want := head.Header().Get("Content-Length"), strconv.Itoa(get.Body.Len()); got != want {
+		t.Fatalf("HEAD Content-Length = %q, want %q", got, want)
+	}
+	for _, key := range []string{"Content-Type", "Cache-Control"} {
+		if got, want := head.Header().Get(key), get.Header().Get(key); got != want {
+			t.Fatalf("HEAD %s = %q, GET has %q", key, got, want)
+		}
+	}
+
+	// 不存在的资源：HEAD 同样返回 404
+	if w := app.Do(http.MethodHead, "/api/v1/demos/404", nil); w.Code != http.StatusNotFound || w.Body.Len() != 0 {
+		t.Fatalf("HEAD missing demo: status = %d, body = %q", w.Code, w.Body.String())
+	}
+}
+
+func TestListDemosEmptyIsArray(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	for _, path := range []string{"/api/v1/demos", "/api/v1/demos?filter[title]=missing"} {
+		w := app.Do(http.MethodGet, path, nil)
+		app.AssertCode(w, http.StatusOK)
+		if !strings.Contains(w.Body.String(), `"data":[]`) {
+			t.Fatalf("GET %s body = %s, want \"data\":[]", path, w.Body.String())
+		}
+	}
+}
+
+func TestCreatedAtUTCPolicy(t *testing.T) {
+	prev := time.Local
+	time.Local = time.FixedZone("CST", 8*3600)
+	t.Cleanup(func() { time.Local = prev })
+
+	app := newTestApp(t, func(cfg *config.Config) { cfg.Database.UTC = true })
+	w := app.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": "utc"})
+	app.AssertStatus(w, http.StatusCreated)
+
+	var data struct {
+		CreatedAt string `json:"created_at"`
+	}
+	app.DecodeData(w, &data)
+	if !strings.HasSuffix(data.CreatedAt, "Z") {
+		t.Fatalf("created_at = %q, want Z suffix", data.CreatedAt)
+	}
+}
+
+func TestListDemosSparseFields(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.createDemo(t, "first", "long content")
+	app.createDemo(t, "second", "more content")
+
+	w := app.Do(http.MethodGet, "/api/v1/demos?fields=id,title", nil)
+	app.AssertCode(w, http.StatusOK)
+	var items []map[string]interface{}
+	app.DecodeData(w, &items)
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	for _, item := range items {
+		if len(item) != 2 || item["id"] == nil || item["title"] == nil {
+			t.Fatalf("item = %v, want only id and title", item)
+		}
+	}
+
+	// 未知字段返回 400
+	w = app.Do(http.MethodGet, "/api/v1/demos?fields=id,password", nil)
+	app.AssertStatus(w, http.StatusBadRequest)
+	if !strings.Contains(app.Decode(w).Message, "password") {
+		t.Fatalf("message = %q, want the unknown field", app.Decode(w).Message)
+	}
+}
+
+func TestListDemosSort(t *testing.T) {
+	app := newTestApp(t, nil)
+	for _, title := range []string{"b", "a", "c"} {
+		app.createDemo(t, title, "")
+	}
+	app.AssertCode(app.Do(http.MethodPatch, "/api/v1/demos/3", map[string]interface{}{"status": 0}), http.StatusOK)
+
+	titles := func(path string) string {
+		w := app.Do(http.MethodGet, path, nil)
+		app.AssertCode(w, http.StatusOK)
+		var demos []model.Demo
+		app.DecodeData(w, &demos)
+		var out []string
+		for _, d := range demos {
+			out = append(out, d.Title)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := map[string]string{
+		"/api/v1/demos?sort=title":         "a,b,c",
+		"/api/v1/demos?sort=-title":        "c,b,a",
+		"/api/v1/demos?sort=-id":           "c,a,b",
+		"/api/v1/demos?sort=status,-title": "c,b,a", // status 0 在前，相同 status 按标题降序
+		"/api/v1/demos?sort=-status,title": "a,b,c",
+	}
+	for path, want := range tests {
+		if got := titles(path); got != want {
+			t.Fatalf("GET %s: titles = %s, want %s", path, got, want)
+		}
+	}
+
+	for _, sort := range []string{"password", "title,title"} {
+		w := app.Do(http.MethodGet, "/api/v1/demos?sort="+sort, nil)
+		app.AssertStatus(w, http.StatusBadRequest)
+	}
+}
+
+func TestListDemosFilter(t *testing.T) {
+	app := newTestApp(t, nil)
+	for _, title := range []string{"go basics", "go advanced", "rust"} {
+		app.createDemo(t, title, "")
+	}
+	app.AssertCode(app.Do(http.MethodPatch, "/api/v1/demos/2", map[string]interface{}{"status": 0}), http.StatusOK)
+
+	count := func(query string) int {
+		w := app.Do(http.MethodGet, "/api/v1/demos?"+query, nil)
+		app.AssertCode(w, http.StatusOK)
+		var demos []model.Demo
+		app.DecodeData(w, &demos)
+		return len(demos)
+	}
+	tests := map[string]int{
+		"filter[title][like]=go":                  2,
+		"filter[title]=rust":                      1,
+		"filter[status]=0":                        1,
+		"filter[status][in]=0,1":                  3,
+		"filter[id][in]=1,3&filter[status]=1":     2,
+		"filter[created_at][gte]=2000-01-01":      3,
+		"filter[created_at][lte]=2000-01-01":      0,
+		"filter[title][like]=go&filter[status]=1": 1,
+	}
+	for query, want := range tests {
+		if got := count(query); got != want {
+			t.Fatalf("GET ?%s: %d demos, want %d", query, got, want)
+		}
+	}
+
+	for _, query := range []string{"filter[password]=x", "filter[title][gte]=a", "filter[status]=abc", "filter[title=go", "filter[]=go"} {
+		w := app.Do(http.MethodGet, "/api/v1/demos?"+query, nil)
+		app.AssertStatus(w, http.StatusBadRequest)
+	}
+	w := app.Do(http.MethodGet, "/api/v1/demos?filter[title=go", nil)
+	if msg := app.Decode(w).Message; !strings.Contains(msg, "filter[title") {
+		t.Fatalf("message = %q, want the malformed key", msg)
+	}
+}
+
+func TestGetDemoIfModifiedSince(t *testing.T) {
+	app := newTestApp(t, nil)
+	demo := app.createDemo(t, "conditional", "")
+	path := fmt.Sprintf("/api/v1/demos/%d", demo.ID)
+
+	w := app.Do(http.MethodGet, path, nil)
+	app.AssertCode(w, http.StatusOK)
+	if want := demo.UpdatedAt.Time().UTC().Format(http.TimeFormat); w.Header().Get("Last-Modified") != want {
+		t.Fatalf("Last-Modified = %q, want %q", w.Header().Get("Last-Modified"), want)
+	}
+
+	app.Header.Set("If-Modified-Since", w.Header().Get("Last-Modified"))
+	if w := app.Do(http.MethodGet, path, nil); w.Code != http.StatusNotModified {
+		t.Fatalf("status = %d, want 304", w.Code)
+	}
+}
+
+func TestUpdateDemoBindsPathAndBody(t *testing.T) {
+	app := newTestApp(t, nil)
+	demo := app.createDemo(t, "before", "")
+	other := app.createDemo(t, "other", "")
+
+	// id 取自路径，请求体中的 id 被忽略
+	w := app.Do(http.MethodPut, fmt.Sprintf("/api/v1/demos/%d", demo.ID), map[string]interface{}{"id": other.ID, "title": "after", "status": 0})
+	app.AssertCode(w, http.StatusOK)
+	if got := app.getDemo(t, demo.ID); got.Title != "after" || got.Status != 0 {
+		t.Fatalf("updated demo = %+v", got)
+	}
+	if got := app.getDemo(t, other.ID); got.Title != "other" {
+		t.Fatalf("other demo changed: %+v", got)
+	}
+
+	w = app.Do(http.MethodPut, "/api/v1/demos/abc", map[string]interface{}{"title": "after"})
+	app.AssertCode(w, http.StatusBadRequest)
+}
+
+func TestDemoJSONBodyLimit(t *testing.T) {
+	app := newTestApp(t, func(cfg *config.Config) { cfg.Request.JSONMaxSize = 1 })
+	demo := app.createDemo(t, "limited", "")
+	large := map[string]interface{}{"title": "large", "content": strings.Repeat("x", 2048)}
+
+	for _, req := range []struct{ method, path string }{
+		{http.MethodPost, "/api/v1/demos"},
+		{http.MethodPut, fmt.Sprintf("/api/v1/demos/%d", demo.ID)},
+		{http.MethodPatch, fmt.Sprintf("/api/v1/demos/%d", demo.ID)},
+	} {
+		w := app.Do(req.method, req.path, large)
+		app.AssertCode(w, http.StatusRequestEntityTooLarge)
+		app.AssertMessage(w, constants.MsgRequestEntityTooLarge)
+	}
+	if got := app.getDemo(t, demo.ID); got.Title != "limited" {
+		t.Fatalf("demo changed by rejected request: %+v", got)
+	}
+}
+
+func TestCreateDemoReturnsLocation(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	w := app.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": "located", "content": "c"})
+	app.AssertStatus(w, http.StatusCreated)
+	app.AssertCode(w, http.StatusCreated)
+	var demo model.Demo
+	app.DecodeData(w, &demo)
+
+	location := w.Header().Get("Location")
+	if want := fmt.Sprintf("/api/v1/demos/%d", demo.ID); location != want {
+		t.Fatalf("Location = %q, want %q", location, want)
+	}
+	// Location 指向的资源可以直接查询
+	if got := app.Do(http.MethodGet, location, nil); got.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d, want 200", location, got.Code)
+	}
+}
+
+func TestDemoRejectsStatusFive(t *testing.T) {
+	app := newTestApp(t, nil)
+	demo := app.createDemo(t, "status", "c")
+	path := fmt.Sprintf("/api/v1/demos/%d", demo.ID)
+
+	for _, tt := range []struct {
+		method, path string
+		body         map[string]interface{}
+	}{
+		{http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": "five", "status": 5}},
+		{http.MethodPut, path, map[string]interface{}{"title": "status", "status": 5}},
+		{http.MethodPatch, path, map[string]interface{}{"status": 5}},
+	} {
+		w := app.Do(tt.method, tt.path, tt.body)
+		app.AssertCode(w, http.StatusBadRequest)
+	}
+
+	if got := app.getDemo(t, demo.ID); got.Status != demo.Status {
+		t.Fatalf("status = %d, want unchanged %d", got.Status, demo.Status)
+	}
+	var count int64
+	app.DB.Model(&model.Demo{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("demos = %d, want 1", count)
+	}
+}
+
+// status 为 0（禁用）时按 0 保存，未传时为启用
+func TestCreateDemoStatus(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	for _, tt := range []struct {
+		title string
+		body  map[string]interface{}
+		want  int
+	}{
+		{"disabled", map[string]interface{}{"title": "disabled", "status": 0}, model.DemoStatusDisabled},
+		{"enabled", map[string]interface{}{"title": "enabled", "status": 1}, model.DemoStatusEnabled},
+		{"absent", map[string]interface{}{"title": "absent"}, model.DemoStatusEnabled},
+	} {
+		w := app.Do(http.MethodPost, "/api/v1/demos", tt.body)
+		app.AssertStatus(w, http.StatusCreated)
+		var created model.Demo
+		app.DecodeData(w, &created)
+		if created.Status != tt.want {
+			t.Fatalf("%s: created status = %d, want %d", tt.title, created.Status, tt.want)
+		}
+		if got := app.getDemo(t, created.ID); got.Status != tt.want {
+			t.Fatalf("%s: stored status = %d, want %d", tt.title, got.Status, tt.want)
+		}
+	}
+}
+
+// 空标题返回 400 而不是 500：接口的参数校验先拦截，绕过校验时 Service 的业务规则错误同样映射为 400
+func TestCreateDemoEmptyTitleIsBadRequest(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.AssertCode(app.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": ""}), http.StatusBadRequest)
+
+	s := webtest.New(t, func(r *gin.Engine) {
+		r.POST("/demos", web.ToGinHandler(func(ctx *web.Context) {
+			err := app.DemoSvc.Create(ctx.Request.Context(), &model.Demo{Title: ""})
+			web.RespondError(ctx, err, "create demo failed")
+		}))
+	})
+	w := s.Do(http.MethodPost, "/demos", nil)
+	s.AssertCode(w, http.StatusBadRequest)
+	s.AssertMessage(w, "title cannot be empty: 参数无效")
+
+	var count int64
+	app.DB.Model(&model.Demo{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("demos = %d, want 0", count)
+	}
+}
+
+func TestListDemosMetaEchoesQuery(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.createDemo(t, "go basics", "")
+
+	w := app.Do(http.MethodGet, "/api/v1/demos?meta=true&filter[status][in]=0,1&filter[title][like]=go&sort=-created_at,title&fields=id,title", nil)
+	app.AssertCode(w, http.StatusOK)
+	var meta web.ListMeta
+	if err := json.Unmarshal(app.Decode(w).Meta, &meta); err != nil {
+		t.Fatalf("decode meta: %v\nbody: %s", err, w.Body.String())
+	}
+
+	filters := make(map[string]web.FilterMeta, len(meta.Filters))
+	for _, f := range meta.Filters {
+		filters[f.Field] = f
+	}
+	// 筛选值为转换后的值：status 为整数
+	if f := filters["status"]; f.Op != "in" || fmt.Sprint(f.Value) != "[0 1]" {
+		t.Fatalf("status filter = %+v, want in [0 1]", f)
+	}
+	if f := filters["title"]; f.Op != "like" || f.Value != "go" {
+		t.Fatalf("title filter = %+v, want like go", f)
+	}
+	if len(meta.Filters) != 2 {
+		t.Fatalf("filters = %+v, want 2", meta.Filters)
+	}
+	if strings.Join(meta.Sort, ",") != "-created_at,title" || strings.Join(meta.Fields, ",") != "id,title" {
+		t.Fatalf("sort = %v, fields = %v", meta.Sort, meta.Fields)
+	}
+	if meta.Page != nil {
+		t.Fatalf("page = %+v, want omitted for an unpaged list", meta.Page)
+	}
+
+	// 不带 meta 参数时不回显
+	if env := app.Decode(app.Do(http.MethodGet, "/api/v1/demos?filter[status]=1", nil)); len(env.Meta) != 0 {
+		t.Fatalf("meta = %s, want omitted", env.Meta)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-api-template/internal/constants"
+	"go-api-template/internal/controller"
+	"go-api-template/internal/model"
+	"go-api-template/pkg/config"
+
+	"gorm.io/gorm"
+)
+
+// countInserts 统计 db 执行的 INSERT 语句数
+func countInserts(t *testing.T, db *gorm.DB) *int {
+	t.Helper()
+
+	var n int
+	if err := db.Callback().Create().After("gorm:create").Register("test:count_inserts", func(*gorm.DB) { n++ }); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	return &n
+}
+
+func TestImportDemosInBatches(t *testing.T) {
+	app := newTestApp(t, func(cfg *config.Config) { cfg.Import.BatchSize = 2 })
+	inserts := countInserts(t, app.DB)
+
+	body := json.RawMessage(`[
+		{"title": "one", "status": 1},
+		{"title": "two", "status": 1},
+		{"content": "missing title"},
+		{"title": "three", "status": 0},
+		{"title": "four", "status": 1},
+		{"title": "five", "status": 1}
+	]`)
+	w := app.Do(http.MethodPost, "/api/v1/demos/import", body)
+	app.AssertCode(w, http.StatusOK)
+
+	var result controller.ImportResult
+	app.DecodeData(w, &result)
+	if result.Inserted != 5 || result.Failed != 1 {
+		t.Fatalf("result = %+v, want 5 inserted, 1 failed", result)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Index != 2 {
+		t.Fatalf("errors = %+v, want index 2", result.Errors)
+	}
+	// 下标在 index 中，字段路径相对于元素
+	if fields := result.Errors[0].Errors; len(fields) != 1 || fields[0].Field != "title" || fields[0].Rule != "required" {
+		t.Fatalf("field errors = %+v, want title required", fields)
+	}
+	// 5 条按每批 2 条插入：3 条 INSERT
+	if *inserts != 3 {
+		t.Fatalf("ran %d INSERT statements, want 3", *inserts)
+	}
+
+	var count int64
+	app.DB.Model(&model.Demo{}).Count(&count)
+	if count != 5 {
+		t.Fatalf("demos = %d, want 5", count)
+	}
+	// status 为 0 的元素按 0 保存
+	app.DB.Model(&model.Demo{}).Where("status = ?", model.DemoStatusDisabled).Count(&count)
+	if count != 1 {
+		t.Fatalf("disabled demos = %d, want 1", count)
+	}
+}
+
+func TestImportDemosRollsBackOnDuplicate(t *testing.T) {
+	app := newTestApp(t, func(cfg *config.Config) { cfg.Import.BatchSize = 1 })
+	app.createDemo(t, "taken", "")
+
+	w := app.Do(http.MethodPost, "/api/v1/demos/import", json.RawMessage(`[{"title":"fresh"},{"title":"taken"}]`))
+	app.AssertStatus(w, http.StatusConflict)
+
+	var count int64
+	app.DB.Model(&model.Demo{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("demos = %d after rolled back import, want 1", count)
+	}
+}
+
+func TestImportDemosBodyTooLarge(t *testing.T) {
+	app := newTestApp(t, func(cfg *config.Config) { cfg.Import.MaxSize = 1 })
+
+	items := make([]string, 0, 20000)
+	for i := 0; i < cap(items); i++ {
+		items = append(items, fmt.Sprintf(`{"title":"item %d","content":"%s"}`, i, strings.Repeat("x", 40)))
+	}
+	w := app.Do(http.MethodPost, "/api/v1/demos/import", json.RawMessage("["+strings.Join(items, ",")+"]"))
+	app.AssertStatus(w, http.StatusRequestEntityTooLarge)
+
+	var count int64
+	app.DB.Model(&model.Demo{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("demos = %d after oversize import, want 0", count)
+	}
+}
+
+func TestImportDemosMalformedJSON(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.AssertStatus(app.Do(http.MethodPost, "/api/v1/demos/import", json.RawMessage(`{"title":"not an array"}`)), http.StatusBadRequest)
+}
+
+func TestImportGatedByFeatureFlag(t *testing.T) {
+	body := json.RawMessage(`[{"title":"flagged"}]`)
+
+	off := newTestApp(t, func(cfg *config.Config) {
+		cfg.FeatureFlags.Flags = map[string]bool{constants.FlagDemoImport: false}
+		cfg.FeatureFlags.HeaderOverride = true
+	})
+	off.AssertStatus(off.Do(http.MethodPost, "/api/v1/demos/import", body), http.StatusNotFound)
+
+	// 允许覆盖时请求头可以临时开启
+	off.Header.Set(constants.HeaderFeatureFlags, constants.FlagDemoImport+"=on")
+	off.AssertCode(off.Do(http.MethodPost, "/api/v1/demos/import", body), http.StatusOK)
+
+	on := newTestApp(t, func(cfg *config.Config) {
+		cfg.FeatureFlags.Flags = map[string]bool{constants.FlagDemoImport: true}
+		cfg.FeatureFlags.HeaderOverride = false
+	})
+	on.AssertCode(on.Do(http.MethodPost, "/api/v1/demos/import", body), http.StatusOK)
+
+	// 不允许覆盖时忽略请求头
+	on.Header.Set(constants.HeaderFeatureFlags, constants.FlagDemoImport+"=off")
+	on.AssertCode(on.Do(http.MethodPost, "/api/v1/demos/import", json.RawMessage(`[{"title":"still on"}]`)), http.StatusOK)
+}
@@ -1,24 +1,51 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"go-api-template/pkg/config"
 	"go-api-template/pkg/logger"
 )
 
+// version 应用版本（编译时通过 -ldflags "-X main.version=..." 注入）
+var version = "dev"
+
 func main() {
+	// 子命令：seed 填充示例数据，validate 校验配置
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "seed":
+			runSeed(os.Args[2:])
+			return
+		case "validate":
+			runValidate(os.Args[2:])
+			return
+		}
+	}
+
 	// 解析命令行参数
-	configPath := flag.String("config", "config/config.yaml", "配置文件路径")
+	var configPaths configFlag
+	flag.Var(&configPaths, "config", "配置文件路径（可重复指定或逗号分隔，后面的文件覆盖前面的字段）")
 	flag.Parse()
+	if len(configPaths) == 0 {
+		configPaths = configFlag{"config/config.yaml"}
+	}
+	configPath := configPaths.String()
 
 	// 加载配置
-	cfg, err := config.LoadConfig(*configPath)
+	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
 		log.Fatalf("❌ 加载配置失败: %v", err)
 	}
@@ -28,12 +55,26 @@ func main() {
 	if err != nil {
 		log.Fatalf("❌ 初始化日志失败: %v", err)
 	}
+	// 优雅关闭（由 Fatal 触发）后以退出码 1 退出；最先注册，在日志关闭、资源清理之后执行
+	var fatalExit atomic.Bool
+	defer func() {
+		if fatalExit.Load() {
+			os.Exit(1)
+		}
+	}()
 	defer logger.Close()
+	defer logPanic(cfg.Logger.PanicGoroutineDump) // 先于 logger.Close 执行，确保 panic 日志写出
 
-	logger.Info("🚀 应用启动中...")
+	logger.Info("🚀 应用启动中",
+		logger.String("version", version),
+		logger.Int("port", cfg.Server.Port),
+		logger.String("mode", cfg.Server.Mode),
+		logger.String("db_driver", cfg.Database.Driver),
+		logger.String("cache_driver", cfg.Cache.Driver),
+	)
 
 	// 初始化应用（通过 Wire 依赖注入）
-	router, cleanup, err := InitializeApp(*configPath)
+	app, cleanup, err := InitializeApp(configPath)
 	if err != nil {
 		logger.Fatalf("❌ 初始化应用失败: %v", err)
 	}
@@ -42,7 +83,85 @@ func main() {
 	// 服务器端口
 	port := fmt.Sprintf(":%d", cfg.Server.Port)
 
-	// 打印启动信息
+	// 先监听端口，确保“就绪”日志输出时服务已可接收请求
+	listener, err := net.Listen("tcp", port)
+	if err != nil {
+		logger.Fatalf("❌ 服务器启动失败: %v", err)
+	}
+
+	// 启动服务器（在 goroutine 中）
+	server := startServer(cfg, app, listener)
+
+	// 终端运行时打印启动信息（日志采集场景下不输出）
+	if isTerminal(os.Stdout) {
+		printBanner(port)
+	}
+
+	// 等待中断信号（优雅关闭）
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	// 启动完成后的 Fatal 走优雅关闭，关闭钩子照常执行（启动阶段的 Fatal 仍立即退出）
+	if cfg.Logger.FatalShutdown {
+		logger.SetFatalHandler(func() {
+			fatalExit.Store(true)
+			select {
+			case quit <- syscall.SIGTERM:
+			default: // 已在关闭中
+			}
+		})
+	}
+	<-quit
+
+	logger.Info("⏳ 正在关闭服务器",
+		logger.Int64("active_requests", app.InFlight.Count()),
+		logger.Int64("active_streams", app.Streams.Active()),
+	)
+
+	// 停止接收新请求，等待在途请求处理完成
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout)*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Error("服务器关闭超时", logger.Err(err))
+	}
+
+	logger.Info("✅ 服务器已关闭",
+		logger.Duration("drain_duration", time.Since(start)),
+		logger.Int64("active_requests", app.InFlight.Count()),
+		logger.Int64("active_streams", app.Streams.Active()),
+	)
+}
+
+// configFlag 可重复指定的配置文件参数
+type configFlag []string
+
+// String 返回逗号分隔的配置文件列表
+func (f *configFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set 追加配置文件（支持逗号分隔）
+func (f *configFlag) Set(value string) error {
+	for _, p := range strings.Split(value, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			*f = append(*f, p)
+		}
+	}
+	return nil
+}
+
+// isTerminal 判断输出是否为终端
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// printBanner 打印启动信息
+func printBanner(port string) {
 	fmt.Println()
 	fmt.Println("========================================")
 	fmt.Println("  Go API Template - 服务已启动")
@@ -50,30 +169,58 @@ func main() {
 	fmt.Printf("🌐 服务地址: http://localhost%s\n", port)
 	fmt.Printf("📚 API 文档:\n")
 	fmt.Printf("   - 健康检查:    GET  http://localhost%s/health\n", port)
+	fmt.Printf("   - 就绪检查:    GET  http://localhost%s/ready\n", port)
 	fmt.Printf("   - Demo 列表:   GET  http://localhost%s/api/v1/demos\n", port)
 	fmt.Printf("   - Demo 详情:   GET  http://localhost%s/api/v1/demos/:id\n", port)
+	fmt.Printf("   - 导出 Demo:   GET  http://localhost%s/api/v1/demos/export.ndjson\n", port)
 	fmt.Printf("   - 创建 Demo:   POST http://localhost%s/api/v1/demos\n", port)
+	fmt.Printf("   - 上传附件:    POST http://localhost%s/api/v1/demos/upload\n", port)
 	fmt.Printf("   - 更新 Demo:   PUT  http://localhost%s/api/v1/demos/:id\n", port)
+	fmt.Printf("   - 部分更新:    PATCH http://localhost%s/api/v1/demos/:id\n", port)
 	fmt.Printf("   - 删除 Demo:   DEL  http://localhost%s/api/v1/demos/:id\n", port)
 	fmt.Println("========================================")
 	fmt.Printf("💡 使用 Ctrl+C 停止服务\n")
 	fmt.Println()
+}
 
-	logger.Infof("服务器启动在端口 %s", port)
+// startServer 在 listener 上启动 HTTP 服务器（在 goroutine 中）并输出就绪日志
+func startServer(cfg *config.Config, app *App, listener net.Listener) *http.Server {
+	server := newHTTPServer(cfg, app.Handler)
+	// 开始关闭时通知流式连接（SSE）发送 shutdown 事件并结束，否则 Shutdown 会一直等到超时
+	server.RegisterOnShutdown(app.Streams.Shutdown)
 
-	// 启动服务器（在 goroutine 中）
 	go func() {
-		if err := router.Run(port); err != nil {
-			logger.Fatalf("❌ 服务器启动失败: %v", err)
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatalf("❌ 服务器运行失败: %v", err)
 		}
 	}()
 
-	// 等待中断信号（优雅关闭）
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	logger.Info("✅ 服务已就绪",
+		logger.Int("port", cfg.Server.Port),
+		logger.String("addr", listener.Addr().String()),
+	)
+	return server
+}
 
-	logger.Info("⏳ 正在关闭服务器...")
-	fmt.Println()
-	fmt.Println("✅ 服务器已关闭")
+// newHTTPServer 根据配置创建 HTTP 服务器（请求头超时与大小限制）
+func newHTTPServer(cfg *config.Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeout) * time.Second,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+	}
+}
+
+// logPanic 记录主 goroutine 的 panic（含调用栈，dumpGoroutines 时附带所有 goroutine）后继续 panic
+func logPanic(dumpGoroutines bool) {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+	fields := []logger.Field{logger.Any("panic", rec), logger.Stack("stack")}
+	if dumpGoroutines {
+		fields = append(fields, logger.AllStacks("goroutines"))
+	}
+	logger.Error("❌ 服务发生 panic", fields...)
+	panic(rec)
 }
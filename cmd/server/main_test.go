@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/logger/logtest"
+)
+
+func TestReadyEventLoggedWithPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	a := newTestApp(t, func(cfg *config.Config) {
+		cfg.Server.Port = port
+		cfg.Scheduler.Enabled = false
+	})
+	app, err := a.initApp(t)
+	if err != nil {
+		t.Fatalf("provideApp: %v", err)
+	}
+
+	server := startServer(a.Config, app, listener)
+	t.Cleanup(func() { _ = server.Shutdown(context.Background()) })
+
+	entries := a.Logs.FilterMessage("✅ 服务已就绪").All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d ready events, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["port"] != int64(port) {
+		t.Fatalf("port = %v, want %d", fields["port"], port)
+	}
+	if fields["addr"] != listener.Addr().String() {
+		t.Fatalf("addr = %v, want %s", fields["addr"], listener.Addr())
+	}
+
+	// 就绪日志输出时服务已可接收请求
+	resp, err := http.Get("http://" + listener.Addr().String() + "/health")
+	if err != nil {
+		t.Fatalf("GET /health: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /health = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestConfigFlagRepeatedAndCommaSeparated(t *testing.T) {
+	var f configFlag
+	for _, v := range []string{"config/config.yaml", "config/config.prod.yaml, config/local.yaml", ""} {
+		if err := f.Set(v); err != nil {
+			t.Fatalf("Set(%q): %v", v, err)
+		}
+	}
+	if got, want := f.String(), "config/config.yaml,config/config.prod.yaml,config/local.yaml"; got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestHTTPServerAppliesHeaderLimits(t *testing.T) {
+	cfg := &config.Config{Server: config.ServerConfig{ReadHeaderTimeout: 3, MaxHeaderBytes: 1024}}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	server := newHTTPServer(cfg, ok)
+	if server.MaxHeaderBytes != 1024 {
+		t.Fatalf("MaxHeaderBytes = %d, want 1024", server.MaxHeaderBytes)
+	}
+	if server.ReadHeaderTimeout != 3*time.Second {
+		t.Fatalf("ReadHeaderTimeout = %v, want 3s", server.ReadHeaderTimeout)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Shutdown(context.Background()) })
+
+	url := "http://" + listener.Addr().String() + "/"
+	do := func(headerSize int) int {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		req.Header.Set("X-Padding", strings.Repeat("a", headerSize))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if status := do(100); status != http.StatusOK {
+		t.Fatalf("small header: status = %d, want 200", status)
+	}
+	// net/http 在 MaxHeaderBytes 之外另留 4KB 余量
+	if status := do(16 << 10); status != http.StatusRequestHeaderFieldsTooLarge {
+		t.Fatalf("oversize header: status = %d, want 431", status)
+	}
+}
+
+func TestHTTPServerHeaderLimitDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  port: 8080\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	server := newHTTPServer(cfg, http.NotFoundHandler())
+	if server.MaxHeaderBytes != 1<<20 || server.ReadHeaderTimeout != 10*time.Second {
+		t.Fatalf("defaults: MaxHeaderBytes = %d, ReadHeaderTimeout = %v", server.MaxHeaderBytes, server.ReadHeaderTimeout)
+	}
+}
+
+func TestLogPanicDumpsGoroutinesWhenEnabled(t *testing.T) {
+	for _, dump := range []bool{true, false} {
+		logs := logtest.New(t)
+
+		func() {
+			defer func() {
+				if rec := recover(); rec != "main crashed" {
+					t.Fatalf("dump=%v: re-panicked with %v", dump, rec)
+				}
+			}()
+			defer logPanic(dump)
+			panic("main crashed")
+		}()
+
+		entries := logs.FilterMessage("❌ 服务发生 panic").All()
+		if len(entries) != 1 {
+			t.Fatalf("dump=%v: got %d panic logs, want 1", dump, len(entries))
+		}
+		goroutines, ok := entries[0].ContextMap()["goroutines"].(string)
+		if ok != dump || (dump && !strings.Contains(goroutines, "goroutine ")) {
+			t.Fatalf("dump=%v: goroutines = %.100q, present = %v", dump, goroutines, ok)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/security"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestClientIPTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name       string
+		proxies    []string // nil 使用 config.yaml 中的默认值（仅回环地址）
+		remoteAddr string
+		want       string
+	}{
+		{name: "default trusts loopback", remoteAddr: "127.0.0.1:40000", want: "203.0.113.7"},
+		{name: "default ignores header from untrusted peer", remoteAddr: "198.51.100.1:40000", want: "198.51.100.1"},
+		{name: "empty list trusts no proxy", proxies: []string{}, remoteAddr: "127.0.0.1:40000", want: "127.0.0.1"},
+		{name: "cidr", proxies: []string{"10.0.0.0/8"}, remoteAddr: "10.1.2.3:40000", want: "203.0.113.7"},
+		{name: "cidr excludes other peers", proxies: []string{"10.0.0.0/8"}, remoteAddr: "127.0.0.1:40000", want: "127.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, func(cfg *config.Config) {
+				if tt.proxies != nil {
+					cfg.Server.TrustedProxies = tt.proxies
+				}
+			})
+			app.Engine.GET("/test/client-ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test/client-ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			w := httptest.NewRecorder()
+			app.Engine.ServeHTTP(w, req)
+
+			if got := w.Body.String(); got != tt.want {
+				t.Fatalf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotFoundBodyIncludesPath(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	w := app.Do(http.MethodGet, "/api/v1/nothing/here?x=1", nil)
+	app.AssertStatus(w, http.StatusNotFound)
+	app.AssertMessage(w, constants.MsgInterfaceNotFound)
+
+	var data struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+	}
+	app.DecodeData(w, &data)
+	if data.Method != http.MethodGet || data.Path != "/api/v1/nothing/here" {
+		t.Fatalf("data = %+v, want GET /api/v1/nothing/here", data)
+	}
+}
+
+func TestMethodNotAllowedBodyIncludesAllowedMethods(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	w := app.Do(http.MethodPost, "/api/v1/demos/1", nil)
+	app.AssertStatus(w, http.StatusMethodNotAllowed)
+	app.AssertMessage(w, constants.MsgMethodNotAllowed)
+
+	var data struct {
+		Method  string   `json:"method"`
+		Path    string   `json:"path"`
+		Allowed []string `json:"allowed"`
+	}
+	app.DecodeData(w, &data)
+	if data.Method != http.MethodPost || data.Path != "/api/v1/demos/1" {
+		t.Fatalf("data = %+v, want POST /api/v1/demos/1", data)
+	}
+	allowed := strings.Join(data.Allowed, ",")
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		if !strings.Contains(allowed, method) {
+			t.Fatalf("allowed = %v, missing %s", data.Allowed, method)
+		}
+	}
+	if strings.Contains(allowed, http.MethodPost) {
+		t.Fatalf("allowed = %v includes the rejected method", data.Allowed)
+	}
+	if got := w.Header().Get("Allow"); got != strings.Join(data.Allowed, ", ") {
+		t.Fatalf("Allow = %q, want %q", got, strings.Join(data.Allowed, ", "))
+	}
+}
+
+func TestAuthOnlyOnWriteRoutes(t *testing.T) {
+	const secret = "test-jwt-secret"
+	app := newTestApp(t, func(cfg *config.Config) {
+		cfg.Auth.Enabled = true
+		cfg.Auth.JWTSecret = secret
+	})
+
+	app.AssertCode(app.Do(http.MethodGet, "/api/v1/demos", nil), http.StatusOK)
+	app.AssertStatus(app.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": "no token"}), http.StatusUnauthorized)
+
+	token, err := security.NewJWT(map[string]interface{}{"sub": "user-1"}, time.Minute, secret)
+	if err != nil {
+		t.Fatalf("NewJWT: %v", err)
+	}
+	app.Header.Set("Authorization", "Bearer "+token)
+	app.createDemo(t, "with token", "")
+}
+
+// 同时开启签名校验和 API Key 时二选一
+func TestWriteRoutesAcceptAPIKeyOrCheckSum(t *testing.T) {
+	app := newTestApp(t, func(cfg *config.Config) {
+		cfg.CheckSum.Enabled = true
+		cfg.CheckSum.Provider = "config"
+		cfg.CheckSum.Apps = map[string]string{"signed": "signed-secret"}
+		cfg.APIKey.Enabled = true
+		cfg.APIKey.Provider = "config"
+		cfg.APIKey.Apps = map[string]string{"simple": "ak_simple"}
+	})
+
+	app.AssertStatus(app.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": "anonymous"}), http.StatusUnauthorized)
+
+	app.Header.Set(constants.HeaderAPIKey, "ak_simple")
+	app.createDemo(t, "with api key", "")
+
+	app.Header.Del(constants.HeaderAPIKey)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	app.Header.Set(constants.HeaderAppKey, "signed")
+	app.Header.Set(constants.HeaderTimestamp, timestamp)
+	app.Header.Set(constants.HeaderNonce, "nonce-1")
+	app.Header.Set(constants.HeaderCheckSum, security.Sha1("signed-secret"+"nonce-1"+timestamp))
+	app.createDemo(t, "with signature", "")
+}
+
+func TestTrailingSlashRedirectStaysOnSite(t *testing.T) {
+	a := newTestApp(t, func(cfg *config.Config) {
+		cfg.Server.TrailingSlash = "redirect"
+		cfg.Scheduler.Enabled = false
+	})
+	app, err := a.initApp(t)
+	if err != nil {
+		t.Fatalf("provideApp: %v", err)
+	}
+
+	for target, location := range map[string]string{
+		"/api/v1/demos/?page=2": "/api/v1/demos?page=2",
+		"//evil.com/":           "/evil.com",
+		"/%5Cevil.com/":         "",
+	} {
+		w := httptest.NewRecorder()
+		app.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if got := w.Header().Get("Location"); got != location {
+			t.Fatalf("%s: Location = %q, want %q", target, got, location)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"go-api-template/internal/seeder"
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/logger"
+)
+
+// runSeed 执行 seed 子命令：填充本地开发用的示例数据
+// 用法：server seed [-config config/config.yaml] [-count 10]
+func runSeed(args []string) {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	var configPaths configFlag
+	fs.Var(&configPaths, "config", "配置文件路径（可重复指定或逗号分隔，后面的文件覆盖前面的字段）")
+	count := fs.Int("count", 10, "示例 Demo 数量")
+	fs.Parse(args)
+	if len(configPaths) == 0 {
+		configPaths = configFlag{"config/config.yaml"}
+	}
+
+	cfg, err := config.LoadConfig(configPaths.String())
+	if err != nil {
+		log.Fatalf("❌ 加载配置失败: %v", err)
+	}
+
+	if _, err := logger.InitLogger(cfg); err != nil {
+		log.Fatalf("❌ 初始化日志失败: %v", err)
+	}
+	defer logger.Close()
+
+	db, err := database.NewMySQLDB(cfg)
+	if err != nil {
+		logger.Fatalf("❌ 连接数据库失败: %v", err)
+	}
+	defer database.Close(db)
+
+	if err := database.Seed(db, seeder.NewDemoSeeder(*count)); err != nil {
+		logger.Fatalf("❌ 数据填充失败: %v", err)
+	}
+	logger.Info("✅ 数据填充完成")
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"go-api-template/pkg/config"
+)
+
+// runValidate 执行 validate 子命令：校验配置文件（适合在 CI 中部署前执行）
+// 用法：server validate [-config config/config.yaml] [-strict]
+// 默认与启动时一致（忽略未知字段）；-strict 时未知字段（通常是拼写错误）视为错误
+func runValidate(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	var configPaths configFlag
+	fs.Var(&configPaths, "config", "配置文件路径（可重复指定或逗号分隔，后面的文件覆盖前面的字段）")
+	strict := fs.Bool("strict", false, "严格模式：拒绝未知的配置字段")
+	fs.Parse(args)
+	if len(configPaths) == 0 {
+		configPaths = configFlag{"config/config.yaml"}
+	}
+	path := configPaths.String()
+
+	if *strict {
+		if err := config.ValidateStrict(path); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if _, err := config.LoadConfig(path); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ 加载配置失败: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ 配置有效: %s\n", path)
+}
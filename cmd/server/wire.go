@@ -4,22 +4,47 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-api-template/internal/constants"
 	"go-api-template/internal/controller"
 	"go-api-template/internal/middleware"
+	"go-api-template/internal/model"
 	"go-api-template/internal/repository"
 	"go-api-template/internal/service"
+	"go-api-template/pkg/audit"
+	"go-api-template/pkg/cache"
 	"go-api-template/pkg/config"
 	"go-api-template/pkg/database"
+	"go-api-template/pkg/geoip"
+	"go-api-template/pkg/lifecycle"
 	"go-api-template/pkg/logger"
+	"go-api-template/pkg/metrics"
+	"go-api-template/pkg/redis"
+	"go-api-template/pkg/scheduler"
+	"go-api-template/pkg/security"
 	"go-api-template/pkg/web"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// App 应用实例
+type App struct {
+	Router   *gin.Engine
+	Handler  http.Handler                   // 对外的 HTTP 处理器（路由外层包裹末尾斜杠规范化）
+	InFlight *middleware.InFlightMiddleware // 在途请求计数，用于关闭时统计
+	Streams  *web.StreamTracker             // 在途流式连接（SSE），关闭时通知其结束
+}
+
 // InitializeApp 初始化应用
-func InitializeApp(configPath string) (*gin.Engine, func(), error) {
+func InitializeApp(configPath string) (*App, func(), error) {
 	wire.Build(
 		// 配置
 		config.LoadConfig,
@@ -27,11 +52,29 @@ func InitializeApp(configPath string) (*gin.Engine, func(), error) {
 		// 日志
 		logger.InitLogger,
 
-		// 数据库
-		database.NewMySQLDB,
+		// 生命周期 - 关闭钩子管理
+		lifecycle.NewManager,
+
+		// 数据库（退出时关闭连接池）
+		provideDB,
+
+		// Redis（redis/chain 缓存驱动时创建，退出前关闭连接）
+		provideRedis,
+
+		// 缓存
+		provideCache,
+		wire.Bind(new(cache.Cache), new(*cache.CacheFacade)),
+
+		// 事务管理器 - Service 层跨 Repository 事务
+		database.NewTxManager,
+		wire.Bind(new(database.Transactor), new(*database.TxManager)),
+
+		// 审计日志 - 敏感操作记录（文件 / audit_logs 表）
+		provideAudit,
 
 		// Repository - Demo 数据访问层
 		repository.NewDemoRepository,
+		wire.Bind(new(repository.DemoRepositoryInterface), new(*repository.DemoRepository)),
 
 		// Service - Demo 业务逻辑层
 		service.NewDemoService,
@@ -39,27 +82,346 @@ func InitializeApp(configPath string) (*gin.Engine, func(), error) {
 		// Controller - Demo 控制器
 		controller.NewDemoController,
 
+		// CheckSum 应用密钥来源（配置文件或 apps 表）、请求序号存储（Redis 或进程内存）
+		provideAppSecrets,
+		provideSequenceStore,
+
+		// API Key 来源（配置文件或 apps 表）
+		provideAPIKeys,
+
+		// IP 归属信息查询（GeoLite2 数据库文件，未启用或无法打开时为 nil）
+		provideGeoIP,
+
 		// Middleware - 中间件
 		middleware.NewMiddleware,
 
-		// Router - 路由配置和清理函数
-		provideRouterAndCleanup,
+		// 流式连接（SSE）跟踪 - 服务关闭时通知客户端
+		web.NewStreamTracker,
+
+		// 就绪检查 - 数据库、Redis
+		provideHealthChecks,
+
+		// App - 路由配置和清理函数
+		// 后台任务调度（退出时等待执行中的任务结束）
+		provideScheduler,
+
+		provideApp,
 	)
 	return nil, nil, nil
 }
 
-// provideRouterAndCleanup 配置路由并提供清理函数
-func provideRouterAndCleanup(
+// provideDB 创建数据库连接，并注册关闭钩子和连接池指标
+func provideDB(cfg *config.Config, lc *lifecycle.Manager, cacheFacade *cache.CacheFacade) (*gorm.DB, error) {
+	db, err := database.NewMySQLDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := setupDB(cfg, lc, cacheFacade, db); err != nil {
+		_ = database.Close(db)
+		return nil, err
+	}
+	return db, nil
+}
+
+// setupDB 为已打开的连接注册关闭钩子和连接池指标（与驱动无关，测试中用 SQLite 执行）
+// database.query_cache 开启时启用查询结果缓存（缓存名称 query）
+func setupDB(cfg *config.Config, lc *lifecycle.Manager, cacheFacade *cache.CacheFacade, db *gorm.DB) error {
+	if cfg.Database.QueryCache {
+		if err := database.RegisterQueryCache(db, cacheFacade.Named("query")); err != nil {
+			return fmt.Errorf("注册查询缓存失败: %w", err)
+		}
+	}
+	lc.OnShutdown("database", func(ctx context.Context) error {
+		return database.Close(db)
+	})
+
+	// 连接池指标（后注册的钩子先执行：先停止采集，再关闭连接）
+	if cfg.Metrics.Enabled {
+		stop, err := database.StartPoolMetrics(db, time.Duration(cfg.Metrics.PoolSampleInterval)*time.Second)
+		if err != nil {
+			return err
+		}
+		lc.OnShutdown("database-metrics", func(ctx context.Context) error {
+			stop()
+			return nil
+		})
+	}
+	return nil
+}
+
+// provideRedis 创建 Redis 客户端，并注册关闭钩子和连接池指标
+// 缓存驱动为 memory 时不需要 Redis，返回 nil
+func provideRedis(cfg *config.Config, lc *lifecycle.Manager) (*redis.Client, error) {
+	if cache.CacheDriver(cfg.Cache.Driver) == cache.DriverMemory {
+		return nil, nil
+	}
+
+	client, err := redis.NewRedisClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	lc.OnShutdown("redis", func(ctx context.Context) error {
+		return client.Close()
+	})
+
+	// 连接池指标
+	if cfg.Metrics.Enabled {
+		stop := client.StartPoolMetrics(time.Duration(cfg.Metrics.PoolSampleInterval) * time.Second)
+		lc.OnShutdown("redis-metrics", func(ctx context.Context) error {
+			stop()
+			return nil
+		})
+	}
+	return client, nil
+}
+
+// provideAudit 根据配置创建审计日志，并注册关闭钩子
+// 未启用时返回不含 sink 的 Logger，Log 为空操作
+func provideAudit(cfg *config.Config, db *gorm.DB, lc *lifecycle.Manager) (*audit.Logger, error) {
+	if !cfg.Audit.Enabled {
+		return audit.NewLogger(), nil
+	}
+
+	var sinks []audit.Sink
+	if cfg.Audit.File != "" {
+		fileSink, err := audit.NewFileSink(cfg.Audit.File)
+		if err != nil {
+			return nil, err
+		}
+		lc.OnShutdown("audit", func(ctx context.Context) error {
+			return fileSink.Close()
+		})
+		sinks = append(sinks, fileSink)
+	}
+	if cfg.Audit.DB {
+		sinks = append(sinks, audit.NewDBSink(db))
+	}
+	return audit.NewLogger(sinks...), nil
+}
+
+// provideCache 根据配置创建缓存门面（过期时间按 cache.ttl_jitter 随机浮动）
+func provideCache(cfg *config.Config, client *redis.Client) (*cache.CacheFacade, error) {
+	driver := cache.CacheDriver(cfg.Cache.Driver)
+	if driver == cache.DriverMemory {
+		manager, err := cache.NewCacheManager(cfg, nil)
+		if err != nil {
+			return nil, err
+		}
+		return cache.NewCacheFacade(manager).WithTTLJitter(cfg.Cache.TTLJitter), nil
+	}
+
+	newManager := cache.NewCacheManager
+	if driver == cache.DriverChain {
+		newManager = cache.NewChainCache
+	}
+	manager, err := newManager(cfg, client.UniversalClient)
+	if err != nil {
+		return nil, err
+	}
+	return cache.NewCacheFacade(manager).WithTTLJitter(cfg.Cache.TTLJitter), nil
+}
+
+// provideAppSecrets CheckSum 的应用密钥来源
+// database：从 apps 表查询，凭证缓存 checksum.cache_ttl（避免每个请求查库）；否则使用配置文件中的 checksum.apps
+func provideAppSecrets(cfg *config.Config, db *gorm.DB, cacheFacade *cache.CacheFacade) (security.AppSecretProvider, error) {
+	switch cfg.CheckSum.Provider {
+	case "database":
+		ttl := time.Duration(cfg.CheckSum.CacheTTL) * time.Second
+		return security.NewCachedAppSecretProvider(repository.NewAppRepository(db), cacheFacade.Named("app"), ttl), nil
+	case "config":
+		return security.NewSequencedAppSecretProvider(security.StaticAppSecretProvider(cfg.CheckSum.Apps), cfg.CheckSum.SequenceApps), nil
+	default:
+		return nil, fmt.Errorf("不支持的 checksum.provider: %s", cfg.CheckSum.Provider)
+	}
+}
+
+// provideAPIKeys API Key 认证的 API Key 来源
+// database：按 apps.api_key_hash 查询，结果缓存 api_key.cache_ttl；否则使用配置文件中的 api_key.apps
+func provideAPIKeys(cfg *config.Config, db *gorm.DB, cacheFacade *cache.CacheFacade) (security.APIKeyProvider, error) {
+	switch cfg.APIKey.Provider {
+	case "database":
+		ttl := time.Duration(cfg.APIKey.CacheTTL) * time.Second
+		return security.NewCachedAPIKeyProvider(repository.NewAppRepository(db), cacheFacade.Named("app"), ttl), nil
+	case "config":
+		return security.NewStaticAPIKeyProvider(cfg.APIKey.Apps), nil
+	default:
+		return nil, fmt.Errorf("不支持的 api_key.provider: %s", cfg.APIKey.Provider)
+	}
+}
+
+// provideSequenceStore CheckSum 的请求序号存储
+// 使用 Redis 时多实例共享；缓存驱动为 memory（没有 Redis）时保存在进程内存，只适用于单实例部署
+func provideSequenceStore(client *redis.Client) security.SequenceStore {
+	if client == nil {
+		return security.NewMemorySequenceStore()
+	}
+	return redis.NewSequenceStore(client)
+}
+
+// provideGeoIP 打开 GeoLite2 数据库文件，并注册关闭钩子
+// 未启用时返回 nil；数据库文件无法打开时记录警告并返回 nil（不查询归属信息，不影响启动）
+func provideGeoIP(cfg *config.Config, lc *lifecycle.Manager, _ *zap.Logger) geoip.Resolver {
+	if !cfg.GeoIP.Enabled {
+		return nil
+	}
+
+	resolver, err := geoip.NewMaxMindResolver(cfg.GeoIP.CountryDB, cfg.GeoIP.ASNDB)
+	if err != nil {
+		logger.Warn("geoip disabled: open database failed", logger.Err(err))
+		return nil
+	}
+	lc.OnShutdown("geoip", func(ctx context.Context) error {
+		return resolver.Close()
+	})
+	return resolver
+}
+
+// provideHealthChecks 就绪检查依赖列表
+// model.SchemaVersion 大于 0 时校验数据库迁移版本，未迁移的数据库上不接收流量（启动检查同样会失败）
+func provideHealthChecks(cfg *config.Config, db *gorm.DB, client *redis.Client, cacheFacade *cache.CacheFacade) []web.HealthCheck {
+	checks := []web.HealthCheck{
+		{Name: "database", Check: func(ctx context.Context) error {
+			return database.Ping(ctx, db)
+		}},
+		// 缓存读写往返（多级缓存会指出失败的层级）
+		{Name: "cache", Check: cacheFacade.HealthCheck},
+	}
+	if model.SchemaVersion > 0 {
+		checks = append(checks, web.HealthCheck{
+			Name:  "schema",
+			Check: database.SchemaVersionCheck(db, cfg.Database.MigrationTable, model.SchemaVersion),
+		})
+	}
+	if client != nil {
+		checks = append(checks, web.HealthCheck{Name: "redis", Check: func(ctx context.Context) error {
+			return client.Ping(ctx).Err()
+		}})
+	}
+	return checks
+}
+
+// checkStartup 启动前执行一次所有依赖检查并逐个记录结果
+// 关键依赖（不在 server.startup_check.optional 中）不可用时返回错误，应用拒绝启动
+func checkStartup(cfg *config.Config, checks []web.HealthCheck) error {
+	optional := make(map[string]bool, len(cfg.Server.StartupCheck.Optional))
+	for _, name := range cfg.Server.StartupCheck.Optional {
+		optional[name] = true
+	}
+
+	timeout := time.Duration(cfg.Server.StartupCheck.Timeout) * time.Second
+	var failed []string
+	for _, r := range web.RunHealthChecks(context.Background(), timeout, checks...) {
+		fields := []logger.Field{
+			logger.String("check", r.Name),
+			logger.String("status", string(r.Status)),
+			logger.Int64("duration_ms", r.Duration),
+			logger.Bool("optional", optional[r.Name]),
+		}
+		switch {
+		case r.Status == web.HealthUp:
+			logger.Info("startup check passed", fields...)
+		case optional[r.Name]:
+			logger.Warn("startup check failed, optional dependency unavailable", append(fields, logger.String("error", r.Error))...)
+		default:
+			logger.Error("startup check failed", append(fields, logger.String("error", r.Error))...)
+			failed = append(failed, r.Name)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("关键依赖不可用: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// softDeleteModels 需要定期清理软删除数据的模型（按模型登记，模型需包含 gorm.DeletedAt 字段），如 &model.Order{}
+// Demo 为物理删除，不在此列
+var softDeleteModels = []interface{}{}
+
+// provideScheduler 创建后台任务调度器并注册任务，退出时停止调度并等待执行中的任务结束
+// 任务在 provideApp 中启动（缓存预热之后）
+func provideScheduler(cfg *config.Config, db *gorm.DB, demoSvc *service.DemoService, lc *lifecycle.Manager) (*scheduler.Scheduler, error) {
+	s := scheduler.New()
+	lc.OnShutdown("scheduler", s.Stop)
+	if !cfg.Scheduler.Enabled {
+		return s, nil
+	}
+
+	// 周期性缓存预热（刷新热点数据）
+	if cfg.Scheduler.CacheWarmUpInterval > 0 {
+		timeout := time.Duration(cfg.Cache.WarmUpTimeout) * time.Second
+		err := s.Every("cache-warm-up", time.Duration(cfg.Scheduler.CacheWarmUpInterval)*time.Second, func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return demoSvc.WarmUp(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 软删除数据清理：物理删除超过保留期的软删除记录
+	if cfg.Scheduler.PurgeInterval > 0 {
+		purger := database.NewPurger(db, cfg.Scheduler.PurgeBatchSize)
+		retention := time.Duration(cfg.Scheduler.PurgeRetentionDays) * 24 * time.Hour
+		for _, m := range softDeleteModels {
+			if err := purger.Register(m, retention); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.Every("purge-soft-deleted", time.Duration(cfg.Scheduler.PurgeInterval)*time.Second, purger.Run); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// provideApp 配置路由并提供清理函数
+func provideApp(
 	cfg *config.Config,
 	demoCtrl *controller.DemoController,
+	demoSvc *service.DemoService,
 	mw *middleware.Middleware,
+	checks []web.HealthCheck,
+	lc *lifecycle.Manager,
+	sched *scheduler.Scheduler,
+	streams *web.StreamTracker,
 	_ *zap.Logger, // 确保 logger 被初始化
-) (*gin.Engine, func()) {
-	router := provideRouter(cfg, demoCtrl, mw)
+) (*App, func(), error) {
+	// 此时数据库、缓存等资源已打开并注册了关闭钩子，但 cleanup 尚未返回给调用方，
+	// 失败时在这里执行关闭钩子，避免连接泄漏（日志由调用方记录错误后关闭）
+	router, err := provideRouter(cfg, demoCtrl, mw, checks)
+	if err != nil {
+		lc.Shutdown()
+		return nil, nil, err
+	}
+
+	// 启动前依赖检查：关键依赖不可用时直接失败，不开始接收请求
+	if cfg.Server.StartupCheck.Enabled {
+		if err := checkStartup(cfg, checks); err != nil {
+			lc.Shutdown()
+			return nil, nil, err
+		}
+	}
+
+	// 缓存预热：在服务开始接收请求前执行
+	if cfg.Cache.WarmUp {
+		cache.WarmUp(context.Background(), time.Duration(cfg.Cache.WarmUpTimeout)*time.Second, demoSvc)
+	}
+
+	// 启动后台任务
+	sched.Start()
+
 	cleanup := func() {
+		// 先按逆序关闭各组件，最后刷新日志
+		lc.Shutdown()
 		logger.Close()
 	}
-	return router, cleanup
+	return &App{
+		Router:   router,
+		Handler:  mw.TrailingSlash.Wrap(router),
+		InFlight: mw.InFlight,
+		Streams:  streams,
+	}, cleanup, nil
 }
 
 // provideRouter 配置路由
@@ -67,40 +429,126 @@ func provideRouter(
 	cfg *config.Config,
 	demoCtrl *controller.DemoController,
 	mw *middleware.Middleware,
-) *gin.Engine {
+	checks []web.HealthCheck,
+) (*gin.Engine, error) {
 	// 设置 Gin 模式
 	gin.SetMode(cfg.Server.Mode)
 
+	// 响应 JSON 编码选项
+	web.SetJSONOptions(web.JSONOptions{
+		Int64AsString: cfg.Response.Int64AsString,
+		TimeFormat:    cfg.Response.TimeFormat,
+		FieldNaming:   cfg.Response.FieldNaming,
+	})
+	// 请求体 JSON 限制
+	web.SetJSONLimits(web.JSONLimits{
+		MaxBytes:            cfg.Request.JSONMaxSize << 10,
+		MaxDepth:            cfg.Request.JSONMaxDepth,
+		RejectDuplicateKeys: cfg.Request.JSONRejectDuplicateKeys,
+	})
+
+	// 时间策略：开启后模型时间统一以 UTC 输出
+	model.SetUTC(cfg.Database.UTC)
+
 	r := gin.New()
+	// 末尾斜杠由 TrailingSlash 统一处理（见 App.Handler），关闭 Gin 的反向重定向，避免 /x 与 /x/ 来回跳转
+	r.RedirectTrailingSlash = cfg.Server.TrailingSlash == middleware.TrailingSlashOff
+
+	// 受信任代理：只有来自这些地址的请求才会解析 X-Forwarded-For 获取 ClientIP
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("设置受信任代理失败: %w", err)
+	}
 
 	// 全局中间件
-	r.Use(gin.Logger())
-	r.Use(gin.Recovery())
-	r.Use(web.ToGinHandler(mw.CORS.Handle()))      // CORS 中间件
-	r.Use(web.ToGinHandler(mw.RequestID.Handle())) // RequestID 中间件
+	r.Use(web.ToGinHandler(mw.AccessLog.Handle()))   // 请求日志（JSON，可按状态码和路由采样）
+	r.Use(web.ToGinHandler(mw.Recovery.Handle()))    // Panic 恢复（JSON 日志）
+	r.Use(web.ToGinHandler(mw.InFlight.Handle()))    // 在途请求计数
+	r.Use(web.ToGinHandler(mw.Timing.Handle()))      // 响应耗时
+	r.Use(web.ToGinHandler(mw.CORS.Handle()))        // CORS 中间件
+	r.Use(web.ToGinHandler(mw.RequestID.Handle()))   // RequestID 中间件
+	r.Use(web.ToGinHandler(mw.GeoIP.Handle()))       // IP 归属信息（国家、ASN），未启用时跳过
+	r.Use(web.ToGinHandler(mw.Loader.Handle()))      // 请求级 Loader
+	r.Use(web.ToGinHandler(mw.Audit.Handle()))       // 审计上下文（操作人、RequestID）
+	r.Use(web.ToGinHandler(mw.FeatureFlag.Handle())) // 请求级功能开关
+	if cfg.RateLimit.Enabled {
+		r.Use(web.ToGinHandler(mw.RateLimit.Handle())) // 限流中间件
+	}
+	if cfg.ContentType.Enabled {
+		r.Use(web.ToGinHandler(mw.ContentType.Handle())) // Content-Type 校验
+	}
 
 	// 处理 404 错误
 	r.NoRoute(web.ToGinHandler(web.NotFoundHandler()))
 
-	// 处理 405 错误
+	// 处理 405 错误（路径存在但方法不匹配时，Gin 默认按 404 处理）
+	r.HandleMethodNotAllowed = true
 	r.NoMethod(web.ToGinHandler(web.MethodNotAllowedHandler()))
 
-	// 健康检查（无需鉴权）
-	r.GET("/health", web.ToGinHandler(web.HealthHandler()))
+	// 健康检查（无需鉴权，GET 路由同时支持 HEAD）
+	root := web.Group(r, "")
+	root.GET("/health", web.HealthHandler())
+
+	// 就绪检查：并行检查各依赖，单个依赖卡住时报告 timeout 而不阻塞响应
+	readyTimeout := time.Duration(cfg.Server.ReadyTimeout) * time.Second
+	root.GET("/ready", web.ReadinessHandler(readyTimeout, checks...))
+
+	// Prometheus 指标
+	if cfg.Metrics.Enabled {
+		r.GET(cfg.Metrics.Path, gin.WrapH(metrics.Handler()))
+	}
+
+	// 写接口认证：启用时只挂在写接口路由组上，读接口保持开放
+	var writeAuth []web.HandlerFunc
+	if cfg.Auth.Enabled {
+		if cfg.Auth.JWTSecret == "" {
+			return nil, fmt.Errorf("启用认证时 auth.jwt_secret 不能为空")
+		}
+		writeAuth = append(writeAuth, mw.Auth.Handle())
+	}
+	if cfg.CheckSum.Enabled && cfg.CheckSum.Provider == "config" && len(cfg.CheckSum.Apps) == 0 {
+		return nil, fmt.Errorf("启用签名校验时 checksum.apps 不能为空")
+	}
+	if cfg.APIKey.Enabled && cfg.APIKey.Provider == "config" && len(cfg.APIKey.Apps) == 0 {
+		return nil, fmt.Errorf("启用 API Key 认证时 api_key.apps 不能为空")
+	}
+	switch {
+	case cfg.CheckSum.Enabled && cfg.APIKey.Enabled:
+		writeAuth = append(writeAuth, mw.APIKey.Or(mw.CheckSum)) // 二选一：有效的 API Key 直接通过，否则校验签名
+	case cfg.CheckSum.Enabled:
+		writeAuth = append(writeAuth, mw.CheckSum.Handle())
+	case cfg.APIKey.Enabled:
+		writeAuth = append(writeAuth, mw.APIKey.Handle())
+	}
+	if cfg.RateLimit.Enabled && (cfg.CheckSum.Enabled || cfg.APIKey.Enabled) {
+		writeAuth = append(writeAuth, mw.RateLimit.HandleApp()) // 应用认证通过后按应用配额限流（与按 IP 的计数相互独立）
+	}
 
 	// API v1 路由组
 	api := r.Group("/api/v1")
 	{
+		// 列表接口允许客户端/CDN 缓存 30 秒
+		listCache := web.CacheControl(30*time.Second, true)
+
 		// Demo CRUD 示例接口
-		demos := api.Group("/demos")
+		demos := web.Group(api, "/demos")
 		{
-			demos.GET("", web.ToGinHandler(demoCtrl.GetAll))        // 获取所有 Demo
-			demos.GET("/:id", web.ToGinHandler(demoCtrl.GetByID))   // 获取单个 Demo
-			demos.POST("", web.ToGinHandler(demoCtrl.Create))       // 创建 Demo
-			demos.PUT("/:id", web.ToGinHandler(demoCtrl.Update))    // 更新 Demo
-			demos.DELETE("/:id", web.ToGinHandler(demoCtrl.Delete)) // 删除 Demo
+			demos.GET("", listCache, demoCtrl.GetAll)                 // 获取所有 Demo
+			demos.GET("/export.ndjson", demoCtrl.Export)              // 导出所有 Demo（NDJSON）
+			demos.GET("/:id", mw.Coalesce.Handle(), demoCtrl.GetByID) // 获取单个 Demo（合并同时到达的相同请求）
+
+			// 批量接口受功能开关控制，关闭时返回 404
+			importFlag := mw.FeatureFlag.Require(constants.FlagDemoImport)
+
+			// 写接口（启用认证时需要 Bearer token，启用签名校验时需要签名请求头，启用 API Key 时需要 X-API-Key）
+			writes := demos.Group("", writeAuth...)
+			writes.POST("", demoCtrl.Create)                    // 创建 Demo
+			writes.POST("/upload", demoCtrl.Upload)             // 上传附件
+			writes.POST("/import", importFlag, demoCtrl.Import) // 批量导入（JSON 数组）
+			writes.PUT("/:id", demoCtrl.Update)                 // 更新 Demo
+			writes.PATCH("/:id", demoCtrl.Patch)                // 部分更新 Demo
+			writes.DELETE("/:id", demoCtrl.Delete)              // 删除 Demo
 		}
 	}
 
-	return r
+	return r, nil
 }
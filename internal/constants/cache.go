@@ -0,0 +1,10 @@
+package constants
+
+// 缓存 Key 常量
+const (
+	// Demo 相关
+	CacheKeyDemo = "demo:%d" // Demo 详情（参数：ID）
+
+	// 预热相关
+	WarmUpDemoPageSize = 20 // 预热的 Demo 数量（第一页）
+)
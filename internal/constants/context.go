@@ -5,6 +5,9 @@ const (
 	// RequestID 相关
 	CtxKeyRequestID = "request_id"
 
+	// 用户信息
+	CtxKeyUserID = "user_id"
+
 	// OAuth 应用信息
 	CtxKeyAppID       = "app_id"
 	CtxKeyAppKey      = "app_key"
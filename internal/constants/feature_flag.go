@@ -0,0 +1,6 @@
+package constants
+
+// 功能开关名称常量（开关值见配置 feature_flags.flags）
+const (
+	FlagDemoImport = "demo_import" // Demo 批量导入接口
+)
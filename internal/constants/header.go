@@ -3,11 +3,31 @@ package constants
 // HTTP Header 常量
 const (
 	// 认证相关 Header
-	HeaderRequestID = "X-Request-ID" // 请求 ID
+	HeaderRequestID     = "X-Request-ID"  // 请求 ID
+	HeaderAuthorization = "Authorization" // 认证信息（Bearer token）
+
+	// 耗时相关 Header
+	HeaderResponseTime = "X-Response-Time" // 响应耗时（毫秒）
+	HeaderServerTiming = "Server-Timing"   // 各阶段耗时（db、cache）
+
+	// 限流相关 Header
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"     // 窗口内允许的请求数
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining" // 窗口内剩余请求数
+	HeaderRateLimitReset     = "X-RateLimit-Reset"     // 窗口重置时间（Unix 秒）
+
+	// 功能开关 Header（feature_flags.header_override 开启时生效）
+	HeaderFeatureFlags = "X-Feature-Flags" // 覆盖功能开关，如 demo_import=off,new_search=on
 
 	// CheckSum 鉴权 Header
 	HeaderAppKey    = "app_key"   // 应用 KEY
 	HeaderTimestamp = "timestamp" // 时间戳
 	HeaderNonce     = "nonce"     // 随机字符串
 	HeaderCheckSum  = "checksum"  // 签名
+	HeaderSequence  = "sequence"  // 请求序号（开启请求序号的应用必填，严格递增）
+
+	// API Key 鉴权 Header（api_key.header 未配置时使用）
+	HeaderAPIKey = "X-API-Key"
+
+	// 幂等重放 Header：响应为同一 nonce 首次请求的响应（checksum.nonce_replay 为 replay 时）
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
 )
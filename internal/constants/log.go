@@ -16,4 +16,9 @@ const (
 	LogFieldTimestamp = "timestamp"
 	LogFieldNonce     = "nonce"
 	LogFieldCheckSum  = "checksum"
+	LogFieldSequence  = "sequence"
+
+	// GeoIP 相关字段
+	LogFieldCountry = "country"
+	LogFieldASN     = "asn"
 )
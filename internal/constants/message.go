@@ -7,12 +7,15 @@ const (
 	MsgFailed  = "failed"
 
 	// 错误消息
-	MsgInterfaceNotFound  = "接口不存在"
-	MsgMethodNotAllowed   = "请求方法不允许"
-	MsgBadRequest         = "请求参数错误"
-	MsgUnauthorized       = "未授权"
-	MsgForbidden          = "禁止访问"
-	MsgNotFound           = "资源不存在"
-	MsgInternalError      = "服务器内部错误"
-	MsgServiceUnavailable = "服务暂时不可用"
+	MsgInterfaceNotFound     = "接口不存在"
+	MsgMethodNotAllowed      = "请求方法不允许"
+	MsgBadRequest            = "请求参数错误"
+	MsgUnauthorized          = "未授权"
+	MsgForbidden             = "禁止访问"
+	MsgNotFound              = "资源不存在"
+	MsgInternalError         = "服务器内部错误"
+	MsgServiceUnavailable    = "服务暂时不可用"
+	MsgTooManyRequests       = "请求过于频繁，请稍后再试"
+	MsgUnsupportedMediaType  = "不支持的请求内容类型"
+	MsgRequestEntityTooLarge = "请求体过大"
 )
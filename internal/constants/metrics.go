@@ -0,0 +1,7 @@
+package constants
+
+// 业务指标名称常量
+const (
+	// Demo 相关
+	MetricDemoCreated = "demo_created_total" // 创建 Demo 次数
+)
@@ -1,31 +1,67 @@
 package controller
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
+	"go-api-template/internal/constants"
 	"go-api-template/internal/model"
 	"go-api-template/internal/service"
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/database"
 	"go-api-template/pkg/errors"
 	"go-api-template/pkg/web"
 )
 
+// demoSortFields 列表接口允许排序的字段（sort 参数白名单，与数据库列名一致）
+var demoSortFields = []string{"id", "title", "status", "created_at", "updated_at"}
+
+// demoFilterFields 列表接口允许筛选的字段和操作符（filter 参数白名单）
+var demoFilterFields = map[string]database.FilterField{
+	"id":         {Column: "id", Ops: []database.FilterOp{database.FilterEq, database.FilterIn}, Parse: database.ParseFilterInt},
+	"title":      {Column: "title", Ops: []database.FilterOp{database.FilterEq, database.FilterLike, database.FilterIn}},
+	"status":     {Column: "status", Ops: []database.FilterOp{database.FilterEq, database.FilterIn}, Parse: database.ParseFilterInt},
+	"created_at": {Column: "created_at", Ops: []database.FilterOp{database.FilterGte, database.FilterLte}, Parse: database.ParseFilterTime},
+	"updated_at": {Column: "updated_at", Ops: []database.FilterOp{database.FilterGte, database.FilterLte}, Parse: database.ParseFilterTime},
+}
+
 // DemoController Demo 控制器
 type DemoController struct {
-	demoService *service.DemoService
+	demoService     *service.DemoService
+	uploadDir       string
+	uploadOpts      web.UploadOptions
+	importMaxSize   int64 // 导入请求体大小上限（字节）
+	importBatchSize int   // 导入每批插入条数
 }
 
 // NewDemoController 创建 Demo Controller
-func NewDemoController(demoService *service.DemoService) *DemoController {
+func NewDemoController(demoService *service.DemoService, cfg *config.Config) *DemoController {
 	return &DemoController{
 		demoService: demoService,
+		uploadDir:   cfg.Upload.Dir,
+		uploadOpts: web.UploadOptions{
+			MaxSize:      cfg.Upload.MaxSize << 20,
+			AllowedExts:  cfg.Upload.AllowedExts,
+			AllowedTypes: cfg.Upload.AllowedTypes,
+		},
+		importMaxSize:   cfg.Import.MaxSize << 20,
+		importBatchSize: cfg.Import.BatchSize,
 	}
 }
 
 // GetByID 根据 ID 获取
+// 响应带 Last-Modified（UpdatedAt），If-Modified-Since 之后未修改时返回 304
 // @Summary 获取单个 Demo
 // @Tags Demo
 // @Param id path int true "Demo ID"
+// @Param If-Modified-Since header string false "上次获取时的 Last-Modified"
 // @Success 200 {object} model.Demo
+// @Success 304 "未修改"
 // @Router /api/v1/demos/{id} [get]
 func (c *DemoController) GetByID(ctx *web.Context) {
 	idStr := ctx.Param("id")
@@ -45,74 +81,257 @@ func (c *DemoController) GetByID(ctx *web.Context) {
 		return
 	}
 
+	if web.LastModified(ctx, demo.UpdatedAt.Time()) {
+		return
+	}
 	web.Success(ctx, demo)
 }
 
 // GetAll 获取所有
+// 支持 filter 参数筛选（如 ?filter[status]=1&filter[created_at][gte]=2026-01-01），
+// sort 参数排序（如 ?sort=-created_at,title），fields 参数只返回指定字段（如 ?fields=id,title），
+// meta=true 时在 meta 中回显实际应用的筛选、排序和返回字段
 // @Summary 获取所有 Demo
 // @Tags Demo
+// @Param filter[status] query string false "筛选：id、status 支持 eq/in，title 支持 eq/like/in，created_at、updated_at 支持 gte/lte，如 filter[title][like]=go"
+// @Param sort query string false "排序（逗号分隔，- 前缀为降序），可选 id、title、status、created_at、updated_at"
+// @Param fields query string false "只返回指定字段（逗号分隔），如 id,title"
+// @Param meta query bool false "是否在 meta 中回显实际应用的查询条件"
 // @Success 200 {array} model.Demo
+// @Failure 400 {object} web.Response "不支持的筛选、排序或返回字段"
 // @Router /api/v1/demos [get]
 func (c *DemoController) GetAll(ctx *web.Context) {
-	demos, err := c.demoService.GetAll(ctx.Request.Context())
+	filters, err := web.ParseFilter(ctx, demoFilterFields)
+	if err != nil {
+		web.BadRequest(ctx, err.Error())
+		return
+	}
+	sort, err := web.ParseSort(ctx, demoSortFields)
+	if err != nil {
+		web.BadRequest(ctx, err.Error())
+		return
+	}
+
+	demos, err := c.demoService.GetAll(ctx.Request.Context(), filters, sort)
 	if err != nil {
 		web.InternalError(ctx, "get demos failed")
 		return
 	}
 
-	web.Success(ctx, demos)
+	data, err := web.SelectFields(ctx, demos)
+	if err != nil {
+		web.BadRequest(ctx, err.Error())
+		return
+	}
+	web.SuccessList(ctx, data, web.NewListMeta(ctx, filters, sort))
+}
+
+// Export 导出所有（NDJSON 流式输出）
+// @Summary 导出所有 Demo
+// @Tags Demo
+// @Produce application/x-ndjson
+// @Success 200 {object} model.Demo "每行一个 Demo"
+// @Router /api/v1/demos/export.ndjson [get]
+func (c *DemoController) Export(ctx *web.Context) {
+	// 输出结束（或客户端断开）后取消查询，避免生产者阻塞
+	streamCtx, cancel := context.WithCancel(ctx.Request.Context())
+	defer cancel()
+
+	rows := make(chan interface{})
+	go func() {
+		defer close(rows)
+		_ = c.demoService.Export(streamCtx, func(demo *model.Demo) error {
+			select {
+			case rows <- demo:
+				return nil
+			case <-streamCtx.Done():
+				return streamCtx.Err()
+			}
+		})
+	}()
+
+	web.StreamNDJSON(ctx, rows)
 }
 
 // CreateRequest 创建请求
 type CreateRequest struct {
-	Title   string `json:"title" binding:"required"`
-	Content string `json:"content"`
-	Status  int    `json:"status"`
+	Title   string `json:"title" form:"title" binding:"required,min=1,max=200"` // 与 varchar(200) 保持一致
+	Content string `json:"content" form:"content"`
+	Status  *int   `json:"status" form:"status" binding:"omitempty,oneof=0 1"` // 1-启用 0-禁用，未传时为启用
+}
+
+// demo 转换为模型，未传 status 时为启用
+func (r *CreateRequest) demo() *model.Demo {
+	status := model.DemoStatusEnabled
+	if r.Status != nil {
+		status = *r.Status
+	}
+	return &model.Demo{
+		Title:   r.Title,
+		Content: r.Content,
+		Status:  status,
+	}
 }
 
 // Create 创建
 // @Summary 创建 Demo
 // @Tags Demo
+// @Accept json,x-www-form-urlencoded,mpfd
 // @Param request body CreateRequest true "创建参数"
-// @Success 200 {object} model.Demo
+// @Success 201 {object} model.Demo
+// @Header 201 {string} Location "新 Demo 的地址，如 /api/v1/demos/1"
+// @Failure 409 "标题已存在"
 // @Router /api/v1/demos [post]
 func (c *DemoController) Create(ctx *web.Context) {
 	var req CreateRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		web.BadRequest(ctx, "invalid request: "+err.Error())
+	if !ctx.MustBind(&req) {
 		return
 	}
 
-	demo := &model.Demo{
-		Title:   req.Title,
-		Content: req.Content,
-		Status:  req.Status,
+	demo := req.demo()
+	err := c.demoService.Create(ctx.Request.Context(), demo)
+	if err != nil {
+		if errors.Is(err, errors.ErrDuplicate) {
+			web.Conflict(ctx, "demo title already exists")
+			return
+		}
+		web.RespondError(ctx, err, "create demo failed")
+		return
 	}
 
-	err := c.demoService.Create(ctx.Request.Context(), demo)
+	// Location 指向新资源（GET /api/v1/demos/:id）
+	web.CreatedAt(ctx, strings.TrimSuffix(ctx.FullPath(), "/")+"/"+strconv.FormatUint(uint64(demo.ID), 10), demo)
+}
+
+// importMaxErrors 导入结果中最多返回的失败明细条数
+const importMaxErrors = 100
+
+// ImportError 导入失败的元素
+type ImportError struct {
+	Index   int              `json:"index"`            // 元素在数组中的下标（从 0 开始）
+	Message string           `json:"message"`          // 失败原因
+	Errors  []web.FieldError `json:"errors,omitempty"` // 字段校验错误
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Inserted int           `json:"inserted"` // 插入条数
+	Failed   int           `json:"failed"`   // 校验失败（跳过）条数
+	Errors   []ImportError `json:"errors"`   // 失败明细（最多 100 条）
+}
+
+// Import 批量导入（请求体为 CreateRequest 数组，流式解析）
+// 校验失败的元素跳过并计入 failed，其余元素在同一事务中分批插入；
+// 请求体格式错误、超限或插入失败时整体回滚
+// @Summary 批量导入 Demo
+// @Tags Demo
+// @Accept json
+// @Param request body []CreateRequest true "导入数据"
+// @Success 200 {object} ImportResult
+// @Failure 400 "JSON 格式错误"
+// @Failure 409 "标题已存在（整体回滚）"
+// @Failure 413 "请求体过大"
+// @Router /api/v1/demos/import [post]
+func (c *DemoController) Import(ctx *web.Context) {
+	result := ImportResult{Errors: []ImportError{}}
+	fail := func(importErr ImportError) {
+		result.Failed++
+		if len(result.Errors) < importMaxErrors {
+			result.Errors = append(result.Errors, importErr)
+		}
+	}
+
+	inserted, err := c.demoService.Import(ctx.Request.Context(), c.importBatchSize, func(emit func(*model.Demo) error) error {
+		return web.DecodeJSONArray(ctx, c.importMaxSize, func(index int, item json.RawMessage) error {
+			var req CreateRequest
+			if err := json.Unmarshal(item, &req); err != nil {
+				fail(ImportError{Index: index, Message: err.Error()})
+				return nil
+			}
+			if err := web.Validate(&req); err != nil {
+				fields, _ := web.TranslateValidationErrors(err)
+				fail(ImportError{Index: index, Message: constants.MsgBadRequest, Errors: fields})
+				return nil
+			}
+			return emit(req.demo())
+		})
+	})
 	if err != nil {
-		web.InternalError(ctx, "create demo failed")
+		switch {
+		case errors.Is(err, errors.ErrBodyTooLarge):
+			web.Error(ctx, http.StatusRequestEntityTooLarge, http.StatusRequestEntityTooLarge, "request body too large")
+		case ctx.Request.Context().Err() != nil:
+			web.BadRequest(ctx, "request canceled")
+		case errors.Is(err, errors.ErrDuplicate):
+			web.Conflict(ctx, "demo title already exists")
+		default:
+			web.RespondError(ctx, err, "import demos failed")
+		}
 		return
 	}
 
-	web.SuccessWithMessage(ctx, "demo created successfully", demo)
+	result.Inserted = inserted
+	web.Success(ctx, result)
 }
 
 // UpdateRequest 更新请求
 type UpdateRequest struct {
-	Title   string `json:"title" binding:"required"`
-	Content string `json:"content"`
-	Status  int    `json:"status"`
+	ID      uint   `uri:"id" json:"-" form:"-" binding:"required"`
+	Title   string `json:"title" form:"title" binding:"required,min=1,max=200"` // 与 varchar(200) 保持一致
+	Content string `json:"content" form:"content"`
+	Status  int    `json:"status" form:"status" binding:"oneof=0 1"` // 1-启用 0-禁用
 }
 
 // Update 更新
 // @Summary 更新 Demo
 // @Tags Demo
 // @Param id path int true "Demo ID"
+// @Accept json,x-www-form-urlencoded,mpfd
 // @Param request body UpdateRequest true "更新参数"
 // @Success 200
+// @Failure 409 "标题已存在"
 // @Router /api/v1/demos/{id} [put]
 func (c *DemoController) Update(ctx *web.Context) {
+	// 路径参数 :id 与请求体一起绑定和校验
+	var req UpdateRequest
+	if !ctx.MustBindAll(&req) {
+		return
+	}
+
+	demo := &model.Demo{
+		Title:   req.Title,
+		Content: req.Content,
+		Status:  req.Status,
+	}
+
+	err := c.demoService.Update(ctx.Request.Context(), req.ID, demo)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			web.NotFound(ctx, "demo not found")
+			return
+		}
+		if errors.Is(err, errors.ErrDuplicate) {
+			web.Conflict(ctx, "demo title already exists")
+			return
+		}
+		web.RespondError(ctx, err, "update demo failed")
+		return
+	}
+
+	web.SuccessWithMessage(ctx, "demo updated successfully", nil)
+}
+
+// Patch 部分更新
+// 只更新请求体中出现的字段：未传的字段保持不变，"content": "" 会清空内容，
+// 而 null 不是合法值（所有字段均不可为 null）
+// @Summary 部分更新 Demo
+// @Tags Demo
+// @Param id path int true "Demo ID"
+// @Param request body object true "需要更新的字段（title/content/status）"
+// @Success 200
+// @Failure 409 "标题已存在"
+// @Router /api/v1/demos/{id} [patch]
+func (c *DemoController) Patch(ctx *web.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
@@ -120,29 +339,72 @@ func (c *DemoController) Update(ctx *web.Context) {
 		return
 	}
 
-	var req UpdateRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
+	var patch web.Patch
+	if err := web.BindPatch(ctx, &patch); err != nil {
+		web.InvalidRequest(ctx, err) // 请求体过大时为 413
+		return
+	}
+
+	for _, key := range []string{"title", "content", "status"} {
+		if patch.IsNull(key) {
+			web.BadRequest(ctx, "invalid request: "+key+" cannot be null")
+			return
+		}
+	}
+
+	updates := make(map[string]interface{})
+
+	var title string
+	if ok, err := patch.Decode("title", &title); err != nil {
 		web.BadRequest(ctx, "invalid request: "+err.Error())
 		return
+	} else if ok {
+		if title == "" {
+			web.BadRequest(ctx, "invalid request: title cannot be empty")
+			return
+		}
+		if utf8.RuneCountInString(title) > 200 {
+			web.BadRequest(ctx, "invalid request: title cannot exceed 200 characters")
+			return
+		}
+		updates["title"] = title
 	}
 
-	demo := &model.Demo{
-		Title:   req.Title,
-		Content: req.Content,
-		Status:  req.Status,
+	var content string
+	if ok, err := patch.Decode("content", &content); err != nil {
+		web.BadRequest(ctx, "invalid request: "+err.Error())
+		return
+	} else if ok {
+		updates["content"] = content // 允许设置为空字符串
 	}
 
-	err = c.demoService.Update(ctx.Request.Context(), uint(id), demo)
+	var status int
+	if ok, err := patch.Decode("status", &status); err != nil {
+		web.BadRequest(ctx, "invalid request: "+err.Error())
+		return
+	} else if ok {
+		if model.CheckDemoStatus(status) != nil {
+			web.BadRequest(ctx, "invalid request: status must be 0 or 1")
+			return
+		}
+		updates["status"] = status
+	}
+
+	err = c.demoService.Patch(ctx.Request.Context(), uint(id), updates)
 	if err != nil {
 		if errors.Is(err, errors.ErrNotFound) {
 			web.NotFound(ctx, "demo not found")
 			return
 		}
-		web.InternalError(ctx, "update demo failed")
+		if errors.Is(err, errors.ErrDuplicate) {
+			web.Conflict(ctx, "demo title already exists")
+			return
+		}
+		web.RespondError(ctx, err, "patch demo failed")
 		return
 	}
 
-	web.SuccessWithMessage(ctx, "demo updated successfully", nil)
+	web.SuccessWithMessage(ctx, "demo patched successfully", nil)
 }
 
 // Delete 删除
@@ -171,3 +433,31 @@ func (c *DemoController) Delete(ctx *web.Context) {
 
 	web.SuccessWithMessage(ctx, "demo deleted successfully", nil)
 }
+
+// Upload 上传附件
+// @Summary 上传 Demo 附件
+// @Tags Demo
+// @Accept mpfd
+// @Param file formData file true "附件（大小与类型受 upload 配置限制）"
+// @Success 200
+// @Failure 400 "不允许的文件类型"
+// @Failure 413 "文件过大"
+// @Router /api/v1/demos/upload [post]
+func (c *DemoController) Upload(ctx *web.Context) {
+	savedPath, err := web.SaveUploadedFile(ctx, "file", c.uploadDir, c.uploadOpts)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrFileTooLarge):
+			web.Error(ctx, http.StatusRequestEntityTooLarge, http.StatusRequestEntityTooLarge, "file too large")
+		case errors.Is(err, errors.ErrFileTypeNotAllowed):
+			web.BadRequest(ctx, "file type not allowed")
+		default:
+			web.BadRequest(ctx, "invalid upload: "+err.Error())
+		}
+		return
+	}
+
+	web.SuccessWithMessage(ctx, "file uploaded successfully", web.Map{
+		"filename": filepath.Base(savedPath),
+	})
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/web"
+)
+
+// AccessLogConfig 请求日志配置
+type AccessLogConfig struct {
+	SampleFirst      int // 每秒每个（状态码, 路由）完整记录的前 N 条，0 表示不采样（全部记录）
+	SampleThereafter int // 超出 SampleFirst 后每 M 条记录 1 条，0 表示超出部分全部丢弃
+
+	SlowThreshold time.Duration // 请求总耗时超过该值时额外记录一条 warn 级别的 "slow request" 日志，0 表示不启用
+}
+
+// AccessLogMiddleware 请求日志中间件
+// 以 JSON 日志记录每个请求（带 RequestID），高 QPS 时按（状态码, 路由）采样；
+// 5xx 以及 Handler 记录了错误的请求始终完整记录，不参与采样；慢请求额外记录一条 warn 日志，同样不参与采样
+type AccessLogMiddleware struct {
+	sampler       *requestSampler
+	slowThreshold time.Duration
+}
+
+// NewAccessLogMiddleware 创建请求日志中间件
+func NewAccessLogMiddleware(cfg *AccessLogConfig) *AccessLogMiddleware {
+	m := &AccessLogMiddleware{}
+	if cfg == nil {
+		return m
+	}
+	if cfg.SampleFirst > 0 {
+		m.sampler = newRequestSampler(cfg.SampleFirst, cfg.SampleThereafter)
+	}
+	m.slowThreshold = cfg.SlowThreshold
+	return m
+}
+
+// Handle 记录请求日志
+func (m *AccessLogMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		latency := time.Since(start)
+		status := ctx.Writer.Status()
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched" // 未匹配路由统一归为一类，避免采样 key 无限增长
+		}
+
+		isError := status >= http.StatusInternalServerError || len(ctx.Errors) > 0
+		isSlow := m.slowThreshold > 0 && latency > m.slowThreshold
+		sampled := isError || m.sampler == nil || m.sampler.allow(strconv.Itoa(status)+" "+route, start)
+		if !sampled && !isSlow {
+			return
+		}
+
+		fields := []logger.Field{
+			logger.String("method", ctx.Request.Method),
+			logger.String("path", ctx.Request.URL.Path),
+			logger.String("route", route),
+			logger.Int("status", status),
+			logger.Duration("latency", latency),
+			logger.String("client_ip", ctx.ClientIP()),
+			logger.Int("size", ctx.Writer.Size()),
+		}
+		if len(ctx.Errors) > 0 {
+			fields = append(fields, logger.String("errors", ctx.Errors.String()))
+		}
+
+		// ctx.Request 在后续中间件中可能被替换（带上 RequestID 等日志字段），这里取最新的
+		log := logger.Ctx(ctx.Request.Context())
+		if isSlow {
+			log.Warn("slow request", append(fields, logger.Duration("threshold", m.slowThreshold))...)
+		}
+		if !sampled {
+			return
+		}
+		switch {
+		case isError:
+			log.Error("request", fields...)
+		case status >= http.StatusBadRequest:
+			log.Warn("request", fields...)
+		default:
+			log.Info("request", fields...)
+		}
+	}
+}
+
+// requestSampler 按 key 每秒计数的采样器（与 zap 的 sampler 语义一致）
+// 每秒内前 first 条全部放行，之后每 thereafter 条放行 1 条
+type requestSampler struct {
+	first      uint64
+	thereafter uint64
+
+	mu       sync.Mutex
+	counters map[string]*sampleCounter
+}
+
+// sampleCounter 单个 key 在当前秒内的计数
+type sampleCounter struct {
+	second int64
+	n      uint64
+}
+
+// newRequestSampler 创建采样器
+func newRequestSampler(first, thereafter int) *requestSampler {
+	return &requestSampler{
+		first:      uint64(first),
+		thereafter: uint64(max(thereafter, 0)),
+		counters:   make(map[string]*sampleCounter),
+	}
+}
+
+// allow 判断该 key 在 now 时刻的这条记录是否放行
+func (s *requestSampler) allow(key string, now time.Time) bool {
+	second := now.Unix()
+
+	s.mu.Lock()
+	c, ok := s.counters[key]
+	if !ok {
+		c = &sampleCounter{}
+		s.counters[key] = c
+	}
+	if c.second != second {
+		c.second = second
+		c.n = 0
+	}
+	c.n++
+	n := c.n
+	s.mu.Unlock()
+
+	if n <= s.first {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
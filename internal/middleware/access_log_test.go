@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/logger/logtest"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAccessLogNeverSamplesErrors(t *testing.T) {
+	logs := logtest.New(t)
+	m := NewAccessLogMiddleware(&AccessLogConfig{SampleFirst: 1, SampleThereafter: 0})
+	s := webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "")
+		g.GET("/ok", func(ctx *web.Context) { ctx.Status(http.StatusOK) })
+		g.GET("/fail", func(ctx *web.Context) { ctx.Status(http.StatusInternalServerError) })
+		g.GET("/handled", func(ctx *web.Context) {
+			_ = ctx.Error(errors.New("handled failure"))
+			ctx.Status(http.StatusOK)
+		})
+	}, m.Handle())
+
+	const n = 10
+	for i := 0; i < n; i++ {
+		s.Do(http.MethodGet, "/ok", nil)
+		s.Do(http.MethodGet, "/fail", nil)
+		s.Do(http.MethodGet, "/handled", nil)
+	}
+
+	count := func(route string) int {
+		c := 0
+		for _, e := range logs.FilterMessage("request").All() {
+			if e.ContextMap()["route"] == route {
+				c++
+			}
+		}
+		return c
+	}
+	// 5xx 和 Handler 记录了错误的请求全部记录
+	if got := count("/fail"); got != n {
+		t.Fatalf("5xx logged %d times, want %d", got, n)
+	}
+	if got := count("/handled"); got != n {
+		t.Fatalf("handler errors logged %d times, want %d", got, n)
+	}
+	// 正常请求每秒只记录第 1 条（跨秒时可能多 1 条）
+	if got := count("/ok"); got < 1 || got > 2 {
+		t.Fatalf("2xx logged %d times, want sampled to 1", got)
+	}
+}
+
+func TestRequestSampler(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := newRequestSampler(2, 3)
+
+	var allowed []int
+	for i := 1; i <= 10; i++ {
+		if s.allow("200 /ok", now) {
+			allowed = append(allowed, i)
+		}
+	}
+	// 前 2 条放行，之后每 3 条放行 1 条
+	want := []int{1, 2, 5, 8}
+	if len(allowed) != len(want) {
+		t.Fatalf("allowed = %v, want %v", allowed, want)
+	}
+	for i := range want {
+		if allowed[i] != want[i] {
+			t.Fatalf("allowed = %v, want %v", allowed, want)
+		}
+	}
+
+	// key 独立计数，新的一秒重新计数
+	if !s.allow("404 /ok", now) {
+		t.Fatal("other key sampled out")
+	}
+	if !s.allow("200 /ok", now.Add(time.Second)) {
+		t.Fatal("counter not reset in the next second")
+	}
+}
+
+func TestAccessLogSlowRequestWarning(t *testing.T) {
+	logs := logtest.New(t)
+	// 采样丢弃全部正常请求：慢请求告警不参与采样
+	m := NewAccessLogMiddleware(&AccessLogConfig{SampleFirst: 1, SlowThreshold: 20 * time.Millisecond})
+	s := webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "")
+		g.GET("/fast", func(ctx *web.Context) { ctx.Status(http.StatusOK) })
+		g.GET("/slow", func(ctx *web.Context) {
+			time.Sleep(30 * time.Millisecond)
+			ctx.Status(http.StatusOK)
+		})
+	}, m.Handle())
+
+	s.Do(http.MethodGet, "/fast", nil)
+	s.Do(http.MethodGet, "/slow", nil)
+	s.Do(http.MethodGet, "/slow", nil)
+
+	slow := logs.FilterMessage("slow request").All()
+	if len(slow) != 2 {
+		t.Fatalf("slow request logged %d times, want 2", len(slow))
+	}
+	fields := slow[0].ContextMap()
+	if slow[0].Level.String() != "warn" || fields["route"] != "/slow" || fields["status"] != int64(200) {
+		t.Fatalf("slow entry = %s %v", slow[0].Level, fields)
+	}
+	for _, key := range []string{"method", "path", "latency", "client_ip", "size", "threshold"} {
+		if _, ok := fields[key]; !ok {
+			t.Fatalf("slow entry missing %s: %v", key, fields)
+		}
+	}
+	if latency := fields["latency"].(time.Duration); latency < 20*time.Millisecond {
+		t.Fatalf("latency = %v, want above threshold", latency)
+	}
+}
+
+func TestAccessLogSlowRequestDisabled(t *testing.T) {
+	logs := logtest.New(t)
+	s := webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").GET("/slow", func(ctx *web.Context) {
+			time.Sleep(5 * time.Millisecond)
+			ctx.Status(http.StatusOK)
+		})
+	}, NewAccessLogMiddleware(&AccessLogConfig{}).Handle())
+
+	s.Do(http.MethodGet, "/slow", nil)
+	if n := logs.FilterMessage("slow request").Len(); n != 0 {
+		t.Fatalf("slow request logged %d times with threshold 0", n)
+	}
+	if n := logs.FilterMessage("request").Len(); n != 1 {
+		t.Fatalf("request logged %d times, want 1", n)
+	}
+}
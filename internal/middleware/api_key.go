@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/security"
+	"go-api-template/pkg/web"
+)
+
+// APIKeyConfig API Key 认证配置
+type APIKeyConfig struct {
+	Provider security.APIKeyProvider // API Key 来源（配置文件或数据库，见 security.CachedAPIKeyProvider）
+	Header   string                  // 携带 API Key 的请求头，默认 X-API-Key
+	Query    string                  // 携带 API Key 的查询参数，为空表示只接受请求头
+}
+
+// APIKeyMiddleware API Key 认证中间件
+// 供无法计算 CheckSum 签名的简单客户端使用：从请求头（优先）或查询参数读取 API Key，
+// 查询到的应用信息存入 Context（app_key、app_id、app_name）；缺少或无效时返回 401，应用已注销、已过期时同样返回 401
+type APIKeyMiddleware struct {
+	provider security.APIKeyProvider
+	header   string
+	query    string
+}
+
+// NewAPIKeyMiddleware 创建 API Key 认证中间件
+func NewAPIKeyMiddleware(config *APIKeyConfig) *APIKeyMiddleware {
+	if config == nil {
+		config = &APIKeyConfig{}
+	}
+	if config.Header == "" {
+		config.Header = constants.HeaderAPIKey
+	}
+	if config.Provider == nil {
+		config.Provider = security.NewStaticAPIKeyProvider(nil)
+	}
+
+	return &APIKeyMiddleware{
+		provider: config.Provider,
+		header:   config.Header,
+		query:    config.Query,
+	}
+}
+
+// HasCredentials 请求是否携带 API Key，不校验是否有效
+func (m *APIKeyMiddleware) HasCredentials(ctx *web.Context) bool {
+	return ctx.GetHeader(m.header) != "" || (m.query != "" && ctx.Query(m.query) != "")
+}
+
+// Handle 校验 API Key
+func (m *APIKeyMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		apiKey := m.key(ctx)
+		if apiKey == "" {
+			web.Unauthorized(ctx, "missing api key")
+			ctx.Abort()
+			return
+		}
+
+		identity, err := security.ResolveAPIKey(ctx.Request.Context(), m.provider, apiKey)
+		if err != nil {
+			m.fail(ctx, err)
+			return
+		}
+		m.authenticated(ctx, identity)
+		ctx.Next()
+	}
+}
+
+// Or 与签名校验二选一：携带有效 API Key 的请求直接通过，不再校验签名；
+// 没有 API Key，或 API Key 无效但带有签名凭证时交给 signature 校验；
+// API Key 无效且没有签名凭证时按 API Key 的错误返回 401
+func (m *APIKeyMiddleware) Or(signature *CheckSumMiddleware) web.HandlerFunc {
+	checkSum := signature.Handle()
+	return func(ctx *web.Context) {
+		apiKey := m.key(ctx)
+		if apiKey == "" {
+			checkSum(ctx)
+			return
+		}
+
+		identity, err := security.ResolveAPIKey(ctx.Request.Context(), m.provider, apiKey)
+		switch {
+		case err == nil:
+			m.authenticated(ctx, identity)
+			ctx.Next()
+		case isAppAuthError(err) && signature.HasCredentials(ctx):
+			checkSum(ctx)
+		default:
+			m.fail(ctx, err)
+		}
+	}
+}
+
+// key 读取 API Key，请求头优先
+func (m *APIKeyMiddleware) key(ctx *web.Context) string {
+	apiKey := ctx.GetHeader(m.header)
+	if apiKey == "" && m.query != "" {
+		apiKey = ctx.Query(m.query)
+	}
+	return apiKey
+}
+
+// authenticated 将应用信息存入 Context
+func (m *APIKeyMiddleware) authenticated(ctx *web.Context, identity *security.APIKeyIdentity) {
+	ctx.Set(constants.CtxKeyAppKey, identity.AppKey)
+	if identity.AppID != "" {
+		ctx.Set(constants.CtxKeyAppID, identity.AppID)
+	}
+	if identity.AppName != "" {
+		ctx.Set(constants.CtxKeyAppName, identity.AppName)
+	}
+}
+
+// fail 按 API Key 的查询错误返回 401 或 500 并中止请求
+func (m *APIKeyMiddleware) fail(ctx *web.Context, err error) {
+	switch {
+	case errors.Is(err, errors.ErrAppNotFound):
+		web.Unauthorized(ctx, "invalid api key")
+	case errors.Is(err, errors.ErrAppRevoked):
+		web.Unauthorized(ctx, "app revoked")
+	case errors.Is(err, errors.ErrAppExpired):
+		web.Unauthorized(ctx, "app expired")
+	default:
+		logger.Ctx(ctx.Request.Context()).Error("resolve api key failed", logger.Err(err))
+		web.InternalError(ctx, constants.MsgInternalError)
+	}
+	ctx.Abort()
+}
+
+// isAppAuthError 是否为凭证本身无效（不存在、已注销、已过期），而不是查询出错
+func isAppAuthError(err error) bool {
+	return errors.Is(err, errors.ErrAppNotFound) || errors.Is(err, errors.ErrAppRevoked) || errors.Is(err, errors.ErrAppExpired)
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/logger/logtest"
+	"go-api-template/pkg/security"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testAPIKey = "ak_test_1"
+
+// stubAPIKeys 固定返回的 API Key 查询结果（注销的应用、查询出错）
+type stubAPIKeys struct {
+	identity *security.APIKeyIdentity
+	err      error
+}
+
+func (p stubAPIKeys) APIKeyIdentity(context.Context, string) (*security.APIKeyIdentity, error) {
+	return p.identity, p.err
+}
+
+// newAPIKeyServer 注册 POST /orders，handler 为认证中间件，响应中返回 Context 中的 app_key
+func newAPIKeyServer(t *testing.T, handler web.HandlerFunc) *webtest.Server {
+	logtest.New(t)
+	return webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").POST("/orders", handler, func(ctx *web.Context) {
+			web.Success(ctx, gin.H{"app_key": ctx.AppKey(), "app_name": ctx.AppName()})
+		})
+	})
+}
+
+// decodeAppKey 发送请求并断言通过认证，返回 Context 中的 app_key
+func decodeAppKey(t *testing.T, s *webtest.Server, target string) string {
+	t.Helper()
+
+	w := s.Do(http.MethodPost, target, nil)
+	s.AssertCode(w, http.StatusOK)
+	var data struct {
+		AppKey string `json:"app_key"`
+	}
+	s.DecodeData(w, &data)
+	return data.AppKey
+}
+
+func TestAPIKeyHeader(t *testing.T) {
+	m := NewAPIKeyMiddleware(&APIKeyConfig{Provider: security.NewStaticAPIKeyProvider(map[string]string{testAppKey: testAPIKey})})
+	s := newAPIKeyServer(t, m.Handle())
+
+	s.Header.Set(constants.HeaderAPIKey, testAPIKey)
+	if got := decodeAppKey(t, s, "/orders"); got != testAppKey {
+		t.Fatalf("app_key = %q, want %q", got, testAppKey)
+	}
+
+	s.Header.Set(constants.HeaderAPIKey, "wrong")
+	w := s.Do(http.MethodPost, "/orders", nil)
+	s.AssertCode(w, http.StatusUnauthorized)
+	s.AssertMessage(w, "invalid api key")
+
+	s.Header.Del(constants.HeaderAPIKey)
+	w = s.Do(http.MethodPost, "/orders", nil)
+	s.AssertCode(w, http.StatusUnauthorized)
+	s.AssertMessage(w, "missing api key")
+}
+
+func TestAPIKeyQueryParam(t *testing.T) {
+	provider := security.NewStaticAPIKeyProvider(map[string]string{testAppKey: testAPIKey, "other": "ak_other"})
+
+	// 未配置查询参数时忽略
+	s := newAPIKeyServer(t, NewAPIKeyMiddleware(&APIKeyConfig{Provider: provider}).Handle())
+	s.AssertCode(s.Do(http.MethodPost, "/orders?api_key="+testAPIKey, nil), http.StatusUnauthorized)
+
+	s = newAPIKeyServer(t, NewAPIKeyMiddleware(&APIKeyConfig{Provider: provider, Query: "api_key"}).Handle())
+	if got := decodeAppKey(t, s, "/orders?api_key="+testAPIKey); got != testAppKey {
+		t.Fatalf("app_key = %q, want %q", got, testAppKey)
+	}
+	s.AssertCode(s.Do(http.MethodPost, "/orders?api_key=wrong", nil), http.StatusUnauthorized)
+
+	// 请求头优先于查询参数
+	s.Header.Set(constants.HeaderAPIKey, "ak_other")
+	if got := decodeAppKey(t, s, "/orders?api_key="+testAPIKey); got != "other" {
+		t.Fatalf("app_key = %q, want the header key's app", got)
+	}
+}
+
+func TestAPIKeyRevokedAndLookupError(t *testing.T) {
+	s := newAPIKeyServer(t, NewAPIKeyMiddleware(&APIKeyConfig{
+		Provider: stubAPIKeys{identity: &security.APIKeyIdentity{AppKey: testAppKey, Revoked: true}},
+	}).Handle())
+	s.Header.Set(constants.HeaderAPIKey, testAPIKey)
+	w := s.Do(http.MethodPost, "/orders", nil)
+	s.AssertCode(w, http.StatusUnauthorized)
+	s.AssertMessage(w, "app revoked")
+
+	s = newAPIKeyServer(t, NewAPIKeyMiddleware(&APIKeyConfig{
+		Provider: stubAPIKeys{err: errors.New("connection refused")},
+	}).Handle())
+	s.Header.Set(constants.HeaderAPIKey, testAPIKey)
+	s.AssertStatus(s.Do(http.MethodPost, "/orders", nil), http.StatusInternalServerError)
+}
+
+// newAPIKeyOrCheckSumServer API Key 与签名二选一
+func newAPIKeyOrCheckSumServer(t *testing.T, apiKeys security.APIKeyProvider) *webtest.Server {
+	apiKey := NewAPIKeyMiddleware(&APIKeyConfig{Provider: apiKeys})
+	checkSum := NewCheckSumMiddleware(&CheckSumConfig{
+		Secrets: security.StaticAppSecretProvider{testAppKey: testAppSecret},
+	})
+	return newAPIKeyServer(t, apiKey.Or(checkSum))
+}
+
+func TestAPIKeyOrCheckSum(t *testing.T) {
+	s := newAPIKeyOrCheckSumServer(t, security.NewStaticAPIKeyProvider(map[string]string{"simple": testAPIKey}))
+
+	// 有效的 API Key 直接通过，不需要签名
+	s.Header.Set(constants.HeaderAPIKey, testAPIKey)
+	if got := decodeAppKey(t, s, "/orders"); got != "simple" {
+		t.Fatalf("app_key = %q, want simple", got)
+	}
+
+	// 没有 API Key 时按签名校验
+	s.Header.Del(constants.HeaderAPIKey)
+	signRequest(s, "nonce-1", "")
+	if got := decodeAppKey(t, s, "/orders"); got != testAppKey {
+		t.Fatalf("app_key = %q, want %q", got, testAppKey)
+	}
+
+	// API Key 无效但签名有效：退回签名校验
+	s.Header.Set(constants.HeaderAPIKey, "wrong")
+	signRequest(s, "nonce-2", "")
+	if got := decodeAppKey(t, s, "/orders"); got != testAppKey {
+		t.Fatalf("app_key = %q, want %q", got, testAppKey)
+	}
+
+	// API Key 无效且签名无效
+	signRequest(s, "nonce-3", "")
+	s.Header.Set(constants.HeaderCheckSum, "bad")
+	s.AssertCode(s.Do(http.MethodPost, "/orders", nil), http.StatusUnauthorized)
+}
+
+func TestAPIKeyOrCheckSumRejects(t *testing.T) {
+	s := newAPIKeyOrCheckSumServer(t, security.NewStaticAPIKeyProvider(map[string]string{"simple": testAPIKey}))
+
+	// 两种凭证都没有：签名校验的错误
+	s.AssertCode(s.Do(http.MethodPost, "/orders", nil), http.StatusUnauthorized)
+
+	// API Key 无效且没有签名凭证：API Key 的错误
+	s.Header.Set(constants.HeaderAPIKey, "wrong")
+	w := s.Do(http.MethodPost, "/orders", nil)
+	s.AssertCode(w, http.StatusUnauthorized)
+	s.AssertMessage(w, "invalid api key")
+
+	// 查询 API Key 出错时返回 500，不退回签名校验
+	s = newAPIKeyOrCheckSumServer(t, stubAPIKeys{err: errors.New("connection refused")})
+	s.Header.Set(constants.HeaderAPIKey, testAPIKey)
+	signRequest(s, "nonce-1", "")
+	s.AssertStatus(s.Do(http.MethodPost, "/orders", nil), http.StatusInternalServerError)
+}
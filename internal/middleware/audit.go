@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"go-api-template/pkg/audit"
+	"go-api-template/pkg/web"
+)
+
+// AuditMiddleware 审计上下文中间件
+// 将操作人、RequestID、客户端 IP 放入请求 context，供 Service 写审计记录时使用
+// 需注册在 RequestID 与认证中间件之后
+type AuditMiddleware struct{}
+
+// NewAuditMiddleware 创建审计上下文中间件
+func NewAuditMiddleware() *AuditMiddleware {
+	return &AuditMiddleware{}
+}
+
+// Handle 注入审计上下文
+func (m *AuditMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		actor := ctx.UserID()
+		if actor == "" {
+			actor = "anonymous"
+		}
+
+		ctx.Request = ctx.Request.WithContext(audit.WithMeta(ctx.Request.Context(), audit.Meta{
+			Actor:     actor,
+			RequestID: ctx.GetRequestID(),
+			IP:        ctx.ClientIP(),
+		}))
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"strings"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/audit"
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/security"
+	"go-api-template/pkg/web"
+)
+
+// AuthMiddleware JWT 认证中间件（HS256）
+// 校验 Authorization: Bearer <token>，将 sub 声明作为用户 ID 存入 Context
+// 通过 web.Group 挂在需要登录的路由组上
+type AuthMiddleware struct {
+	secret string
+}
+
+// NewAuthMiddleware 创建认证中间件
+func NewAuthMiddleware(secret string) *AuthMiddleware {
+	return &AuthMiddleware{secret: secret}
+}
+
+// Handle 校验 token
+func (m *AuthMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		token, ok := strings.CutPrefix(ctx.GetHeader(constants.HeaderAuthorization), "Bearer ")
+		if !ok || token == "" {
+			web.Unauthorized(ctx, constants.MsgUnauthorized)
+			ctx.Abort()
+			return
+		}
+
+		claims, err := security.ParseJWT(token, m.secret)
+		if err != nil {
+			message := "invalid token"
+			if errors.Is(err, errors.ErrTokenExpired) {
+				message = "token expired"
+			}
+			web.Unauthorized(ctx, message)
+			ctx.Abort()
+			return
+		}
+		userID, err := claims.GetSubject()
+		if err != nil || userID == "" {
+			web.Unauthorized(ctx, "invalid token")
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set(constants.CtxKeyUserID, userID)
+
+		// 审计记录的操作人改为当前用户
+		meta := audit.MetaFromContext(ctx.Request.Context())
+		meta.Actor = userID
+		ctx.Request = ctx.Request.WithContext(audit.WithMeta(ctx.Request.Context(), meta))
+
+		ctx.Next()
+	}
+}
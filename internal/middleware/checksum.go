@@ -0,0 +1,288 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/security"
+	"go-api-template/pkg/web"
+)
+
+// nonce 重复时的处理方式
+const (
+	NonceReplayReject = "reject" // 拒绝（401），默认
+	NonceReplayReplay = "replay" // 返回首次请求的响应（幂等重试）
+)
+
+// CheckSumConfig 请求签名配置
+type CheckSumConfig struct {
+	Secrets     security.AppSecretProvider // 应用密钥来源（配置文件或数据库，见 security.CachedAppSecretProvider）
+	Sequences   security.SequenceStore     // 请求序号存储（开启请求序号的应用使用），为空时使用进程内存
+	Window      time.Duration              // timestamp 与服务器时间的最大偏差
+	NonceReplay string                     // nonce 重复时的处理：NonceReplayReject / NonceReplayReplay
+}
+
+// CheckSumMiddleware 请求签名中间件
+// 校验 app_key、timestamp、nonce、checksum 请求头，checksum = SHA1(secret + nonce + timestamp)，
+// 应用已注销、已过期时返回 401；
+// 同一 app_key 的 nonce 在 2 倍 Window 内只能使用一次（超出 Window 的请求已因 timestamp 被拒绝），防止请求重放。
+// replay 模式下记录首次请求的响应，客户端用同一 nonce 重试时直接返回该响应（带 Idempotent-Replayed 头），
+// 不会重复执行；首次请求仍在处理中时返回 409，返回 5xx 时不记录，允许重试。
+// 开启请求序号的应用（AppCredential.RequireSequence）还需带 sequence 请求头，
+// checksum = SHA1(secret + nonce + timestamp + sequence)，序号必须大于该应用上一个被接受的序号，否则返回 401
+type CheckSumMiddleware struct {
+	secrets   security.AppSecretProvider
+	sequences security.SequenceStore
+	window    time.Duration
+	replay    bool
+
+	mu        sync.Mutex
+	nonces    map[string]*nonceEntry
+	nextSweep time.Time
+}
+
+// nonceEntry 已使用的 nonce
+type nonceEntry struct {
+	expiresAt time.Time
+	response  *coalescedResponse // replay 模式下首次请求的响应，处理中为 nil
+}
+
+// NewCheckSumMiddleware 创建请求签名中间件
+func NewCheckSumMiddleware(config *CheckSumConfig) *CheckSumMiddleware {
+	if config == nil {
+		config = &CheckSumConfig{}
+	}
+	if config.Window <= 0 {
+		config.Window = 5 * time.Minute
+	}
+
+	if config.Secrets == nil {
+		config.Secrets = security.StaticAppSecretProvider{}
+	}
+	if config.Sequences == nil {
+		config.Sequences = security.NewMemorySequenceStore()
+	}
+
+	return &CheckSumMiddleware{
+		secrets:   config.Secrets,
+		sequences: config.Sequences,
+		window:    config.Window,
+		replay:    config.NonceReplay == NonceReplayReplay,
+		nonces:    make(map[string]*nonceEntry),
+	}
+}
+
+// Handle 校验签名和 nonce
+func (m *CheckSumMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		appKey := ctx.GetHeader(constants.HeaderAppKey)
+		timestamp := ctx.GetHeader(constants.HeaderTimestamp)
+		nonce := ctx.GetHeader(constants.HeaderNonce)
+		checksum := ctx.GetHeader(constants.HeaderCheckSum)
+		if appKey == "" || timestamp == "" || nonce == "" || checksum == "" {
+			web.Unauthorized(ctx, "missing checksum headers")
+			ctx.Abort()
+			return
+		}
+
+		now := time.Now()
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil || now.Sub(time.Unix(ts, 0)).Abs() > m.window {
+			web.Unauthorized(ctx, "timestamp expired")
+			ctx.Abort()
+			return
+		}
+
+		cred, err := security.ResolveAppCredential(ctx.Request.Context(), m.secrets, appKey)
+		switch {
+		case errors.Is(err, errors.ErrAppRevoked):
+			web.Unauthorized(ctx, "app revoked")
+			ctx.Abort()
+			return
+		case errors.Is(err, errors.ErrAppExpired):
+			web.Unauthorized(ctx, "app expired")
+			ctx.Abort()
+			return
+		case err != nil && !errors.Is(err, errors.ErrAppNotFound):
+			logger.Ctx(ctx.Request.Context()).Error("resolve app secret failed",
+				logger.String(constants.LogFieldAppKey, appKey),
+				logger.Err(err),
+			)
+			web.InternalError(ctx, constants.MsgInternalError)
+			ctx.Abort()
+			return
+		}
+		var (
+			sequence    string
+			sequenceNum uint64
+		)
+		if cred != nil && cred.RequireSequence {
+			sequence = ctx.GetHeader(constants.HeaderSequence)
+			sequenceNum, err = strconv.ParseUint(sequence, 10, 64)
+			if err != nil {
+				web.Unauthorized(ctx, "missing or invalid sequence")
+				ctx.Abort()
+				return
+			}
+		}
+		if err != nil || !m.validCheckSum(cred, checksum, timestamp, nonce, sequence) {
+			logger.Ctx(ctx.Request.Context()).Warn("invalid checksum",
+				logger.String(constants.LogFieldAppKey, appKey),
+				logger.String(constants.LogFieldTimestamp, timestamp),
+				logger.String(constants.LogFieldNonce, nonce),
+			)
+			web.Unauthorized(ctx, "invalid checksum")
+			ctx.Abort()
+			return
+		}
+		ctx.Set(constants.CtxKeyAppKey, appKey)
+
+		key := appKey + ":" + nonce
+		entry, used := m.use(key, now)
+		if used {
+			m.handleReused(ctx, entry)
+			return
+		}
+		if cred.RequireSequence && !m.advance(ctx, appKey, sequenceNum) {
+			m.release(key) // 请求未被处理，nonce 可以重新使用
+			return
+		}
+		if !m.replay {
+			ctx.Next()
+			return
+		}
+
+		// Handler panic 时没有可记录的响应，释放 nonce
+		completed := false
+		defer func() {
+			if !completed {
+				m.release(key)
+			}
+		}()
+
+		w := &recordingWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = w
+		ctx.Next()
+		ctx.Writer = w.ResponseWriter
+		completed = true
+
+		m.record(key, &coalescedResponse{
+			status: w.Status(),
+			header: w.Header().Clone(),
+			body:   w.body.Bytes(),
+		})
+	}
+}
+
+// HasCredentials 请求是否携带签名凭证（app_key 请求头），不校验是否有效
+func (m *CheckSumMiddleware) HasCredentials(ctx *web.Context) bool {
+	return ctx.GetHeader(constants.HeaderAppKey) != ""
+}
+
+// validCheckSum 校验签名，开启请求序号的应用签名中包含序号
+func (m *CheckSumMiddleware) validCheckSum(cred *security.AppCredential, checksum, timestamp, nonce, sequence string) bool {
+	if cred.RequireSequence {
+		return security.ValidateSequencedCheckSum(checksum, timestamp, nonce, sequence, cred.Secret)
+	}
+	return security.ValidateCheckSum(checksum, timestamp, nonce, cred.Secret)
+}
+
+// advance 记录请求序号，重复或乱序时返回 401，存储出错时返回 500，均已中止请求
+func (m *CheckSumMiddleware) advance(ctx *web.Context, appKey string, sequence uint64) bool {
+	err := m.sequences.Advance(ctx.Request.Context(), appKey, sequence)
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, errors.ErrSequenceReplayed) {
+		logger.Ctx(ctx.Request.Context()).Warn("sequence replayed",
+			logger.String(constants.LogFieldAppKey, appKey),
+			logger.Uint64(constants.LogFieldSequence, sequence),
+		)
+		web.Unauthorized(ctx, "sequence out of order")
+	} else {
+		logger.Ctx(ctx.Request.Context()).Error("advance sequence failed",
+			logger.String(constants.LogFieldAppKey, appKey),
+			logger.Err(err),
+		)
+		web.InternalError(ctx, constants.MsgInternalError)
+	}
+	ctx.Abort()
+	return false
+}
+
+// handleReused 处理重复的 nonce
+func (m *CheckSumMiddleware) handleReused(ctx *web.Context, entry nonceEntry) {
+	switch {
+	case !m.replay:
+		web.Unauthorized(ctx, "nonce already used")
+	case entry.response == nil:
+		web.Conflict(ctx, "request with this nonce is still in progress")
+	default:
+		resp := entry.response
+		header := ctx.Writer.Header()
+		for key, values := range resp.header {
+			if _, ok := header[key]; !ok {
+				header[key] = values
+			}
+		}
+		header.Set(constants.HeaderIdempotentReplayed, "true")
+		ctx.Writer.WriteHeader(resp.status)
+		_, _ = ctx.Writer.Write(resp.body)
+	}
+	ctx.Abort()
+}
+
+// use 登记 nonce，已登记过时返回其记录和 true
+func (m *CheckSumMiddleware) use(key string, now time.Time) (nonceEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sweep(now)
+
+	if e, ok := m.nonces[key]; ok && now.Before(e.expiresAt) {
+		return *e, true
+	}
+	m.nonces[key] = &nonceEntry{expiresAt: now.Add(2 * m.window)}
+	return nonceEntry{}, false
+}
+
+// record 记录首次请求的响应；5xx 时释放 nonce，允许客户端用同一 nonce 重试
+func (m *CheckSumMiddleware) record(key string, resp *coalescedResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if resp.status >= http.StatusInternalServerError {
+		delete(m.nonces, key)
+		return
+	}
+	if e, ok := m.nonces[key]; ok {
+		e.response = resp
+	}
+}
+
+// release 释放 nonce
+func (m *CheckSumMiddleware) release(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.nonces, key)
+}
+
+// sweep 定期清理已过期的 nonce，避免内存无限增长
+func (m *CheckSumMiddleware) sweep(now time.Time) {
+	if now.Before(m.nextSweep) {
+		return
+	}
+	for key, e := range m.nonces {
+		if !now.Before(e.expiresAt) {
+			delete(m.nonces, key)
+		}
+	}
+	m.nextSweep = now.Add(m.window)
+}
@@ -0,0 +1,210 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/logger/logtest"
+	"go-api-template/pkg/security"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	testAppKey    = "app-1"
+	testAppSecret = "secret-1"
+)
+
+// signRequest 为测试服务器设置签名请求头，sequence 为空时不带请求序号
+func signRequest(s *webtest.Server, nonce, sequence string) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	s.Header.Set(constants.HeaderAppKey, testAppKey)
+	s.Header.Set(constants.HeaderTimestamp, timestamp)
+	s.Header.Set(constants.HeaderNonce, nonce)
+	if sequence == "" {
+		s.Header.Del(constants.HeaderSequence)
+		s.Header.Set(constants.HeaderCheckSum, security.Sha1(testAppSecret+nonce+timestamp))
+		return
+	}
+	s.Header.Set(constants.HeaderSequence, sequence)
+	s.Header.Set(constants.HeaderCheckSum, security.Sha1(testAppSecret+nonce+timestamp+sequence))
+}
+
+// newCheckSumServer 注册 POST /orders，calls 记录 Handler 执行次数，status 为 Handler 返回的状态码
+func newCheckSumServer(t *testing.T, config *CheckSumConfig, calls *atomic.Int32, status *atomic.Int32) *webtest.Server {
+	logtest.New(t)
+	m := NewCheckSumMiddleware(config)
+	return webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").POST("/orders", m.Handle(), func(ctx *web.Context) {
+			n := calls.Add(1)
+			if code := int(status.Load()); code != 0 {
+				ctx.JSON(code, gin.H{"code": code})
+				return
+			}
+			ctx.Header("X-Order", strconv.Itoa(int(n)))
+			web.Success(ctx, gin.H{"order": n})
+		})
+	})
+}
+
+func TestCheckSumRejectsReusedNonce(t *testing.T) {
+	var calls, status atomic.Int32
+	s := newCheckSumServer(t, &CheckSumConfig{
+		Secrets: security.StaticAppSecretProvider{testAppKey: testAppSecret},
+	}, &calls, &status)
+
+	signRequest(s, "nonce-1", "")
+	s.AssertCode(s.Do(http.MethodPost, "/orders", nil), http.StatusOK)
+
+	w := s.Do(http.MethodPost, "/orders", nil)
+	s.AssertCode(w, http.StatusUnauthorized)
+	s.AssertMessage(w, "nonce already used")
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+
+	signRequest(s, "nonce-2", "")
+	s.AssertCode(s.Do(http.MethodPost, "/orders", nil), http.StatusOK)
+}
+
+func TestCheckSumReplaysFirstResponse(t *testing.T) {
+	var calls, status atomic.Int32
+	s := newCheckSumServer(t, &CheckSumConfig{
+		Secrets:     security.StaticAppSecretProvider{testAppKey: testAppSecret},
+		NonceReplay: NonceReplayReplay,
+	}, &calls, &status)
+
+	signRequest(s, "nonce-1", "")
+	first := s.Do(http.MethodPost, "/orders", nil)
+	s.AssertCode(first, http.StatusOK)
+	if first.Header().Get(constants.HeaderIdempotentReplayed) != "" {
+		t.Fatal("first response marked as replayed")
+	}
+
+	retry := s.Do(http.MethodPost, "/orders", nil)
+	s.AssertCode(retry, http.StatusOK)
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+	if retry.Body.String() != first.Body.String() || retry.Header().Get("X-Order") != "1" {
+		t.Fatalf("retry = %s (X-Order %q), want the first response %s",
+			retry.Body.String(), retry.Header().Get("X-Order"), first.Body.String())
+	}
+	if retry.Header().Get(constants.HeaderIdempotentReplayed) != "true" {
+		t.Fatalf("%s = %q, want true", constants.HeaderIdempotentReplayed, retry.Header().Get(constants.HeaderIdempotentReplayed))
+	}
+}
+
+// 5xx 响应不记录，同一 nonce 重试时重新执行
+func TestCheckSumReplayRetriesAfterServerError(t *testing.T) {
+	var calls, status atomic.Int32
+	s := newCheckSumServer(t, &CheckSumConfig{
+		Secrets:     security.StaticAppSecretProvider{testAppKey: testAppSecret},
+		NonceReplay: NonceReplayReplay,
+	}, &calls, &status)
+
+	status.Store(http.StatusServiceUnavailable)
+	signRequest(s, "nonce-1", "")
+	s.AssertStatus(s.Do(http.MethodPost, "/orders", nil), http.StatusServiceUnavailable)
+
+	status.Store(0)
+	w := s.Do(http.MethodPost, "/orders", nil)
+	s.AssertCode(w, http.StatusOK)
+	if calls.Load() != 2 || w.Header().Get(constants.HeaderIdempotentReplayed) != "" {
+		t.Fatalf("calls = %d, replayed = %q; want the retry to run the handler", calls.Load(), w.Header().Get(constants.HeaderIdempotentReplayed))
+	}
+}
+
+func TestCheckSumInvalidSignature(t *testing.T) {
+	var calls, status atomic.Int32
+	s := newCheckSumServer(t, &CheckSumConfig{
+		Secrets:     security.StaticAppSecretProvider{testAppKey: testAppSecret},
+		NonceReplay: NonceReplayReplay,
+	}, &calls, &status)
+
+	signRequest(s, "nonce-1", "")
+	s.Header.Set(constants.HeaderCheckSum, "bad")
+	s.AssertCode(s.Do(http.MethodPost, "/orders", nil), http.StatusUnauthorized)
+
+	// 签名错误的请求不占用 nonce
+	signRequest(s, "nonce-1", "")
+	s.AssertCode(s.Do(http.MethodPost, "/orders", nil), http.StatusOK)
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+}
+
+func newSequencedCheckSumServer(t *testing.T) (*webtest.Server, *atomic.Int32) {
+	var calls, status atomic.Int32
+	s := newCheckSumServer(t, &CheckSumConfig{
+		Secrets: security.NewSequencedAppSecretProvider(
+			security.StaticAppSecretProvider{testAppKey: testAppSecret, "plain": "plain-secret"},
+			[]string{testAppKey},
+		),
+	}, &calls, &status)
+	return s, &calls
+}
+
+func TestCheckSumSequenceInOrder(t *testing.T) {
+	s, calls := newSequencedCheckSumServer(t)
+
+	for i, seq := range []string{"1", "2", "5", "100"} {
+		signRequest(s, "nonce-"+strconv.Itoa(i), seq)
+		s.AssertCode(s.Do(http.MethodPost, "/orders", nil), http.StatusOK)
+	}
+	if calls.Load() != 4 {
+		t.Fatalf("handler ran %d times, want 4", calls.Load())
+	}
+}
+
+func TestCheckSumSequenceOutOfOrder(t *testing.T) {
+	s, calls := newSequencedCheckSumServer(t)
+
+	signRequest(s, "nonce-1", "5")
+	s.AssertCode(s.Do(http.MethodPost, "/orders", nil), http.StatusOK)
+
+	// 新的 nonce、有效的签名，但序号重复或更小
+	for i, seq := range []string{"5", "3"} {
+		signRequest(s, "nonce-replay-"+strconv.Itoa(i), seq)
+		w := s.Do(http.MethodPost, "/orders", nil)
+		s.AssertCode(w, http.StatusUnauthorized)
+		s.AssertMessage(w, "sequence out of order")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+
+	// 被拒绝的请求释放了 nonce，带新序号重试可以通过
+	signRequest(s, "nonce-replay-0", "6")
+	s.AssertCode(s.Do(http.MethodPost, "/orders", nil), http.StatusOK)
+}
+
+func TestCheckSumSequenceRequiredAndSigned(t *testing.T) {
+	s, _ := newSequencedCheckSumServer(t)
+
+	// 开启序号的应用缺少序号
+	signRequest(s, "nonce-1", "")
+	w := s.Do(http.MethodPost, "/orders", nil)
+	s.AssertCode(w, http.StatusUnauthorized)
+	s.AssertMessage(w, "missing or invalid sequence")
+
+	// 序号不在签名中（篡改序号）
+	signRequest(s, "nonce-2", "1")
+	s.Header.Set(constants.HeaderSequence, "2")
+	s.AssertCode(s.Do(http.MethodPost, "/orders", nil), http.StatusUnauthorized)
+
+	// 未开启序号的应用不受影响
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	s.Header.Del(constants.HeaderSequence)
+	s.Header.Set(constants.HeaderAppKey, "plain")
+	s.Header.Set(constants.HeaderTimestamp, timestamp)
+	s.Header.Set(constants.HeaderNonce, "nonce-3")
+	s.Header.Set(constants.HeaderCheckSum, security.Sha1("plain-secret"+"nonce-3"+timestamp))
+	s.AssertCode(s.Do(http.MethodPost, "/orders", nil), http.StatusOK)
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/web"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/singleflight"
+)
+
+// coalesceKeyHeaders 参与合并 key 的请求头：影响响应内容或区分用户的请求头不同，不能共享响应
+var coalesceKeyHeaders = []string{
+	"Accept",
+	"Accept-Encoding",
+	"Accept-Language",
+	"Authorization",
+	"Cookie",
+	"If-None-Match",
+	"If-Modified-Since",
+	constants.HeaderFeatureFlags,
+}
+
+// CoalesceMiddleware 相同 GET 请求合并中间件（路由级，按需启用）
+// 同一时刻到达的相同请求（方法 + 路径 + 查询参数 + coalesceKeyHeaders）只执行一次 Handler，
+// 其余请求等待并共享其响应（状态码、响应头、响应体），用于热点详情接口防止请求洪峰直接打到数据库。
+// 只合并 GET/HEAD；执行请求返回 5xx 时不共享，等待的请求各自重新执行
+type CoalesceMiddleware struct {
+	group singleflight.Group
+}
+
+// NewCoalesceMiddleware 创建请求合并中间件
+func NewCoalesceMiddleware() *CoalesceMiddleware {
+	return &CoalesceMiddleware{}
+}
+
+// coalescedResponse 执行请求记录下的响应
+type coalescedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// Handle 合并相同请求
+func (m *CoalesceMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		method := ctx.Request.Method
+		if method != http.MethodGet && method != http.MethodHead {
+			ctx.Next()
+			return
+		}
+
+		executed := false
+		v, _, _ := m.group.Do(coalesceKey(ctx.Request), func() (interface{}, error) {
+			executed = true
+			w := &recordingWriter{ResponseWriter: ctx.Writer}
+			ctx.Writer = w
+			ctx.Next()
+			ctx.Writer = w.ResponseWriter
+
+			return &coalescedResponse{
+				status: w.Status(),
+				header: w.Header().Clone(),
+				body:   w.body.Bytes(),
+			}, nil
+		})
+		if executed {
+			return
+		}
+
+		resp := v.(*coalescedResponse)
+		if resp.status >= http.StatusInternalServerError {
+			ctx.Next() // 执行请求失败（如其客户端断开导致查询被取消），自行处理
+			return
+		}
+
+		// 保留本请求已设置的响应头（如 X-Request-ID），其余沿用执行请求的响应头
+		header := ctx.Writer.Header()
+		for key, values := range resp.header {
+			if _, ok := header[key]; !ok {
+				header[key] = values
+			}
+		}
+		ctx.Writer.WriteHeader(resp.status)
+		_, _ = ctx.Writer.Write(resp.body)
+		ctx.Abort()
+	}
+}
+
+// coalesceKey 合并 key：方法、路径、查询参数和影响响应的请求头
+func coalesceKey(r *http.Request) string {
+	var sb strings.Builder
+	sb.WriteString(r.Method)
+	sb.WriteByte(' ')
+	sb.WriteString(r.URL.RequestURI())
+	for _, name := range coalesceKeyHeaders {
+		sb.WriteByte('\n')
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(strings.Join(r.Header.Values(name), ","))
+	}
+	return sb.String()
+}
+
+// recordingWriter 照常写出响应，同时记录响应体供等待的请求共享
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write 写出并记录响应体
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString 写出并记录响应体
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
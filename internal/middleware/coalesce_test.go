@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// blockingService 模拟慢查询：每次调用计数，阻塞到 release 关闭
+type blockingService struct {
+	calls   atomic.Int32
+	entered chan struct{}
+	release chan struct{}
+	status  int
+}
+
+func newBlockingService(status int) *blockingService {
+	return &blockingService{entered: make(chan struct{}, 100), release: make(chan struct{}), status: status}
+}
+
+func (s *blockingService) handle(ctx *web.Context) {
+	s.calls.Add(1)
+	s.entered <- struct{}{}
+	<-s.release
+	ctx.Header("X-Served-By", "handler")
+	ctx.Data(s.status, "application/json", []byte(`{"id":1}`))
+}
+
+func newCoalesceServer(t *testing.T, svc *blockingService) *webtest.Server {
+	m := NewCoalesceMiddleware()
+	return webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "")
+		g.GET("/demos/:id", m.Handle(), svc.handle)
+		g.POST("/demos/:id", m.Handle(), svc.handle)
+	})
+}
+
+// concurrent 同时发出 n 个请求，等第一个请求进入 Handler 后再等待一段时间，让其余请求加入合并，然后放行
+func concurrent(s *webtest.Server, svc *blockingService, n int, newRequest func(i int) *http.Request) []*httptest.ResponseRecorder {
+	responses := make([]*httptest.ResponseRecorder, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			s.Engine.ServeHTTP(w, newRequest(i))
+			responses[i] = w
+		}()
+	}
+	<-svc.entered
+	time.Sleep(50 * time.Millisecond)
+	close(svc.release)
+	wg.Wait()
+	return responses
+}
+
+func TestCoalesceCallsServiceOnce(t *testing.T) {
+	svc := newBlockingService(http.StatusOK)
+	s := newCoalesceServer(t, svc)
+
+	const n = 20
+	responses := concurrent(s, svc, n, func(int) *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/demos/1?fields=id", nil)
+	})
+
+	if calls := svc.calls.Load(); calls != 1 {
+		t.Fatalf("service called %d times for %d identical requests, want 1", calls, n)
+	}
+	for i, w := range responses {
+		if w.Code != http.StatusOK || w.Body.String() != `{"id":1}` || w.Header().Get("X-Served-By") != "handler" {
+			t.Fatalf("response %d: %d %q %v", i, w.Code, w.Body.String(), w.Header())
+		}
+	}
+}
+
+func TestCoalesceKeysByHeaders(t *testing.T) {
+	svc := newBlockingService(http.StatusOK)
+	s := newCoalesceServer(t, svc)
+
+	// 不同用户的请求不共享响应
+	concurrent(s, svc, 4, func(i int) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/demos/1", nil)
+		r.Header.Set("Authorization", []string{"Bearer a", "Bearer b"}[i%2])
+		return r
+	})
+	if calls := svc.calls.Load(); calls != 2 {
+		t.Fatalf("service called %d times for 2 distinct users, want 2", calls)
+	}
+}
+
+func TestCoalesceSkipsWritesAndErrors(t *testing.T) {
+	post := newBlockingService(http.StatusOK)
+	s := newCoalesceServer(t, post)
+	concurrent(s, post, 3, func(int) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/demos/1", nil)
+	})
+	if calls := post.calls.Load(); calls != 3 {
+		t.Fatalf("POST: service called %d times, want 3", calls)
+	}
+
+	// 5xx 不共享，等待的请求各自重新执行
+	failing := newBlockingService(http.StatusInternalServerError)
+	s = newCoalesceServer(t, failing)
+	concurrent(s, failing, 3, func(int) *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/demos/1", nil)
+	})
+	if calls := failing.calls.Load(); calls != 3 {
+		t.Fatalf("5xx: service called %d times, want 3", calls)
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/web"
+)
+
+// ContentTypeMiddleware Content-Type 校验中间件
+// 对 POST/PUT/PATCH 请求要求 Content-Type 在允许列表内，否则返回 415，避免绑定阶段报出难以理解的错误
+type ContentTypeMiddleware struct {
+	allowed map[string]struct{}
+	exempt  map[string]struct{}
+	message string
+}
+
+// ContentTypeConfig Content-Type 校验配置
+type ContentTypeConfig struct {
+	Allowed     []string // 允许的媒体类型（不含参数，如 application/json）
+	ExemptPaths []string // 不校验的路由（Gin 路由模式，如 /api/v1/demos/upload）
+}
+
+// NewContentTypeMiddleware 创建 Content-Type 校验中间件
+func NewContentTypeMiddleware(config *ContentTypeConfig) *ContentTypeMiddleware {
+	// 设置默认值
+	if config == nil {
+		config = &ContentTypeConfig{}
+	}
+
+	allowed := config.Allowed
+	if len(allowed) == 0 {
+		allowed = []string{"application/json"}
+	}
+
+	m := &ContentTypeMiddleware{
+		allowed: make(map[string]struct{}, len(allowed)),
+		exempt:  make(map[string]struct{}, len(config.ExemptPaths)),
+		message: fmt.Sprintf("%s，支持：%s", constants.MsgUnsupportedMediaType, strings.Join(allowed, ", ")),
+	}
+	for _, t := range allowed {
+		m.allowed[strings.ToLower(t)] = struct{}{}
+	}
+	for _, p := range config.ExemptPaths {
+		m.exempt[p] = struct{}{}
+	}
+	return m
+}
+
+// Handle 校验请求的 Content-Type
+func (m *ContentTypeMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		if !m.check(ctx.Request, ctx.FullPath()) {
+			web.Error(ctx, http.StatusUnsupportedMediaType, http.StatusUnsupportedMediaType, m.message)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// check 判断请求是否满足要求
+// 只校验 POST/PUT/PATCH；没有请求体、未匹配路由（交给 404/405 处理）或在豁免列表中的请求直接放行
+func (m *ContentTypeMiddleware) check(r *http.Request, route string) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return true
+	}
+	if r.ContentLength == 0 {
+		return true
+	}
+	if route == "" {
+		return true
+	}
+	if _, ok := m.exempt[route]; ok {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	_, ok := m.allowed[mediaType]
+	return ok
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newContentTypeServer(t *testing.T) *webtest.Server {
+	ok := web.ToGinHandler(func(ctx *web.Context) { web.Success(ctx, nil) })
+	return webtest.New(t, func(r *gin.Engine) {
+		r.POST("/demos", ok)
+		r.PUT("/demos", ok)
+		r.GET("/demos", ok)
+		r.POST("/upload", ok)
+	}, NewContentTypeMiddleware(&ContentTypeConfig{ExemptPaths: []string{"/upload"}}).Handle())
+}
+
+func TestContentTypeAccepted(t *testing.T) {
+	s := newContentTypeServer(t)
+
+	for _, contentType := range []string{"application/json", "application/json; charset=utf-8", "Application/JSON"} {
+		s.Header.Set("Content-Type", contentType)
+		s.AssertCode(s.Do(http.MethodPost, "/demos", `{"title":"a"}`), http.StatusOK)
+	}
+
+	// 非 POST/PUT/PATCH、没有请求体、豁免路由不校验
+	s.Header.Set("Content-Type", "text/plain")
+	s.AssertCode(s.Do(http.MethodGet, "/demos", nil), http.StatusOK)
+	s.AssertCode(s.Do(http.MethodPost, "/demos", nil), http.StatusOK)
+	s.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	s.AssertCode(s.Do(http.MethodPost, "/upload", "--x--"), http.StatusOK)
+}
+
+func TestContentTypeRejected(t *testing.T) {
+	s := newContentTypeServer(t)
+
+	for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded", "application/json; charset"} {
+		s.Header.Set("Content-Type", contentType)
+		w := s.Do(http.MethodPut, "/demos", `{"title":"a"}`)
+		s.AssertStatus(w, http.StatusUnsupportedMediaType)
+		s.AssertMessage(w, constants.MsgUnsupportedMediaType+"，支持：application/json")
+	}
+}
@@ -1,14 +1,19 @@
 package middleware
 
 import (
+	"strconv"
+
 	"go-api-template/pkg/web"
 )
 
+// defaultCORSMaxAge 预检请求默认缓存时间（秒）
+const defaultCORSMaxAge = 86400
+
 // CORSMiddleware CORS 跨域中间件
 type CORSMiddleware struct {
 	allowOrigins []string
-	allowMethods []string
-	allowHeaders []string
+	policy       corsHeaders            // 全局策略
+	origins      map[string]corsHeaders // 按来源的策略
 }
 
 // CORSConfig CORS 配置
@@ -16,6 +21,25 @@ type CORSConfig struct {
 	AllowOrigins []string // 允许的来源，如：["http://localhost:3000", "https://example.com"]
 	AllowMethods []string // 允许的方法，如：["GET", "POST", "PUT", "DELETE"]
 	AllowHeaders []string // 允许的请求头，如：["Content-Type", "Authorization"]
+	MaxAge       int      // 预检请求缓存时间（秒），默认 86400
+
+	// Origins 按来源的策略（来源 -> 策略），如内部工具可以缓存更久、允许更多方法；
+	// 列出的来源视为允许的来源，未列出的来源使用全局策略
+	Origins map[string]CORSPolicy
+}
+
+// CORSPolicy 单个来源的 CORS 策略，未设置的字段使用全局配置
+type CORSPolicy struct {
+	AllowMethods []string // 允许的方法
+	AllowHeaders []string // 允许的请求头
+	MaxAge       int      // 预检请求缓存时间（秒）
+}
+
+// corsHeaders 策略对应的响应头值（创建中间件时计算）
+type corsHeaders struct {
+	methods string
+	headers string
+	maxAge  string
 }
 
 // NewCORSMiddleware 创建 CORS 中间件
@@ -37,11 +61,29 @@ func NewCORSMiddleware(config *CORSConfig) *CORSMiddleware {
 		config.AllowHeaders = []string{"Content-Type", "Authorization", "X-Request-ID"}
 	}
 
-	return &CORSMiddleware{
+	if config.MaxAge <= 0 {
+		config.MaxAge = defaultCORSMaxAge
+	}
+
+	m := &CORSMiddleware{
 		allowOrigins: config.AllowOrigins,
-		allowMethods: config.AllowMethods,
-		allowHeaders: config.AllowHeaders,
+		origins:      make(map[string]corsHeaders, len(config.Origins)),
 	}
+	m.policy = m.headers(config.AllowMethods, config.AllowHeaders, config.MaxAge)
+	for origin, p := range config.Origins {
+		methods, headers, maxAge := config.AllowMethods, config.AllowHeaders, config.MaxAge
+		if len(p.AllowMethods) > 0 {
+			methods = p.AllowMethods
+		}
+		if len(p.AllowHeaders) > 0 {
+			headers = p.AllowHeaders
+		}
+		if p.MaxAge > 0 {
+			maxAge = p.MaxAge
+		}
+		m.origins[origin] = m.headers(methods, headers, maxAge)
+	}
+	return m
 }
 
 // NewDefaultCORSMiddleware 创建默认配置的 CORS 中间件
@@ -55,8 +97,18 @@ func (m *CORSMiddleware) Handle() web.HandlerFunc {
 		// 获取请求来源
 		origin := ctx.GetHeader("Origin")
 
+		// 按来源的策略，未列出的来源使用全局策略
+		policy, listed := m.origins[origin]
+		if !listed {
+			policy = m.policy
+		}
+		if len(m.origins) > 0 {
+			// 预检响应随来源变化，避免共享缓存把一个来源的策略用于其他来源
+			ctx.Writer.Header().Add("Vary", "Origin")
+		}
+
 		// 检查来源是否允许
-		if m.isOriginAllowed(origin) {
+		if listed || m.isOriginAllowed(origin) {
 			// 设置 CORS 响应头
 			ctx.Header("Access-Control-Allow-Origin", origin)
 		} else if len(m.allowOrigins) == 1 && m.allowOrigins[0] == "*" {
@@ -65,10 +117,10 @@ func (m *CORSMiddleware) Handle() web.HandlerFunc {
 		}
 
 		// 设置其他 CORS 响应头
-		ctx.Header("Access-Control-Allow-Methods", m.joinStrings(m.allowMethods))
-		ctx.Header("Access-Control-Allow-Headers", m.joinStrings(m.allowHeaders))
+		ctx.Header("Access-Control-Allow-Methods", policy.methods)
+		ctx.Header("Access-Control-Allow-Headers", policy.headers)
 		ctx.Header("Access-Control-Allow-Credentials", "true")
-		ctx.Header("Access-Control-Max-Age", "86400") // 预检请求缓存 24 小时
+		ctx.Header("Access-Control-Max-Age", policy.maxAge) // 预检请求缓存时间
 
 		// OPTIONS 请求直接返回（预检请求）
 		if ctx.Request.Method == "OPTIONS" {
@@ -81,6 +133,15 @@ func (m *CORSMiddleware) Handle() web.HandlerFunc {
 	}
 }
 
+// headers 计算策略对应的响应头值
+func (m *CORSMiddleware) headers(methods, headers []string, maxAge int) corsHeaders {
+	return corsHeaders{
+		methods: m.joinStrings(methods),
+		headers: m.joinStrings(headers),
+		maxAge:  strconv.Itoa(maxAge),
+	}
+}
+
 // isOriginAllowed 检查来源是否允许
 func (m *CORSMiddleware) isOriginAllowed(origin string) bool {
 	if origin == "" {
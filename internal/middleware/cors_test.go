@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	internalOrigin = "https://admin.internal"
+	partnerOrigin  = "https://partner.example.com"
+	publicOrigin   = "https://www.example.com"
+)
+
+func newCORSServer(t *testing.T) *webtest.Server {
+	m := NewCORSMiddleware(&CORSConfig{
+		AllowOrigins: []string{publicOrigin},
+		AllowMethods: []string{"GET", "POST"},
+		AllowHeaders: []string{"Content-Type"},
+		MaxAge:       600,
+		Origins: map[string]CORSPolicy{
+			internalOrigin: {AllowMethods: []string{"GET", "POST", "PUT", "DELETE"}, AllowHeaders: []string{"Content-Type", "Authorization"}, MaxAge: 86400},
+			partnerOrigin:  {MaxAge: 60}, // 只覆盖缓存时间，方法和请求头使用全局配置
+		},
+	})
+	return webtest.New(t, func(r *gin.Engine) {
+		r.GET("/demos", web.ToGinHandler(func(ctx *web.Context) { web.Success(ctx, nil) }))
+	}, m.Handle())
+}
+
+// preflight 发送 origin 的预检请求
+func preflight(s *webtest.Server, origin string) *httptest.ResponseRecorder {
+	s.Header.Set("Origin", origin)
+	s.Header.Set("Access-Control-Request-Method", "PUT")
+	return s.Do(http.MethodOptions, "/demos", nil)
+}
+
+func TestCORSPerOriginPreflight(t *testing.T) {
+	s := newCORSServer(t)
+
+	tests := []struct {
+		origin  string
+		methods string
+		headers string
+		maxAge  string
+	}{
+		{internalOrigin, "GET, POST, PUT, DELETE", "Content-Type, Authorization", "86400"},
+		{partnerOrigin, "GET, POST", "Content-Type", "60"},
+		{publicOrigin, "GET, POST", "Content-Type", "600"}, // 未单独配置：全局策略
+	}
+	for _, tt := range tests {
+		w := preflight(s, tt.origin)
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("%s: status = %d, want 204", tt.origin, w.Code)
+		}
+		want := map[string]string{
+			"Access-Control-Allow-Origin":  tt.origin,
+			"Access-Control-Allow-Methods": tt.methods,
+			"Access-Control-Allow-Headers": tt.headers,
+			"Access-Control-Max-Age":       tt.maxAge,
+			"Vary":                         "Origin",
+		}
+		for name, value := range want {
+			if got := w.Header().Get(name); got != value {
+				t.Fatalf("%s: %s = %q, want %q", tt.origin, name, got, value)
+			}
+		}
+	}
+}
+
+func TestCORSUnlistedOriginNotAllowed(t *testing.T) {
+	s := newCORSServer(t)
+
+	w := preflight(s, "https://evil.example.org")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want none", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Fatalf("Access-Control-Max-Age = %q, want the global 600", got)
+	}
+
+	// 普通请求按来源设置响应头并继续处理
+	s.Header.Set("Origin", internalOrigin)
+	w = s.Do(http.MethodGet, "/demos", nil)
+	s.AssertCode(w, http.StatusOK)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != internalOrigin {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, internalOrigin)
+	}
+}
+
+// 没有按来源的策略时保持原有行为：默认 86400，不加 Vary
+func TestCORSDefaults(t *testing.T) {
+	s := webtest.New(t, func(r *gin.Engine) {
+		r.GET("/demos", web.ToGinHandler(func(ctx *web.Context) { web.Success(ctx, nil) }))
+	}, NewDefaultCORSMiddleware().Handle())
+
+	w := preflight(s, publicOrigin)
+	if w.Header().Get("Access-Control-Max-Age") != "86400" || w.Header().Get("Vary") != "" {
+		t.Fatalf("Max-Age = %q, Vary = %q; want 86400 and no Vary",
+			w.Header().Get("Access-Control-Max-Age"), w.Header().Get("Vary"))
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != publicOrigin {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, publicOrigin)
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/featureflag"
+	"go-api-template/pkg/web"
+)
+
+// FeatureFlagMiddleware 功能开关中间件
+// 为每个请求生成 featureflag.Flags 放入请求 context；允许覆盖时可通过 X-Feature-Flags 请求头临时开关（用于测试/灰度）
+type FeatureFlagMiddleware struct {
+	provider      featureflag.Provider
+	allowOverride bool
+}
+
+// NewFeatureFlagMiddleware 创建功能开关中间件
+func NewFeatureFlagMiddleware(provider featureflag.Provider, allowOverride bool) *FeatureFlagMiddleware {
+	return &FeatureFlagMiddleware{provider: provider, allowOverride: allowOverride}
+}
+
+// Handle 生成请求级功能开关
+func (m *FeatureFlagMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		var overrides map[string]bool
+		if m.allowOverride {
+			overrides = featureflag.ParseOverrides(ctx.GetHeader(constants.HeaderFeatureFlags))
+		}
+		flags := featureflag.New(m.provider, overrides)
+		ctx.Request = ctx.Request.WithContext(featureflag.WithFlags(ctx.Request.Context(), flags))
+		ctx.Next()
+	}
+}
+
+// Require 要求功能开关开启，关闭时按接口不存在处理（404）
+// 需注册在 Handle 之后
+func (m *FeatureFlagMiddleware) Require(name string) web.HandlerFunc {
+	return func(ctx *web.Context) {
+		if !featureflag.Enabled(ctx.Request.Context(), name) {
+			web.NotFound(ctx, constants.MsgInterfaceNotFound)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
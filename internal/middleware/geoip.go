@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/geoip"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/web"
+)
+
+// GeoIPMiddleware IP 归属信息中间件
+// 按 ClientIP 查询国家和 ASN，放入请求 context（geoip.FromContext）并附加到日志字段（请求日志中同样带上）；
+// 未配置 Resolver 时不做任何处理，查询失败时只记录 debug 日志，请求照常处理
+type GeoIPMiddleware struct {
+	resolver geoip.Resolver
+}
+
+// NewGeoIPMiddleware 创建 IP 归属信息中间件，resolver 为 nil 表示不启用
+func NewGeoIPMiddleware(resolver geoip.Resolver) *GeoIPMiddleware {
+	return &GeoIPMiddleware{resolver: resolver}
+}
+
+// Handle 查询 IP 归属信息
+func (m *GeoIPMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		if m.resolver == nil {
+			ctx.Next()
+			return
+		}
+
+		ip := net.ParseIP(ctx.ClientIP())
+		if ip == nil {
+			ctx.Next()
+			return
+		}
+
+		loc, err := m.resolver.Lookup(ip)
+		if err != nil {
+			logger.Ctx(ctx.Request.Context()).Debug("geoip lookup failed",
+				logger.String(constants.LogFieldIP, ip.String()),
+				logger.Err(err),
+			)
+			ctx.Next()
+			return
+		}
+
+		var fields []logger.Field
+		if loc.Country != "" {
+			fields = append(fields, logger.String(constants.LogFieldCountry, loc.Country))
+		}
+		if loc.ASN != 0 {
+			fields = append(fields, logger.Uint(constants.LogFieldASN, loc.ASN))
+		}
+		reqCtx := geoip.WithLocation(ctx.Request.Context(), loc)
+		if len(fields) > 0 {
+			reqCtx = logger.WithFields(reqCtx, fields...)
+		}
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+
+		ctx.Next()
+	}
+}
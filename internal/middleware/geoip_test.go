@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/geoip"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/logger/logtest"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// stubResolver 固定返回 loc 或 err，记录查询的 IP
+type stubResolver struct {
+	loc    *geoip.Location
+	err    error
+	lookup []string
+}
+
+func (r *stubResolver) Lookup(ip net.IP) (*geoip.Location, error) {
+	r.lookup = append(r.lookup, ip.String())
+	return r.loc, r.err
+}
+
+// newGeoIPServer 与路由配置相同：请求日志在外层，GeoIP 之后的 Handler 读取 context 中的归属信息并记录一条日志
+func newGeoIPServer(t *testing.T, resolver geoip.Resolver) (*webtest.Server, *observer.ObservedLogs, **geoip.Location) {
+	logs := logtest.New(t)
+	var seen *geoip.Location
+	s := webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").GET("/ping", func(ctx *web.Context) {
+			seen, _ = geoip.FromContext(ctx.Request.Context())
+			logger.Ctx(ctx.Request.Context()).Info("handled")
+			web.Success(ctx, nil)
+		})
+	}, NewAccessLogMiddleware(nil).Handle(), NewGeoIPMiddleware(resolver).Handle())
+	return s, logs, &seen
+}
+
+func TestGeoIPAttachesLocation(t *testing.T) {
+	resolver := &stubResolver{loc: &geoip.Location{Country: "CN", ASN: 4134, ASOrg: "Chinanet"}}
+	s, logs, seen := newGeoIPServer(t, resolver)
+
+	s.AssertCode(s.Do(http.MethodGet, "/ping", nil), http.StatusOK)
+
+	if len(resolver.lookup) != 1 || resolver.lookup[0] != "192.0.2.1" {
+		t.Fatalf("lookups = %v, want the client IP once", resolver.lookup)
+	}
+	if *seen == nil || (*seen).Country != "CN" || (*seen).ASN != 4134 {
+		t.Fatalf("context location = %+v", *seen)
+	}
+	for _, msg := range []string{"handled", "request"} {
+		entries := logs.FilterMessage(msg).All()
+		if len(entries) != 1 {
+			t.Fatalf("%q logged %d times", msg, len(entries))
+		}
+		fields := entries[0].ContextMap()
+		if fields[constants.LogFieldCountry] != "CN" || fields[constants.LogFieldASN] != uint64(4134) {
+			t.Fatalf("%q fields = %v, want country CN and asn 4134", msg, fields)
+		}
+	}
+}
+
+// 查询失败或未配置 Resolver 时请求照常处理，不带归属信息
+func TestGeoIPFailsOpen(t *testing.T) {
+	for name, resolver := range map[string]geoip.Resolver{
+		"lookup error": &stubResolver{err: errors.New("database unavailable")},
+		"disabled":     nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			s, logs, seen := newGeoIPServer(t, resolver)
+
+			s.AssertCode(s.Do(http.MethodGet, "/ping", nil), http.StatusOK)
+			if *seen != nil {
+				t.Fatalf("context location = %+v, want none", *seen)
+			}
+			fields := logs.FilterMessage("request").All()[0].ContextMap()
+			if _, ok := fields[constants.LogFieldCountry]; ok {
+				t.Fatalf("request log has country: %v", fields)
+			}
+		})
+	}
+}
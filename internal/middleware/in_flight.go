@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"go-api-template/pkg/web"
+)
+
+// InFlightMiddleware 在途请求计数中间件
+// 用于关闭时统计仍在处理中的请求数
+type InFlightMiddleware struct {
+	count atomic.Int64
+}
+
+// NewInFlightMiddleware 创建在途请求计数中间件
+func NewInFlightMiddleware() *InFlightMiddleware {
+	return &InFlightMiddleware{}
+}
+
+// Handle 统计在途请求
+func (m *InFlightMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		m.count.Add(1)
+		defer m.count.Add(-1)
+
+		ctx.Next()
+	}
+}
+
+// Count 当前在途请求数
+func (m *InFlightMiddleware) Count() int64 {
+	return m.count.Load()
+}
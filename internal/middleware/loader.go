@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/web"
+)
+
+// LoaderMiddleware 请求级 Loader 中间件
+// 为每个请求创建新的 database.Loader，同一请求内重复的 FindByID 只访问一次数据库
+type LoaderMiddleware struct{}
+
+// NewLoaderMiddleware 创建请求级 Loader 中间件
+func NewLoaderMiddleware() *LoaderMiddleware {
+	return &LoaderMiddleware{}
+}
+
+// Handle 安装请求级 Loader
+func (m *LoaderMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		reqCtx := database.WithLoader(ctx.Request.Context(), database.NewLoader())
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+
+		ctx.Next()
+	}
+}
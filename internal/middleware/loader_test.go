@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"go-api-template/internal/model"
+	"go-api-template/internal/repository"
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// countQueries 统计 db 执行的查询次数
+func countQueries(t *testing.T, db *gorm.DB) *int {
+	t.Helper()
+
+	var n int
+	if err := db.Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) { n++ }); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+	return &n
+}
+
+func TestLoaderDeduplicatesFindByIDWithinRequest(t *testing.T) {
+	db := dbtest.Open(t, &model.Demo{})
+	demo := model.Demo{Title: "loaded"}
+	if err := db.Create(&demo).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	repo := repository.NewDemoRepository(db)
+	queries := countQueries(t, db)
+
+	s := webtest.New(t, func(r *gin.Engine) {
+		r.GET("/demo", web.ToGinHandler(func(ctx *web.Context) {
+			for i := 0; i < 2; i++ {
+				if _, err := repo.FindByID(ctx.Request.Context(), demo.ID); err != nil {
+					web.RespondError(ctx, err, "find demo failed")
+					return
+				}
+			}
+			web.Success(ctx, nil)
+		}))
+	}, NewLoaderMiddleware().Handle())
+
+	s.AssertCode(s.Do(http.MethodGet, "/demo", nil), http.StatusOK)
+	if *queries != 1 {
+		t.Fatalf("two FindByID calls in one request ran %d queries, want 1", *queries)
+	}
+
+	// 每个请求使用新的 Loader
+	s.AssertCode(s.Do(http.MethodGet, "/demo", nil), http.StatusOK)
+	if *queries != 2 {
+		t.Fatalf("after second request: %d queries, want 2", *queries)
+	}
+}
@@ -1,32 +1,114 @@
 package middleware
 
 import (
+	"time"
+
 	"go-api-template/pkg/config"
+	"go-api-template/pkg/featureflag"
+	"go-api-template/pkg/geoip"
+	"go-api-template/pkg/security"
+	"go-api-template/pkg/web"
 )
 
 // Middleware 中间件集合
 type Middleware struct {
-	RequestID *RequestIDMiddleware
-	CORS      *CORSMiddleware
+	Recovery    *RecoveryMiddleware
+	AccessLog   *AccessLogMiddleware
+	RequestID   *RequestIDMiddleware
+	CORS        *CORSMiddleware
+	RateLimit   *RateLimitMiddleware
+	InFlight    *InFlightMiddleware
+	Timing      *TimingMiddleware
+	Loader      *LoaderMiddleware
+	ContentType *ContentTypeMiddleware
+	Audit       *AuditMiddleware
+	Auth        *AuthMiddleware
+	CheckSum    *CheckSumMiddleware
+	APIKey      *APIKeyMiddleware
+	FeatureFlag *FeatureFlagMiddleware
+	Coalesce    *CoalesceMiddleware
+	GeoIP       *GeoIPMiddleware
+
+	// 包裹在路由外层（路由匹配之前执行），见 App.Handler
+	TrailingSlash *TrailingSlashMiddleware
 }
 
 // NewMiddleware 创建中间件集合
-func NewMiddleware(cfg *config.Config) *Middleware {
+// appSecrets、sequences 为 CheckSum 的应用密钥来源和请求序号存储（见 provideAppSecrets、provideSequenceStore），
+// apiKeys 为 API Key 来源（见 provideAPIKeys），
+// geo 为 IP 归属信息查询（未启用时为 nil，见 provideGeoIP）
+func NewMiddleware(cfg *config.Config, appSecrets security.AppSecretProvider, sequences security.SequenceStore, apiKeys security.APIKeyProvider, geo geoip.Resolver) *Middleware {
 	// 根据配置创建 CORS 中间件
 	var corsMiddleware *CORSMiddleware
 	if cfg.CORS.Enabled {
+		origins := make(map[string]CORSPolicy, len(cfg.CORS.Origins))
+		for origin, p := range cfg.CORS.Origins {
+			origins[origin] = CORSPolicy{AllowMethods: p.AllowMethods, AllowHeaders: p.AllowHeaders, MaxAge: p.MaxAge}
+		}
 		corsMiddleware = NewCORSMiddleware(&CORSConfig{
 			AllowOrigins: cfg.CORS.AllowOrigins,
 			AllowMethods: cfg.CORS.AllowMethods,
 			AllowHeaders: cfg.CORS.AllowHeaders,
+			MaxAge:       cfg.CORS.MaxAge,
+			Origins:      origins,
 		})
 	} else {
 		// CORS 未启用时使用默认配置
 		corsMiddleware = NewDefaultCORSMiddleware()
 	}
 
+	checkSum := NewCheckSumMiddleware(&CheckSumConfig{
+		Secrets:     appSecrets,
+		Sequences:   sequences,
+		Window:      time.Duration(cfg.CheckSum.Window) * time.Second,
+		NonceReplay: cfg.CheckSum.NonceReplay,
+	})
+	apiKey := NewAPIKeyMiddleware(&APIKeyConfig{
+		Provider: apiKeys,
+		Header:   cfg.APIKey.Header,
+		Query:    cfg.APIKey.Query,
+	})
+	// 携带应用凭证的请求由写接口上的 HandleApp 按应用限流，认证通过后退还预占的 IP 配额
+	var credentials []func(ctx *web.Context) bool
+	if cfg.CheckSum.Enabled {
+		credentials = append(credentials, checkSum.HasCredentials)
+	}
+	if cfg.APIKey.Enabled {
+		credentials = append(credentials, apiKey.HasCredentials)
+	}
+
 	return &Middleware{
+		Recovery: NewRecoveryMiddleware(cfg.Logger.PanicGoroutineDump),
+		AccessLog: NewAccessLogMiddleware(&AccessLogConfig{
+			SampleFirst:      cfg.Logger.AccessLog.SampleFirst,
+			SampleThereafter: cfg.Logger.AccessLog.SampleThereafter,
+			SlowThreshold:    time.Duration(cfg.Logger.AccessLog.SlowThresholdMs) * time.Millisecond,
+		}),
 		RequestID: NewRequestIDMiddleware(),
 		CORS:      corsMiddleware,
+		RateLimit: NewRateLimitMiddleware(&RateLimitConfig{
+			Limit:       cfg.RateLimit.Limit,
+			Window:      time.Duration(cfg.RateLimit.Window) * time.Second,
+			Provider:    StaticRateLimitProvider(cfg.RateLimit.Apps),
+			Credentials: credentials,
+		}),
+		InFlight: NewInFlightMiddleware(),
+		Timing:   NewTimingMiddleware(cfg.Server.ServerTiming),
+		Loader:   NewLoaderMiddleware(),
+		ContentType: NewContentTypeMiddleware(&ContentTypeConfig{
+			Allowed:     cfg.ContentType.Allowed,
+			ExemptPaths: cfg.ContentType.ExemptPaths,
+		}),
+		Audit:    NewAuditMiddleware(),
+		Auth:     NewAuthMiddleware(cfg.Auth.JWTSecret),
+		CheckSum: checkSum,
+		APIKey:   apiKey,
+		FeatureFlag: NewFeatureFlagMiddleware(
+			featureflag.NewStaticProvider(cfg.FeatureFlags.Flags),
+			cfg.FeatureFlags.HeaderOverride,
+		),
+		Coalesce:      NewCoalesceMiddleware(),
+		GeoIP:         NewGeoIPMiddleware(geo),
+		TrailingSlash: NewTrailingSlashMiddleware(cfg.Server.TrailingSlash),
 	}
 }
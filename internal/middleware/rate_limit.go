@@ -0,0 +1,233 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/web"
+)
+
+// RateLimitMiddleware 限流中间件（固定窗口）
+// Handle 按客户端 IP 计数（全局）；HandleApp 挂在 CheckSum / API Key 认证之后，按应用计数，配额由 RateLimitProvider 提供。
+// 两者使用独立的计数：携带应用凭证的请求先预占一次 IP 配额，应用认证通过后退还，应用配额可以高于单个 IP 的配额
+// （IP 配额已用完时仍会拒绝，认证失败的请求不退还，防止借伪造的凭证头绕过限流）。
+// 应用按 app_key 计数而不是 app_id：CheckSum 与 API Key 认证都会设置 app_key（rate_limit.apps 也以它为键），
+// 而 app_id 只有数据库中的应用才有。
+// 每个响应都会带上 X-RateLimit-* 头（按应用计数时为该应用的配额），方便客户端了解剩余配额
+type RateLimitMiddleware struct {
+	limit       int
+	window      time.Duration
+	provider    RateLimitProvider
+	credentials []func(ctx *web.Context) bool
+
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	nextSweep time.Time
+}
+
+// RateLimitProvider 按应用提供限流配额（每个窗口允许的请求数）
+type RateLimitProvider interface {
+	// AppLimit 返回应用的配额，未单独配置时返回 false（使用默认配额）
+	AppLimit(appKey string) (int, bool)
+}
+
+// StaticRateLimitProvider 固定配置的应用配额（app_key -> 每个窗口允许的请求数）
+type StaticRateLimitProvider map[string]int
+
+// AppLimit 返回应用的配额
+func (p StaticRateLimitProvider) AppLimit(appKey string) (int, bool) {
+	limit, ok := p[appKey]
+	return limit, ok && limit > 0
+}
+
+// rateWindow 单个客户端的计数窗口
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// ctxKeyRateReservation 携带应用凭证的请求预占的 IP 配额
+const ctxKeyRateReservation = "rate_limit_reservation"
+
+// rateReservation 预占的一次配额，应用认证通过后退还
+type rateReservation struct {
+	key      string
+	resetAt  time.Time
+	refunded bool
+}
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	Limit    int               // 每个窗口允许的请求数（默认配额）
+	Window   time.Duration     // 窗口时长
+	Provider RateLimitProvider // 按应用的配额，为 nil 时所有应用使用默认配额
+
+	// Credentials 判断请求是否携带应用凭证（如 CheckSumMiddleware.HasCredentials），携带时由 HandleApp 按应用计数
+	Credentials []func(ctx *web.Context) bool
+}
+
+// NewRateLimitMiddleware 创建限流中间件
+func NewRateLimitMiddleware(config *RateLimitConfig) *RateLimitMiddleware {
+	// 设置默认值
+	if config == nil {
+		config = &RateLimitConfig{}
+	}
+
+	if config.Limit <= 0 {
+		config.Limit = 100
+	}
+
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+
+	return &RateLimitMiddleware{
+		limit:       config.Limit,
+		window:      config.Window,
+		provider:    config.Provider,
+		credentials: config.Credentials,
+		windows:     make(map[string]*rateWindow),
+	}
+}
+
+// Handle 按客户端 IP 限流（全局中间件）
+// 携带应用凭证的请求同样先消耗一次 IP 配额，应用认证通过后由 HandleApp（或请求结束时）退还
+func (m *RateLimitMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		key := "ip:" + ctx.ClientIP()
+		remaining, resetAt, allowed := m.take(key, m.limit, time.Now())
+		if !m.allow(ctx, m.limit, remaining, resetAt, allowed) {
+			return
+		}
+		if !m.hasCredentials(ctx) {
+			ctx.Next()
+			return
+		}
+
+		reservation := &rateReservation{key: key, resetAt: resetAt}
+		ctx.Set(ctxKeyRateReservation, reservation)
+		ctx.Next()
+		// 路由没有挂 HandleApp 时，在请求结束后退还
+		if ctx.GetString(constants.CtxKeyAppKey) != "" {
+			m.refund(reservation)
+		}
+	}
+}
+
+// HandleApp 按应用限流，挂在 CheckSum / API Key 认证之后；没有认证的应用时不限流
+// 认证通过的请求先退还 Handle 预占的 IP 配额，再按应用计数
+func (m *RateLimitMiddleware) HandleApp() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		appKey := ctx.GetString(constants.CtxKeyAppKey)
+		if appKey == "" {
+			ctx.Next()
+			return
+		}
+		if v, ok := ctx.Get(ctxKeyRateReservation); ok {
+			m.refund(v.(*rateReservation))
+		}
+		m.limitBy(ctx, "app:"+appKey, m.appLimit(appKey))
+	}
+}
+
+// limitBy 为 key 消耗一次配额，超限时返回 429
+func (m *RateLimitMiddleware) limitBy(ctx *web.Context, key string, limit int) {
+	remaining, resetAt, allowed := m.take(key, limit, time.Now())
+	if m.allow(ctx, limit, remaining, resetAt, allowed) {
+		ctx.Next()
+	}
+}
+
+// allow 设置限流响应头，未被允许时返回 429 并中止请求
+func (m *RateLimitMiddleware) allow(ctx *web.Context, limit, remaining int, resetAt time.Time, allowed bool) bool {
+	// 在 Handler 写入响应体之前设置限流响应头
+	ctx.Header(constants.HeaderRateLimitLimit, strconv.Itoa(limit))
+	ctx.Header(constants.HeaderRateLimitRemaining, strconv.Itoa(remaining))
+	ctx.Header(constants.HeaderRateLimitReset, strconv.FormatInt(resetAt.Unix(), 10))
+
+	if !allowed {
+		retryAfter := int(time.Until(resetAt).Seconds()) + 1
+		ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+		web.Error(ctx, http.StatusTooManyRequests, http.StatusTooManyRequests, constants.MsgTooManyRequests)
+		ctx.Abort()
+		return false
+	}
+	return true
+}
+
+// hasCredentials 请求是否携带应用凭证
+func (m *RateLimitMiddleware) hasCredentials(ctx *web.Context) bool {
+	for _, has := range m.credentials {
+		if has(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// appLimit 返回应用的配额，未单独配置时使用默认配额
+func (m *RateLimitMiddleware) appLimit(appKey string) int {
+	if m.provider != nil {
+		if limit, ok := m.provider.AppLimit(appKey); ok {
+			return limit
+		}
+	}
+	return m.limit
+}
+
+// take 为 key 消耗一次配额
+// 返回剩余配额、窗口重置时间以及本次请求是否被允许
+func (m *RateLimitMiddleware) take(key string, limit int, now time.Time) (int, time.Time, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w := m.current(key, now)
+	if w.count >= limit {
+		return 0, w.resetAt, false
+	}
+
+	w.count++
+	return limit - w.count, w.resetAt, true
+}
+
+// refund 退还预占的配额（只退还一次；窗口已重置时不退还）
+func (m *RateLimitMiddleware) refund(r *rateReservation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.refunded {
+		return
+	}
+	r.refunded = true
+	if w, ok := m.windows[r.key]; ok && w.resetAt.Equal(r.resetAt) && w.count > 0 {
+		w.count--
+	}
+}
+
+// current 返回 key 当前的计数窗口，已过期时开始新窗口（调用方持有锁）
+func (m *RateLimitMiddleware) current(key string, now time.Time) *rateWindow {
+	m.sweep(now)
+
+	w, ok := m.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &rateWindow{resetAt: now.Add(m.window)}
+		m.windows[key] = w
+	}
+	return w
+}
+
+// sweep 定期清理已过期的窗口，避免内存无限增长
+func (m *RateLimitMiddleware) sweep(now time.Time) {
+	if now.Before(m.nextSweep) {
+		return
+	}
+	for key, w := range m.windows {
+		if !now.Before(w.resetAt) {
+			delete(m.windows, key)
+		}
+	}
+	m.nextSweep = now.Add(m.window)
+}
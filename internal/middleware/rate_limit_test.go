@@ -0,0 +1,261 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newRateLimitServer(t *testing.T, m *RateLimitMiddleware) *webtest.Server {
+	return webtest.New(t, func(r *gin.Engine) {
+		r.GET("/ping", web.ToGinHandler(func(ctx *web.Context) { web.Success(ctx, nil) }))
+	}, m.Handle())
+}
+
+func TestRateLimitHeadersDecrement(t *testing.T) {
+	s := newRateLimitServer(t, NewRateLimitMiddleware(&RateLimitConfig{Limit: 3, Window: time.Minute}))
+
+	var reset string
+	for i, want := range []string{"2", "1", "0"} {
+		w := s.Do(http.MethodGet, "/ping", nil)
+		s.AssertStatus(w, http.StatusOK)
+		if got := w.Header().Get(constants.HeaderRateLimitLimit); got != "3" {
+			t.Fatalf("request %d: %s = %q, want 3", i+1, constants.HeaderRateLimitLimit, got)
+		}
+		if got := w.Header().Get(constants.HeaderRateLimitRemaining); got != want {
+			t.Fatalf("request %d: %s = %q, want %s", i+1, constants.HeaderRateLimitRemaining, got, want)
+		}
+
+		got := w.Header().Get(constants.HeaderRateLimitReset)
+		if _, err := strconv.ParseInt(got, 10, 64); err != nil {
+			t.Fatalf("request %d: %s = %q, want unix seconds", i+1, constants.HeaderRateLimitReset, got)
+		}
+		if reset != "" && got != reset {
+			t.Fatalf("request %d: reset changed within window: %s -> %s", i+1, reset, got)
+		}
+		reset = got
+	}
+
+	w := s.Do(http.MethodGet, "/ping", nil)
+	s.AssertCode(w, http.StatusTooManyRequests)
+	if got := w.Header().Get(constants.HeaderRateLimitRemaining); got != "0" {
+		t.Fatalf("429: %s = %q, want 0", constants.HeaderRateLimitRemaining, got)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Fatal("429: missing Retry-After")
+	}
+}
+
+func TestRateLimitWindowResets(t *testing.T) {
+	m := NewRateLimitMiddleware(&RateLimitConfig{Limit: 1, Window: time.Minute})
+	now := time.Now()
+
+	if _, _, ok := m.take("ip:a", 1, now); !ok {
+		t.Fatal("first request rejected")
+	}
+	if _, _, ok := m.take("ip:a", 1, now.Add(time.Second)); ok {
+		t.Fatal("second request in window allowed")
+	}
+	if remaining, _, ok := m.take("ip:a", 1, now.Add(time.Minute)); !ok || remaining != 0 {
+		t.Fatalf("after window: allowed = %v, remaining = %d", ok, remaining)
+	}
+}
+
+// fakeAppAuth 模拟 CheckSum：带 app_key 请求头时认证通过（app_key 为 bad 时返回 401）
+func fakeAppAuth(ctx *web.Context) {
+	switch appKey := ctx.GetHeader(constants.HeaderAppKey); appKey {
+	case "":
+		web.Unauthorized(ctx, "missing checksum headers")
+		ctx.Abort()
+	case "bad":
+		web.Unauthorized(ctx, "invalid checksum")
+		ctx.Abort()
+	default:
+		ctx.Set(constants.CtxKeyAppKey, appKey)
+	}
+}
+
+// newAppRateLimitServer 与路由配置相同：全局按 IP 限流，写接口认证后按应用限流
+func newAppRateLimitServer(t *testing.T, ipLimit int, apps StaticRateLimitProvider) *webtest.Server {
+	m := NewRateLimitMiddleware(&RateLimitConfig{
+		Limit:    ipLimit,
+		Window:   time.Minute,
+		Provider: apps,
+		Credentials: []func(ctx *web.Context) bool{func(ctx *web.Context) bool {
+			return ctx.GetHeader(constants.HeaderAppKey) != ""
+		}},
+	})
+	return webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "")
+		g.GET("/ping", func(ctx *web.Context) { web.Success(ctx, nil) })
+		g.POST("/orders", fakeAppAuth, m.HandleApp(), func(ctx *web.Context) { web.Success(ctx, nil) })
+	}, m.Handle())
+}
+
+// doAs 以 appKey 身份发送请求（appKey 为空时不带凭证）
+func doAs(s *webtest.Server, appKey, method, path string) *httptest.ResponseRecorder {
+	s.Header.Del(constants.HeaderAppKey)
+	if appKey != "" {
+		s.Header.Set(constants.HeaderAppKey, appKey)
+	}
+	return s.Do(method, path, nil)
+}
+
+// 应用配额高于 IP 配额：同一 IP 上的应用请求按应用配额计数，不受 IP 配额限制
+func TestRateLimitAppQuotaAboveIPLimit(t *testing.T) {
+	s := newAppRateLimitServer(t, 2, StaticRateLimitProvider{"app-a": 5})
+
+	for i := 1; i <= 5; i++ {
+		w := doAs(s, "app-a", http.MethodPost, "/orders")
+		s.AssertCode(w, http.StatusOK)
+		if got := w.Header().Get(constants.HeaderRateLimitLimit); got != "5" {
+			t.Fatalf("request %d: %s = %q, want 5", i, constants.HeaderRateLimitLimit, got)
+		}
+		if got := w.Header().Get(constants.HeaderRateLimitRemaining); got != strconv.Itoa(5-i) {
+			t.Fatalf("request %d: %s = %q, want %d", i, constants.HeaderRateLimitRemaining, got, 5-i)
+		}
+	}
+	s.AssertCode(doAs(s, "app-a", http.MethodPost, "/orders"), http.StatusTooManyRequests)
+
+	// 应用请求没有占用 IP 配额
+	for i := 0; i < 2; i++ {
+		s.AssertCode(doAs(s, "", http.MethodGet, "/ping"), http.StatusOK)
+	}
+	s.AssertCode(doAs(s, "", http.MethodGet, "/ping"), http.StatusTooManyRequests)
+}
+
+func TestRateLimitAppsHaveIndependentBuckets(t *testing.T) {
+	s := newAppRateLimitServer(t, 100, StaticRateLimitProvider{"app-a": 2, "app-b": 3})
+
+	for i := 0; i < 2; i++ {
+		s.AssertCode(doAs(s, "app-a", http.MethodPost, "/orders"), http.StatusOK)
+	}
+	w := doAs(s, "app-a", http.MethodPost, "/orders")
+	s.AssertCode(w, http.StatusTooManyRequests)
+	if got := w.Header().Get(constants.HeaderRateLimitLimit); got != "2" {
+		t.Fatalf("app-a: %s = %q, want 2", constants.HeaderRateLimitLimit, got)
+	}
+
+	for i := 0; i < 3; i++ {
+		w := doAs(s, "app-b", http.MethodPost, "/orders")
+		s.AssertCode(w, http.StatusOK)
+		if got := w.Header().Get(constants.HeaderRateLimitLimit); got != "3" {
+			t.Fatalf("app-b: %s = %q, want 3", constants.HeaderRateLimitLimit, got)
+		}
+	}
+	s.AssertCode(doAs(s, "app-b", http.MethodPost, "/orders"), http.StatusTooManyRequests)
+
+	// 未单独配置的应用使用默认配额
+	w = doAs(s, "app-c", http.MethodPost, "/orders")
+	s.AssertCode(w, http.StatusOK)
+	if got := w.Header().Get(constants.HeaderRateLimitLimit); got != "100" {
+		t.Fatalf("app-c: %s = %q, want 100", constants.HeaderRateLimitLimit, got)
+	}
+}
+
+// 携带凭证但认证失败的请求计入 IP 配额，不能借凭证头绕过限流
+func TestRateLimitFailedAppAuthCountsAgainstIP(t *testing.T) {
+	s := newAppRateLimitServer(t, 2, nil)
+
+	for i := 0; i < 2; i++ {
+		s.AssertCode(doAs(s, "bad", http.MethodPost, "/orders"), http.StatusUnauthorized)
+	}
+	s.AssertCode(doAs(s, "bad", http.MethodPost, "/orders"), http.StatusTooManyRequests)
+	s.AssertCode(doAs(s, "", http.MethodGet, "/ping"), http.StatusTooManyRequests)
+}
+
+// newBlockingRateLimitServer 与 newAppRateLimitServer 相同，但 handler 阻塞到 release 关闭，用于并发请求
+// GET /slow 不做应用认证；POST /orders 认证后按应用限流
+func newBlockingRateLimitServer(t *testing.T, ipLimit int, apps StaticRateLimitProvider, entered chan<- struct{}, release <-chan struct{}) *webtest.Server {
+	m := NewRateLimitMiddleware(&RateLimitConfig{
+		Limit:    ipLimit,
+		Window:   time.Minute,
+		Provider: apps,
+		Credentials: []func(ctx *web.Context) bool{func(ctx *web.Context) bool {
+			return ctx.GetHeader(constants.HeaderAppKey) != ""
+		}},
+	})
+	block := func(ctx *web.Context) {
+		entered <- struct{}{}
+		<-release
+		web.Success(ctx, nil)
+	}
+	return webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "")
+		g.GET("/slow", block)
+		g.POST("/orders", fakeAppAuth, m.HandleApp(), block)
+	}, m.Handle())
+}
+
+// serveConcurrently 并发发送 n 个带 appKey 请求头的请求，返回在 release 之前就结束的请求的状态码
+func serveConcurrently(t *testing.T, s *webtest.Server, n int, appKey, method, path string, entered <-chan struct{}, release chan<- struct{}) (passed int, early []int) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	done := make(chan int, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set(constants.HeaderAppKey, appKey)
+			w := httptest.NewRecorder()
+			s.Engine.ServeHTTP(w, req)
+			done <- w.Code
+		}()
+	}
+
+	// 等到每个请求要么进入 handler，要么已经返回
+	timeout := time.After(5 * time.Second)
+	for passed+len(early) < n {
+		select {
+		case <-entered:
+			passed++
+		case code := <-done:
+			early = append(early, code)
+		case <-timeout:
+			t.Fatalf("timed out: %d requests in handler, %d finished", passed, len(early))
+		}
+	}
+	close(release)
+	wg.Wait()
+	return passed, early
+}
+
+// 并发的伪造凭证请求在进入 handler 之前就占用 IP 配额，不能同时超过 IP 配额
+func TestRateLimitForgedCredentialsConcurrent(t *testing.T) {
+	entered, release := make(chan struct{}, 20), make(chan struct{})
+	s := newBlockingRateLimitServer(t, 2, nil, entered, release)
+
+	passed, early := serveConcurrently(t, s, 20, "forged", http.MethodGet, "/slow", entered, release)
+	if passed != 2 {
+		t.Fatalf("%d requests reached the handler, want 2", passed)
+	}
+	for _, code := range early {
+		if code != http.StatusTooManyRequests {
+			t.Fatalf("rejected request status = %d, want 429", code)
+		}
+	}
+	// 没有通过应用认证，配额不退还
+	s.AssertCode(doAs(s, "", http.MethodGet, "/slow"), http.StatusTooManyRequests)
+}
+
+// 认证通过的应用请求在进入 handler 之前已退还 IP 配额，并发数不受 IP 配额限制
+func TestRateLimitAuthenticatedAppsConcurrent(t *testing.T) {
+	entered, release := make(chan struct{}, 5), make(chan struct{})
+	s := newBlockingRateLimitServer(t, 2, StaticRateLimitProvider{"app-a": 5}, entered, release)
+
+	passed, early := serveConcurrently(t, s, 5, "app-a", http.MethodPost, "/orders", entered, release)
+	if passed != 5 || len(early) != 0 {
+		t.Fatalf("%d requests reached the handler, %d finished early (%v); want all 5 in the handler", passed, len(early), early)
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/web"
+)
+
+// RecoveryMiddleware Panic 恢复中间件
+// 以 JSON 日志记录 panic 和调用栈，返回 500；开启 dumpGoroutines 时额外输出所有 goroutine 的调用栈
+type RecoveryMiddleware struct {
+	dumpGoroutines bool
+}
+
+// NewRecoveryMiddleware 创建 Panic 恢复中间件
+func NewRecoveryMiddleware(dumpGoroutines bool) *RecoveryMiddleware {
+	return &RecoveryMiddleware{dumpGoroutines: dumpGoroutines}
+}
+
+// Handle 处理 Panic
+func (m *RecoveryMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			// ctx.Request 在后续中间件中可能被替换（带上 RequestID 等日志字段），这里取最新的
+			log := logger.Ctx(ctx.Request.Context())
+			fields := []logger.Field{
+				logger.Any("panic", rec),
+				logger.String("method", ctx.Request.Method),
+				logger.String("path", ctx.Request.URL.Path),
+			}
+
+			// 客户端断开连接导致的写入失败，无需调用栈，也无法再写响应
+			if isBrokenPipe(rec) {
+				log.Warn("客户端连接已断开", fields...)
+				ctx.Abort()
+				return
+			}
+
+			fields = append(fields, logger.Stack("stack"))
+			if m.dumpGoroutines {
+				fields = append(fields, logger.AllStacks("goroutines"))
+			}
+			log.Error("请求处理发生 panic", fields...)
+
+			if ctx.Writer.Written() {
+				ctx.Abort() // 响应已开始发送，只能中断
+				return
+			}
+			web.InternalError(ctx, constants.MsgInternalError)
+			ctx.Abort()
+		}()
+
+		ctx.Next()
+	}
+}
+
+// isBrokenPipe 判断 panic 是否由客户端断开连接引起
+func isBrokenPipe(rec interface{}) bool {
+	err, ok := rec.(error)
+	if !ok {
+		return false
+	}
+	if errors.Is(err, http.ErrAbortHandler) {
+		return true
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var sysErr *os.SyscallError
+	if !errors.As(opErr, &sysErr) {
+		return false
+	}
+	msg := strings.ToLower(sysErr.Error())
+	return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-api-template/pkg/logger/logtest"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parkedGoroutineMarker 阻塞在此函数中的 goroutine 应出现在全量 dump 中
+func parkedGoroutineMarker(started chan<- struct{}, release <-chan struct{}) {
+	close(started)
+	<-release
+}
+
+func TestRecoveryGoroutineDump(t *testing.T) {
+	for _, dump := range []bool{true, false} {
+		logs := logtest.New(t)
+		started, release := make(chan struct{}), make(chan struct{})
+		go parkedGoroutineMarker(started, release)
+		<-started
+
+		s := webtest.New(t, func(r *gin.Engine) {
+			r.GET("/panic", web.ToGinHandler(func(ctx *web.Context) { panic("boom") }))
+		}, NewRecoveryMiddleware(dump).Handle())
+
+		s.AssertCode(s.Do(http.MethodGet, "/panic", nil), http.StatusInternalServerError)
+		close(release)
+
+		entries := logs.FilterMessage("请求处理发生 panic").All()
+		if len(entries) != 1 {
+			t.Fatalf("dump=%v: got %d panic logs, want 1", dump, len(entries))
+		}
+		fields := entries[0].ContextMap()
+		if fields["panic"] != "boom" {
+			t.Fatalf("dump=%v: panic = %v", dump, fields["panic"])
+		}
+		if stack, _ := fields["stack"].(string); !strings.Contains(stack, "TestRecoveryGoroutineDump") {
+			t.Fatalf("dump=%v: stack does not include the panicking handler:\n%s", dump, stack)
+		}
+
+		goroutines, ok := fields["goroutines"].(string)
+		if ok != dump {
+			t.Fatalf("dump=%v: goroutines field present = %v", dump, ok)
+		}
+		if dump && !strings.Contains(goroutines, "parkedGoroutineMarker") {
+			t.Fatalf("goroutine dump does not include other goroutines:\n%s", goroutines)
+		}
+	}
+}
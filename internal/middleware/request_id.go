@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"go-api-template/internal/constants"
+	"go-api-template/pkg/logger"
 	"go-api-template/pkg/web"
 
 	"github.com/gin-gonic/gin"
@@ -30,6 +31,11 @@ func (m *RequestIDMiddleware) Handle() web.HandlerFunc {
 		// 存入 Context，供后续使用
 		ctx.Set(constants.CtxKeyRequestID, requestID)
 		
+		// 附加到请求 context 的日志字段，Service/Repository 通过 logger.Ctx(ctx) 输出时自动带上
+		ctx.Request = ctx.Request.WithContext(logger.WithFields(ctx.Request.Context(),
+			logger.String(constants.LogFieldRequestID, requestID),
+		))
+		
 		// 将 RequestID 写入响应头，方便追踪
 		ctx.Header(constants.HeaderRequestID, requestID)
 		
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/timing"
+	"go-api-template/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimingMiddleware 响应耗时中间件
+// 设置 X-Response-Time（毫秒），可选输出 Server-Timing（db、cache 等阶段耗时）
+type TimingMiddleware struct {
+	serverTiming bool
+}
+
+// NewTimingMiddleware 创建响应耗时中间件
+func NewTimingMiddleware(serverTiming bool) *TimingMiddleware {
+	return &TimingMiddleware{serverTiming: serverTiming}
+}
+
+// Handle 统计响应耗时
+func (m *TimingMiddleware) Handle() web.HandlerFunc {
+	return func(ctx *web.Context) {
+		recorder := timing.NewRecorder()
+		ctx.Request = ctx.Request.WithContext(timing.WithRecorder(ctx.Request.Context(), recorder))
+
+		// 包装 ResponseWriter，在响应头发送前写入耗时
+		w := &timingWriter{
+			ResponseWriter: ctx.Writer,
+			start:          time.Now(),
+			recorder:       recorder,
+			serverTiming:   m.serverTiming,
+		}
+		ctx.Writer = w
+
+		ctx.Next()
+
+		// Handler 没有写响应体（如 204）时，在这里补上
+		w.writeTimingHeaders()
+	}
+}
+
+// timingWriter 在响应头发送前写入耗时头
+type timingWriter struct {
+	gin.ResponseWriter
+	start        time.Time
+	recorder     *timing.Recorder
+	serverTiming bool
+	done         bool
+}
+
+// writeTimingHeaders 写入耗时头（只写一次，响应头已发送后不再写）
+func (w *timingWriter) writeTimingHeaders() {
+	if w.done || w.ResponseWriter.Written() {
+		return
+	}
+	w.done = true
+
+	elapsed := timing.Milliseconds(time.Since(w.start))
+	w.Header().Set(constants.HeaderResponseTime, strconv.FormatFloat(elapsed, 'f', 2, 64))
+
+	if w.serverTiming {
+		if value := w.recorder.ServerTiming(); value != "" {
+			w.Header().Set(constants.HeaderServerTiming, value)
+		}
+	}
+}
+
+// WriteHeaderNow 发送响应头
+func (w *timingWriter) WriteHeaderNow() {
+	w.writeTimingHeaders()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write 写入响应体
+func (w *timingWriter) Write(data []byte) (int, error) {
+	w.writeTimingHeaders()
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString 写入字符串响应体
+func (w *timingWriter) WriteString(s string) (int, error) {
+	w.writeTimingHeaders()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush 刷新缓冲区
+func (w *timingWriter) Flush() {
+	w.writeTimingHeaders()
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/timing"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTimingServer(t *testing.T, serverTiming bool) *webtest.Server {
+	return webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "")
+		g.GET("/json", func(ctx *web.Context) {
+			stop := timing.Track(ctx.Request.Context(), "db")
+			time.Sleep(2 * time.Millisecond)
+			stop()
+			web.Success(ctx, nil)
+		})
+		g.GET("/empty", func(ctx *web.Context) { ctx.Status(http.StatusNoContent) })
+	}, NewTimingMiddleware(serverTiming).Handle())
+}
+
+func TestResponseTimeHeader(t *testing.T) {
+	s := newTimingServer(t, false)
+
+	for _, path := range []string{"/json", "/empty"} {
+		w := s.Do(http.MethodGet, path, nil)
+		value := w.Header().Get(constants.HeaderResponseTime)
+		ms, err := strconv.ParseFloat(value, 64)
+		if err != nil || ms < 0 {
+			t.Fatalf("%s: %s = %q, want non-negative number", path, constants.HeaderResponseTime, value)
+		}
+		if w.Header().Get(constants.HeaderServerTiming) != "" {
+			t.Fatalf("%s: Server-Timing set while disabled", path)
+		}
+	}
+}
+
+func TestServerTimingHeader(t *testing.T) {
+	s := newTimingServer(t, true)
+
+	w := s.Do(http.MethodGet, "/json", nil)
+	value := w.Header().Get(constants.HeaderServerTiming)
+	if !strings.HasPrefix(value, "db;dur=") {
+		t.Fatalf("%s = %q, want db phase", constants.HeaderServerTiming, value)
+	}
+	if _, err := strconv.ParseFloat(strings.TrimPrefix(value, "db;dur="), 64); err != nil {
+		t.Fatalf("%s = %q, want numeric duration", constants.HeaderServerTiming, value)
+	}
+}
+
+func TestTimingTrackWithoutRecorder(t *testing.T) {
+	// 没有 Recorder 的 context（如后台任务）不记录也不 panic
+	timing.Track(context.Background(), "db")()
+}
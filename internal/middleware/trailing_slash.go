@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// 末尾斜杠处理方式
+const (
+	TrailingSlashRedirect = "redirect" // 重定向到不带末尾斜杠的地址（GET/HEAD 301，其他方法 308 保留请求方法和请求体）
+	TrailingSlashRewrite  = "rewrite"  // 内部改写路径后继续处理，客户端无感知
+	TrailingSlashOff      = "off"      // 不处理，沿用 Gin 默认行为
+)
+
+// TrailingSlashMiddleware 末尾斜杠规范化
+// /api/v1/demos/ 与 /api/v1/demos 视为同一地址。需要在路由匹配之前处理，因此以 http.Handler 的形式包裹整个路由，
+// 而不是注册为 Gin 中间件；改写后的路径不存在时照常由 NoRoute 返回 404。
+// 路径中连续的斜杠合并为一个，重定向目标始终是本站地址（不会跳转到 //evil.com 这样的其他站点）
+type TrailingSlashMiddleware struct {
+	mode string
+}
+
+// NewTrailingSlashMiddleware 创建末尾斜杠规范化中间件
+func NewTrailingSlashMiddleware(mode string) *TrailingSlashMiddleware {
+	return &TrailingSlashMiddleware{mode: mode}
+}
+
+// Wrap 包裹路由
+func (m *TrailingSlashMiddleware) Wrap(next http.Handler) http.Handler {
+	if m.mode == TrailingSlashOff {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		if len(path) <= 1 || !strings.HasSuffix(path, "/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		canonical := canonicalPath(path)
+		rawCanonical := canonicalPath(r.URL.RawPath)
+
+		// 以 "/\" 开头的地址会被浏览器当作 "//host"（协议相对地址），不能作为重定向目标，改为内部改写
+		if m.mode == TrailingSlashRewrite || !isLocalPath(canonical) {
+			r.URL.Path = canonical
+			r.URL.RawPath = rawCanonical
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		target := *r.URL
+		target.Path = canonical
+		target.RawPath = rawCanonical
+		status := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+		http.Redirect(w, r, target.RequestURI(), status)
+	})
+}
+
+// canonicalPath 合并连续的斜杠并去掉末尾斜杠，结果以且只以一个 "/" 开头（空路径原样返回）
+// 如 //evil.com/ 得到 /evil.com，避免重定向到 //evil.com（协议相对地址，即其他站点）
+func canonicalPath(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(path))
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && i > 0 && path[i-1] == '/' {
+			continue
+		}
+		sb.WriteByte(path[i])
+	}
+
+	canonical := strings.TrimRight(sb.String(), "/")
+	if canonical == "" {
+		return "/"
+	}
+	return canonical
+}
+
+// isLocalPath 判断路径作为 Location 时是否指向本站（以 "/" 开头，且第二个字符不是 "/" 或 "\"）
+func isLocalPath(path string) bool {
+	if !strings.HasPrefix(path, "/") {
+		return false
+	}
+	return len(path) == 1 || (path[1] != '/' && path[1] != '\\')
+}
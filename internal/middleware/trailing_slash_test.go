@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTrailingSlashHandler 包裹一个记录最终路径的 Handler：只有 /api/v1/demos 返回 200，其他路径 404
+func newTrailingSlashHandler(mode string) (http.Handler, *string) {
+	var seen string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.URL.Path
+		if r.URL.Path != "/api/v1/demos" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	return NewTrailingSlashMiddleware(mode).Wrap(next), &seen
+}
+
+func serveTrailingSlash(h http.Handler, method, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func TestTrailingSlashRedirect(t *testing.T) {
+	h, _ := newTrailingSlashHandler(TrailingSlashRedirect)
+
+	tests := []struct {
+		method   string
+		target   string
+		status   int
+		location string
+	}{
+		{http.MethodGet, "/api/v1/demos/?page=2", http.StatusMovedPermanently, "/api/v1/demos?page=2"},
+		{http.MethodHead, "/api/v1/demos//", http.StatusMovedPermanently, "/api/v1/demos"},
+		{http.MethodPost, "/api/v1/demos/", http.StatusPermanentRedirect, "/api/v1/demos"},
+		{http.MethodGet, "/api//v1/demos/", http.StatusMovedPermanently, "/api/v1/demos"},
+		// 开头的多个斜杠合并，不会跳转到其他站点
+		{http.MethodGet, "//evil.com/", http.StatusMovedPermanently, "/evil.com"},
+		{http.MethodGet, "///evil.com//path/", http.StatusMovedPermanently, "/evil.com/path"},
+		{http.MethodGet, "/%2F%2Fevil.com/", http.StatusMovedPermanently, "/evil.com"},
+		{http.MethodGet, "/api/v1/demos", http.StatusOK, ""},
+		{http.MethodGet, "/", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		w := serveTrailingSlash(h, tt.method, tt.target)
+		if w.Code != tt.status || w.Header().Get("Location") != tt.location {
+			t.Fatalf("%s %s: status = %d, Location = %q, want %d %q",
+				tt.method, tt.target, w.Code, w.Header().Get("Location"), tt.status, tt.location)
+		}
+	}
+}
+
+func TestTrailingSlashRedirectBackslashHost(t *testing.T) {
+	h, seen := newTrailingSlashHandler(TrailingSlashRedirect)
+
+	// /\evil.com 会被浏览器当作 //evil.com：不重定向，改写后由路由处理
+	for _, target := range []string{"/%5Cevil.com/", "//%5Cevil.com/"} {
+		w := serveTrailingSlash(h, http.MethodGet, target)
+		if w.Code != http.StatusNotFound || w.Header().Get("Location") != "" {
+			t.Fatalf("%s: status = %d, Location = %q, want 404 without redirect", target, w.Code, w.Header().Get("Location"))
+		}
+		if *seen != `/\evil.com` {
+			t.Fatalf("%s: routed path = %q, want /\\evil.com", target, *seen)
+		}
+	}
+}
+
+func TestTrailingSlashRewrite(t *testing.T) {
+	h, seen := newTrailingSlashHandler(TrailingSlashRewrite)
+
+	for _, target := range []string{"/api/v1/demos/", "/api/v1/demos///", "//api/v1/demos/"} {
+		w := serveTrailingSlash(h, http.MethodPost, target)
+		if w.Code != http.StatusOK || w.Header().Get("Location") != "" {
+			t.Fatalf("%s: status = %d, Location = %q, want 200 without redirect", target, w.Code, w.Header().Get("Location"))
+		}
+		if *seen != "/api/v1/demos" {
+			t.Fatalf("%s: routed path = %q", target, *seen)
+		}
+	}
+
+	if w := serveTrailingSlash(h, http.MethodGet, "//evil.com/"); w.Code != http.StatusNotFound || *seen != "/evil.com" {
+		t.Fatalf("//evil.com/: status = %d, routed path = %q", w.Code, *seen)
+	}
+}
+
+func TestTrailingSlashOff(t *testing.T) {
+	h, seen := newTrailingSlashHandler(TrailingSlashOff)
+
+	if w := serveTrailingSlash(h, http.MethodGet, "/api/v1/demos/"); w.Code != http.StatusNotFound || *seen != "/api/v1/demos/" {
+		t.Fatalf("status = %d, routed path = %q, want untouched", w.Code, *seen)
+	}
+}
+
+func TestCanonicalPath(t *testing.T) {
+	tests := map[string]string{
+		"":                 "",
+		"/":                "/",
+		"///":              "/",
+		"/a/":              "/a",
+		"//a//b//":         "/a/b",
+		"/%2F%2Fevil.com/": "/%2F%2Fevil.com",
+	}
+	for in, want := range tests {
+		if got := canonicalPath(in); got != want {
+			t.Fatalf("canonicalPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package model
+
+// 应用状态
+const (
+	AppStatusRevoked = 0 // 已注销
+	AppStatusActive  = 1 // 正常
+)
+
+// App 接入应用（CheckSum 签名认证、API Key 认证）
+type App struct {
+	BaseModel
+	AppKey    string `json:"app_key" gorm:"type:varchar(64);not null;uniqueIndex:uk_app_key"`
+	Name      string `json:"name" gorm:"type:varchar(100);not null"`
+	Secret    string `json:"-" gorm:"type:varchar(128);not null"`
+	Status    int    `json:"status" gorm:"default:1;comment:状态 1-正常 0-已注销"`
+	ExpiresAt *Time  `json:"expires_at" gorm:"comment:过期时间，为空表示长期有效"`
+
+	RequireSequence bool `json:"require_sequence" gorm:"not null;default:false;comment:是否要求严格递增的请求序号"`
+
+	// APIKeyHash API Key 的 SHA-256（security.HashAPIKey），为空表示不能使用 API Key 认证
+	APIKeyHash *string `json:"-" gorm:"type:char(64);uniqueIndex:uk_api_key_hash;comment:API Key 的 SHA-256"`
+}
+
+// TableName 指定表名
+func (App) TableName() string {
+	return "apps"
+}
@@ -0,0 +1,8 @@
+package model
+
+// BaseModel 基础字段（主键和创建/更新时间），嵌入到其他模型中使用
+type BaseModel struct {
+	ID        uint `json:"id" gorm:"primaryKey"`
+	CreatedAt Time `json:"created_at"`
+	UpdatedAt Time `json:"updated_at"`
+}
@@ -1,18 +1,31 @@
 package model
 
-import "time"
+import "go-api-template/pkg/errors"
+
+// Demo 状态
+const (
+	DemoStatusDisabled = 0 // 禁用
+	DemoStatusEnabled  = 1 // 启用
+)
 
 // Demo 演示模型
 type Demo struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Title     string    `json:"title" gorm:"type:varchar(200);not null"`
-	Content   string    `json:"content" gorm:"type:text"`
-	Status    int       `json:"status" gorm:"default:1;comment:状态 1-启用 0-禁用"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	BaseModel
+	Title   string `json:"title" gorm:"type:varchar(200);not null;uniqueIndex:uk_title"`
+	Content string `json:"content" gorm:"type:text"`
+	// Status 不设置 gorm 默认值：GORM 插入时会用默认值替换零值，导致无法创建禁用（0）的记录；未指定时由调用方设为启用
+	Status int `json:"status" gorm:"comment:状态 1-启用 0-禁用"`
 }
 
 // TableName 指定表名
 func (Demo) TableName() string {
 	return "demos"
 }
+
+// CheckDemoStatus 检查状态是否为允许的值（DemoStatusDisabled / DemoStatusEnabled），否则返回 errors.ErrInvalidParams
+func CheckDemoStatus(status int) error {
+	if status != DemoStatusDisabled && status != DemoStatusEnabled {
+		return errors.Wrapf(errors.ErrInvalidParams, "invalid demo status: %d", status)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"testing"
+
+	"go-api-template/pkg/errors"
+)
+
+func TestCheckDemoStatus(t *testing.T) {
+	for _, status := range []int{DemoStatusDisabled, DemoStatusEnabled} {
+		if err := CheckDemoStatus(status); err != nil {
+			t.Fatalf("CheckDemoStatus(%d) = %v", status, err)
+		}
+	}
+	for _, status := range []int{-1, 2, 5} {
+		if err := CheckDemoStatus(status); !errors.Is(err, errors.ErrInvalidParams) {
+			t.Fatalf("CheckDemoStatus(%d) = %v, want ErrInvalidParams", status, err)
+		}
+	}
+}
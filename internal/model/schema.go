@@ -0,0 +1,5 @@
+package model
+
+// SchemaVersion 当前代码期望的数据库迁移版本（迁移版本表中的 version）
+// 新增迁移时同步修改；大于 0 时就绪检查会校验数据库版本，不一致时返回 503。0 表示不校验
+const SchemaVersion uint64 = 0
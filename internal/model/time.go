@@ -0,0 +1,76 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// utcPolicy 时间策略：开启后 Time 序列化为 UTC（见 SetUTC）
+var utcPolicy atomic.Bool
+
+// SetUTC 设置时间策略，开启后 Time 统一以 UTC 输出（RFC3339，带 Z 后缀），不受服务器时区影响
+// 应在路由注册前调用；存储时区由 database.utc 控制（见 database.NewMySQLDB）
+func SetUTC(enabled bool) {
+	utcPolicy.Store(enabled)
+}
+
+// Time 模型时间类型，JSON 按时间策略输出 RFC3339，数据库读写与 time.Time 一致
+type Time time.Time
+
+// Now 当前时间
+func Now() Time {
+	return Time(time.Now())
+}
+
+// Time 转换为 time.Time（开启 UTC 策略时为 UTC 时间）
+func (t Time) Time() time.Time {
+	if utcPolicy.Load() {
+		return time.Time(t).UTC()
+	}
+	return time.Time(t)
+}
+
+// IsZero 是否为零值
+func (t Time) IsZero() bool {
+	return time.Time(t).IsZero()
+}
+
+// String 实现 fmt.Stringer
+func (t Time) String() string {
+	return t.Time().String()
+}
+
+// MarshalJSON 输出 RFC3339 格式
+func (t Time) MarshalJSON() ([]byte, error) {
+	return t.Time().MarshalJSON()
+}
+
+// UnmarshalJSON 解析 RFC3339 格式
+func (t *Time) UnmarshalJSON(data []byte) error {
+	var tt time.Time
+	if err := tt.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*t = Time(tt)
+	return nil
+}
+
+// Value 实现 driver.Valuer（写入时由驱动按连接时区转换）
+func (t Time) Value() (driver.Value, error) {
+	return time.Time(t), nil
+}
+
+// Scan 实现 sql.Scanner
+func (t *Time) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case time.Time:
+		*t = Time(v)
+	case nil:
+		*t = Time{}
+	default:
+		return fmt.Errorf("model.Time: 不支持的类型 %T", src)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useLocal 在测试期间把服务器时区设置为 loc
+func useLocal(t *testing.T, loc *time.Location) {
+	t.Helper()
+
+	prev := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = prev })
+}
+
+// useUTC 在测试期间设置时间策略
+func useUTC(t *testing.T, enabled bool) {
+	t.Helper()
+
+	SetUTC(enabled)
+	t.Cleanup(func() { SetUTC(false) })
+}
+
+func TestCreatedAtSerializesWithZSuffix(t *testing.T) {
+	for _, loc := range []*time.Location{time.FixedZone("CST", 8*3600), time.FixedZone("PDT", -7*3600), time.UTC} {
+		useLocal(t, loc)
+		useUTC(t, true)
+
+		demo := Demo{BaseModel: BaseModel{ID: 1, CreatedAt: Now(), UpdatedAt: Now()}}
+		data, err := json.Marshal(demo)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+
+		var out struct {
+			CreatedAt string `json:"created_at"`
+		}
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatalf("unmarshal %s: %v", data, err)
+		}
+		if !strings.HasSuffix(out.CreatedAt, "Z") {
+			t.Fatalf("server zone %s: created_at = %q, want Z suffix", loc, out.CreatedAt)
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, out.CreatedAt)
+		if err != nil || !parsed.Equal(demo.CreatedAt.Time()) {
+			t.Fatalf("created_at %q does not round-trip: %v", out.CreatedAt, err)
+		}
+	}
+}
+
+func TestTimeKeepsLocalZoneWithoutUTCPolicy(t *testing.T) {
+	useLocal(t, time.FixedZone("CST", 8*3600))
+	useUTC(t, false)
+
+	data, err := json.Marshal(Now())
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.HasSuffix(strings.Trim(string(data), `"`), "+08:00") {
+		t.Fatalf("time = %s, want +08:00 offset", data)
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go-api-template/internal/model"
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/security"
+
+	"gorm.io/gorm"
+)
+
+// 编译时检查：AppRepository 可作为 CheckSum 的应用凭证来源和 API Key 来源
+var (
+	_ security.AppSecretProvider = (*AppRepository)(nil)
+	_ security.APIKeyProvider    = (*AppRepository)(nil)
+)
+
+// AppRepository 接入应用数据访问层
+type AppRepository struct {
+	*database.BaseRepository
+}
+
+// NewAppRepository 创建 App Repository
+func NewAppRepository(db *gorm.DB) *AppRepository {
+	return &AppRepository{BaseRepository: database.NewBaseRepository(db)}
+}
+
+// FindByAppKey 根据 app_key 查询应用
+func (r *AppRepository) FindByAppKey(ctx context.Context, appKey string) (*model.App, error) {
+	var app model.App
+	err := r.BaseRepository.FindOne(ctx, &app, "app_key = ?", appKey)
+	if errors.Is(err, errors.ErrNotFound) {
+		return nil, errors.Wrapf(errors.ErrAppNotFound, "app_key: %s", appKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// AppCredential 查询应用凭证（实现 security.AppSecretProvider，通常再包一层 security.CachedAppSecretProvider）
+func (r *AppRepository) AppCredential(ctx context.Context, appKey string) (*security.AppCredential, error) {
+	app, err := r.FindByAppKey(ctx, appKey)
+	if err != nil {
+		return nil, err
+	}
+
+	cred := &security.AppCredential{
+		AppKey:  app.AppKey,
+		Secret:  app.Secret,
+		Revoked: app.Status == model.AppStatusRevoked,
+
+		RequireSequence: app.RequireSequence,
+	}
+	if app.ExpiresAt != nil {
+		expiresAt := time.Time(*app.ExpiresAt)
+		cred.ExpiresAt = &expiresAt
+	}
+	return cred, nil
+}
+
+// APIKeyIdentity 按 API Key 查询应用（实现 security.APIKeyProvider，通常再包一层 security.CachedAPIKeyProvider）
+// 表中只保存 API Key 的哈希，按哈希查询
+func (r *AppRepository) APIKeyIdentity(ctx context.Context, apiKey string) (*security.APIKeyIdentity, error) {
+	var app model.App
+	err := r.BaseRepository.FindOne(ctx, &app, "api_key_hash = ?", security.HashAPIKey(apiKey))
+	if errors.Is(err, errors.ErrNotFound) {
+		return nil, errors.Wrap(errors.ErrAppNotFound, "unknown api key")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &security.APIKeyIdentity{
+		AppID:   strconv.FormatUint(uint64(app.ID), 10),
+		AppKey:  app.AppKey,
+		AppName: app.Name,
+		Revoked: app.Status == model.AppStatusRevoked,
+	}
+	if app.ExpiresAt != nil {
+		expiresAt := time.Time(*app.ExpiresAt)
+		identity.ExpiresAt = &expiresAt
+	}
+	return identity, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-template/internal/model"
+	"go-api-template/pkg/cache"
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/security"
+
+	"gorm.io/gorm"
+)
+
+// newCachedAppSecrets 数据库应用凭证 + 内存缓存（与 provideAppSecrets 的 database 模式相同），queries 记录数据库查询次数
+func newCachedAppSecrets(t *testing.T, apps ...*model.App) (*security.CachedAppSecretProvider, *gorm.DB, *int) {
+	t.Helper()
+
+	db := dbtest.Open(t, &model.App{})
+	for _, app := range apps {
+		if err := db.Create(app).Error; err != nil {
+			t.Fatalf("seed app: %v", err)
+		}
+	}
+	queries := 0
+	db.Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) { queries++ })
+
+	manager, err := cache.NewCacheManager(&config.Config{Cache: config.CacheConfig{Driver: string(cache.DriverMemory), TTL: 60}}, nil)
+	if err != nil {
+		t.Fatalf("NewCacheManager: %v", err)
+	}
+	return security.NewCachedAppSecretProvider(NewAppRepository(db), cache.NewCacheFacade(manager), time.Minute), db, &queries
+}
+
+func TestCachedAppSecretsHitDatabaseOnce(t *testing.T) {
+	ctx := context.Background()
+	p, _, queries := newCachedAppSecrets(t, &model.App{AppKey: "app-1", Name: "one", Secret: "s1", Status: model.AppStatusActive})
+
+	for i := 0; i < 5; i++ {
+		secret, err := security.ResolveAppSecret(ctx, p, "app-1")
+		if err != nil || secret != "s1" {
+			t.Fatalf("validation %d: secret = %q, err = %v", i+1, secret, err)
+		}
+	}
+	if *queries != 1 {
+		t.Fatalf("%d database queries for 5 validations, want 1", *queries)
+	}
+
+	// Invalidate 后重新查询数据库
+	if err := p.Invalidate(ctx, "app-1"); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	if _, err := security.ResolveAppSecret(ctx, p, "app-1"); err != nil {
+		t.Fatalf("after invalidate: %v", err)
+	}
+	if *queries != 2 {
+		t.Fatalf("%d database queries after invalidate, want 2", *queries)
+	}
+}
+
+func TestCachedAppSecretsRevokedAndExpired(t *testing.T) {
+	ctx := context.Background()
+	expired := model.Time(time.Now().Add(-time.Hour))
+	p, db, queries := newCachedAppSecrets(t,
+		&model.App{AppKey: "revoked", Name: "revoked", Secret: "s", Status: model.AppStatusActive},
+		&model.App{AppKey: "expired", Name: "expired", Secret: "s", Status: model.AppStatusActive, ExpiresAt: &expired},
+	)
+	// Status 默认值为 1，注销状态需单独更新
+	if err := db.Model(&model.App{}).Where("app_key = ?", "revoked").Update("status", model.AppStatusRevoked).Error; err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := security.ResolveAppCredential(ctx, p, "revoked"); !errors.Is(err, errors.ErrAppRevoked) {
+			t.Fatalf("revoked: err = %v, want ErrAppRevoked", err)
+		}
+		if _, err := security.ResolveAppCredential(ctx, p, "expired"); !errors.Is(err, errors.ErrAppExpired) {
+			t.Fatalf("expired: err = %v, want ErrAppExpired", err)
+		}
+	}
+	// 注销、过期的凭证同样缓存，重复校验不再查询数据库
+	if *queries != 2 {
+		t.Fatalf("%d database queries, want 2", *queries)
+	}
+
+	// 应用不存在时不缓存
+	for i := 0; i < 2; i++ {
+		if _, err := security.ResolveAppCredential(ctx, p, "missing"); !errors.Is(err, errors.ErrAppNotFound) {
+			t.Fatalf("missing: err = %v, want ErrAppNotFound", err)
+		}
+	}
+	if *queries != 4 {
+		t.Fatalf("%d database queries after two misses, want 4", *queries)
+	}
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"go-api-template/internal/model"
 	"go-api-template/pkg/database"
@@ -10,10 +12,37 @@ import (
 	"gorm.io/gorm"
 )
 
+// DemoRepositoryInterface Demo 数据访问接口
+// Service 层依赖此接口而非具体实现，单元测试时可替换为不依赖数据库的 fake/mock
+type DemoRepositoryInterface interface {
+	FindByID(ctx context.Context, id uint) (*model.Demo, error)
+	FindAll(ctx context.Context, filters database.FilterSet, sort database.Sort) ([]*model.Demo, error)
+	FindPage(ctx context.Context, page, pageSize int, sort database.Sort) ([]*model.Demo, int64, error)
+	StreamAll(ctx context.Context, fn func(*model.Demo) error) error
+	Create(ctx context.Context, demo *model.Demo) error
+	CreateBatch(ctx context.Context, demos []*model.Demo) error
+	Update(ctx context.Context, demo *model.Demo) error
+	UpdateFields(ctx context.Context, id uint, updates map[string]interface{}) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// demoDefaultSort 分页和搜索的默认排序：创建时间倒序
+var demoDefaultSort = database.SortField{Column: "created_at", Desc: true}
+
+// demoListCacheTTL Demo 列表查询结果的缓存时间（database.query_cache 开启时生效）
+const demoListCacheTTL = 10 * time.Second
+
+// demoUpdateChunkSize 批量更新时每条 UPDATE 包含的 ID 数量上限
+const demoUpdateChunkSize = 500
+
+// 编译时检查：DemoRepository 实现了 DemoRepositoryInterface
+var _ DemoRepositoryInterface = (*DemoRepository)(nil)
+
 // DemoRepository Demo 数据访问层
 type DemoRepository struct {
 	*database.BaseRepository // 嵌入 BaseRepository，复用基础方法
 	db                       *gorm.DB
+	fullText                 *database.FullText // 标题、内容全文检索
 }
 
 // NewDemoRepository 创建 Demo Repository
@@ -21,27 +50,33 @@ func NewDemoRepository(db *gorm.DB) *DemoRepository {
 	return &DemoRepository{
 		BaseRepository: database.NewBaseRepository(db),
 		db:             db,
+		fullText:       database.NewFullText(db, model.Demo{}.TableName(), "title", "content"),
 	}
 }
 
 // ========== 使用 BaseRepository 的通用方法 ==========
 
 // FindByID 根据 ID 查询（使用基类方法）
+// 同一请求内的重复查询只访问一次数据库（请求级 Loader，见 database.Load）
 func (r *DemoRepository) FindByID(ctx context.Context, id uint) (*model.Demo, error) {
-	var demo model.Demo
-	err := r.BaseRepository.FindByID(ctx, id, &demo)
+	demo, err := database.Load(ctx, demoLoaderKey(id), func() (model.Demo, error) {
+		var demo model.Demo
+		err := r.BaseRepository.FindByID(ctx, id, &demo)
+		return demo, err
+	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "demo not found, id: %d", id)
 	}
-	return &demo, nil
+	return &demo, nil // 返回副本，调用方修改不影响 Loader 中的结果
 }
 
 // FindAll 查询所有（使用基类方法）
-func (r *DemoRepository) FindAll(ctx context.Context) ([]*model.Demo, error) {
+// 按 filters 筛选，sort 为空时按数据库默认顺序返回；结果缓存 demoListCacheTTL，写入 demos 表后失效
+func (r *DemoRepository) FindAll(ctx context.Context, filters database.FilterSet, sort database.Sort) ([]*model.Demo, error) {
 	var demos []*model.Demo
-	err := r.BaseRepository.FindAll(ctx, &demos, "1 = 1") // 查询所有
+	err := sort.Apply(r.DB(ctx).Scopes(filters.Scope(), database.CacheFor(demoListCacheTTL))).Find(&demos).Error
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "query all failed")
 	}
 	return demos, nil
 }
@@ -51,13 +86,30 @@ func (r *DemoRepository) Create(ctx context.Context, demo *model.Demo) error {
 	return r.BaseRepository.Create(ctx, demo)
 }
 
+// CreateBatch 批量创建（一条多行 INSERT）
+func (r *DemoRepository) CreateBatch(ctx context.Context, demos []*model.Demo) error {
+	if len(demos) == 0 {
+		return nil
+	}
+	return r.BaseRepository.CreateInBatches(ctx, demos, len(demos))
+}
+
 // Update 更新（使用基类方法）
 func (r *DemoRepository) Update(ctx context.Context, demo *model.Demo) error {
+	defer r.forget(ctx, demo.ID)
 	return r.BaseRepository.Update(ctx, demo)
 }
 
+// UpdateFields 更新指定字段（使用基类方法）
+func (r *DemoRepository) UpdateFields(ctx context.Context, id uint, updates map[string]interface{}) error {
+	defer r.forget(ctx, id)
+	_, err := r.BaseRepository.UpdateFields(ctx, &model.Demo{}, "id = ?", updates, id)
+	return err
+}
+
 // Delete 删除（使用基类方法）
 func (r *DemoRepository) Delete(ctx context.Context, id uint) error {
+	defer r.forget(ctx, id)
 	return r.BaseRepository.Delete(ctx, &model.Demo{}, id)
 }
 
@@ -67,7 +119,7 @@ func (r *DemoRepository) Delete(ctx context.Context, id uint) error {
 func (r *DemoRepository) FindByStatus(ctx context.Context, status int) ([]*model.Demo, error) {
 	var demos []*model.Demo
 	// 直接使用 GORM，保留灵活性
-	err := r.db.WithContext(ctx).
+	err := r.DB(ctx).
 		Where("status = ?", status).
 		Order("created_at DESC").
 		Find(&demos).Error
@@ -77,19 +129,36 @@ func (r *DemoRepository) FindByStatus(ctx context.Context, status int) ([]*model
 	return demos, nil
 }
 
-// FindPage 分页查询（使用基类方法）
-func (r *DemoRepository) FindPage(ctx context.Context, page, pageSize int) ([]*model.Demo, int64, error) {
+// FindPage 分页查询，sort 为空时按创建时间倒序
+func (r *DemoRepository) FindPage(ctx context.Context, page, pageSize int, sort database.Sort) ([]*model.Demo, int64, error) {
 	var demos []*model.Demo
-	total, err := r.BaseRepository.FindPage(ctx, &demos, page, pageSize, "1 = 1")
+	var total int64
+
+	query := r.DB(ctx).Model(&model.Demo{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "count failed")
+	}
+
+	offset := (page - 1) * pageSize
+	err := sort.Apply(query.Offset(offset).Limit(pageSize), demoDefaultSort).Find(&demos).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, errors.Wrap(err, "query page failed")
 	}
 	return demos, total, nil
 }
 
 // UpdateStatus 更新状态（使用基类方法）
+// 状态不合法时返回 ErrInvalidParams，没有匹配到该 ID 时返回 ErrNotFound
 func (r *DemoRepository) UpdateStatus(ctx context.Context, id uint, status int) error {
-	return r.BaseRepository.UpdateColumn(ctx, &model.Demo{}, "id = ?", "status", status, id)
+	if err := model.CheckDemoStatus(status); err != nil {
+		return err
+	}
+	defer r.forget(ctx, id)
+	err := database.MustAffect(r.BaseRepository.UpdateColumn(ctx, &model.Demo{}, "id = ?", "status", status, id))
+	if errors.Is(err, errors.ErrNoRowsAffected) {
+		return errors.Wrapf(errors.ErrNotFound, "demo not found, id: %d", id)
+	}
+	return err
 }
 
 // CountByStatus 统计指定状态的数量（使用基类方法）
@@ -97,6 +166,22 @@ func (r *DemoRepository) CountByStatus(ctx context.Context, status int) (int64,
 	return r.BaseRepository.Count(ctx, &model.Demo{}, "status = ?", status)
 }
 
+// DemoStatusCount 按状态分组的数量
+type DemoStatusCount struct {
+	Status int   `json:"status"`
+	Count  int64 `json:"count"`
+}
+
+// CountGroupedByStatus 按状态分组统计数量（使用基类方法），按状态升序，没有记录的状态不返回
+func (r *DemoRepository) CountGroupedByStatus(ctx context.Context) ([]DemoStatusCount, error) {
+	var counts []DemoStatusCount
+	err := r.BaseRepository.Aggregate(ctx, &model.Demo{}, "status", map[string]string{"count": "COUNT(*)"}, &counts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
 // ExistsByTitle 检查标题是否存在（使用基类方法）
 func (r *DemoRepository) ExistsByTitle(ctx context.Context, title string) (bool, error) {
 	return r.BaseRepository.Exists(ctx, &model.Demo{}, "title = ?", title)
@@ -104,13 +189,16 @@ func (r *DemoRepository) ExistsByTitle(ctx context.Context, title string) (bool,
 
 // ========== 高级查询（直接使用 GORM，展示灵活性）==========
 
-// Search 搜索（支持多条件）
-func (r *DemoRepository) Search(ctx context.Context, keyword string, status *int, page, pageSize int) ([]*model.Demo, int64, error) {
+// Search 搜索（支持多条件），sort 为空时按创建时间倒序
+func (r *DemoRepository) Search(ctx context.Context, keyword string, status *int, page, pageSize int, sort database.Sort) ([]*model.Demo, int64, error) {
 	var demos []*model.Demo
 	var total int64
 
+	// 查询耗时指标按 demo.search 统计
+	ctx = database.WithOperation(ctx, "demo.search")
+
 	// 构建查询（直接使用 GORM 的链式调用）
-	query := r.db.WithContext(ctx).Model(&model.Demo{})
+	query := r.DB(ctx).Model(&model.Demo{})
 
 	// 关键词搜索
 	if keyword != "" {
@@ -129,7 +217,7 @@ func (r *DemoRepository) Search(ctx context.Context, keyword string, status *int
 
 	// 分页查询
 	offset := (page - 1) * pageSize
-	err := query.Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&demos).Error
+	err := sort.Apply(query.Offset(offset).Limit(pageSize), demoDefaultSort).Find(&demos).Error
 	if err != nil {
 		return nil, 0, errors.Wrap(err, "search failed")
 	}
@@ -137,12 +225,81 @@ func (r *DemoRepository) Search(ctx context.Context, keyword string, status *int
 	return demos, total, nil
 }
 
+// SearchFullText 按标题、内容全文检索，结果按相关度倒序
+// 使用数据库的全文索引（MySQL FULLTEXT / PostgreSQL GIN，见 database.FullText），没有索引时退回 LIKE，按创建时间倒序
+func (r *DemoRepository) SearchFullText(ctx context.Context, keyword string, page, pageSize int) ([]*model.Demo, int64, error) {
+	var demos []*model.Demo
+	var total int64
+
+	ctx = database.WithOperation(ctx, "demo.search_fulltext")
+	query := r.DB(ctx).Model(&model.Demo{})
+
+	ranked := false
+	if keyword != "" {
+		var err error
+		if query, ranked, err = r.fullText.Match(ctx, query, keyword); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "count fulltext search results failed")
+	}
+
+	query = query.Offset((page - 1) * pageSize).Limit(pageSize)
+	if !ranked {
+		query = database.Sort{demoDefaultSort}.Apply(query)
+	}
+	if err := query.Find(&demos).Error; err != nil {
+		return nil, 0, errors.Wrap(err, "fulltext search failed")
+	}
+
+	return demos, total, nil
+}
+
+// StreamAll 逐行遍历所有记录（直接使用 GORM Rows，不一次性加载到内存）
+// fn 返回错误或 ctx 取消时停止遍历
+func (r *DemoRepository) StreamAll(ctx context.Context, fn func(*model.Demo) error) error {
+	rows, err := r.DB(ctx).Model(&model.Demo{}).Order("id").Rows()
+	if err != nil {
+		return errors.Wrap(err, "query rows failed")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := database.CheckContext(ctx, "stream demos"); err != nil {
+			return err
+		}
+		var demo model.Demo
+		if err := r.db.ScanRows(rows, &demo); err != nil {
+			return errors.Wrap(err, "scan row failed")
+		}
+		if err := fn(&demo); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(rows.Err(), "iterate rows failed")
+}
+
 // BatchUpdateStatus 批量更新状态（直接使用 GORM）
+// ids 较多时在同一事务中按 demoUpdateChunkSize 分批更新，ctx 取消时停止并回滚；状态不合法时返回 ErrInvalidParams
 func (r *DemoRepository) BatchUpdateStatus(ctx context.Context, ids []uint, status int) error {
-	err := r.db.WithContext(ctx).
-		Model(&model.Demo{}).
-		Where("id IN ?", ids).
-		Update("status", status).Error
+	if err := model.CheckDemoStatus(status); err != nil {
+		return err
+	}
+	defer r.forget(ctx, ids...)
+	err := r.Transaction(ctx, func(tx *gorm.DB) error {
+		for start := 0; start < len(ids); start += demoUpdateChunkSize {
+			if err := database.CheckContext(ctx, "batch update status"); err != nil {
+				return err
+			}
+			chunk := ids[start:min(start+demoUpdateChunkSize, len(ids))]
+			if err := tx.Model(&model.Demo{}).Where("id IN ?", chunk).Update("status", status).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return errors.Wrap(err, "batch update status failed")
 	}
@@ -162,9 +319,25 @@ func (r *DemoRepository) CreateWithTx(ctx context.Context, tx *gorm.DB, demo *mo
 
 // UpdateWithTx 在事务中更新（供 Service 层使用）
 func (r *DemoRepository) UpdateWithTx(ctx context.Context, tx *gorm.DB, demo *model.Demo) error {
+	defer r.forget(ctx, demo.ID)
 	err := tx.WithContext(ctx).Save(demo).Error
 	if err != nil {
 		return errors.Wrap(err, "update with tx failed")
 	}
 	return nil
 }
+
+// ========== 请求级 Loader ==========
+
+// demoLoaderKey Demo 在请求级 Loader 中的 Key
+func demoLoaderKey(id uint) string {
+	return fmt.Sprintf("demo:%d", id)
+}
+
+// forget 数据变更后清除请求级 Loader 中的结果
+func (r *DemoRepository) forget(ctx context.Context, ids ...uint) {
+	loader := database.LoaderFromContext(ctx)
+	for _, id := range ids {
+		loader.Forget(demoLoaderKey(id))
+	}
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-api-template/internal/model"
+	"go-api-template/pkg/cache"
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+func newTestDemoRepository(t *testing.T) *DemoRepository {
+	t.Helper()
+	return NewDemoRepository(dbtest.Open(t, &model.Demo{}))
+}
+
+func TestUpdateStatusNotFound(t *testing.T) {
+	ctx := context.Background()
+	r := newTestDemoRepository(t)
+
+	err := r.UpdateStatus(ctx, 404, model.DemoStatusDisabled)
+	if !errors.Is(err, errors.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+// 状态为 0（禁用）的记录按 0 保存，不会被默认值替换
+func TestCreateDisabled(t *testing.T) {
+	ctx := context.Background()
+	r := newTestDemoRepository(t)
+
+	demo := &model.Demo{Title: "disabled", Status: model.DemoStatusDisabled}
+	if err := r.Create(ctx, demo); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	got, err := r.FindByID(ctx, demo.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if got.Status != model.DemoStatusDisabled {
+		t.Fatalf("status = %d, want %d", got.Status, model.DemoStatusDisabled)
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	ctx := context.Background()
+	r := newTestDemoRepository(t)
+	demo := &model.Demo{Title: "status", Status: model.DemoStatusEnabled}
+	if err := r.Create(ctx, demo); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := r.UpdateStatus(ctx, demo.ID, model.DemoStatusDisabled); err != nil {
+		t.Fatalf("UpdateStatus: %v", err)
+	}
+	got, err := r.FindByID(ctx, demo.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if got.Status != model.DemoStatusDisabled {
+		t.Fatalf("status = %d, want %d", got.Status, model.DemoStatusDisabled)
+	}
+
+	if err := r.UpdateStatus(ctx, demo.ID, 5); !errors.Is(err, errors.ErrInvalidParams) {
+		t.Fatalf("invalid status: err = %v, want ErrInvalidParams", err)
+	}
+	if err := r.BatchUpdateStatus(ctx, []uint{demo.ID}, 5); !errors.Is(err, errors.ErrInvalidParams) {
+		t.Fatalf("batch invalid status: err = %v, want ErrInvalidParams", err)
+	}
+	if got, _ := r.FindByID(ctx, demo.ID); got.Status != model.DemoStatusDisabled {
+		t.Fatalf("status = %d after rejected updates, want %d", got.Status, model.DemoStatusDisabled)
+	}
+}
+
+func TestCreateDuplicateTitle(t *testing.T) {
+	ctx := context.Background()
+	r := newTestDemoRepository(t)
+
+	if err := r.Create(ctx, &model.Demo{Title: "same"}); err != nil {
+		t.Fatalf("first Create: %v", err)
+	}
+	if err := r.Create(ctx, &model.Demo{Title: "same"}); !errors.Is(err, errors.ErrDuplicate) {
+		t.Fatalf("second Create err = %v, want ErrDuplicate", err)
+	}
+}
+
+func TestBatchUpdateStatusStopsWhenCancelled(t *testing.T) {
+	r := newTestDemoRepository(t)
+	demos := make([]model.Demo, demoUpdateChunkSize*2+1)
+	ids := make([]uint, len(demos))
+	for i := range demos {
+		demos[i] = model.Demo{Title: fmt.Sprintf("demo-%d", i), Status: model.DemoStatusEnabled}
+	}
+	if err := r.db.CreateInBatches(demos, 200).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	for i := range demos {
+		ids[i] = demos[i].ID
+	}
+
+	// 第一批 UPDATE 执行后取消（模拟客户端断开）
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := 0
+	r.db.Callback().Update().After("gorm:update").Register("test:cancel_after_first", func(tx *gorm.DB) {
+		updates++
+		cancel()
+	})
+
+	err := r.BatchUpdateStatus(ctx, ids, model.DemoStatusDisabled)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if updates != 1 {
+		t.Fatalf("ran %d UPDATE statements, want 1", updates)
+	}
+
+	// 事务回滚，第一批的更新也不保留
+	var disabled int64
+	r.db.Model(&model.Demo{}).Where("status = ?", model.DemoStatusDisabled).Count(&disabled)
+	if disabled != 0 {
+		t.Fatalf("%d demos disabled after cancel, want 0", disabled)
+	}
+}
+
+func TestStreamAllStopsWhenCancelled(t *testing.T) {
+	r := newTestDemoRepository(t)
+	for i := 0; i < 5; i++ {
+		if err := r.Create(context.Background(), &model.Demo{Title: fmt.Sprintf("demo-%d", i)}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	seen := 0
+	err := r.StreamAll(ctx, func(*model.Demo) error {
+		seen++
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || seen != 1 {
+		t.Fatalf("seen = %d, err = %v, want 1 row and context.Canceled", seen, err)
+	}
+}
+
+// SQLite 没有全文索引：SearchFullText 退回 LIKE，分页后按创建时间倒序
+func TestSearchFullTextFallsBackToLike(t *testing.T) {
+	ctx := context.Background()
+	r := newTestDemoRepository(t)
+	for _, d := range []*model.Demo{
+		{Title: "golang 1", Content: "a"},
+		{Title: "other", Content: "about golang"},
+		{Title: "golang 3", Content: "c"},
+		{Title: "python", Content: "d"},
+	} {
+		if err := r.Create(ctx, d); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	demos, total, err := r.SearchFullText(ctx, "golang", 1, 2)
+	if err != nil {
+		t.Fatalf("SearchFullText: %v", err)
+	}
+	if total != 3 {
+		t.Fatalf("total = %d, want 3", total)
+	}
+	if len(demos) != 2 || time.Time(demos[0].CreatedAt).Before(time.Time(demos[1].CreatedAt)) {
+		t.Fatalf("page 1 = %+v, want 2 demos newest first", demos)
+	}
+
+	if _, total, err := r.SearchFullText(ctx, "", 1, 10); err != nil || total != 4 {
+		t.Fatalf("empty keyword: total = %d, err = %v, want 4", total, err)
+	}
+}
+
+func TestCountGroupedByStatus(t *testing.T) {
+	ctx := context.Background()
+	r := newTestDemoRepository(t)
+	for i, status := range []int{model.DemoStatusEnabled, model.DemoStatusDisabled, model.DemoStatusEnabled} {
+		if err := r.Create(ctx, &model.Demo{Title: fmt.Sprintf("demo-%d", i), Status: status}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	// Status 默认值为 1，禁用状态需单独更新
+	if err := r.db.Model(&model.Demo{}).Where("title = ?", "demo-1").Update("status", model.DemoStatusDisabled).Error; err != nil {
+		t.Fatalf("disable: %v", err)
+	}
+
+	counts, err := r.CountGroupedByStatus(ctx)
+	if err != nil {
+		t.Fatalf("CountGroupedByStatus: %v", err)
+	}
+	want := []DemoStatusCount{{Status: model.DemoStatusDisabled, Count: 1}, {Status: model.DemoStatusEnabled, Count: 2}}
+	if len(counts) != len(want) || counts[0] != want[0] || counts[1] != want[1] {
+		t.Fatalf("counts = %+v, want %+v", counts, want)
+	}
+}
+
+// FindAll 的结果缓存：原生 SQL 修改不可见，通过 Repository 写入后失效
+func TestFindAllQueryCache(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t, &model.Demo{})
+	manager, err := cache.NewCacheManager(&config.Config{Cache: config.CacheConfig{Driver: string(cache.DriverMemory), TTL: 60}}, nil)
+	if err != nil {
+		t.Fatalf("NewCacheManager: %v", err)
+	}
+	if err := database.RegisterQueryCache(db, cache.NewCacheFacade(manager)); err != nil {
+		t.Fatalf("RegisterQueryCache: %v", err)
+	}
+	r := NewDemoRepository(db)
+	if err := r.Create(ctx, &model.Demo{Title: "first"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	findTitles := func() []string {
+		demos, err := r.FindAll(ctx, nil, nil)
+		if err != nil {
+			t.Fatalf("FindAll: %v", err)
+		}
+		out := make([]string, len(demos))
+		for i, d := range demos {
+			out[i] = d.Title
+		}
+		return out
+	}
+
+	findTitles()
+	db.Exec("UPDATE demos SET title = 'raw'")
+	if got := findTitles(); len(got) != 1 || got[0] != "first" {
+		t.Fatalf("titles = %v, want cached [first]", got)
+	}
+
+	if err := r.Create(ctx, &model.Demo{Title: "second"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := findTitles(); len(got) != 2 || got[0] != "raw" {
+		t.Fatalf("titles = %v, want fresh results after Create", got)
+	}
+}
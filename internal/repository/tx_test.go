@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"go-api-template/internal/model"
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/errors"
+)
+
+func TestTransactionRollsBackAcrossRepositories(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t, &model.Demo{}, &model.App{})
+	demos := NewDemoRepository(db)
+	apps := NewAppRepository(db)
+	txm := database.NewTxManager(db)
+
+	errAbort := errors.New("abort")
+	err := txm.Transaction(ctx, func(ctx context.Context) error {
+		if err := demos.Create(ctx, &model.Demo{Title: "in tx"}); err != nil {
+			return err
+		}
+		if err := apps.Create(ctx, &model.App{AppKey: "tx-app", Name: "tx", Secret: "s"}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("Transaction err = %v, want errAbort", err)
+	}
+
+	for name, m := range map[string]interface{}{"demos": &model.Demo{}, "apps": &model.App{}} {
+		var n int64
+		if err := db.Model(m).Count(&n).Error; err != nil {
+			t.Fatalf("count %s: %v", name, err)
+		}
+		if n != 0 {
+			t.Fatalf("%s has %d rows after rollback, want 0", name, n)
+		}
+	}
+}
+
+func TestTransactionCommitsAcrossRepositories(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t, &model.Demo{}, &model.App{})
+	demos := NewDemoRepository(db)
+	apps := NewAppRepository(db)
+
+	err := database.NewTxManager(db).Transaction(ctx, func(ctx context.Context) error {
+		if err := demos.Create(ctx, &model.Demo{Title: "in tx"}); err != nil {
+			return err
+		}
+		return apps.Create(ctx, &model.App{AppKey: "tx-app", Name: "tx", Secret: "s"})
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+	if _, err := apps.FindByAppKey(ctx, "tx-app"); err != nil {
+		t.Fatalf("FindByAppKey after commit: %v", err)
+	}
+	if exists, err := demos.ExistsByTitle(ctx, "in tx"); err != nil || !exists {
+		t.Fatalf("ExistsByTitle after commit = %v, %v", exists, err)
+	}
+}
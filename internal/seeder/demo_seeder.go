@@ -0,0 +1,69 @@
+package seeder
+
+import (
+	"fmt"
+
+	"go-api-template/internal/model"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/tools"
+
+	"gorm.io/gorm"
+)
+
+// demoTitleFormat 示例 Demo 标题（按序号生成，用于判断是否已填充）
+const demoTitleFormat = "示例 Demo #%d"
+
+// DemoSeeder 示例 Demo 填充器
+type DemoSeeder struct {
+	count int
+}
+
+// NewDemoSeeder 创建示例 Demo 填充器
+// count 为示例 Demo 的数量
+func NewDemoSeeder(count int) *DemoSeeder {
+	return &DemoSeeder{count: count}
+}
+
+// Name 填充器名称
+func (s *DemoSeeder) Name() string {
+	return "demo"
+}
+
+// Seed 填充示例 Demo
+// 以标题判断是否已存在，已存在的跳过，因此重复执行不会产生重复数据
+func (s *DemoSeeder) Seed(tx *gorm.DB) error {
+	titles := make([]string, s.count)
+	for i := range titles {
+		titles[i] = fmt.Sprintf(demoTitleFormat, i+1)
+	}
+
+	var existing []string
+	if err := tx.Model(&model.Demo{}).Where("title IN ?", titles).Pluck("title", &existing).Error; err != nil {
+		return err
+	}
+	seeded := make(map[string]bool, len(existing))
+	for _, title := range existing {
+		seeded[title] = true
+	}
+
+	var demos []*model.Demo
+	for _, title := range titles {
+		if seeded[title] {
+			continue
+		}
+		demos = append(demos, &model.Demo{
+			Title:   title,
+			Content: tools.RandString(32),
+			Status:  1,
+		})
+	}
+
+	logger.Info("填充示例 Demo",
+		logger.Int("created", len(demos)),
+		logger.Int("skipped", len(existing)),
+	)
+	if len(demos) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(demos, 100).Error
+}
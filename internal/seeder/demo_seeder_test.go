@@ -0,0 +1,41 @@
+package seeder
+
+import (
+	"testing"
+
+	"go-api-template/internal/model"
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/logger/logtest"
+)
+
+func TestDemoSeederTwiceNoDuplicates(t *testing.T) {
+	logtest.New(t)
+	db := dbtest.Open(t, &model.Demo{})
+
+	for i := 0; i < 2; i++ {
+		if err := database.Seed(db, NewDemoSeeder(5)); err != nil {
+			t.Fatalf("seed run %d: %v", i+1, err)
+		}
+	}
+
+	var titles []string
+	if err := db.Model(&model.Demo{}).Order("id").Pluck("title", &titles).Error; err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(titles) != 5 {
+		t.Fatalf("got %d demos after seeding twice, want 5: %v", len(titles), titles)
+	}
+
+	// 数量增加时只补充缺少的部分
+	if err := database.Seed(db, NewDemoSeeder(8)); err != nil {
+		t.Fatalf("seed 8: %v", err)
+	}
+	var count int64
+	if err := db.Model(&model.Demo{}).Count(&count).Error; err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 8 {
+		t.Fatalf("got %d demos, want 8", count)
+	}
+}
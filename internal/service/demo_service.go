@@ -2,114 +2,313 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 
+	"go-api-template/internal/constants"
 	"go-api-template/internal/model"
 	"go-api-template/internal/repository"
+	"go-api-template/pkg/audit"
+	"go-api-template/pkg/cache"
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/database"
 	"go-api-template/pkg/errors"
 	"go-api-template/pkg/logger"
+	"go-api-template/pkg/metrics"
 )
 
 // DemoService Demo 业务逻辑层
 type DemoService struct {
-	demoRepo *repository.DemoRepository
+	demoRepo repository.DemoRepositoryInterface
+	tx       database.Transactor
+	cache    cache.Cache
+	cacheTTL time.Duration
+	audit    *audit.Logger
 }
 
+// 编译时检查：DemoService 支持缓存预热
+var _ cache.Warmer = (*DemoService)(nil)
+
 // NewDemoService 创建 Demo Service
-func NewDemoService(demoRepo *repository.DemoRepository) *DemoService {
+func NewDemoService(demoRepo repository.DemoRepositoryInterface, tx database.Transactor, c cache.Cache, auditor *audit.Logger, cfg *config.Config) *DemoService {
 	return &DemoService{
 		demoRepo: demoRepo,
+		tx:       tx,
+		cache:    c,
+		cacheTTL: time.Duration(cfg.Cache.TTL) * time.Second,
+		audit:    auditor,
 	}
 }
 
-// GetByID 根据 ID 获取
+// GetByID 根据 ID 获取（优先读缓存）
 func (s *DemoService) GetByID(ctx context.Context, id uint) (*model.Demo, error) {
-	demo, err := s.demoRepo.FindByID(ctx, id)
+	value, err := s.cache.Remember(ctx, demoCacheKey(id), s.cacheTTL, func() (string, error) {
+		demo, err := s.demoRepo.FindByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return encodeDemo(demo)
+	})
 	if err != nil {
-		logger.Error("get demo by id failed",
+		logger.Ctx(ctx).Error("get demo by id failed",
 			logger.Uint("id", id),
 			logger.Err(err),
 		)
 		return nil, err
 	}
-	return demo, nil
+
+	var demo model.Demo
+	if err := json.Unmarshal([]byte(value), &demo); err != nil {
+		return nil, errors.Wrapf(err, "decode cached demo failed, id: %d", id)
+	}
+	return &demo, nil
+}
+
+// WarmUp 预热缓存：预加载第一页 Demo 详情
+func (s *DemoService) WarmUp(ctx context.Context) error {
+	demos, _, err := s.demoRepo.FindPage(ctx, 1, constants.WarmUpDemoPageSize, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, demo := range demos {
+		value, err := encodeDemo(demo)
+		if err != nil {
+			return err
+		}
+		if err := s.cache.Set(ctx, demoCacheKey(demo.ID), value, s.cacheTTL); err != nil {
+			return errors.Wrapf(err, "cache demo failed, id: %d", demo.ID)
+		}
+	}
+	return nil
+}
+
+// forget 数据变更后清除缓存
+func (s *DemoService) forget(ctx context.Context, id uint) {
+	if err := s.cache.Delete(ctx, demoCacheKey(id)); err != nil {
+		logger.Ctx(ctx).Warn("delete demo cache failed",
+			logger.Uint("id", id),
+			logger.Err(err),
+		)
+	}
+}
+
+// demoCacheKey Demo 详情缓存 Key
+func demoCacheKey(id uint) string {
+	return fmt.Sprintf(constants.CacheKeyDemo, id)
+}
+
+// encodeDemo 序列化 Demo 用于缓存
+func encodeDemo(demo *model.Demo) (string, error) {
+	data, err := json.Marshal(demo)
+	if err != nil {
+		return "", errors.Wrap(err, "encode demo failed")
+	}
+	return string(data), nil
 }
 
-// GetAll 获取所有
-func (s *DemoService) GetAll(ctx context.Context) ([]*model.Demo, error) {
-	demos, err := s.demoRepo.FindAll(ctx)
+// GetAll 获取所有（按 filters 筛选），sort 为空时按数据库默认顺序
+func (s *DemoService) GetAll(ctx context.Context, filters database.FilterSet, sort database.Sort) ([]*model.Demo, error) {
+	demos, err := s.demoRepo.FindAll(ctx, filters, sort)
 	if err != nil {
-		logger.Error("get all demos failed", logger.Err(err))
+		logger.Ctx(ctx).Error("get all demos failed", logger.Err(err))
 		return nil, err
 	}
 	return demos, nil
 }
 
+// Export 逐条导出所有 Demo（不一次性加载到内存）
+func (s *DemoService) Export(ctx context.Context, fn func(*model.Demo) error) error {
+	err := s.demoRepo.StreamAll(ctx, fn)
+	if err != nil && ctx.Err() == nil {
+		logger.Ctx(ctx).Error("export demos failed", logger.Err(err))
+	}
+	return err
+}
+
 // Create 创建
 func (s *DemoService) Create(ctx context.Context, demo *model.Demo) error {
 	// 业务逻辑校验
 	if demo.Title == "" {
-		return errors.New("title cannot be empty")
+		return errors.WithHint(errors.Wrap(errors.ErrInvalidParams, "title cannot be empty"), "title 为必填字段，请传入非空的标题")
+	}
+	if err := model.CheckDemoStatus(demo.Status); err != nil {
+		return err
 	}
 
+	// 标题唯一由数据库唯一索引保证（避免先查后插的竞态），重复时返回 errors.ErrDuplicate
 	err := s.demoRepo.Create(ctx, demo)
 	if err != nil {
-		logger.Error("create demo failed",
-			logger.String("title", demo.Title),
-			logger.Err(err),
-		)
+		if !errors.Is(err, errors.ErrDuplicate) {
+			logger.Ctx(ctx).Error("create demo failed",
+				logger.String("title", demo.Title),
+				logger.Err(err),
+			)
+		}
 		return err
 	}
 
-	logger.Info("demo created successfully",
+	metrics.Inc(constants.MetricDemoCreated)
+	logger.Ctx(ctx).Info("demo created successfully",
 		logger.Uint("id", demo.ID),
 		logger.String("title", demo.Title),
 	)
 	return nil
 }
 
+// Import 批量导入：在同一事务中每 batchSize 条插入一次，任一批失败则全部回滚
+// produce 通过 emit 逐个提交待插入的 Demo（已校验），返回插入条数
+func (s *DemoService) Import(ctx context.Context, batchSize int, produce func(emit func(*model.Demo) error) error) (int, error) {
+	inserted := 0
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		batch := make([]*model.Demo, 0, batchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := database.CheckContext(ctx, "import demos"); err != nil {
+				return err
+			}
+			if err := s.demoRepo.CreateBatch(ctx, batch); err != nil {
+				return err
+			}
+			inserted += len(batch)
+			batch = make([]*model.Demo, 0, batchSize)
+			return nil
+		}
+
+		err := produce(func(demo *model.Demo) error {
+			if err := model.CheckDemoStatus(demo.Status); err != nil {
+				return err
+			}
+			batch = append(batch, demo)
+			if len(batch) >= batchSize {
+				return flush()
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return flush()
+	})
+	if err != nil {
+		// 请求体错误、客户端断开不属于服务端错误
+		if ctx.Err() == nil && !errors.Is(err, errors.ErrInvalidParams) && !errors.Is(err, errors.ErrBodyTooLarge) &&
+			!errors.Is(err, errors.ErrDuplicate) {
+			logger.Ctx(ctx).Error("import demos failed", logger.Err(err))
+		}
+		return 0, err
+	}
+
+	metrics.Add(constants.MetricDemoCreated, float64(inserted))
+	logger.Ctx(ctx).Info("demos imported successfully", logger.Int("inserted", inserted))
+	return inserted, nil
+}
+
 // Update 更新
 func (s *DemoService) Update(ctx context.Context, id uint, demo *model.Demo) error {
-	// 检查是否存在
-	existing, err := s.demoRepo.FindByID(ctx, id)
-	if err != nil {
+	if err := model.CheckDemoStatus(demo.Status); err != nil {
 		return err
 	}
 
-	// 更新字段
-	existing.Title = demo.Title
-	existing.Content = demo.Content
-	existing.Status = demo.Status
+	// 查询与更新在同一事务中执行
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		// 检查是否存在
+		existing, err := s.demoRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	err = s.demoRepo.Update(ctx, existing)
+		// 更新字段
+		existing.Title = demo.Title
+		existing.Content = demo.Content
+		existing.Status = demo.Status
+
+		return s.demoRepo.Update(ctx, existing)
+	})
 	if err != nil {
-		logger.Error("update demo failed",
-			logger.Uint("id", id),
-			logger.Err(err),
-		)
+		if !errors.Is(err, errors.ErrNotFound) && !errors.Is(err, errors.ErrDuplicate) {
+			logger.Ctx(ctx).Error("update demo failed",
+				logger.Uint("id", id),
+				logger.Err(err),
+			)
+		}
 		return err
 	}
 
-	logger.Info("demo updated successfully", logger.Uint("id", id))
+	s.forget(ctx, id)
+	logger.Ctx(ctx).Info("demo updated successfully", logger.Uint("id", id))
 	return nil
 }
 
-// Delete 删除
-func (s *DemoService) Delete(ctx context.Context, id uint) error {
-	// 检查是否存在
-	_, err := s.demoRepo.FindByID(ctx, id)
+// Patch 部分更新（只更新 updates 中出现的字段）
+func (s *DemoService) Patch(ctx context.Context, id uint, updates map[string]interface{}) error {
+	if value, ok := updates["status"]; ok {
+		status, isInt := value.(int)
+		if !isInt {
+			return errors.Wrapf(errors.ErrInvalidParams, "invalid demo status: %v", value)
+		}
+		if err := model.CheckDemoStatus(status); err != nil {
+			return err
+		}
+	}
+
+	// 查询与更新在同一事务中执行
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		// 检查是否存在
+		if _, err := s.demoRepo.FindByID(ctx, id); err != nil {
+			return err
+		}
+
+		// 没有需要更新的字段
+		if len(updates) == 0 {
+			return nil
+		}
+
+		return s.demoRepo.UpdateFields(ctx, id, updates)
+	})
 	if err != nil {
+		if !errors.Is(err, errors.ErrNotFound) && !errors.Is(err, errors.ErrDuplicate) {
+			logger.Ctx(ctx).Error("patch demo failed",
+				logger.Uint("id", id),
+				logger.Err(err),
+			)
+		}
 		return err
 	}
 
-	err = s.demoRepo.Delete(ctx, id)
+	s.forget(ctx, id)
+	logger.Ctx(ctx).Info("demo patched successfully", logger.Uint("id", id))
+	return nil
+}
+
+// Delete 删除
+func (s *DemoService) Delete(ctx context.Context, id uint) error {
+	// 查询与删除在同一事务中执行，删除前的内容写入审计日志
+	var before *model.Demo
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		// 检查是否存在
+		demo, err := s.demoRepo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		before = demo
+		return s.demoRepo.Delete(ctx, id)
+	})
 	if err != nil {
-		logger.Error("delete demo failed",
-			logger.Uint("id", id),
-			logger.Err(err),
-		)
+		if !errors.Is(err, errors.ErrNotFound) {
+			logger.Ctx(ctx).Error("delete demo failed",
+				logger.Uint("id", id),
+				logger.Err(err),
+			)
+		}
 		return err
 	}
 
-	logger.Info("demo deleted successfully", logger.Uint("id", id))
+	s.forget(ctx, id)
+	s.audit.Log(ctx, "demo.delete", fmt.Sprintf("demo:%d", id), before, nil)
+	logger.Ctx(ctx).Info("demo deleted successfully", logger.Uint("id", id))
 	return nil
 }
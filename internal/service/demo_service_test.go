@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go-api-template/internal/model"
+	"go-api-template/internal/repository"
+	"go-api-template/pkg/cache"
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/logger/logtest"
+)
+
+// fakeDemoRepository 不依赖数据库的 Demo Repository，记录每个方法的调用次数
+// 未实现的方法调用时 panic（嵌入的接口为 nil）
+type fakeDemoRepository struct {
+	repository.DemoRepositoryInterface
+
+	demos  map[uint]*model.Demo
+	nextID uint
+	calls  map[string]int
+}
+
+func newFakeDemoRepository(demos ...*model.Demo) *fakeDemoRepository {
+	r := &fakeDemoRepository{demos: make(map[uint]*model.Demo), calls: make(map[string]int)}
+	for _, demo := range demos {
+		_ = r.Create(context.Background(), demo)
+	}
+	r.calls = make(map[string]int)
+	return r
+}
+
+func (r *fakeDemoRepository) FindByID(_ context.Context, id uint) (*model.Demo, error) {
+	r.calls["FindByID"]++
+	demo, ok := r.demos[id]
+	if !ok {
+		return nil, errors.Wrapf(errors.ErrNotFound, "demo %d", id)
+	}
+	copied := *demo
+	return &copied, nil
+}
+
+func (r *fakeDemoRepository) Create(_ context.Context, demo *model.Demo) error {
+	r.calls["Create"]++
+	r.nextID++
+	demo.ID = r.nextID
+	copied := *demo
+	r.demos[demo.ID] = &copied
+	return nil
+}
+
+func (r *fakeDemoRepository) Delete(_ context.Context, id uint) error {
+	r.calls["Delete"]++
+	delete(r.demos, id)
+	return nil
+}
+
+// fakeTransactor 直接执行 fn 的 Transactor
+type fakeTransactor struct{}
+
+func (fakeTransactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+var _ database.Transactor = fakeTransactor{}
+
+// newTestDemoService 使用 fake Repository 和内存缓存创建 Service
+func newTestDemoService(t *testing.T, repo repository.DemoRepositoryInterface) *DemoService {
+	t.Helper()
+
+	logtest.New(t)
+	cfg := &config.Config{Cache: config.CacheConfig{Driver: string(cache.DriverMemory), TTL: 60}}
+	manager, err := cache.NewCacheManager(cfg, nil)
+	if err != nil {
+		t.Fatalf("create cache: %v", err)
+	}
+	return NewDemoService(repo, fakeTransactor{}, cache.NewCacheFacade(manager), nil, cfg)
+}
+
+func TestCreateRejectsEmptyTitle(t *testing.T) {
+	repo := newFakeDemoRepository()
+	svc := newTestDemoService(t, repo)
+
+	err := svc.Create(context.Background(), &model.Demo{Title: "", Content: "no title"})
+	if !errors.Is(err, errors.ErrInvalidParams) {
+		t.Fatalf("err = %v, want ErrInvalidParams", err)
+	}
+	if hints := errors.GetAllHints(err); len(hints) != 1 || !strings.Contains(hints[0], "title") {
+		t.Fatalf("hints = %v, want a hint naming the title field", hints)
+	}
+	if n := repo.calls["Create"]; n != 0 {
+		t.Fatalf("repository Create called %d times, want 0", n)
+	}
+}
+
+func TestCreateWithFakeRepository(t *testing.T) {
+	repo := newFakeDemoRepository()
+	svc := newTestDemoService(t, repo)
+
+	demo := &model.Demo{Title: "fake", Status: model.DemoStatusEnabled}
+	if err := svc.Create(context.Background(), demo); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if demo.ID == 0 || repo.calls["Create"] != 1 {
+		t.Fatalf("id = %d, Create calls = %d", demo.ID, repo.calls["Create"])
+	}
+}
+
+func TestRejectsInvalidStatusBeforeRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeDemoRepository(&model.Demo{Title: "existing", Status: model.DemoStatusEnabled})
+	svc := newTestDemoService(t, repo)
+
+	tests := map[string]func() error{
+		"create": func() error { return svc.Create(ctx, &model.Demo{Title: "bad", Status: 5}) },
+		"update": func() error { return svc.Update(ctx, 1, &model.Demo{Title: "bad", Status: 5}) },
+		"patch":  func() error { return svc.Patch(ctx, 1, map[string]interface{}{"status": 5}) },
+		"patch non-int": func() error {
+			return svc.Patch(ctx, 1, map[string]interface{}{"status": "1"})
+		},
+		"import": func() error {
+			_, err := svc.Import(ctx, 10, func(emit func(*model.Demo) error) error {
+				return emit(&model.Demo{Title: "bad", Status: 5})
+			})
+			return err
+		},
+	}
+	for name, call := range tests {
+		if err := call(); !errors.Is(err, errors.ErrInvalidParams) {
+			t.Fatalf("%s: err = %v, want ErrInvalidParams", name, err)
+		}
+	}
+	if len(repo.calls) != 0 {
+		t.Fatalf("repository called: %v", repo.calls)
+	}
+}
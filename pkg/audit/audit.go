@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/metrics"
+)
+
+// Record 审计记录
+type Record struct {
+	Time      time.Time   `json:"time"`
+	RequestID string      `json:"request_id,omitempty"`
+	Actor     string      `json:"actor"`
+	IP        string      `json:"ip,omitempty"`
+	Action    string      `json:"action"`   // 操作，如 demo.delete
+	Resource  string      `json:"resource"` // 资源，如 demo:1
+	Before    interface{} `json:"before,omitempty"`
+	After     interface{} `json:"after,omitempty"`
+}
+
+// Sink 审计记录的写入目标（文件、数据库等），只追加不修改
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, rec *Record) error
+}
+
+// Logger 审计日志，与应用日志分开输出
+// 写入失败不影响业务请求，只记录错误日志并累加 audit_write_failures_total 用于告警
+type Logger struct {
+	sinks []Sink
+}
+
+// NewLogger 创建审计日志，没有 sink 时 Log 不做任何事
+func NewLogger(sinks ...Sink) *Logger {
+	return &Logger{sinks: sinks}
+}
+
+// Log 记录一次敏感操作，actor、request id 等取自 ctx（见 WithMeta）
+// 应在变更成功（事务提交）之后调用
+func (l *Logger) Log(ctx context.Context, action, resource string, before, after interface{}) {
+	if l == nil || len(l.sinks) == 0 {
+		return
+	}
+
+	meta := MetaFromContext(ctx)
+	rec := &Record{
+		Time:      time.Now(),
+		RequestID: meta.RequestID,
+		Actor:     meta.Actor,
+		IP:        meta.IP,
+		Action:    action,
+		Resource:  resource,
+		Before:    before,
+		After:     after,
+	}
+
+	// 请求结束或被取消不应导致审计记录丢失
+	ctx = context.WithoutCancel(ctx)
+	for _, sink := range l.sinks {
+		if err := sink.Write(ctx, rec); err != nil {
+			metrics.IncWith("audit_write_failures_total", []string{"sink"}, sink.Name())
+			logger.Ctx(ctx).Error("audit write failed",
+				logger.String("sink", sink.Name()),
+				logger.String("action", action),
+				logger.String("resource", resource),
+				logger.Err(err),
+			)
+		}
+	}
+}
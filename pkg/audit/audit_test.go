@@ -0,0 +1,92 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+
+	"go-api-template/pkg/audit"
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/logger/logtest"
+	"go-api-template/pkg/metrics"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// memorySink 记录写入的审计记录
+type memorySink struct {
+	records []*audit.Record
+}
+
+func (s *memorySink) Name() string { return "memory" }
+
+func (s *memorySink) Write(_ context.Context, rec *audit.Record) error {
+	s.records = append(s.records, rec)
+	return nil
+}
+
+// failingSink 总是写入失败
+type failingSink struct{}
+
+func (failingSink) Name() string { return "failing" }
+
+func (failingSink) Write(context.Context, *audit.Record) error {
+	return errors.New("disk full")
+}
+
+func failureCount(t *testing.T, sink string) float64 {
+	t.Helper()
+
+	var m dto.Metric
+	if err := metrics.CounterVec("audit_write_failures_total", "sink").WithLabelValues(sink).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestLogUsesRequestMeta(t *testing.T) {
+	sink := &memorySink{}
+	ctx := audit.WithMeta(context.Background(), audit.Meta{Actor: "alice", RequestID: "req-1", IP: "10.0.0.1"})
+
+	audit.NewLogger(sink).Log(ctx, "demo.delete", "demo:1", map[string]string{"title": "t"}, nil)
+
+	if len(sink.records) != 1 {
+		t.Fatalf("got %d records, want 1", len(sink.records))
+	}
+	rec := sink.records[0]
+	if rec.Actor != "alice" || rec.RequestID != "req-1" || rec.IP != "10.0.0.1" || rec.Action != "demo.delete" || rec.Resource != "demo:1" || rec.Time.IsZero() {
+		t.Fatalf("record = %+v", rec)
+	}
+}
+
+func TestLogWithoutMetaUsesSystemActor(t *testing.T) {
+	sink := &memorySink{}
+	audit.NewLogger(sink).Log(context.Background(), "demo.purge", "demos", nil, nil)
+
+	if len(sink.records) != 1 || sink.records[0].Actor != "system" {
+		t.Fatalf("records = %+v, want one with actor system", sink.records)
+	}
+}
+
+func TestLogSinkFailureIsAlarmedNotFatal(t *testing.T) {
+	logs := logtest.New(t)
+	sink := &memorySink{}
+	before := failureCount(t, "failing")
+
+	audit.NewLogger(failingSink{}, sink).Log(context.Background(), "demo.delete", "demo:1", nil, nil)
+
+	if len(sink.records) != 1 {
+		t.Fatalf("healthy sink got %d records, want 1", len(sink.records))
+	}
+	if got := failureCount(t, "failing"); got != before+1 {
+		t.Fatalf("audit_write_failures_total = %v, want %v", got, before+1)
+	}
+	if logs.FilterMessage("audit write failed").Len() != 1 {
+		t.Fatal("missing error log for failed sink")
+	}
+}
+
+func TestNilLoggerIsNoop(t *testing.T) {
+	var l *audit.Logger
+	l.Log(context.Background(), "demo.delete", "demo:1", nil, nil)
+	audit.NewLogger().Log(context.Background(), "demo.delete", "demo:1", nil, nil)
+}
@@ -0,0 +1,26 @@
+package audit
+
+import "context"
+
+// Meta 审计记录的请求上下文信息
+type Meta struct {
+	Actor     string // 操作人
+	RequestID string // 请求 ID
+	IP        string // 客户端 IP
+}
+
+// metaKey context 中 Meta 的 key
+type metaKey struct{}
+
+// WithMeta 将审计上下文信息放入 ctx
+func WithMeta(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, meta)
+}
+
+// MetaFromContext 从 ctx 中取出审计上下文信息，没有时 Actor 为 system（如后台任务）
+func MetaFromContext(ctx context.Context) Meta {
+	if meta, ok := ctx.Value(metaKey{}).(Meta); ok {
+		return meta
+	}
+	return Meta{Actor: "system"}
+}
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go-api-template/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// Entry audit_logs 表记录
+type Entry struct {
+	ID        uint64    `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"not null;index"`
+	RequestID string    `gorm:"type:varchar(64)"`
+	Actor     string    `gorm:"type:varchar(64);not null;index"`
+	IP        string    `gorm:"type:varchar(64)"`
+	Action    string    `gorm:"type:varchar(64);not null;index"`
+	Resource  string    `gorm:"type:varchar(128);not null"`
+	Before    *string   `gorm:"type:json"`
+	After     *string   `gorm:"type:json"`
+}
+
+// TableName 指定表名
+func (Entry) TableName() string {
+	return "audit_logs"
+}
+
+// DBSink 写入 audit_logs 表
+// 直接使用 db 而不是 ctx 中的事务，业务事务回滚不会带走审计记录
+type DBSink struct {
+	db *gorm.DB
+}
+
+// NewDBSink 创建数据库 sink
+func NewDBSink(db *gorm.DB) *DBSink {
+	return &DBSink{db: db}
+}
+
+// Name sink 名称
+func (s *DBSink) Name() string {
+	return "db"
+}
+
+// Write 插入一条记录
+func (s *DBSink) Write(ctx context.Context, rec *Record) error {
+	before, err := marshalState(rec.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalState(rec.After)
+	if err != nil {
+		return err
+	}
+
+	entry := &Entry{
+		CreatedAt: rec.Time,
+		RequestID: rec.RequestID,
+		Actor:     rec.Actor,
+		IP:        rec.IP,
+		Action:    rec.Action,
+		Resource:  rec.Resource,
+		Before:    before,
+		After:     after,
+	}
+	if err := s.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return errors.Wrap(err, "insert audit log failed")
+	}
+	return nil
+}
+
+// marshalState 将变更前后的状态编码为 JSON，nil 保存为 NULL
+func marshalState(v interface{}) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal audit state failed")
+	}
+	s := string(b)
+	return &s, nil
+}
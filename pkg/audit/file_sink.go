@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go-api-template/pkg/errors"
+)
+
+// FileSink 以 JSON Lines 格式追加写入文件
+// 不做切割，由运维按合规要求归档
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink 打开（不存在则创建）审计日志文件
+func NewFileSink(path string) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrapf(err, "create audit log dir failed: %s", path)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return nil, errors.Wrapf(err, "open audit log failed: %s", path)
+	}
+	return &FileSink{file: f}, nil
+}
+
+// Name sink 名称
+func (s *FileSink) Name() string {
+	return "file"
+}
+
+// Write 写入一行记录
+func (s *FileSink) Write(_ context.Context, rec *Record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return errors.Wrap(err, "marshal audit record failed")
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(line); err != nil {
+		return errors.Wrap(err, "write audit log failed")
+	}
+	return nil
+}
+
+// Close 关闭文件
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/tools"
+
+	"github.com/eko/gocache/lib/v4/cache"
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+// breakerCache 带熔断的缓存层
+// Redis 持续出错时熔断器打开，读写直接返回 errors.ErrCircuitOpen，
+// Remember 随即回退到回调（查数据库），不再等待 Redis 超时
+type breakerCache struct {
+	cache.SetterCacheInterface[string]
+	breaker *tools.CircuitBreaker
+}
+
+// withCircuitBreaker 按配置为缓存层加上熔断，未启用时原样返回
+func withCircuitBreaker(cfg *config.Config, c cache.SetterCacheInterface[string]) cache.SetterCacheInterface[string] {
+	bc := cfg.Cache.CircuitBreaker
+	if !bc.Enabled {
+		return c
+	}
+
+	return &breakerCache{
+		SetterCacheInterface: c,
+		breaker: tools.NewCircuitBreaker(tools.CircuitBreakerConfig{
+			FailureThreshold: bc.FailureThreshold,
+			OpenTimeout:      time.Duration(bc.OpenTimeout) * time.Second,
+			HalfOpenProbes:   bc.HalfOpenProbes,
+			// 未命中是正常结果，不计为失败
+			IsFailure: func(err error) bool { return err != nil && !isNotFound(err) },
+			OnStateChange: func(from, to tools.CircuitState) {
+				logger.Warn("redis cache circuit breaker state changed",
+					logger.String("from", from.String()),
+					logger.String("to", to.String()),
+				)
+			},
+		}),
+	}
+}
+
+// Get 获取缓存
+func (b *breakerCache) Get(ctx context.Context, key any) (string, error) {
+	var value string
+	err := b.breaker.Execute(func() error {
+		var err error
+		value, err = b.SetterCacheInterface.Get(ctx, key)
+		return err
+	})
+	return value, err
+}
+
+// GetWithTTL 获取缓存及剩余过期时间
+func (b *breakerCache) GetWithTTL(ctx context.Context, key any) (string, time.Duration, error) {
+	var (
+		value string
+		ttl   time.Duration
+	)
+	err := b.breaker.Execute(func() error {
+		var err error
+		value, ttl, err = b.SetterCacheInterface.GetWithTTL(ctx, key)
+		return err
+	})
+	return value, ttl, err
+}
+
+// Set 设置缓存
+func (b *breakerCache) Set(ctx context.Context, key any, object string, options ...store.Option) error {
+	return b.breaker.Execute(func() error {
+		return b.SetterCacheInterface.Set(ctx, key, object, options...)
+	})
+}
+
+// Delete 删除缓存
+func (b *breakerCache) Delete(ctx context.Context, key any) error {
+	return b.breaker.Execute(func() error {
+		return b.SetterCacheInterface.Delete(ctx, key)
+	})
+}
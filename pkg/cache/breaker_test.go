@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-api-template/pkg/config"
+	pkgerrors "go-api-template/pkg/errors"
+	"go-api-template/pkg/logger/logtest"
+
+	"github.com/eko/gocache/lib/v4/cache"
+)
+
+// downCache 模拟宕机的 Redis：所有读取返回错误，并记录调用次数
+type downCache struct {
+	cache.SetterCacheInterface[string]
+	gets int
+}
+
+func (c *downCache) Get(ctx context.Context, key any) (string, error) {
+	c.gets++
+	return "", errors.New("connection refused")
+}
+
+func TestBreakerCacheFastFailsToCallback(t *testing.T) {
+	logtest.New(t)
+	down := &downCache{}
+	cfg := &config.Config{Cache: config.CacheConfig{CircuitBreaker: config.CircuitBreakerConfig{
+		Enabled:          true,
+		FailureThreshold: 2,
+		OpenTimeout:      60,
+		HalfOpenProbes:   1,
+	}}}
+	c := withCircuitBreaker(cfg, down)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get(ctx, "k"); err == nil {
+			t.Fatal("Get succeeded on a down cache")
+		}
+	}
+
+	// 熔断器打开后不再访问 Redis，直接返回 ErrCircuitOpen
+	if _, err := c.Get(ctx, "k"); !pkgerrors.Is(err, pkgerrors.ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+	if down.gets != 2 {
+		t.Fatalf("down cache called %d times, want 2", down.gets)
+	}
+}
+
+func TestBreakerCacheDisabled(t *testing.T) {
+	down := &downCache{}
+	if c := withCircuitBreaker(&config.Config{}, down); c != down {
+		t.Fatalf("disabled breaker wrapped the cache: %T", c)
+	}
+}
@@ -4,38 +4,71 @@ import (
 	"context"
 	"time"
 
+	"go-api-template/pkg/timing"
+	"go-api-template/pkg/tools"
+
 	"github.com/eko/gocache/lib/v4/cache"
 	"github.com/eko/gocache/lib/v4/store"
 )
 
 // CacheFacade 缓存门面
 type CacheFacade struct {
-	manager cache.CacheInterface[string]
+	manager   cache.CacheInterface[string]
+	name      string // 缓存名称（指标 Label）
+	ttlJitter int    // Set / Remember 的过期时间随机浮动 ±ttlJitter%，0 表示不浮动
 }
 
 // NewCacheFacade 创建缓存门面
 func NewCacheFacade(manager cache.CacheInterface[string]) *CacheFacade {
 	return &CacheFacade{
 		manager: manager,
+		name:    DefaultCacheName,
+	}
+}
+
+// Named 返回共享同一底层缓存、但以 name 统计命中率的门面
+// 如 facade.Named("demo")，用于区分不同业务缓存的效果
+func (f *CacheFacade) Named(name string) *CacheFacade {
+	return &CacheFacade{
+		manager:   f.manager,
+		name:      name,
+		ttlJitter: f.ttlJitter,
+	}
+}
+
+// WithTTLJitter 返回共享同一底层缓存、过期时间随机浮动 ±percent% 的门面（Named 得到的门面沿用该设置）
+// 大量 Key 以相同 TTL（如 cache.ttl）写入时会同时过期，集中回源；浮动后过期时间错开。结果始终为正
+func (f *CacheFacade) WithTTLJitter(percent int) *CacheFacade {
+	return &CacheFacade{
+		manager:   f.manager,
+		name:      f.name,
+		ttlJitter: max(percent, 0),
 	}
 }
 
 // Get 获取缓存
 func (f *CacheFacade) Get(ctx context.Context, key string) (string, error) {
+	defer timing.Track(ctx, "cache")()
+
 	value, err := f.manager.Get(ctx, key)
+	f.recordGet(err)
 	if err != nil {
 		return "", err
 	}
 	return value, nil
 }
 
-// Set 设置缓存
+// Set 设置缓存（配置了 WithTTLJitter 时 ttl 随机浮动）
 func (f *CacheFacade) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
-	return f.manager.Set(ctx, key, value, store.WithExpiration(ttl))
+	defer timing.Track(ctx, "cache")()
+
+	return f.manager.Set(ctx, key, value, store.WithExpiration(tools.Jitter(ttl, f.ttlJitter)))
 }
 
 // Delete 删除缓存
 func (f *CacheFacade) Delete(ctx context.Context, key string) error {
+	defer timing.Track(ctx, "cache")()
+
 	return f.manager.Delete(ctx, key)
 }
 
@@ -46,7 +79,7 @@ func (f *CacheFacade) Has(ctx context.Context, key string) bool {
 }
 
 // Remember 记忆模式（Laravel 风格）
-// 如果缓存存在则返回缓存值，否则执行回调函数并将结果缓存
+// 如果缓存存在则返回缓存值，否则执行回调函数并将结果缓存（过期时间同 Set）
 func (f *CacheFacade) Remember(ctx context.Context, key string, ttl time.Duration, callback func() (string, error)) (string, error) {
 	// 先尝试获取缓存
 	value, err := f.Get(ctx, key)
@@ -55,6 +88,7 @@ func (f *CacheFacade) Remember(ctx context.Context, key string, ttl time.Duratio
 	}
 
 	// 缓存未命中，执行回调
+	f.recordLoad()
 	value, err = callback()
 	if err != nil {
 		return "", err
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestRedisFacade 单级 Redis 缓存门面，过期时间可通过 miniredis.TTL 读取
+func newTestRedisFacade(t *testing.T) (*CacheFacade, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	manager, err := NewCacheManager(&config.Config{Cache: config.CacheConfig{Driver: string(DriverRedis)}}, client)
+	if err != nil {
+		t.Fatalf("NewCacheManager: %v", err)
+	}
+	return NewCacheFacade(manager), mr
+}
+
+func TestTTLJitterWithinBand(t *testing.T) {
+	ctx := context.Background()
+	base, mr := newTestRedisFacade(t)
+	f := base.WithTTLJitter(10).Named("jitter")
+
+	const ttl = 300 * time.Second
+	lo, hi := 270*time.Second, 330*time.Second
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("k%d", i)
+		if i%2 == 0 {
+			if err := f.Set(ctx, key, "v", ttl); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+		} else if _, err := f.Remember(ctx, key, ttl, func() (string, error) { return "v", nil }); err != nil {
+			t.Fatalf("Remember: %v", err)
+		}
+
+		got := mr.TTL(key)
+		if got < lo || got > hi {
+			t.Fatalf("%s TTL = %v, want within [%v, %v]", key, got, lo, hi)
+		}
+		seen[got] = true
+	}
+	if len(seen) < 10 {
+		t.Fatalf("only %d distinct TTLs across 50 keys, want them spread", len(seen))
+	}
+
+	// 未配置浮动的门面保持原 TTL
+	if err := base.Set(ctx, "fixed", "v", ttl); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got := mr.TTL("fixed"); got != ttl {
+		t.Fatalf("TTL without jitter = %v, want %v", got, ttl)
+	}
+}
@@ -19,10 +19,11 @@ type CacheDriver string
 const (
 	DriverRedis  CacheDriver = "redis"
 	DriverMemory CacheDriver = "memory"
+	DriverChain  CacheDriver = "chain"
 )
 
 // NewCacheManager 根据配置创建缓存管理器
-func NewCacheManager(cfg *config.Config, redisClient *redis.Client) (cache.CacheInterface[string], error) {
+func NewCacheManager(cfg *config.Config, redisClient redis.UniversalClient) (cache.CacheInterface[string], error) {
 	driver := CacheDriver(cfg.Cache.Driver)
 
 	switch driver {
@@ -31,7 +32,7 @@ func NewCacheManager(cfg *config.Config, redisClient *redis.Client) (cache.Cache
 			return nil, fmt.Errorf("redis client is required for redis driver")
 		}
 		redisStore := redis_store.NewRedis(redisClient)
-		return cache.New[string](redisStore), nil
+		return withCircuitBreaker(cfg, cache.New[string](redisStore)), nil
 
 	case DriverMemory:
 		// 使用配置的 TTL 作为默认过期时间
@@ -46,8 +47,8 @@ func NewCacheManager(cfg *config.Config, redisClient *redis.Client) (cache.Cache
 }
 
 // NewChainCache 创建多级缓存（L1: Memory, L2: Redis）
-// 先查内存缓存（快），未命中再查 Redis
-func NewChainCache(cfg *config.Config, redisClient *redis.Client) (cache.CacheInterface[string], error) {
+// 先查内存缓存（快），未命中再查 Redis；启用熔断时只熔断 Redis 层，内存层不受影响
+func NewChainCache(cfg *config.Config, redisClient redis.UniversalClient) (cache.CacheInterface[string], error) {
 	if redisClient == nil {
 		return nil, fmt.Errorf("redis client is required for chain cache")
 	}
@@ -64,7 +65,7 @@ func NewChainCache(cfg *config.Config, redisClient *redis.Client) (cache.CacheIn
 	// 创建链式缓存
 	chainCache := cache.NewChain[string](
 		cache.New[string](memoryStore),
-		cache.New[string](redisStore),
+		withCircuitBreaker(cfg, cache.New[string](redisStore)),
 	)
 
 	return chainCache, nil
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/tools"
+
+	"github.com/eko/gocache/lib/v4/cache"
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+const (
+	// healthCheckKeyPrefix 健康检查哨兵 key 前缀（后接随机串，避免多实例互相干扰）
+	healthCheckKeyPrefix = "health:cache:"
+	// healthCheckTimeout 健康检查超时时间
+	healthCheckTimeout = 2 * time.Second
+	// healthCheckTTL 哨兵 key 过期时间（删除失败时兜底）
+	healthCheckTTL = 10 * time.Second
+)
+
+// HealthCheck 缓存健康检查：对哨兵 key 做一次 写入/读取/删除 往返
+// 多级缓存逐层检查，出错时返回失败的层级，如 "tier 2 (redis): ..."
+func (f *CacheFacade) HealthCheck(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	key := healthCheckKeyPrefix + tools.RandString(16)
+
+	chain, ok := f.manager.(*cache.ChainCache[string])
+	if !ok {
+		return roundTrip(ctx, f.manager, key)
+	}
+	for i, tier := range chain.GetCaches() {
+		if err := roundTrip(ctx, tier, key); err != nil {
+			return errors.Wrapf(err, "tier %d (%s)", i+1, storeType(tier))
+		}
+	}
+	return nil
+}
+
+// roundTrip 写入、读取并比对、删除哨兵 key
+func roundTrip(ctx context.Context, c cache.CacheInterface[string], key string) error {
+	value := fmt.Sprintf("%d", time.Now().UnixNano())
+
+	if err := c.Set(ctx, key, value, store.WithExpiration(healthCheckTTL)); err != nil {
+		return errors.Wrap(err, "set")
+	}
+	got, err := c.Get(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "get")
+	}
+	if got != value {
+		return errors.New("get: value mismatch")
+	}
+	if err := c.Delete(ctx, key); err != nil {
+		return errors.Wrap(err, "delete")
+	}
+	return nil
+}
+
+// storeType 缓存层的存储类型（如 redis、go-cache）
+func storeType(c cache.SetterCacheInterface[string]) string {
+	if codec := c.GetCodec(); codec != nil && codec.GetStore() != nil {
+		return codec.GetStore().GetType()
+	}
+	return c.GetType()
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go-api-template/pkg/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/eko/gocache/lib/v4/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestChainCache(t *testing.T) (*CacheFacade, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+
+	manager, err := NewChainCache(&config.Config{Cache: config.CacheConfig{TTL: 60}}, client)
+	if err != nil {
+		t.Fatalf("NewChainCache: %v", err)
+	}
+	return NewCacheFacade(manager), mr
+}
+
+func TestHealthCheckMemory(t *testing.T) {
+	manager, err := NewCacheManager(&config.Config{Cache: config.CacheConfig{Driver: string(DriverMemory), TTL: 60}}, nil)
+	if err != nil {
+		t.Fatalf("NewCacheManager: %v", err)
+	}
+	if err := NewCacheFacade(manager).HealthCheck(context.Background()); err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+}
+
+func TestHealthCheckChainReportsFailingTier(t *testing.T) {
+	f, mr := newTestChainCache(t)
+
+	if err := f.HealthCheck(context.Background()); err != nil {
+		t.Fatalf("healthy chain: %v", err)
+	}
+	// 哨兵 key 已删除
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Fatalf("sentinel keys left in redis: %v", keys)
+	}
+
+	// Redis 宕机：内存层正常，报告第 2 层失败
+	mr.Close()
+	err := f.HealthCheck(context.Background())
+	if err == nil {
+		t.Fatal("HealthCheck succeeded with redis down")
+	}
+	if !strings.Contains(err.Error(), "tier 2 (redis)") {
+		t.Fatalf("err = %v, want tier 2 (redis)", err)
+	}
+}
+
+func TestHealthCheckSingleStoreFailure(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+
+	manager, err := NewCacheManager(&config.Config{Cache: config.CacheConfig{Driver: string(DriverRedis)}}, client)
+	if err != nil {
+		t.Fatalf("NewCacheManager: %v", err)
+	}
+	if _, ok := manager.(*cache.ChainCache[string]); ok {
+		t.Fatal("redis driver returned a chain cache")
+	}
+	f := NewCacheFacade(manager)
+
+	mr.Close()
+	err = f.HealthCheck(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), "set") {
+		t.Fatalf("err = %v, want set failure", err)
+	}
+}
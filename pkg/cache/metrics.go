@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/metrics"
+
+	"github.com/eko/gocache/lib/v4/store"
+)
+
+// 缓存指标（按缓存名称区分）
+const (
+	MetricCacheHits   = "cache_hits_total"   // 缓存命中次数
+	MetricCacheMisses = "cache_misses_total" // 缓存未命中次数
+	MetricCacheErrors = "cache_errors_total" // 缓存读取出错次数（不含未命中）
+	MetricCacheLoads  = "cache_loads_total"  // Remember 未命中后执行回调的次数
+
+	// DefaultCacheName 未指定名称时的缓存名称
+	DefaultCacheName = "default"
+)
+
+// cacheLabels 缓存指标的 Label
+var cacheLabels = []string{"cache"}
+
+// recordGet 根据 Get 的结果记录命中/未命中/出错
+func (f *CacheFacade) recordGet(err error) {
+	switch {
+	case err == nil:
+		metrics.IncWith(MetricCacheHits, cacheLabels, f.name)
+	case isNotFound(err):
+		metrics.IncWith(MetricCacheMisses, cacheLabels, f.name)
+	default:
+		metrics.IncWith(MetricCacheErrors, cacheLabels, f.name)
+	}
+}
+
+// recordLoad 记录一次回调加载
+func (f *CacheFacade) recordLoad() {
+	metrics.IncWith(MetricCacheLoads, cacheLabels, f.name)
+}
+
+// isNotFound 判断是否为缓存未命中
+func isNotFound(err error) bool {
+	return errors.Is(err, store.NotFound{})
+}
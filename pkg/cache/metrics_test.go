@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/metrics"
+
+	"github.com/eko/gocache/lib/v4/store"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// fakeCache 内存 map 实现的缓存，err 不为 nil 时所有读取返回该错误
+type fakeCache struct {
+	data map[string]string
+	err  error
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{data: make(map[string]string)}
+}
+
+func (c *fakeCache) Get(ctx context.Context, key any) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	value, ok := c.data[key.(string)]
+	if !ok {
+		return "", store.NotFoundWithCause(errors.New("missing"))
+	}
+	return value, nil
+}
+
+func (c *fakeCache) Set(ctx context.Context, key any, value string, options ...store.Option) error {
+	c.data[key.(string)] = value
+	return nil
+}
+
+func (c *fakeCache) Delete(ctx context.Context, key any) error {
+	delete(c.data, key.(string))
+	return nil
+}
+
+func (c *fakeCache) Invalidate(ctx context.Context, options ...store.InvalidateOption) error {
+	return nil
+}
+
+func (c *fakeCache) Clear(ctx context.Context) error {
+	c.data = make(map[string]string)
+	return nil
+}
+
+func (c *fakeCache) GetType() string { return "fake" }
+
+// cacheCounter 读取缓存指标的当前值
+func cacheCounter(t *testing.T, metric, name string) float64 {
+	t.Helper()
+
+	var m dto.Metric
+	if err := metrics.CounterVec(metric, cacheLabels...).WithLabelValues(name).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+// counterDeltas 记录执行 fn 前后各缓存指标的变化
+func counterDeltas(t *testing.T, name string, fn func()) map[string]float64 {
+	t.Helper()
+
+	names := []string{MetricCacheHits, MetricCacheMisses, MetricCacheErrors, MetricCacheLoads}
+	before := make(map[string]float64, len(names))
+	for _, n := range names {
+		before[n] = cacheCounter(t, n, name)
+	}
+	fn()
+	deltas := make(map[string]float64, len(names))
+	for _, n := range names {
+		deltas[n] = cacheCounter(t, n, name) - before[n]
+	}
+	return deltas
+}
+
+func assertDeltas(t *testing.T, got map[string]float64, hits, misses, errs, loads float64) {
+	t.Helper()
+
+	want := map[string]float64{MetricCacheHits: hits, MetricCacheMisses: misses, MetricCacheErrors: errs, MetricCacheLoads: loads}
+	for metric, w := range want {
+		if got[metric] != w {
+			t.Fatalf("%s moved by %v, want %v (all: %v)", metric, got[metric], w, got)
+		}
+	}
+}
+
+func TestCacheMetricsGet(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeCache()
+	f := NewCacheFacade(fake).Named("test_get")
+
+	deltas := counterDeltas(t, "test_get", func() {
+		_, _ = f.Get(ctx, "k") // 未命中
+		_ = f.Set(ctx, "k", "v", time.Minute)
+		_, _ = f.Get(ctx, "k") // 命中
+		_, _ = f.Get(ctx, "k") // 命中
+	})
+	assertDeltas(t, deltas, 2, 1, 0, 0)
+
+	fake.err = errors.New("connection refused")
+	deltas = counterDeltas(t, "test_get", func() {
+		_, _ = f.Get(ctx, "k")
+	})
+	assertDeltas(t, deltas, 0, 0, 1, 0)
+}
+
+func TestCacheMetricsRemember(t *testing.T) {
+	ctx := context.Background()
+	f := NewCacheFacade(newFakeCache()).Named("test_remember")
+	calls := 0
+	load := func() (string, error) {
+		calls++
+		return "loaded", nil
+	}
+
+	deltas := counterDeltas(t, "test_remember", func() {
+		for i := 0; i < 3; i++ {
+			value, err := f.Remember(ctx, "k", time.Minute, load)
+			if err != nil || value != "loaded" {
+				t.Fatalf("Remember = %q, %v", value, err)
+			}
+		}
+	})
+	// 第一次未命中并回调，之后两次命中
+	assertDeltas(t, deltas, 2, 1, 0, 1)
+	if calls != 1 {
+		t.Fatalf("callback called %d times, want 1", calls)
+	}
+}
+
+func TestCacheMetricsNamedSeparately(t *testing.T) {
+	ctx := context.Background()
+	base := NewCacheFacade(newFakeCache())
+	a := base.Named("test_a")
+
+	deltaA := counterDeltas(t, "test_a", func() {
+		deltaB := counterDeltas(t, "test_b", func() {
+			_, _ = a.Get(ctx, "missing")
+		})
+		assertDeltas(t, deltaB, 0, 0, 0, 0)
+	})
+	assertDeltas(t, deltaA, 0, 1, 0, 0)
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-api-template/pkg/logger"
+)
+
+// Warmer 缓存预热接口
+// Service 实现该接口，在服务开始接收请求前预加载热点数据
+type Warmer interface {
+	// WarmUp 预热缓存
+	WarmUp(ctx context.Context) error
+}
+
+// WarmUp 依次执行所有预热器
+// 预热失败只记录警告，不阻塞启动
+func WarmUp(ctx context.Context, timeout time.Duration, warmers ...Warmer) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, w := range warmers {
+		start := time.Now()
+		name := fmt.Sprintf("%T", w)
+		if err := w.WarmUp(ctx); err != nil {
+			logger.Warn("cache warm-up failed",
+				logger.String("warmer", name),
+				logger.Err(err),
+			)
+			continue
+		}
+		logger.Info("cache warm-up finished",
+			logger.String("warmer", name),
+			logger.Duration("elapsed", time.Since(start)),
+		)
+	}
+}
@@ -0,0 +1,40 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/cache"
+	"go-api-template/pkg/logger/logtest"
+)
+
+type countingWarmer struct {
+	calls int
+	err   error
+}
+
+func (w *countingWarmer) WarmUp(ctx context.Context) error {
+	w.calls++
+	return w.err
+}
+
+func TestWarmUpRunsEachWarmerOnce(t *testing.T) {
+	logs := logtest.New(t)
+	failing := &countingWarmer{err: errors.New("db down")}
+	ok := &countingWarmer{}
+
+	cache.WarmUp(context.Background(), time.Second, failing, ok)
+
+	if failing.calls != 1 || ok.calls != 1 {
+		t.Fatalf("calls = %d, %d, want 1, 1", failing.calls, ok.calls)
+	}
+	// 失败只记录警告，不影响后续预热器
+	if n := logs.FilterMessage("cache warm-up failed").Len(); n != 1 {
+		t.Fatalf("failed logs = %d, want 1", n)
+	}
+	if n := logs.FilterMessage("cache warm-up finished").Len(); n != 1 {
+		t.Fatalf("finished logs = %d, want 1", n)
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFile 在临时目录写入文件，返回路径
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadConfigMergesOverlay(t *testing.T) {
+	base := writeFile(t, "config.yaml", `
+server:
+  port: 8080
+  mode: debug
+  trusted_proxies: ["127.0.0.1", "::1"]
+database:
+  host: localhost
+  port: 3306
+  username: root
+  password: password
+  database: app
+cache:
+  driver: memory
+  ttl: 60
+`)
+	prod := writeFile(t, "config.prod.yaml", `
+server:
+  mode: release
+  trusted_proxies: ["10.0.0.0/8"]
+database:
+  host: db.internal
+  password: prod-secret
+`)
+
+	cfg, err := LoadConfig(base + ", " + prod)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+
+	// 覆盖层中出现的字段
+	if cfg.Server.Mode != "release" || cfg.Database.Host != "db.internal" || cfg.Database.Password != "prod-secret" {
+		t.Fatalf("overlay not applied: mode=%q host=%q password=%q", cfg.Server.Mode, cfg.Database.Host, cfg.Database.Password)
+	}
+	// 列表整体替换
+	if want := []string{"10.0.0.0/8"}; !reflect.DeepEqual(cfg.Server.TrustedProxies, want) {
+		t.Fatalf("trusted_proxies = %v, want %v", cfg.Server.TrustedProxies, want)
+	}
+	// 未出现的字段保留基础配置
+	if cfg.Server.Port != 8080 || cfg.Database.Port != 3306 || cfg.Database.Username != "root" || cfg.Database.Database != "app" {
+		t.Fatalf("base values lost: %+v", cfg.Database)
+	}
+	if cfg.Cache.Driver != "memory" || cfg.Cache.TTL != 60 {
+		t.Fatalf("cache = %+v, want base values", cfg.Cache)
+	}
+	// 两个文件都没有的字段使用默认值
+	if cfg.Server.ShutdownTimeout != 10 {
+		t.Fatalf("shutdown_timeout = %d, want default 10", cfg.Server.ShutdownTimeout)
+	}
+}
+
+func TestLoadConfigOverlayOrder(t *testing.T) {
+	a := writeFile(t, "a.yaml", "server:\n  port: 1\n")
+	b := writeFile(t, "b.yaml", "server:\n  port: 2\n")
+
+	for _, tt := range []struct {
+		path string
+		want int
+	}{
+		{a + "," + b, 2},
+		{b + "," + a, 1},
+	} {
+		cfg, err := LoadConfig(tt.path)
+		if err != nil {
+			t.Fatalf("LoadConfig(%s): %v", tt.path, err)
+		}
+		if cfg.Server.Port != tt.want {
+			t.Fatalf("LoadConfig(%s): port = %d, want %d", tt.path, cfg.Server.Port, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	base := writeFile(t, "config.yaml", "server:\n  port: 1\n")
+	_, err := LoadConfig(base + "," + filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "读取配置文件失败") {
+		t.Fatalf("err = %v, want read error", err)
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// envPrefix 环境变量前缀
+const envPrefix = "APP_"
+
+// fileRefPattern 配置值中的文件引用：${file:/run/secrets/db_pass}
+var fileRefPattern = regexp.MustCompile(`^\$\{file:(.+)\}$`)
+
+// resolveFileRefs 递归解析配置中的 ${file:/path} 引用，替换为文件内容
+func resolveFileRefs(node map[string]interface{}) error {
+	for key, value := range node {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if err := resolveFileRefs(v); err != nil {
+				return err
+			}
+		case string:
+			m := fileRefPattern.FindStringSubmatch(v)
+			if m == nil {
+				continue
+			}
+			secret, err := readSecretFile(m[1])
+			if err != nil {
+				return fmt.Errorf("配置项 %s 引用的文件无效: %w", key, err)
+			}
+			node[key] = secret
+		}
+	}
+	return nil
+}
+
+// secretFileEnvKeys APP_<PATH>_FILE 环境变量名 -> 配置路径，由 Config 的 yaml 标签生成
+// 路径各级大写后以 _ 连接，包括嵌套和带下划线的键，如 APP_API_KEY_HEADER_FILE -> api_key.header；
+// 只包含字符串字段（密钥文件的内容按字符串处理）；两个配置项对应同一个环境变量名时 panic
+var secretFileEnvKeys = collectSecretFileEnvKeys(reflect.TypeOf(Config{}), nil, make(map[string][]string))
+
+// collectSecretFileEnvKeys 递归收集结构体 t 中字符串字段对应的环境变量名
+func collectSecretFileEnvKeys(t reflect.Type, path []string, out map[string][]string) map[string][]string {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fieldPath := append(append([]string(nil), path...), name)
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			collectSecretFileEnvKeys(field.Type, fieldPath, out)
+		case reflect.String:
+			env := envPrefix + strings.ToUpper(strings.Join(fieldPath, "_")) + "_FILE"
+			if other, ok := out[env]; ok {
+				// 不同路径拼接后同名（如 a_b.c 与 a.b_c），新增配置项时需要改名
+				panic(fmt.Sprintf("config: %s 同时对应 %s 和 %s", env, strings.Join(other, "."), strings.Join(fieldPath, ".")))
+			}
+			out[env] = fieldPath
+		}
+	}
+	return out
+}
+
+// applySecretFileEnv 应用 APP_<PATH>_FILE 环境变量
+// 例如 APP_DATABASE_PASSWORD_FILE=/run/secrets/db_pass 会将文件内容设置到 database.password，
+// APP_API_KEY_HEADER_FILE 设置到 api_key.header；不对应任何配置项的 APP_*_FILE 环境变量忽略
+func applySecretFileEnv(merged map[string]interface{}) error {
+	for _, env := range os.Environ() {
+		name, path, _ := strings.Cut(env, "=")
+		keys, ok := secretFileEnvKeys[name]
+		if !ok {
+			continue
+		}
+
+		secret, err := readSecretFile(path)
+		if err != nil {
+			return fmt.Errorf("环境变量 %s 引用的文件无效: %w", name, err)
+		}
+
+		node := merged
+		for _, key := range keys[:len(keys)-1] {
+			child, ok := node[key].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[key] = child
+			}
+			node = child
+		}
+		node[keys[len(keys)-1]] = secret
+	}
+	return nil
+}
+
+// readSecretFile 读取密钥文件，去掉末尾换行
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
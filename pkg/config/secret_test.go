@@ -0,0 +1,117 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSecretFileEnv(t *testing.T) {
+	base := writeFile(t, "config.yaml", "database:\n  password: from-yaml\n")
+	secret := writeFile(t, "db_pass", "s3cr3t\n\n")
+	t.Setenv("APP_DATABASE_PASSWORD_FILE", secret)
+
+	cfg, err := LoadConfig(base)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Database.Password != "s3cr3t" {
+		t.Fatalf("password = %q, want %q", cfg.Database.Password, "s3cr3t")
+	}
+}
+
+func TestSecretFileEnvNewSection(t *testing.T) {
+	base := writeFile(t, "config.yaml", "server:\n  port: 1\n")
+	secret := writeFile(t, "jwt", "jwt-secret\r\n")
+	t.Setenv("APP_AUTH_JWT_SECRET_FILE", secret)
+
+	cfg, err := LoadConfig(base)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Auth.JWTSecret != "jwt-secret" {
+		t.Fatalf("jwt_secret = %q, want %q", cfg.Auth.JWTSecret, "jwt-secret")
+	}
+}
+
+func TestSecretFileRefInline(t *testing.T) {
+	secret := writeFile(t, "db_pass", "inline-secret\n")
+	base := writeFile(t, "config.yaml", "database:\n  username: root\n  password: ${file:"+secret+"}\n")
+
+	cfg, err := LoadConfig(base)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Database.Password != "inline-secret" {
+		t.Fatalf("password = %q, want %q", cfg.Database.Password, "inline-secret")
+	}
+	if cfg.Database.Username != "root" {
+		t.Fatalf("username = %q, want root", cfg.Database.Username)
+	}
+}
+
+func TestSecretFileMissing(t *testing.T) {
+	missing := t.TempDir() + "/missing"
+
+	inline := writeFile(t, "config.yaml", "database:\n  password: ${file:"+missing+"}\n")
+	if _, err := LoadConfig(inline); err == nil || !strings.Contains(err.Error(), "配置项 password 引用的文件无效") {
+		t.Fatalf("inline: err = %v, want missing file error", err)
+	}
+
+	base := writeFile(t, "config.yaml", "server:\n  port: 1\n")
+	t.Setenv("APP_DATABASE_PASSWORD_FILE", missing)
+	if _, err := LoadConfig(base); err == nil || !strings.Contains(err.Error(), "APP_DATABASE_PASSWORD_FILE") {
+		t.Fatalf("env: err = %v, want missing file error", err)
+	}
+}
+
+// 带下划线的配置段（api_key、rate_limit 等）和嵌套配置项
+func TestSecretFileEnvUnderscoredSection(t *testing.T) {
+	base := writeFile(t, "config.yaml", "api_key:\n  header: X-API-Key\n")
+	t.Setenv("APP_API_KEY_HEADER_FILE", writeFile(t, "header", "X-Client-Key\n"))
+	t.Setenv("APP_SERVER_TRAILING_SLASH_FILE", writeFile(t, "slash", "rewrite\n"))
+
+	cfg, err := LoadConfig(base)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.APIKey.Header != "X-Client-Key" {
+		t.Fatalf("api_key.header = %q, want X-Client-Key", cfg.APIKey.Header)
+	}
+	if cfg.Server.TrailingSlash != "rewrite" {
+		t.Fatalf("server.trailing_slash = %q, want rewrite", cfg.Server.TrailingSlash)
+	}
+}
+
+// 不对应任何配置项的 APP_*_FILE 环境变量忽略，即使文件不存在也不影响启动
+func TestSecretFileEnvIgnoresUnknownNames(t *testing.T) {
+	base := writeFile(t, "config.yaml", "server:\n  port: 1\n")
+	missing := t.TempDir() + "/missing"
+	t.Setenv("APP_FOO_BAR_FILE", missing)
+	t.Setenv("APP_SERVER_PORT_FILE", missing) // 非字符串配置项
+	t.Setenv("APP_API_FILE", missing)
+
+	cfg, err := LoadConfig(base)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Server.Port != 1 {
+		t.Fatalf("server.port = %d, want 1", cfg.Server.Port)
+	}
+}
+
+func TestSecretFileEnvKeys(t *testing.T) {
+	for name, want := range map[string]string{
+		"APP_DATABASE_PASSWORD_FILE":     "database.password",
+		"APP_API_KEY_HEADER_FILE":        "api_key.header",
+		"APP_AUTH_JWT_SECRET_FILE":       "auth.jwt_secret",
+		"APP_SERVER_TRAILING_SLASH_FILE": "server.trailing_slash",
+	} {
+		if got := strings.Join(secretFileEnvKeys[name], "."); got != want {
+			t.Fatalf("%s -> %q, want %q", name, got, want)
+		}
+	}
+	// 非字符串配置项不生成环境变量名
+	if _, ok := secretFileEnvKeys["APP_SERVER_PORT_FILE"]; ok {
+		t.Fatal("APP_SERVER_PORT_FILE should not be a secret file env")
+	}
+}
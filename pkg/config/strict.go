@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateStrict 严格校验配置文件：拒绝未知的 YAML 字段
+// yaml.Unmarshal 会静默忽略未知字段，拼写错误（如 databse:）只会让配置回退为默认值；
+// 严格模式逐个检查文件中的每个字段，返回所有未知字段及其所在行
+// path 与 LoadConfig 相同，支持逗号分隔的多个文件
+func ValidateStrict(path string) error {
+	var problems []string
+	for _, p := range strings.Split(path, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("读取配置文件失败 %s: %w", p, err)
+		}
+
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("解析配置文件失败 %s: %w", p, err)
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+
+		for _, problem := range unknownKeys(doc.Content[0], reflect.TypeOf(Config{}), "") {
+			problems = append(problems, p+":"+problem)
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New("配置文件包含未知字段:\n  " + strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// unknownKeys 对照结构体的 yaml 标签检查节点中的未知字段
+// 返回 "行号: 字段路径" 形式的问题列表
+func unknownKeys(node *yaml.Node, t reflect.Type, prefix string) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var problems []string
+	switch {
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			path := joinKey(prefix, key.Value)
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%d: %s", key.Line, path))
+				continue
+			}
+			problems = append(problems, unknownKeys(value, fieldType, path)...)
+		}
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			problems = append(problems, unknownKeys(node.Content[i+1], t.Elem(), joinKey(prefix, node.Content[i].Value))...)
+		}
+	case node.Kind == yaml.SequenceNode && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array):
+		for i, item := range node.Content {
+			problems = append(problems, unknownKeys(item, t.Elem(), fmt.Sprintf("%s[%d]", prefix, i))...)
+		}
+	}
+	return problems
+}
+
+// yamlFields 结构体的 yaml 字段名到字段类型的映射（展开 inline 字段）
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(","+opts+",", ",inline,") && field.Type.Kind() == reflect.Struct {
+			for k, v := range yamlFields(field.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name) // 与 yaml.v3 的默认字段名一致
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// joinKey 拼接字段路径
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
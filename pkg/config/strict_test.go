@@ -0,0 +1,46 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const typoConfig = `
+server:
+  port: 8080
+databse:
+  host: localhost
+cache:
+  driver: memory
+  ttll: 60
+`
+
+func TestValidateStrictRejectsUnknownKeys(t *testing.T) {
+	path := writeFile(t, "config.yaml", typoConfig)
+
+	err := ValidateStrict(path)
+	if err == nil {
+		t.Fatal("ValidateStrict accepted unknown keys")
+	}
+	for _, want := range []string{path + ":4: databse", path + ":8: cache.ttll"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestLoadConfigToleratesUnknownKeys(t *testing.T) {
+	cfg, err := LoadConfig(writeFile(t, "config.yaml", typoConfig))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.Server.Port != 8080 || cfg.Cache.Driver != "memory" {
+		t.Fatalf("known keys not loaded: port = %d, driver = %q", cfg.Server.Port, cfg.Cache.Driver)
+	}
+}
+
+func TestValidateStrictAcceptsShippedConfig(t *testing.T) {
+	if err := ValidateStrict("../../config/config.yaml"); err != nil {
+		t.Fatalf("ValidateStrict: %v", err)
+	}
+}
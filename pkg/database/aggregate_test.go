@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"go-api-template/pkg/errors"
+)
+
+func newAggregateRepository(t *testing.T) *BaseRepository {
+	return newTestRepository(t,
+		testItem{Name: "a", Status: 1},
+		testItem{Name: "b", Status: 2},
+		testItem{Name: "c", Status: 1},
+		testItem{Name: "d", Status: 3},
+		testItem{Name: "e", Status: 1},
+	)
+}
+
+func TestAggregateGroupBy(t *testing.T) {
+	ctx := context.Background()
+	r := newAggregateRepository(t)
+
+	var rows []struct {
+		Status int
+		Count  int64
+		MaxID  uint
+	}
+	err := r.Aggregate(ctx, &testItem{}, "status", map[string]string{"count": "COUNT(*)", "max_id": "max(id)"}, &rows, nil)
+	if err != nil {
+		t.Fatalf("Aggregate: %v", err)
+	}
+	want := []struct {
+		status int
+		count  int64
+		maxID  uint
+	}{{1, 3, 5}, {2, 1, 2}, {3, 1, 4}}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v", rows)
+	}
+	for i, w := range want {
+		if rows[i].Status != w.status || rows[i].Count != w.count || rows[i].MaxID != w.maxID {
+			t.Fatalf("rows[%d] = %+v, want %+v", i, rows[i], w)
+		}
+	}
+}
+
+func TestAggregateWhereWithoutGroup(t *testing.T) {
+	ctx := context.Background()
+	r := newAggregateRepository(t)
+
+	var total struct {
+		Count int64
+		Sum   int64
+	}
+	err := r.Aggregate(ctx, &testItem{}, "", map[string]string{"count": "COUNT(*)", "sum": "SUM(status)"}, &total, "status > ?", 1)
+	if err != nil {
+		t.Fatalf("Aggregate: %v", err)
+	}
+	if total.Count != 2 || total.Sum != 5 {
+		t.Fatalf("total = %+v, want count 2, sum 5", total)
+	}
+}
+
+// 不在白名单中的列、函数和别名全部拒绝，不会拼接到 SQL
+func TestAggregateRejectsUnsafeInput(t *testing.T) {
+	ctx := context.Background()
+	r := newAggregateRepository(t)
+	count := map[string]string{"count": "COUNT(*)"}
+
+	tests := []struct {
+		name       string
+		groupBy    string
+		aggregates map[string]string
+	}{
+		{"injected group", "status; DROP TABLE test_items", count},
+		{"expression group", "status + 1", count},
+		{"unknown group", "missing", count},
+		{"go field name", "Status", count},
+		{"no aggregates", "status", nil},
+		{"sum star", "status", map[string]string{"s": "SUM(*)"}},
+		{"unknown column", "status", map[string]string{"s": "SUM(missing)"}},
+		{"free expression", "status", map[string]string{"s": "SUM(id) + (SELECT 1)"}},
+		{"unsupported function", "status", map[string]string{"s": "GROUP_CONCAT(name)"}},
+		{"bad alias", "status", map[string]string{"c; --": "COUNT(*)"}},
+	}
+	for _, tt := range tests {
+		var rows []map[string]interface{}
+		err := r.Aggregate(ctx, &testItem{}, tt.groupBy, tt.aggregates, &rows, nil)
+		if !errors.Is(err, errors.ErrInvalidParams) {
+			t.Fatalf("%s: err = %v, want ErrInvalidParams", tt.name, err)
+		}
+	}
+
+	var n int64
+	if err := r.DB(ctx).Model(&testItem{}).Count(&n).Error; err != nil || n != 5 {
+		t.Fatalf("table intact: count = %d, err = %v", n, err)
+	}
+}
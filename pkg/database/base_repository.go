@@ -2,10 +2,15 @@ package database
 
 import (
 	"context"
+	"regexp"
+	"sort"
+	"strings"
 
 	"go-api-template/pkg/errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/utils"
 )
 
 // BaseRepository 基础 Repository，提供通用的 CRUD 操作
@@ -20,15 +25,16 @@ func NewBaseRepository(db *gorm.DB) *BaseRepository {
 }
 
 // DB 获取数据库连接（用于复杂查询）
+// context 中绑定了事务（见 WithTx）时返回该事务，否则返回连接池
 func (r *BaseRepository) DB(ctx context.Context) *gorm.DB {
-	return r.db.WithContext(ctx)
+	return Conn(ctx, r.db)
 }
 
 // ========== 查询操作 ==========
 
 // FindByID 根据 ID 查询单条记录
 func (r *BaseRepository) FindByID(ctx context.Context, id interface{}, dest interface{}) error {
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(dest).Error
+	err := r.DB(ctx).Where("id = ?", id).First(dest).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errors.ErrNotFound
@@ -40,7 +46,7 @@ func (r *BaseRepository) FindByID(ctx context.Context, id interface{}, dest inte
 
 // FindOne 根据条件查询单条记录
 func (r *BaseRepository) FindOne(ctx context.Context, dest interface{}, query interface{}, args ...interface{}) error {
-	err := r.db.WithContext(ctx).Where(query, args...).First(dest).Error
+	err := r.DB(ctx).Where(query, args...).First(dest).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errors.ErrNotFound
@@ -52,18 +58,33 @@ func (r *BaseRepository) FindOne(ctx context.Context, dest interface{}, query in
 
 // FindAll 查询所有记录
 func (r *BaseRepository) FindAll(ctx context.Context, dest interface{}, query interface{}, args ...interface{}) error {
-	err := r.db.WithContext(ctx).Where(query, args...).Find(dest).Error
+	err := r.DB(ctx).Where(query, args...).Find(dest).Error
 	if err != nil {
 		return errors.Wrap(err, "query all failed")
 	}
 	return nil
 }
 
+// FindInBatches 按主键顺序每次查询 batchSize 条到 dest，每批调用一次 fn（batch 从 1 开始）
+// fn 返回错误或 ctx 取消时停止，不再查询后续批次
+func (r *BaseRepository) FindInBatches(ctx context.Context, dest interface{}, batchSize int, fn func(batch int) error, query interface{}, args ...interface{}) error {
+	err := r.DB(ctx).Where(query, args...).FindInBatches(dest, batchSize, func(_ *gorm.DB, batch int) error {
+		if err := CheckContext(ctx, "find in batches"); err != nil {
+			return err
+		}
+		return fn(batch)
+	}).Error
+	if err != nil {
+		return errors.Wrap(err, "find in batches failed")
+	}
+	return nil
+}
+
 // FindPage 分页查询
 func (r *BaseRepository) FindPage(ctx context.Context, dest interface{}, page, pageSize int, query interface{}, args ...interface{}) (int64, error) {
 	var total int64
 
-	db := r.db.WithContext(ctx).Model(dest)
+	db := r.DB(ctx).Model(dest)
 	if query != nil {
 		db = db.Where(query, args...)
 	}
@@ -86,7 +107,7 @@ func (r *BaseRepository) FindPage(ctx context.Context, dest interface{}, page, p
 // Count 统计数量
 func (r *BaseRepository) Count(ctx context.Context, model interface{}, query interface{}, args ...interface{}) (int64, error) {
 	var count int64
-	db := r.db.WithContext(ctx).Model(model)
+	db := r.DB(ctx).Model(model)
 	if query != nil {
 		db = db.Where(query, args...)
 	}
@@ -100,78 +121,259 @@ func (r *BaseRepository) Count(ctx context.Context, model interface{}, query int
 // Exists 判断记录是否存在
 func (r *BaseRepository) Exists(ctx context.Context, model interface{}, query interface{}, args ...interface{}) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(model).Where(query, args...).Limit(1).Count(&count).Error
+	err := r.DB(ctx).Model(model).Where(query, args...).Limit(1).Count(&count).Error
 	if err != nil {
 		return false, errors.Wrap(err, "check exists failed")
 	}
 	return count > 0, nil
 }
 
+// ========== 聚合查询 ==========
+
+var (
+	// aggregateExprPattern 聚合表达式：COUNT(*) 或 COUNT/SUM/AVG/MIN/MAX(列名)
+	aggregateExprPattern = regexp.MustCompile(`(?i)^(count|sum|avg|min|max)\(\s*(\*|\w+)\s*\)$`)
+	// aggregateAliasPattern 结果列名
+	aggregateAliasPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Aggregate 分组聚合查询，结果按分组列升序写入 dest（结构体切片指针，字段对应 groupBy 和 aggregates 的 key）
+// groupBy 为分组列，为空时不分组（整表聚合）；aggregates 为 结果列名 -> 聚合表达式，如 {"count": "COUNT(*)", "total": "SUM(amount)"}。
+// 分组列和聚合表达式中的列必须是 model 的数据库列（白名单），聚合函数只支持 COUNT/SUM/AVG/MIN/MAX，
+// 否则返回 errors.ErrInvalidParams，不会拼接到 SQL 中
+//
+//	var rows []struct{ Status int; Count int64 }
+//	err := r.Aggregate(ctx, &model.Demo{}, "status", map[string]string{"count": "COUNT(*)"}, &rows, nil)
+func (r *BaseRepository) Aggregate(ctx context.Context, model interface{}, groupBy string, aggregates map[string]string, dest interface{}, query interface{}, args ...interface{}) error {
+	db := r.DB(ctx)
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return errors.Wrap(err, "aggregate: parse model failed")
+	}
+
+	// column 校验列名并加引号
+	column := func(name string) (string, error) {
+		if field := stmt.Schema.LookUpField(name); field != nil && field.DBName == name {
+			return stmt.Quote(name), nil
+		}
+		return "", errors.Wrapf(errors.ErrInvalidParams, "invalid aggregate column: %s", name)
+	}
+
+	var selects []string
+	var group string
+	if groupBy != "" {
+		col, err := column(groupBy)
+		if err != nil {
+			return err
+		}
+		group = col
+		selects = append(selects, col)
+	}
+
+	if len(aggregates) == 0 {
+		return errors.Wrap(errors.ErrInvalidParams, "aggregate: no aggregates")
+	}
+	aliases := make([]string, 0, len(aggregates))
+	for alias := range aggregates {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases) // 固定 SELECT 顺序
+	for _, alias := range aliases {
+		if !aggregateAliasPattern.MatchString(alias) {
+			return errors.Wrapf(errors.ErrInvalidParams, "invalid aggregate alias: %s", alias)
+		}
+		m := aggregateExprPattern.FindStringSubmatch(strings.TrimSpace(aggregates[alias]))
+		if m == nil {
+			return errors.Wrapf(errors.ErrInvalidParams, "invalid aggregate expression: %s", aggregates[alias])
+		}
+		fn, arg := strings.ToUpper(m[1]), m[2]
+		if arg != "*" {
+			col, err := column(arg)
+			if err != nil {
+				return err
+			}
+			arg = col
+		} else if fn != "COUNT" {
+			return errors.Wrapf(errors.ErrInvalidParams, "invalid aggregate expression: %s", aggregates[alias])
+		}
+		selects = append(selects, fn+"("+arg+") AS "+stmt.Quote(alias))
+	}
+
+	tx := db.Model(model).Select(strings.Join(selects, ", "))
+	if query != nil {
+		tx = tx.Where(query, args...)
+	}
+	if group != "" {
+		tx = tx.Group(group).Order(group)
+	}
+	if err := tx.Scan(dest).Error; err != nil {
+		return errors.Wrap(err, "aggregate failed")
+	}
+	return nil
+}
+
 // ========== 创建操作 ==========
 
 // Create 创建记录
 func (r *BaseRepository) Create(ctx context.Context, value interface{}) error {
-	err := r.db.WithContext(ctx).Create(value).Error
+	err := r.DB(ctx).Create(value).Error
 	if err != nil {
-		return errors.Wrap(err, "create failed")
+		return wrapWriteError(err, "create failed")
 	}
 	return nil
 }
 
 // CreateInBatches 批量创建
 func (r *BaseRepository) CreateInBatches(ctx context.Context, value interface{}, batchSize int) error {
-	err := r.db.WithContext(ctx).CreateInBatches(value, batchSize).Error
+	err := r.DB(ctx).CreateInBatches(value, batchSize).Error
 	if err != nil {
-		return errors.Wrap(err, "create in batches failed")
+		return wrapWriteError(err, "create in batches failed")
 	}
 	return nil
 }
 
+// FirstOrCreate 按 attrs 查询记录，不存在时用 attrs + defaults 创建，返回是否新建
+// defaults 只在创建时使用，不参与查询；并发创建触发唯一约束冲突时重新查询，返回已存在的记录
+// 注意：PostgreSQL 事务中唯一约束冲突会使事务失效，此时无法重新查询，需要在事务外调用
+//
+//	created, err := r.FirstOrCreate(ctx, &tag, map[string]interface{}{"name": "go"}, map[string]interface{}{"color": "blue"})
+func (r *BaseRepository) FirstOrCreate(ctx context.Context, dest interface{}, attrs map[string]interface{}, defaults map[string]interface{}) (bool, error) {
+	db := r.DB(ctx).Where(attrs)
+	if len(defaults) > 0 {
+		db = db.Attrs(defaults)
+	}
+	result := db.FirstOrCreate(dest)
+	if result.Error == nil {
+		return result.RowsAffected > 0, nil // 查询命中时 RowsAffected 为 0
+	}
+	if !errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return false, wrapWriteError(result.Error, "first or create failed")
+	}
+
+	// 其他请求在查询和创建之间插入了相同记录
+	if err := r.DB(ctx).Where(attrs).First(dest).Error; err != nil {
+		return false, errors.Wrap(err, "first or create: find after duplicate failed")
+	}
+	return false, nil
+}
+
 // ========== 更新操作 ==========
 
 // Update 更新记录（全部字段）
 func (r *BaseRepository) Update(ctx context.Context, value interface{}) error {
-	err := r.db.WithContext(ctx).Save(value).Error
+	err := r.DB(ctx).Save(value).Error
 	if err != nil {
-		return errors.Wrap(err, "update failed")
+		return wrapWriteError(err, "update failed")
 	}
 	return nil
 }
 
-// UpdateFields 更新指定字段
-func (r *BaseRepository) UpdateFields(ctx context.Context, model interface{}, query interface{}, updates map[string]interface{}, args ...interface{}) error {
-	err := r.db.WithContext(ctx).Model(model).Where(query, args...).Updates(updates).Error
-	if err != nil {
-		return errors.Wrap(err, "update fields failed")
+// UpdateFields 更新指定字段，返回受影响的行数
+func (r *BaseRepository) UpdateFields(ctx context.Context, model interface{}, query interface{}, updates map[string]interface{}, args ...interface{}) (int64, error) {
+	result := r.DB(ctx).Model(model).Where(query, args...).Updates(updates)
+	if result.Error != nil {
+		return 0, wrapWriteError(result.Error, "update fields failed")
 	}
-	return nil
+	return result.RowsAffected, nil
 }
 
-// UpdateColumn 更新单个字段（不触发钩子）
-func (r *BaseRepository) UpdateColumn(ctx context.Context, model interface{}, query interface{}, column string, value interface{}, args ...interface{}) error {
-	err := r.db.WithContext(ctx).Model(model).Where(query, args...).Update(column, value).Error
-	if err != nil {
-		return errors.Wrap(err, "update column failed")
+// UpdateColumn 更新单个字段（不触发钩子），返回受影响的行数
+func (r *BaseRepository) UpdateColumn(ctx context.Context, model interface{}, query interface{}, column string, value interface{}, args ...interface{}) (int64, error) {
+	result := r.DB(ctx).Model(model).Where(query, args...).Update(column, value)
+	if result.Error != nil {
+		return 0, wrapWriteError(result.Error, "update column failed")
 	}
-	return nil
+	return result.RowsAffected, nil
+}
+
+// UpdateAndReturn 更新指定字段，并把更新后的记录写入 dest（model 类型的结构体指针或切片指针），省去调用方再查一次
+// 支持 RETURNING 的数据库（PostgreSQL、SQLite）一条语句完成；MySQL 在同一事务中先锁定匹配行的主键（FOR UPDATE），
+// 按主键更新后再查询，因此更新条件中的字段被修改也能取回记录。没有匹配的记录时返回 errors.ErrNotFound
+//
+//	var demo model.Demo
+//	err := r.UpdateAndReturn(ctx, &model.Demo{}, "id = ?", map[string]interface{}{"status": 0}, &demo, id)
+func (r *BaseRepository) UpdateAndReturn(ctx context.Context, model interface{}, query interface{}, updates map[string]interface{}, dest interface{}, args ...interface{}) error {
+	db := r.DB(ctx)
+	if utils.Contains(db.Callback().Update().Clauses, "RETURNING") {
+		result := db.Model(dest).Clauses(clause.Returning{}).Where(query, args...).Updates(updates)
+		if result.Error != nil {
+			return wrapWriteError(result.Error, "update and return failed")
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrNotFound
+		}
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return errors.Wrap(err, "update and return: parse model failed")
+	}
+	if stmt.Schema.PrioritizedPrimaryField == nil {
+		return errors.Newf("update and return: model %s has no primary key", stmt.Schema.Name)
+	}
+	pk := stmt.Schema.PrioritizedPrimaryField.DBName
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var ids []interface{}
+		err := tx.Model(model).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+			Where(query, args...).Pluck(pk, &ids).Error
+		if err != nil {
+			return errors.Wrap(err, "update and return: lock rows failed")
+		}
+		if len(ids) == 0 {
+			return errors.ErrNotFound
+		}
+
+		inIDs := clause.IN{Column: clause.Column{Name: pk}, Values: ids}
+		if err := tx.Model(model).Where(inIDs).Updates(updates).Error; err != nil {
+			return wrapWriteError(err, "update and return failed")
+		}
+		if err := tx.Where(inIDs).Find(dest).Error; err != nil {
+			return errors.Wrap(err, "update and return: reload failed")
+		}
+		return nil
+	})
 }
 
 // ========== 删除操作 ==========
 
 // Delete 删除记录
 func (r *BaseRepository) Delete(ctx context.Context, model interface{}, id interface{}) error {
-	err := r.db.WithContext(ctx).Delete(model, id).Error
+	err := r.DB(ctx).Delete(model, id).Error
 	if err != nil {
 		return errors.Wrap(err, "delete failed")
 	}
 	return nil
 }
 
-// DeleteWhere 根据条件删除
-func (r *BaseRepository) DeleteWhere(ctx context.Context, model interface{}, query interface{}, args ...interface{}) error {
-	err := r.db.WithContext(ctx).Where(query, args...).Delete(model).Error
+// DeleteWhere 根据条件删除，返回受影响的行数
+func (r *BaseRepository) DeleteWhere(ctx context.Context, model interface{}, query interface{}, args ...interface{}) (int64, error) {
+	result := r.DB(ctx).Where(query, args...).Delete(model)
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "delete where failed")
+	}
+	return result.RowsAffected, nil
+}
+
+// wrapWriteError 包装写操作错误，唯一约束冲突转换为 errors.ErrDuplicate（需开启 gorm.Config.TranslateError）
+func wrapWriteError(err error, msg string) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return errors.WithDetail(errors.WithStack(errors.ErrDuplicate), err.Error())
+	}
+	return errors.Wrap(err, msg)
+}
+
+// MustAffect 要求至少影响一行，用于包装返回 (行数, error) 的方法
+// 没有匹配到任何行时返回 errors.ErrNoRowsAffected
+//
+//	err := database.MustAffect(r.UpdateColumn(ctx, &model.User{}, "id = ?", "status", 1, id))
+func MustAffect(rowsAffected int64, err error) error {
 	if err != nil {
-		return errors.Wrap(err, "delete where failed")
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.WithStack(errors.ErrNoRowsAffected)
 	}
 	return nil
 }
@@ -179,15 +381,16 @@ func (r *BaseRepository) DeleteWhere(ctx context.Context, model interface{}, que
 // ========== 事务操作 ==========
 
 // Transaction 执行事务
+// 跨多个 Repository 的事务请使用 TxManager
 func (r *BaseRepository) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
-	return r.db.WithContext(ctx).Transaction(fn)
+	return r.DB(ctx).Transaction(fn)
 }
 
 // ========== 原生 SQL ==========
 
 // Exec 执行原生 SQL
 func (r *BaseRepository) Exec(ctx context.Context, sql string, values ...interface{}) error {
-	err := r.db.WithContext(ctx).Exec(sql, values...).Error
+	err := r.DB(ctx).Exec(sql, values...).Error
 	if err != nil {
 		return errors.Wrap(err, "exec sql failed")
 	}
@@ -196,7 +399,7 @@ func (r *BaseRepository) Exec(ctx context.Context, sql string, values ...interfa
 
 // Raw 执行原生查询
 func (r *BaseRepository) Raw(ctx context.Context, dest interface{}, sql string, values ...interface{}) error {
-	err := r.db.WithContext(ctx).Raw(sql, values...).Scan(dest).Error
+	err := r.DB(ctx).Raw(sql, values...).Scan(dest).Error
 	if err != nil {
 		return errors.Wrap(err, "raw query failed")
 	}
@@ -0,0 +1,229 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// testItem 测试用模型
+type testItem struct {
+	ID     uint   `gorm:"primaryKey"`
+	Name   string `gorm:"uniqueIndex"`
+	Status int
+}
+
+func newTestRepository(t *testing.T, items ...testItem) *BaseRepository {
+	t.Helper()
+
+	db := dbtest.Open(t, &testItem{})
+	for i := range items {
+		if err := db.Create(&items[i]).Error; err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	return NewBaseRepository(db)
+}
+
+func TestWritesReportZeroRowsAffected(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t, testItem{Name: "a", Status: 1})
+
+	writes := map[string]func() (int64, error){
+		"UpdateFields": func() (int64, error) {
+			return r.UpdateFields(ctx, &testItem{}, "id = ?", map[string]interface{}{"status": 2}, 999)
+		},
+		"UpdateColumn": func() (int64, error) {
+			return r.UpdateColumn(ctx, &testItem{}, "id = ?", "status", 2, 999)
+		},
+		"DeleteWhere": func() (int64, error) {
+			return r.DeleteWhere(ctx, &testItem{}, "name = ?", "missing")
+		},
+	}
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			rows, err := write()
+			if err != nil || rows != 0 {
+				t.Fatalf("rows = %d, err = %v, want 0, nil", rows, err)
+			}
+			if err := MustAffect(write()); !errors.Is(err, errors.ErrNoRowsAffected) {
+				t.Fatalf("MustAffect err = %v, want ErrNoRowsAffected", err)
+			}
+		})
+	}
+}
+
+func TestMustAffectMatchedRows(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t, testItem{Name: "a", Status: 1}, testItem{Name: "b", Status: 1})
+
+	rows, err := r.UpdateColumn(ctx, &testItem{}, "status = ?", "status", 0, 1)
+	if err != nil || rows != 2 {
+		t.Fatalf("rows = %d, err = %v, want 2, nil", rows, err)
+	}
+	if err := MustAffect(r.DeleteWhere(ctx, &testItem{}, "name = ?", "a")); err != nil {
+		t.Fatalf("MustAffect: %v", err)
+	}
+}
+
+func TestMustAffectKeepsWriteError(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t, testItem{Name: "a"}, testItem{Name: "b"})
+
+	// 唯一约束冲突：返回原错误而不是 ErrNoRowsAffected
+	err := MustAffect(r.UpdateFields(ctx, &testItem{}, "name = ?", map[string]interface{}{"name": "a"}, "b"))
+	if !errors.Is(err, errors.ErrDuplicate) {
+		t.Fatalf("err = %v, want ErrDuplicate", err)
+	}
+}
+
+func TestFirstOrCreateCreates(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	var item testItem
+	created, err := r.FirstOrCreate(ctx, &item, map[string]interface{}{"name": "new"}, map[string]interface{}{"status": 3})
+	if err != nil || !created {
+		t.Fatalf("created = %v, err = %v, want true, nil", created, err)
+	}
+	if item.ID == 0 || item.Name != "new" || item.Status != 3 {
+		t.Fatalf("item = %+v, want name new with default status 3", item)
+	}
+}
+
+func TestFirstOrCreateFindsExisting(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t, testItem{Name: "old", Status: 1})
+
+	var item testItem
+	created, err := r.FirstOrCreate(ctx, &item, map[string]interface{}{"name": "old"}, map[string]interface{}{"status": 3})
+	if err != nil || created {
+		t.Fatalf("created = %v, err = %v, want false, nil", created, err)
+	}
+	// defaults 只在创建时使用
+	if item.ID == 0 || item.Status != 1 {
+		t.Fatalf("item = %+v, want existing row with status 1", item)
+	}
+	if n, _ := r.Count(ctx, &testItem{}, "1 = 1"); n != 1 {
+		t.Fatalf("rows = %d, want 1", n)
+	}
+}
+
+func TestFirstOrCreateConcurrentInsert(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+	db := r.DB(ctx)
+
+	// 模拟并发：查询未命中后、插入前，另一个请求插入了相同的记录
+	raced := false
+	err := db.Callback().Create().Before("gorm:create").Register("test:race", func(tx *gorm.DB) {
+		if raced {
+			return
+		}
+		raced = true
+		if err := db.Session(&gorm.Session{NewDB: true}).Exec("INSERT INTO test_items (name, status) VALUES (?, ?)", "race", 7).Error; err != nil {
+			t.Errorf("concurrent insert: %v", err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var item testItem
+	created, err := r.FirstOrCreate(ctx, &item, map[string]interface{}{"name": "race"}, map[string]interface{}{"status": 1})
+	if err != nil || created {
+		t.Fatalf("created = %v, err = %v, want false, nil", created, err)
+	}
+	if item.Status != 7 {
+		t.Fatalf("item = %+v, want the concurrently inserted row", item)
+	}
+}
+
+// disableReturning 去掉 UPDATE 的 RETURNING 子句，模拟 MySQL（走事务内先更新后查询的分支）
+func disableReturning(db *gorm.DB) {
+	update := db.Callback().Update()
+	clauses := update.Clauses[:0:0]
+	for _, c := range update.Clauses {
+		if c != "RETURNING" {
+			clauses = append(clauses, c)
+		}
+	}
+	update.Clauses = clauses
+}
+
+func TestUpdateAndReturn(t *testing.T) {
+	for _, returning := range []bool{true, false} {
+		name := "returning"
+		if !returning {
+			name = "query after update"
+		}
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			r := newTestRepository(t, testItem{Name: "a", Status: 1}, testItem{Name: "b", Status: 1})
+			if !returning {
+				disableReturning(r.DB(ctx))
+			}
+
+			var statements []string
+			r.DB(ctx).Callback().Query().After("gorm:query").Register("test:count_query", func(tx *gorm.DB) {
+				statements = append(statements, "query")
+			})
+
+			// 更新条件中的字段被修改，也能取回记录
+			var item testItem
+			err := r.UpdateAndReturn(ctx, &testItem{}, "name = ? AND status = ?", map[string]interface{}{"status": 2}, &item, "a", 1)
+			if err != nil {
+				t.Fatalf("UpdateAndReturn: %v", err)
+			}
+			if item.ID == 0 || item.Name != "a" || item.Status != 2 {
+				t.Fatalf("dest = %+v, want updated row a", item)
+			}
+
+			// RETURNING 一条语句完成；否则锁定主键 + 重新查询
+			if want := map[bool]int{true: 0, false: 2}[returning]; len(statements) != want {
+				t.Fatalf("ran %d queries, want %d", len(statements), want)
+			}
+
+			var stored testItem
+			if err := r.DB(ctx).First(&stored, item.ID).Error; err != nil || stored.Status != 2 {
+				t.Fatalf("stored = %+v, %v", stored, err)
+			}
+
+			// 多行更新写入切片
+			var items []testItem
+			if err := r.UpdateAndReturn(ctx, &testItem{}, "status > ?", map[string]interface{}{"status": 3}, &items, 0); err != nil {
+				t.Fatalf("UpdateAndReturn slice: %v", err)
+			}
+			if len(items) != 2 || items[0].Status != 3 || items[1].Status != 3 {
+				t.Fatalf("dest = %+v, want 2 rows with status 3", items)
+			}
+
+			err = r.UpdateAndReturn(ctx, &testItem{}, "name = ?", map[string]interface{}{"status": 0}, &item, "missing")
+			if !errors.Is(err, errors.ErrNotFound) {
+				t.Fatalf("missing row: err = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestUpdateAndReturnDuplicate(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t, testItem{Name: "a"}, testItem{Name: "b"})
+	disableReturning(r.DB(ctx))
+
+	var item testItem
+	err := r.UpdateAndReturn(ctx, &testItem{}, "name = ?", map[string]interface{}{"name": "b"}, &item, "a")
+	if !errors.Is(err, errors.ErrDuplicate) {
+		t.Fatalf("err = %v, want ErrDuplicate", err)
+	}
+	// 事务回滚，记录未改变
+	var count int64
+	r.DB(ctx).Model(&testItem{}).Where("name = ?", "a").Count(&count)
+	if count != 1 {
+		t.Fatalf("row a count = %d after failed update, want 1", count)
+	}
+}
@@ -0,0 +1,17 @@
+package database
+
+import (
+	"context"
+
+	"go-api-template/pkg/errors"
+)
+
+// CheckContext 长时间循环（分批查询、分批写入、逐行遍历）每轮开始前调用
+// ctx 已取消或超时（如客户端断开）时返回包装了 ctx.Err() 的错误（errors.Is(err, context.Canceled) 成立），
+// 调用方应立即返回，尽早释放数据库连接；否则返回 nil
+func CheckContext(ctx context.Context, op string) error {
+	if err := ctx.Err(); err != nil {
+		return errors.Wrapf(err, "%s aborted", op)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"go-api-template/pkg/errors"
+)
+
+func TestCheckContext(t *testing.T) {
+	if err := CheckContext(context.Background(), "loop"); err != nil {
+		t.Fatalf("live context: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := CheckContext(ctx, "loop")
+	if !errors.Is(err, context.Canceled) || err.Error() != "loop aborted: context canceled" {
+		t.Fatalf("err = %v, want wrapped context.Canceled", err)
+	}
+}
+
+func TestFindInBatchesStopsWhenCancelled(t *testing.T) {
+	items := make([]testItem, 10)
+	for i := range items {
+		items[i] = testItem{Name: string(rune('a' + i))}
+	}
+	r := newTestRepository(t, items...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var batches []int
+	var dest []testItem
+	err := r.FindInBatches(ctx, &dest, 3, func(batch int) error {
+		batches = append(batches, batch)
+		cancel() // 客户端在第一批处理时断开
+		return nil
+	}, "1 = 1")
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if len(batches) != 1 {
+		t.Fatalf("processed batches %v after cancel, want only the first", batches)
+	}
+}
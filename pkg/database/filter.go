@@ -0,0 +1,81 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FilterOp 筛选操作符
+type FilterOp string
+
+const (
+	FilterEq   FilterOp = "eq"   // 等于
+	FilterGte  FilterOp = "gte"  // 大于等于
+	FilterLte  FilterOp = "lte"  // 小于等于
+	FilterLike FilterOp = "like" // 包含（LIKE %值%，值中的 % 和 _ 会被转义）
+	FilterIn   FilterOp = "in"   // 在列表中（逗号分隔）
+)
+
+// FilterField 可筛选字段（白名单），见 web.ParseFilter
+type FilterField struct {
+	Column string                            // 列名
+	Ops    []FilterOp                        // 允许的操作符
+	Parse  func(string) (interface{}, error) // 值转换（如 ParseFilterInt），为 nil 时按字符串比较
+}
+
+// Filter 单个筛选条件
+type Filter struct {
+	Column string
+	Op     FilterOp
+	Value  interface{} // FilterIn 时为 []interface{}
+}
+
+// FilterSet 筛选条件，多个条件之间为 AND
+type FilterSet []Filter
+
+// Scope 返回应用筛选条件的 GORM scope，用法：db.Scopes(filters.Scope())
+// 列名通过 clause.Column 引用，值作为参数绑定，不拼接到 SQL 中
+func (fs FilterSet) Scope() func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, f := range fs {
+			col := clause.Column{Name: f.Column}
+			switch f.Op {
+			case FilterEq:
+				db = db.Where(clause.Eq{Column: col, Value: f.Value})
+			case FilterGte:
+				db = db.Where(clause.Gte{Column: col, Value: f.Value})
+			case FilterLte:
+				db = db.Where(clause.Lte{Column: col, Value: f.Value})
+			case FilterLike:
+				db = db.Where(clause.Like{Column: col, Value: "%" + escapeLike(f.Value) + "%"})
+			case FilterIn:
+				values, _ := f.Value.([]interface{})
+				db = db.Where(clause.IN{Column: col, Values: values})
+			}
+		}
+		return db
+	}
+}
+
+// escapeLike 转义 LIKE 通配符（MySQL 默认转义字符为 \）
+func escapeLike(v interface{}) string {
+	s, _ := v.(string)
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// ParseFilterInt 将筛选值转换为整数
+func ParseFilterInt(s string) (interface{}, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// ParseFilterTime 将筛选值转换为时间，支持 RFC3339 和 2006-01-02（按本地时区）
+func ParseFilterTime(s string) (interface{}, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(time.DateOnly, s, time.Local)
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"go-api-template/pkg/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// fullTextMode 全文检索方式
+type fullTextMode int
+
+const (
+	fullTextLike     fullTextMode = iota // 无全文索引：LIKE '%kw%'，不排序
+	fullTextMySQL                        // MySQL FULLTEXT 索引：MATCH ... AGAINST
+	fullTextPostgres                     // PostgreSQL GIN 索引：to_tsvector @@ plainto_tsquery
+)
+
+// PostgresTextSearchConfig PostgreSQL 全文检索使用的分词配置，需与索引表达式一致
+const PostgresTextSearchConfig = "simple"
+
+// FullText 单张表的全文检索
+// 按数据库驱动选择检索方式：MySQL 使用 MATCH ... AGAINST，PostgreSQL 使用 to_tsvector / plainto_tsquery，
+// 结果按相关度倒序；表上没有对应的全文索引（或其他数据库）时退回 LIKE '%kw%'。
+// 是否存在索引在第一次检索时检测并缓存，新建索引后需重启服务生效
+//
+// 所需索引（列与 NewFullText 的 columns 一致、顺序相同）：
+//
+//	-- MySQL（中文内容使用 ngram 分词）
+//	ALTER TABLE demos ADD FULLTEXT INDEX ft_demos (title, content) WITH PARSER ngram;
+//	-- PostgreSQL
+//	CREATE INDEX ft_demos ON demos USING GIN (to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(content, '')));
+type FullText struct {
+	db      *gorm.DB
+	table   string
+	columns []string
+
+	mu       sync.Mutex
+	detected bool
+	mode     fullTextMode
+}
+
+// NewFullText 创建全文检索，columns 为参与检索的列
+func NewFullText(db *gorm.DB, table string, columns ...string) *FullText {
+	return &FullText{db: db, table: table, columns: columns}
+}
+
+// Match 在 query 上加上关键词条件；有全文索引时同时按相关度倒序排序，返回是否已排序
+// 未排序（LIKE）时由调用方决定默认排序
+func (f *FullText) Match(ctx context.Context, query *gorm.DB, keyword string) (*gorm.DB, bool, error) {
+	mode, err := f.detect(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch mode {
+	case fullTextMySQL:
+		match := "MATCH(" + strings.Join(f.columns, ", ") + ") AGAINST (? IN NATURAL LANGUAGE MODE)"
+		return query.Where(match, keyword).Clauses(orderByExpr(match, keyword)), true, nil
+	case fullTextPostgres:
+		doc := f.postgresDocument()
+		tsquery := "plainto_tsquery('" + PostgresTextSearchConfig + "', ?)"
+		return query.
+			Where(doc+" @@ "+tsquery, keyword).
+			Clauses(orderByExpr("ts_rank("+doc+", "+tsquery+")", keyword)), true, nil
+	default:
+		pattern := "%" + keyword + "%"
+		conds := make([]string, len(f.columns))
+		args := make([]interface{}, len(f.columns))
+		for i, col := range f.columns {
+			conds[i] = col + " LIKE ?"
+			args[i] = pattern
+		}
+		return query.Where(strings.Join(conds, " OR "), args...), false, nil
+	}
+}
+
+// postgresDocument PostgreSQL 检索文档表达式（与索引表达式一致才能使用索引）
+func (f *FullText) postgresDocument() string {
+	parts := make([]string, len(f.columns))
+	for i, col := range f.columns {
+		parts[i] = "coalesce(" + col + ", '')"
+	}
+	return "to_tsvector('" + PostgresTextSearchConfig + "', " + strings.Join(parts, " || ' ' || ") + ")"
+}
+
+// detect 检测检索方式，检测成功后缓存；检测失败时返回错误，下次重新检测
+func (f *FullText) detect(ctx context.Context) (fullTextMode, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.detected {
+		return f.mode, nil
+	}
+
+	mode := fullTextLike
+	db := f.db.WithContext(ctx)
+	switch db.Dialector.Name() {
+	case "mysql":
+		var indexes []struct {
+			IndexName  string
+			ColumnName string
+		}
+		err := db.Raw(`SELECT index_name AS index_name, column_name AS column_name
+			FROM information_schema.statistics
+			WHERE table_schema = DATABASE() AND table_name = ? AND index_type = 'FULLTEXT'
+			ORDER BY index_name, seq_in_index`, f.table).Scan(&indexes).Error
+		if err != nil {
+			return 0, errors.Wrap(err, "detect fulltext index failed")
+		}
+		// MATCH 的列必须与某个 FULLTEXT 索引的列完全一致
+		columns := make(map[string][]string)
+		for _, idx := range indexes {
+			columns[idx.IndexName] = append(columns[idx.IndexName], idx.ColumnName)
+		}
+		for _, cols := range columns {
+			if strings.EqualFold(strings.Join(cols, ","), strings.Join(f.columns, ",")) {
+				mode = fullTextMySQL
+				break
+			}
+		}
+	case "postgres":
+		var count int64
+		err := db.Raw(`SELECT count(*) FROM pg_indexes
+			WHERE schemaname = current_schema() AND tablename = ? AND indexdef LIKE '%to_tsvector%'`, f.table).
+			Scan(&count).Error
+		if err != nil {
+			return 0, errors.Wrap(err, "detect fulltext index failed")
+		}
+		if count > 0 {
+			mode = fullTextPostgres
+		}
+	}
+
+	f.mode, f.detected = mode, true
+	return mode, nil
+}
+
+// orderByExpr 按带参数的表达式倒序排序
+func orderByExpr(expr string, vars ...interface{}) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{SQL: expr + " DESC", Vars: vars, WithoutParentheses: true}}
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"go-api-template/pkg/database/dbtest"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type testArticle struct {
+	ID      uint   `gorm:"primaryKey"`
+	Title   string `gorm:"size:255"`
+	Content string `gorm:"type:text"`
+}
+
+func (testArticle) TableName() string { return "test_articles" }
+
+// matchSQL 以 DryRun 生成指定检索方式的 SQL（不访问数据库，与驱动无关）
+func matchSQL(t *testing.T, mode fullTextMode) string {
+	t.Helper()
+
+	db := dbtest.Open(t)
+	f := NewFullText(db, "test_articles", "title", "content")
+	f.mode, f.detected = mode, true
+
+	query := db.Session(&gorm.Session{DryRun: true}).Model(&testArticle{})
+	query, _, err := f.Match(context.Background(), query, "golang")
+	if err != nil {
+		t.Fatalf("Match: %v", err)
+	}
+	var rows []testArticle
+	return query.Find(&rows).Statement.SQL.String()
+}
+
+func TestFullTextMySQLQuery(t *testing.T) {
+	sql := matchSQL(t, fullTextMySQL)
+	for _, want := range []string{
+		"MATCH(title, content) AGAINST (? IN NATURAL LANGUAGE MODE)",
+		"ORDER BY MATCH(title, content) AGAINST (? IN NATURAL LANGUAGE MODE) DESC",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Fatalf("sql = %s, want %q", sql, want)
+		}
+	}
+}
+
+func TestFullTextPostgresQuery(t *testing.T) {
+	sql := matchSQL(t, fullTextPostgres)
+	doc := "to_tsvector('simple', coalesce(title, '') || ' ' || coalesce(content, ''))"
+	for _, want := range []string{
+		doc + " @@ plainto_tsquery('simple', ?)",
+		"ORDER BY ts_rank(" + doc + ", plainto_tsquery('simple', ?)) DESC",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Fatalf("sql = %s, want %q", sql, want)
+		}
+	}
+}
+
+// 其他数据库（SQLite）没有全文索引：退回 LIKE，不排序
+func TestFullTextFallsBackToLike(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t, &testArticle{})
+	db.Create(&[]testArticle{
+		{Title: "learn golang", Content: "basics"},
+		{Title: "rust", Content: "golang comparison"},
+		{Title: "python", Content: "scripts"},
+	})
+
+	f := NewFullText(db, "test_articles", "title", "content")
+	query, ranked, err := f.Match(ctx, db.WithContext(ctx).Model(&testArticle{}), "golang")
+	if err != nil {
+		t.Fatalf("Match: %v", err)
+	}
+	if ranked {
+		t.Fatal("LIKE fallback reported ranked results")
+	}
+	var rows []testArticle
+	if err := query.Order("id").Find(&rows).Error; err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Title != "learn golang" || rows[1].Title != "rust" {
+		t.Fatalf("rows = %+v, want the two golang articles", rows)
+	}
+	if f.mode != fullTextLike || !f.detected {
+		t.Fatalf("mode = %d detected = %v, want cached LIKE", f.mode, f.detected)
+	}
+}
+
+// TestFullTextMySQL 在真实 MySQL 上验证索引检测和相关度排序，设置 TEST_MYSQL_DSN 时运行
+//
+//	TEST_MYSQL_DSN='root:root@tcp(127.0.0.1:3306)/test?parseTime=true' go test ./pkg/database -run FullTextMySQL
+func TestFullTextMySQL(t *testing.T) {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MYSQL_DSN not set")
+	}
+
+	ctx := context.Background()
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open mysql: %v", err)
+	}
+	_ = db.Migrator().DropTable(&testArticle{})
+	if err := db.AutoMigrate(&testArticle{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Migrator().DropTable(&testArticle{}) })
+
+	// 没有 FULLTEXT 索引时退回 LIKE
+	if _, ranked, err := NewFullText(db, "test_articles", "title", "content").Match(ctx, db.Model(&testArticle{}), "golang"); err != nil || ranked {
+		t.Fatalf("without index: ranked = %v, err = %v", ranked, err)
+	}
+
+	if err := db.Exec("ALTER TABLE test_articles ADD FULLTEXT INDEX ft_test_articles (title, content)").Error; err != nil {
+		t.Fatalf("create fulltext index: %v", err)
+	}
+	db.Create(&[]testArticle{
+		{Title: "python", Content: "golang mentioned once"},
+		{Title: "golang golang", Content: "golang everywhere"},
+		{Title: "unrelated", Content: "nothing here"},
+	})
+
+	f := NewFullText(db, "test_articles", "title", "content")
+	query, ranked, err := f.Match(ctx, db.Model(&testArticle{}), "golang")
+	if err != nil {
+		t.Fatalf("Match: %v", err)
+	}
+	if !ranked {
+		t.Fatal("fulltext index not detected")
+	}
+	var rows []testArticle
+	if err := query.Find(&rows).Error; err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Title != "golang golang" {
+		t.Fatalf("rows = %+v, want most relevant first", rows)
+	}
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"sync"
+
+	"go-api-template/pkg/errors"
+)
+
+// loaderKey context 中存放 Loader 的 Key
+type loaderKey struct{}
+
+// errLoaderPanic 查询函数 panic 时等待中的调用收到的错误
+var errLoaderPanic = errors.New("loader fetch panicked")
+
+// Loader 请求级查询结果缓存（dataloader 风格）
+// 同一请求内相同 Key 的查询只执行一次：结果在请求结束前被复用，并发的相同查询合并为一次
+// 只缓存成功的结果；数据变更后应调用 Forget 清除对应 Key
+type Loader struct {
+	mu      sync.Mutex
+	entries map[string]*loaderEntry
+}
+
+// loaderEntry 单个 Key 的查询结果
+type loaderEntry struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// NewLoader 创建请求级 Loader
+func NewLoader() *Loader {
+	return &Loader{entries: make(map[string]*loaderEntry)}
+}
+
+// WithLoader 将 Loader 绑定到 context
+func WithLoader(ctx context.Context, l *Loader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+// LoaderFromContext 获取 context 中的 Loader，不存在时返回 nil
+// nil Loader 可以安全使用：Load 直接执行查询，Forget 不做任何事
+func LoaderFromContext(ctx context.Context) *Loader {
+	l, _ := ctx.Value(loaderKey{}).(*Loader)
+	return l
+}
+
+// Load 获取 key 对应的结果，首次调用时执行 fetch
+func (l *Loader) Load(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	if l == nil {
+		return fetch()
+	}
+
+	l.mu.Lock()
+	if e, ok := l.entries[key]; ok {
+		l.mu.Unlock()
+		<-e.done
+		return e.value, e.err
+	}
+	e := &loaderEntry{done: make(chan struct{})}
+	l.entries[key] = e
+	l.mu.Unlock()
+
+	// fetch panic 时也要唤醒等待中的调用，避免其永久阻塞
+	e.err = errLoaderPanic
+	defer func() {
+		if e.err != nil {
+			// 失败的结果不缓存，后续调用重新查询（等待中的调用仍返回本次错误）
+			l.mu.Lock()
+			if l.entries[key] == e {
+				delete(l.entries, key)
+			}
+			l.mu.Unlock()
+		}
+		close(e.done)
+	}()
+
+	e.value, e.err = fetch()
+	return e.value, e.err
+}
+
+// Forget 清除 key 的缓存结果
+func (l *Loader) Forget(key string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, key)
+}
+
+// Load 使用 context 中的 Loader 获取 key 对应的结果（类型安全版本）
+// context 中没有 Loader 时直接执行 fetch
+func Load[T any](ctx context.Context, key string, fetch func() (T, error)) (T, error) {
+	value, err := LoaderFromContext(ctx).Load(key, func() (interface{}, error) {
+		return fetch()
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return value.(T), nil
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"go-api-template/pkg/metrics"
+
+	"gorm.io/gorm"
+)
+
+// 数据库连接池指标
+const (
+	MetricDBOpenConnections = "db_pool_open_connections"      // 已建立的连接数（使用中 + 空闲）
+	MetricDBInUse           = "db_pool_in_use"                // 使用中的连接数
+	MetricDBIdle            = "db_pool_idle"                  // 空闲连接数
+	MetricDBWaitCount       = "db_pool_wait_count"            // 累计等待连接的次数
+	MetricDBWaitDuration    = "db_pool_wait_duration_seconds" // 累计等待连接的时长
+
+	MetricDBQueryDuration = "db_query_duration_seconds" // SQL 耗时分布（按 operation 区分）
+)
+
+// queryLabels SQL 耗时指标的 Label
+var queryLabels = []string{"operation"}
+
+// StartPoolMetrics 定期采集连接池统计并导出为 Prometheus 仪表盘
+// 返回停止采集的函数，应在关闭数据库前调用
+func StartPoolMetrics(db *gorm.DB, interval time.Duration) (stop func(), err error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库实例失败: %w", err)
+	}
+
+	return metrics.StartSampler(interval, func() {
+		stats := sqlDB.Stats()
+		metrics.Set(MetricDBOpenConnections, float64(stats.OpenConnections))
+		metrics.Set(MetricDBInUse, float64(stats.InUse))
+		metrics.Set(MetricDBIdle, float64(stats.Idle))
+		metrics.Set(MetricDBWaitCount, float64(stats.WaitCount))
+		metrics.Set(MetricDBWaitDuration, stats.WaitDuration.Seconds())
+	}), nil
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/metrics"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestStartPoolMetricsReportsNonNegative(t *testing.T) {
+	db := dbtest.Open(t, &testItem{})
+	if err := db.Create(&testItem{Name: "a"}).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	stop, err := StartPoolMetrics(db, time.Hour)
+	if err != nil {
+		t.Fatalf("StartPoolMetrics: %v", err)
+	}
+	defer stop()
+
+	for _, name := range []string{MetricDBOpenConnections, MetricDBInUse, MetricDBIdle, MetricDBWaitCount, MetricDBWaitDuration} {
+		var m dto.Metric
+		if err := metrics.Gauge(name).Write(&m); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if v := m.GetGauge().GetValue(); v < 0 {
+			t.Fatalf("%s = %v, want >= 0", name, v)
+		}
+	}
+	// 已执行过查询，至少有一个连接
+	var m dto.Metric
+	_ = metrics.Gauge(MetricDBOpenConnections).Write(&m)
+	if m.GetGauge().GetValue() < 1 {
+		t.Fatalf("%s = %v, want >= 1", MetricDBOpenConnections, m.GetGauge().GetValue())
+	}
+
+	// stop 可重复调用
+	stop()
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// DefaultMigrationTable 迁移版本表的默认表名（golang-migrate 的默认表名）
+const DefaultMigrationTable = "schema_migrations"
+
+// SchemaVersionCheck 检查数据库已执行的迁移版本是否与 want 一致，用于就绪检查
+// 迁移版本表与 golang-migrate 的格式一致：只有一行，version 为当前版本，dirty 表示上次迁移执行失败未完成；
+// 表不存在、没有记录、dirty 或版本不一致时返回错误（数据库版本高于 want 同样视为不一致：旧代码不一定兼容新的表结构）
+func SchemaVersionCheck(db *gorm.DB, table string, want uint64) func(ctx context.Context) error {
+	if table == "" {
+		table = DefaultMigrationTable
+	}
+	return func(ctx context.Context) error {
+		var version uint64
+		var dirty bool
+		err := db.WithContext(ctx).Table(table).Select("version", "dirty").Limit(1).Row().Scan(&version, &dirty)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("数据库未执行迁移（%s 表中没有记录），期望版本 %d", table, want)
+		}
+		if err != nil {
+			return fmt.Errorf("查询迁移版本失败: %w", err)
+		}
+		if dirty {
+			return fmt.Errorf("数据库迁移版本 %d 未完成（dirty），需要修复后重新执行迁移", version)
+		}
+		if version != want {
+			return fmt.Errorf("数据库迁移版本 %d 与期望版本 %d 不一致", version, want)
+		}
+		return nil
+	}
+}
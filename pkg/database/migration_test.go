@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go-api-template/pkg/database/dbtest"
+)
+
+func TestSchemaVersionCheck(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	check := SchemaVersionCheck(db, "", 3)
+
+	// 迁移版本表不存在
+	if err := check(ctx); err == nil || !strings.Contains(err.Error(), "查询迁移版本失败") {
+		t.Fatalf("missing table: err = %v", err)
+	}
+
+	if err := db.Exec("CREATE TABLE " + DefaultMigrationTable + " (version bigint NOT NULL, dirty boolean NOT NULL)").Error; err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	if err := check(ctx); err == nil || !strings.Contains(err.Error(), "没有记录") {
+		t.Fatalf("empty table: err = %v", err)
+	}
+
+	tests := []struct {
+		version uint64
+		dirty   bool
+		want    string // 错误信息包含的内容，为空表示检查通过
+	}{
+		{2, false, "数据库迁移版本 2 与期望版本 3 不一致"},
+		{4, false, "数据库迁移版本 4 与期望版本 3 不一致"}, // 数据库版本更高同样不一致
+		{3, true, "dirty"},
+		{3, false, ""},
+	}
+	for _, tt := range tests {
+		db.Exec("DELETE FROM " + DefaultMigrationTable)
+		if err := db.Exec("INSERT INTO "+DefaultMigrationTable+" (version, dirty) VALUES (?, ?)", tt.version, tt.dirty).Error; err != nil {
+			t.Fatalf("insert version: %v", err)
+		}
+		err := check(ctx)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Fatalf("version %d: err = %v, want nil", tt.version, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Fatalf("version %d dirty %v: err = %v, want %q", tt.version, tt.dirty, err, tt.want)
+		}
+	}
+}
+
+func TestSchemaVersionCheckCustomTable(t *testing.T) {
+	db := dbtest.Open(t)
+	db.Exec("CREATE TABLE app_migrations (version bigint NOT NULL, dirty boolean NOT NULL)")
+	db.Exec("INSERT INTO app_migrations (version, dirty) VALUES (7, false)")
+
+	if err := SchemaVersionCheck(db, "app_migrations", 7)(context.Background()); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -13,7 +14,17 @@ import (
 
 // NewMySQLDB 创建 MySQL 数据库连接
 func NewMySQLDB(cfg *config.Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=%t&loc=%s",
+	// UTC 策略：驱动读写时间均按 UTC 处理，自动时间戳也使用 UTC
+	loc := cfg.Database.Loc
+	nowFunc := time.Now
+	if cfg.Database.UTC {
+		loc = "UTC"
+		nowFunc = func() time.Time { return time.Now().UTC() }
+	}
+
+	// clientFoundRows=true：UPDATE 返回匹配的行数而不是实际变更的行数，
+	// 这样更新为相同值时 RowsAffected 也不为 0（MustAffect 依赖此行为）
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=%t&loc=%s&clientFoundRows=true",
 		cfg.Database.Username,
 		cfg.Database.Password,
 		cfg.Database.Host,
@@ -21,16 +32,25 @@ func NewMySQLDB(cfg *config.Config) (*gorm.DB, error) {
 		cfg.Database.Database,
 		cfg.Database.Charset,
 		cfg.Database.ParseTime,
-		cfg.Database.Loc,
+		loc,
 	)
 
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
+		// 将驱动错误转换为 GORM 通用错误（如唯一约束冲突为 gorm.ErrDuplicatedKey）
+		TranslateError: true,
+		NowFunc:        nowFunc,
+		NamingStrategy: NewNamingStrategy(cfg.Database.Naming),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("连接数据库失败: %w", err)
 	}
 
+	// 记录 SQL 耗时（用于 Server-Timing）
+	if err := registerTimingCallbacks(db); err != nil {
+		return nil, fmt.Errorf("注册数据库回调失败: %w", err)
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("获取数据库实例失败: %w", err)
@@ -42,3 +62,21 @@ func NewMySQLDB(cfg *config.Config) (*gorm.DB, error) {
 
 	return db, nil
 }
+
+// Ping 检查数据库连接是否可用
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("获取数据库实例失败: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Close 关闭数据库连接池
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("获取数据库实例失败: %w", err)
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/lifecycle"
+	"go-api-template/pkg/logger/logtest"
+)
+
+// 反复"启动、关闭"应用：每次关闭钩子都关闭连接池，不留下打开的连接
+func TestCloseOnShutdownReleasesConnections(t *testing.T) {
+	logtest.New(t)
+
+	for i := 0; i < 5; i++ {
+		db := dbtest.Open(t)
+		lc := lifecycle.NewManager(&config.Config{Server: config.ServerConfig{ShutdownTimeout: 1}})
+		lc.OnShutdown("database", func(ctx context.Context) error {
+			return Close(db)
+		})
+
+		// 并发查询，使连接池中打开多个连接
+		var wg sync.WaitGroup
+		for j := 0; j < 4; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var n int
+				_ = db.Raw("SELECT 1").Scan(&n).Error
+			}()
+		}
+		wg.Wait()
+
+		sqlDB, err := db.DB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sqlDB.Stats().OpenConnections == 0 {
+			t.Fatal("expected open connections before shutdown")
+		}
+
+		lc.Shutdown()
+
+		if n := sqlDB.Stats().OpenConnections; n != 0 {
+			t.Fatalf("round %d: %d connections still open after shutdown", i, n)
+		}
+		if err := Ping(context.Background(), db); err == nil {
+			t.Fatalf("round %d: ping succeeded after shutdown", i)
+		}
+	}
+}
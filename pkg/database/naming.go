@@ -0,0 +1,36 @@
+package database
+
+import (
+	"go-api-template/pkg/config"
+
+	"gorm.io/gorm/schema"
+)
+
+// namingStrategy 在 GORM 默认命名策略（snake_case、复数表名）的基础上支持表名前缀、单数表名和列名映射
+type namingStrategy struct {
+	schema.NamingStrategy
+	columns map[string]string // Go 字段名 或 表名.字段名 -> 列名
+}
+
+// NewNamingStrategy 根据配置创建命名策略
+// 只影响没有实现 TableName 方法的模型和没有设置 column 标签的字段
+func NewNamingStrategy(cfg config.NamingConfig) schema.Namer {
+	return namingStrategy{
+		NamingStrategy: schema.NamingStrategy{
+			TablePrefix:   cfg.TablePrefix,
+			SingularTable: cfg.SingularTable,
+		},
+		columns: cfg.Columns,
+	}
+}
+
+// ColumnName 列名：优先使用 表名.字段名 的映射，其次 字段名 的映射，否则按默认规则
+func (ns namingStrategy) ColumnName(table, column string) string {
+	if name, ok := ns.columns[table+"."+column]; ok {
+		return name
+	}
+	if name, ok := ns.columns[column]; ok {
+		return name
+	}
+	return ns.NamingStrategy.ColumnName(table, column)
+}
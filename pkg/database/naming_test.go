@@ -0,0 +1,94 @@
+package database
+
+import (
+	"testing"
+
+	"go-api-template/pkg/config"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testOrderItem 没有 TableName 方法的模型
+type testOrderItem struct {
+	ID        uint `gorm:"primaryKey"`
+	OrderNo   string
+	UnitPrice int
+	Quantity  int `gorm:"column:qty"` // column 标签优先于映射
+}
+
+// testLegacy 自定义了表名的模型
+type testLegacy struct {
+	ID uint `gorm:"primaryKey"`
+}
+
+func (testLegacy) TableName() string { return "legacy_records" }
+
+func openWithNaming(t *testing.T, cfg config.NamingConfig) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		NamingStrategy: NewNamingStrategy(cfg),
+	})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1) // 内存库每个连接独立
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	if err := db.AutoMigrate(&testOrderItem{}, &testLegacy{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestNamingStrategyTableName(t *testing.T) {
+	db := openWithNaming(t, config.NamingConfig{
+		TablePrefix:   "t_",
+		SingularTable: true,
+		Columns: map[string]string{
+			"OrderNo":                     "order_number",
+			"t_test_order_item.UnitPrice": "price_cents",
+			"other_table.ID":              "other_id",
+			"Quantity":                    "quantity_ignored",
+		},
+	})
+
+	for _, table := range []string{"t_test_order_item", "legacy_records"} {
+		if !db.Migrator().HasTable(table) {
+			t.Fatalf("table %s not created", table)
+		}
+	}
+	if db.Migrator().HasTable("test_order_items") {
+		t.Fatal("default plural table name used")
+	}
+
+	for _, column := range []string{"id", "order_number", "price_cents", "qty"} {
+		if !db.Migrator().HasColumn(&testOrderItem{}, column) {
+			t.Fatalf("column %s missing", column)
+		}
+	}
+
+	// 读写使用映射后的表名和列名
+	if err := db.Create(&testOrderItem{OrderNo: "A1", UnitPrice: 100, Quantity: 2}).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	var price int
+	if err := db.Table("t_test_order_item").Where("order_number = ?", "A1").Pluck("price_cents", &price).Error; err != nil || price != 100 {
+		t.Fatalf("price_cents = %d, %v", price, err)
+	}
+}
+
+func TestNamingStrategyDefault(t *testing.T) {
+	db := openWithNaming(t, config.NamingConfig{})
+
+	if !db.Migrator().HasTable("test_order_items") {
+		t.Fatal("default table name test_order_items not used")
+	}
+	if !db.Migrator().HasColumn(&testOrderItem{}, "unit_price") {
+		t.Fatal("default column name unit_price not used")
+	}
+}
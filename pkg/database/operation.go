@@ -0,0 +1,21 @@
+package database
+
+import "context"
+
+// operationKey context 中业务操作名的 key
+type operationKey struct{}
+
+// WithOperation 为 ctx 中执行的 SQL 标记业务操作名（如 demo.search）
+// 查询耗时指标按操作名区分，而不是原始 SQL；未标记时使用 GORM 操作类型（query、create 等）
+func WithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey{}, name)
+}
+
+// OperationFromContext 获取 ctx 中的业务操作名，未标记时返回空字符串
+func OperationFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	name, _ := ctx.Value(operationKey{}).(string)
+	return name
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// deletedAtType 软删除字段类型
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// purgeTarget 登记的软删除模型
+type purgeTarget struct {
+	model     interface{}
+	table     string
+	pkColumn  string
+	delColumn string
+	retention time.Duration
+}
+
+// Purger 软删除数据清理：物理删除 DeletedAt 早于保留期的记录
+// 只清理通过 Register 登记的模型，按批删除，避免长事务和大量锁
+type Purger struct {
+	db        *gorm.DB
+	batchSize int
+
+	mu      sync.Mutex
+	targets []purgeTarget
+}
+
+// NewPurger 创建软删除清理器，batchSize 为每批删除的条数（不大于 0 时为 500）
+func NewPurger(db *gorm.DB, batchSize int) *Purger {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &Purger{db: db, batchSize: batchSize}
+}
+
+// Register 登记需要清理的模型，模型必须包含 gorm.DeletedAt 字段且有主键
+//
+//	purger.Register(&model.Order{}, 30*24*time.Hour)
+func (p *Purger) Register(model interface{}, retention time.Duration) error {
+	if retention <= 0 {
+		return fmt.Errorf("purge: 保留期必须大于 0")
+	}
+
+	stmt := &gorm.Statement{DB: p.db}
+	if err := stmt.Parse(model); err != nil {
+		return fmt.Errorf("purge: 解析模型失败: %w", err)
+	}
+	sch := stmt.Schema
+
+	var delColumn string
+	for _, field := range sch.Fields {
+		if field.FieldType == deletedAtType && field.DBName != "" {
+			delColumn = field.DBName
+			break
+		}
+	}
+	if delColumn == "" {
+		return fmt.Errorf("purge: 模型 %s 没有 gorm.DeletedAt 字段", sch.Name)
+	}
+	if sch.PrioritizedPrimaryField == nil {
+		return fmt.Errorf("purge: 模型 %s 没有主键", sch.Name)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.targets = append(p.targets, purgeTarget{
+		model:     model,
+		table:     sch.Table,
+		pkColumn:  sch.PrioritizedPrimaryField.DBName,
+		delColumn: delColumn,
+		retention: retention,
+	})
+	return nil
+}
+
+// Run 清理所有登记模型的过期软删除记录，可作为定时任务执行
+// 单个模型失败时继续清理其他模型，返回遇到的第一个错误
+func (p *Purger) Run(ctx context.Context) error {
+	p.mu.Lock()
+	targets := append([]purgeTarget(nil), p.targets...)
+	p.mu.Unlock()
+
+	var firstErr error
+	for _, t := range targets {
+		cutoff := time.Now().Add(-t.retention)
+		purged, err := p.purge(ctx, t, cutoff)
+
+		fields := []logger.Field{
+			logger.String("table", t.table),
+			logger.Int64("purged", purged),
+			logger.Time("cutoff", cutoff),
+		}
+		if err != nil {
+			logger.Ctx(ctx).Error("purge soft-deleted rows failed", append(fields, logger.Err(err))...)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		logger.Ctx(ctx).Info("purged soft-deleted rows", fields...)
+	}
+	return firstErr
+}
+
+// purge 按批删除单个模型中 DeletedAt 早于 cutoff 的记录，返回删除的总条数
+// 先查出一批主键再按主键删除（DELETE 不支持 LIMIT 的数据库同样适用）
+func (p *Purger) purge(ctx context.Context, t purgeTarget, cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		if err := CheckContext(ctx, "purge"); err != nil {
+			return total, err
+		}
+
+		var ids []interface{}
+		err := p.db.WithContext(ctx).Unscoped().Model(t.model).
+			Where(clause.Lt{Column: clause.Column{Name: t.delColumn}, Value: cutoff}).
+			Order(clause.OrderByColumn{Column: clause.Column{Name: t.pkColumn}}).
+			Limit(p.batchSize).
+			Pluck(t.pkColumn, &ids).Error
+		if err != nil {
+			return total, errors.Wrap(err, "query expired rows failed")
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		result := p.db.WithContext(ctx).Unscoped().
+			Where(clause.IN{Column: clause.Column{Name: t.pkColumn}, Values: ids}).
+			Delete(t.model)
+		if result.Error != nil {
+			return total, errors.Wrap(result.Error, "delete expired rows failed")
+		}
+		total += result.RowsAffected
+
+		if len(ids) < p.batchSize {
+			return total, nil
+		}
+	}
+}
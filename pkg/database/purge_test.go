@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/logger/logtest"
+
+	"gorm.io/gorm"
+)
+
+// testTrashItem 带软删除字段的测试模型
+type testTrashItem struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	DeletedAt gorm.DeletedAt
+}
+
+func TestPurgeOnlyExpiredSoftDeletedRows(t *testing.T) {
+	logs := logtest.New(t)
+	db := dbtest.Open(t, &testTrashItem{})
+	now := time.Now()
+
+	seed := map[string]*time.Time{
+		"live": nil,
+	}
+	for _, name := range []string{"old-1", "old-2", "old-3", "old-4", "old-5"} {
+		at := now.Add(-40 * 24 * time.Hour)
+		seed[name] = &at
+	}
+	recent := now.Add(-time.Hour)
+	seed["recent"] = &recent
+
+	for name, deletedAt := range seed {
+		item := testTrashItem{Name: name}
+		if deletedAt != nil {
+			item.DeletedAt = gorm.DeletedAt{Time: *deletedAt, Valid: true}
+		}
+		if err := db.Create(&item).Error; err != nil {
+			t.Fatalf("seed %s: %v", name, err)
+		}
+	}
+
+	// 批大小 2：5 条过期记录分 3 批删除
+	p := NewPurger(db, 2)
+	if err := p.Register(&testTrashItem{}, 30*24*time.Hour); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var names []string
+	if err := db.Unscoped().Model(&testTrashItem{}).Order("name").Pluck("name", &names).Error; err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(names) != 2 || names[0] != "live" || names[1] != "recent" {
+		t.Fatalf("remaining rows = %v, want [live recent]", names)
+	}
+
+	entries := logs.FilterMessage("purged soft-deleted rows").All()
+	if len(entries) != 1 || entries[0].ContextMap()["purged"] != int64(5) {
+		t.Fatalf("purge log = %v, want purged=5", entries)
+	}
+}
+
+func TestPurgeRegisterValidation(t *testing.T) {
+	p := NewPurger(dbtest.Open(t), 0)
+
+	if err := p.Register(&testTrashItem{}, 0); err == nil {
+		t.Fatal("zero retention accepted")
+	}
+	// 没有 DeletedAt 字段的模型不能登记
+	if err := p.Register(&testItem{}, time.Hour); err == nil {
+		t.Fatal("model without DeletedAt accepted")
+	}
+}
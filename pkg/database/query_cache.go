@@ -0,0 +1,168 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go-api-template/pkg/cache"
+	"go-api-template/pkg/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+)
+
+const (
+	// queryCacheTTLKey CacheFor 在 Statement 中设置的缓存时间
+	queryCacheTTLKey = "query_cache:ttl"
+	// queryCacheVersionTTL 表版本号的保存时间，远大于查询结果的缓存时间
+	queryCacheVersionTTL = 24 * time.Hour
+)
+
+// volatileSQLPattern 结果随时间或每次执行变化的函数、加锁读，这类查询不缓存
+var volatileSQLPattern = regexp.MustCompile(`(?i)\b(now|sysdate|curdate|curtime|current_date|current_time|current_timestamp|localtime|localtimestamp|utc_date|utc_time|utc_timestamp|unix_timestamp|rand|random|uuid|uuid_short|gen_random_uuid|last_insert_id|connection_id|found_rows)\b|\bfor\s+(update|share)\b|\block\s+in\s+share\s+mode\b`)
+
+// CacheFor 查询结果缓存 ttl 的 Scope（需先通过 RegisterQueryCache 启用，未启用时不生效）
+// 只适合读多写少、允许短时间不一致的查询，ttl 应尽量短：
+//
+//	db.WithContext(ctx).Scopes(database.CacheFor(10 * time.Second)).Find(&demos)
+//
+// 缓存 Key 为 表版本 + SQL 及参数的哈希；通过 GORM 对该表执行 Create/Update/Delete 后表版本变化，之前的结果不再命中。
+// 以下查询即使加了 CacheFor 也直接查库：事务中的查询、多表查询（JOIN、子查询）、
+// 含 NOW()、RAND() 等易变函数或 FOR UPDATE 等加锁读的查询。
+// 结果按 JSON 编码缓存，json:"-" 的字段不会被缓存（命中时为零值），不要用于这类模型
+func CacheFor(ttl time.Duration) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Set(queryCacheTTLKey, ttl)
+	}
+}
+
+// queryCache 查询结果缓存
+type queryCache struct {
+	cache cache.Cache
+	seq   atomic.Uint64 // 表版本号后缀，同一纳秒内多次写入时仍能区分
+}
+
+// cachedResult 缓存的查询结果
+type cachedResult struct {
+	Rows int64           `json:"rows"`
+	Data json.RawMessage `json:"data"`
+}
+
+// RegisterQueryCache 启用查询结果缓存：替换 GORM 的查询回调，并在写操作后使对应表的缓存失效
+// 只有 GORM 的 Create/Update/Delete 会使缓存失效，Exec 执行的原生 SQL 不会；
+// 事务中的写入在语句执行时即失效，提交前并发执行的查询可能把旧数据重新写入缓存，最多持续该查询的 ttl；
+// 表版本号保存在 c 中，使用 Redis 时多实例共享，使用内存缓存时只在本实例内失效
+func RegisterQueryCache(db *gorm.DB, c cache.Cache) error {
+	qc := &queryCache{cache: c}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Query().Replace("gorm:query", qc.query),
+		cb.Create().After("gorm:create").Register("query_cache:invalidate_create", qc.invalidate),
+		cb.Update().After("gorm:update").Register("query_cache:invalidate_update", qc.invalidate),
+		cb.Delete().After("gorm:delete").Register("query_cache:invalidate_delete", qc.invalidate),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// query 查询回调：标记了 CacheFor 且可以缓存的查询先读缓存，未命中时查库并写入缓存
+func (qc *queryCache) query(db *gorm.DB) {
+	value, ok := db.Get(queryCacheTTLKey)
+	ttl, _ := value.(time.Duration)
+	if !ok || ttl <= 0 || db.Error != nil || db.DryRun || inTransaction(db) || db.Statement.Table == "" {
+		callbacks.Query(db)
+		return
+	}
+
+	callbacks.BuildQuerySQL(db)
+	if db.Error != nil {
+		return
+	}
+	sql := db.Statement.SQL.String()
+	if !cacheableSQL(sql) {
+		callbacks.Query(db) // SQL 已生成，不会重复生成
+		return
+	}
+
+	ctx := db.Statement.Context
+	key := qc.resultKey(ctx, db.Statement.Table, db.Dialector.Explain(sql, db.Statement.Vars...))
+	if data, err := qc.cache.Get(ctx, key); err == nil {
+		var result cachedResult
+		if json.Unmarshal([]byte(data), &result) == nil && json.Unmarshal(result.Data, db.Statement.Dest) == nil {
+			db.RowsAffected = result.Rows
+			if db.Statement.Result != nil {
+				db.Statement.Result.RowsAffected = result.Rows
+			}
+			if result.Rows == 0 && db.Statement.RaiseErrorOnNotFound {
+				db.AddError(gorm.ErrRecordNotFound)
+			}
+			return
+		}
+	}
+
+	callbacks.Query(db)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		return
+	}
+	data, err := json.Marshal(db.Statement.Dest)
+	if err != nil {
+		return
+	}
+	encoded, err := json.Marshal(cachedResult{Rows: db.RowsAffected, Data: data})
+	if err != nil {
+		return
+	}
+	_ = qc.cache.Set(ctx, key, string(encoded), ttl) // 写缓存失败不影响查询结果
+}
+
+// invalidate 写操作成功后更新表版本号，该表之前缓存的查询结果不再命中
+func (qc *queryCache) invalidate(db *gorm.DB) {
+	if db.Error != nil || db.DryRun || db.Statement.Table == "" {
+		return
+	}
+	version := strconv.FormatInt(time.Now().UnixNano(), 36) + "." + strconv.FormatUint(qc.seq.Add(1), 36)
+	_ = qc.cache.Set(db.Statement.Context, queryCacheVersionKey(db.Statement.Table), version, queryCacheVersionTTL)
+}
+
+// resultKey 查询结果的缓存 Key：表版本号 + SQL（参数已代入）的哈希
+// 表版本号不存在（该表从未写入或已过期）时为 0
+func (qc *queryCache) resultKey(ctx context.Context, table, sql string) string {
+	version, err := qc.cache.Get(ctx, queryCacheVersionKey(table))
+	if err != nil || version == "" {
+		version = "0"
+	}
+	sum := sha256.Sum256([]byte(sql))
+	return "query:" + table + ":" + version + ":" + hex.EncodeToString(sum[:])
+}
+
+// queryCacheVersionKey 表版本号的缓存 Key
+func queryCacheVersionKey(table string) string {
+	return "query:version:" + table
+}
+
+// cacheableSQL 判断 SQL 能否缓存：单表查询（无 JOIN、子查询），不含易变函数和加锁读
+// 多表查询涉及的其他表写入时无法使缓存失效
+func cacheableSQL(sql string) bool {
+	upper := strings.ToUpper(sql)
+	if strings.Contains(upper, " JOIN ") || strings.Count(upper, "SELECT") > 1 {
+		return false
+	}
+	return !volatileSQLPattern.MatchString(sql)
+}
+
+// inTransaction 判断查询是否在事务中执行（事务中可能读到未提交的数据，不能缓存）
+func inTransaction(db *gorm.DB) bool {
+	_, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+	return ok
+}
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/cache"
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/errors"
+
+	"gorm.io/gorm"
+)
+
+// newQueryCacheDB 启用查询缓存（内存缓存）的测试数据库，已写入一篇文章
+func newQueryCacheDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db := dbtest.Open(t, &testArticle{})
+	manager, err := cache.NewCacheManager(&config.Config{Cache: config.CacheConfig{Driver: string(cache.DriverMemory), TTL: 60}}, nil)
+	if err != nil {
+		t.Fatalf("NewCacheManager: %v", err)
+	}
+	if err := RegisterQueryCache(db, cache.NewCacheFacade(manager)); err != nil {
+		t.Fatalf("RegisterQueryCache: %v", err)
+	}
+	if err := db.Create(&testArticle{ID: 1, Title: "v1"}).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	return db
+}
+
+// titles 查询所有文章标题，scopes 为空时不使用缓存
+func titles(t *testing.T, db *gorm.DB, scopes ...func(*gorm.DB) *gorm.DB) []string {
+	t.Helper()
+
+	var rows []testArticle
+	if err := db.WithContext(context.Background()).Scopes(scopes...).Order("id").Find(&rows).Error; err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	out := make([]string, len(rows))
+	for i, row := range rows {
+		out[i] = row.Title
+	}
+	return out
+}
+
+// setTitleRaw 用原生 SQL 修改标题：不会使缓存失效，用于判断查询是否命中缓存
+func setTitleRaw(t *testing.T, db *gorm.DB, title string) {
+	t.Helper()
+	if err := db.Exec("UPDATE test_articles SET title = ? WHERE id = 1", title).Error; err != nil {
+		t.Fatalf("raw update: %v", err)
+	}
+}
+
+func TestQueryCacheHitAndMiss(t *testing.T) {
+	db := newQueryCacheDB(t)
+	cached := CacheFor(time.Minute)
+
+	if got := titles(t, db, cached); len(got) != 1 || got[0] != "v1" {
+		t.Fatalf("miss: titles = %v, want [v1]", got)
+	}
+	setTitleRaw(t, db, "v2")
+
+	// 命中：返回缓存的旧结果
+	if got := titles(t, db, cached); got[0] != "v1" {
+		t.Fatalf("hit: titles = %v, want cached [v1]", got)
+	}
+	// 未标记 CacheFor 的查询直接查库
+	if got := titles(t, db); got[0] != "v2" {
+		t.Fatalf("uncached: titles = %v, want [v2]", got)
+	}
+	// 条件不同的查询是另一个缓存 Key
+	var rows []testArticle
+	if err := db.Scopes(cached).Where("id = ?", 1).Find(&rows).Error; err != nil || rows[0].Title != "v2" {
+		t.Fatalf("different query: rows = %+v, err = %v", rows, err)
+	}
+}
+
+func TestQueryCacheInvalidatedByWrites(t *testing.T) {
+	db := newQueryCacheDB(t)
+	cached := CacheFor(time.Minute)
+	titles(t, db, cached)
+
+	// Create
+	if err := db.Create(&testArticle{ID: 2, Title: "second"}).Error; err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := titles(t, db, cached); len(got) != 2 {
+		t.Fatalf("after create: titles = %v, want 2 rows", got)
+	}
+
+	// Update
+	if err := db.Model(&testArticle{ID: 1}).Update("title", "renamed").Error; err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := titles(t, db, cached); got[0] != "renamed" {
+		t.Fatalf("after update: titles = %v, want renamed first", got)
+	}
+
+	// Delete
+	if err := db.Delete(&testArticle{ID: 2}).Error; err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got := titles(t, db, cached); len(got) != 1 {
+		t.Fatalf("after delete: titles = %v, want 1 row", got)
+	}
+}
+
+// 缓存命中时 First 仍返回 ErrRecordNotFound
+func TestQueryCacheNotFound(t *testing.T) {
+	db := newQueryCacheDB(t)
+
+	for i := 0; i < 2; i++ {
+		var row testArticle
+		err := db.Scopes(CacheFor(time.Minute)).First(&row, 404).Error
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("attempt %d: err = %v, want ErrRecordNotFound", i+1, err)
+		}
+	}
+}
+
+func TestQueryCacheSkipsUncacheableQueries(t *testing.T) {
+	db := newQueryCacheDB(t)
+	cached := CacheFor(time.Minute)
+
+	queries := map[string]func(db *gorm.DB) string{
+		"volatile function": func(db *gorm.DB) string {
+			var row testArticle
+			db.Scopes(cached).Where("abs(random()) >= 0").First(&row)
+			return row.Title
+		},
+		"subquery": func(db *gorm.DB) string {
+			var row testArticle
+			db.Scopes(cached).Where("id IN (?)", db.Table("test_articles").Select("id")).First(&row)
+			return row.Title
+		},
+		"transaction": func(db *gorm.DB) string {
+			var row testArticle
+			_ = db.Transaction(func(tx *gorm.DB) error {
+				return tx.Scopes(cached).First(&row).Error
+			})
+			return row.Title
+		},
+	}
+	for name, query := range queries {
+		setTitleRaw(t, db, name+" 1")
+		query(db)
+		setTitleRaw(t, db, name+" 2")
+		if got := query(db); got != name+" 2" {
+			t.Fatalf("%s: title = %q, want the database value (not cached)", name, got)
+		}
+	}
+}
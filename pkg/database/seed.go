@@ -0,0 +1,30 @@
+package database
+
+import (
+	"fmt"
+
+	"go-api-template/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// Seeder 数据填充器（用于本地开发的示例数据）
+// 实现应保证幂等：重复执行不会插入重复数据
+type Seeder interface {
+	// Name 填充器名称（用于日志）
+	Name() string
+	// Seed 填充数据
+	Seed(tx *gorm.DB) error
+}
+
+// Seed 按顺序执行数据填充器
+// 每个填充器在独立事务中执行，失败时回滚该填充器并停止后续执行
+func Seed(db *gorm.DB, seeders ...Seeder) error {
+	for _, s := range seeders {
+		if err := db.Transaction(s.Seed); err != nil {
+			return fmt.Errorf("数据填充失败 %s: %w", s.Name(), err)
+		}
+		logger.Info("数据填充完成", logger.String("seeder", s.Name()))
+	}
+	return nil
+}
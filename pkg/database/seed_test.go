@@ -0,0 +1,51 @@
+package database
+
+import (
+	"testing"
+
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/logger/logtest"
+
+	"gorm.io/gorm"
+)
+
+// funcSeeder 以函数实现的填充器
+type funcSeeder struct {
+	name string
+	fn   func(tx *gorm.DB) error
+}
+
+func (s funcSeeder) Name() string           { return s.name }
+func (s funcSeeder) Seed(tx *gorm.DB) error { return s.fn(tx) }
+
+func TestSeedRollsBackFailedSeederAndStops(t *testing.T) {
+	logtest.New(t)
+	db := dbtest.Open(t, &testItem{})
+
+	ran := false
+	err := Seed(db,
+		funcSeeder{name: "ok", fn: func(tx *gorm.DB) error { return tx.Create(&testItem{Name: "kept"}).Error }},
+		funcSeeder{name: "broken", fn: func(tx *gorm.DB) error {
+			if err := tx.Create(&testItem{Name: "rolled back"}).Error; err != nil {
+				return err
+			}
+			return errors.New("boom")
+		}},
+		funcSeeder{name: "after", fn: func(tx *gorm.DB) error { ran = true; return nil }},
+	)
+	if err == nil {
+		t.Fatal("Seed succeeded, want error")
+	}
+	if ran {
+		t.Fatal("seeder after the failure ran")
+	}
+
+	var names []string
+	if err := db.Model(&testItem{}).Pluck("name", &names).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "kept" {
+		t.Fatalf("names = %v, want [kept]", names)
+	}
+}
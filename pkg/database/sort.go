@@ -0,0 +1,43 @@
+package database
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SortField 排序字段
+type SortField struct {
+	Column string // 列名（调用方需按白名单校验，见 web.ParseSort）
+	Desc   bool   // 是否降序
+}
+
+// Sort 排序条件，按顺序依次排序
+type Sort []SortField
+
+// Apply 将排序条件加到查询上，为空时使用 fallback
+// 列名通过 clause.Column 引用（由方言加引号），不直接拼接到 SQL 中
+func (s Sort) Apply(db *gorm.DB, fallback ...SortField) *gorm.DB {
+	fields := s
+	if len(fields) == 0 {
+		fields = fallback
+	}
+	for _, f := range fields {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: f.Column}, Desc: f.Desc})
+	}
+	return db
+}
+
+// String 返回排序条件的文本形式，如 "created_at DESC, title ASC"（用于日志）
+func (s Sort) String() string {
+	parts := make([]string, len(s))
+	for i, f := range s {
+		dir := "ASC"
+		if f.Desc {
+			dir = "DESC"
+		}
+		parts[i] = f.Column + " " + dir
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"time"
+
+	"go-api-template/pkg/metrics"
+	"go-api-template/pkg/timing"
+
+	"gorm.io/gorm"
+)
+
+const timingStartKey = "timing:start"
+
+// registerTimingCallbacks 注册 GORM 回调，记录 SQL 耗时：
+//   - 请求的 timing.Recorder（阶段名 db，用于 Server-Timing）
+//   - db_query_duration_seconds 直方图（Label operation 取自 WithOperation，未标记时为 GORM 操作类型）
+func registerTimingCallbacks(db *gorm.DB) error {
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(timingStartKey, time.Now())
+	}
+	after := func(kind string) func(tx *gorm.DB) {
+		return func(tx *gorm.DB) {
+			start, ok := tx.InstanceGet(timingStartKey)
+			if !ok {
+				return
+			}
+			elapsed := time.Since(start.(time.Time))
+			timing.Record(tx.Statement.Context, "db", elapsed)
+
+			operation := OperationFromContext(tx.Statement.Context)
+			if operation == "" {
+				operation = kind
+			}
+			metrics.ObserveWith(MetricDBQueryDuration, queryLabels, elapsed.Seconds(), operation)
+		}
+	}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register("timing:before_create", before),
+		cb.Create().After("gorm:create").Register("timing:after_create", after("create")),
+		cb.Query().Before("gorm:query").Register("timing:before_query", before),
+		cb.Query().After("gorm:query").Register("timing:after_query", after("query")),
+		cb.Update().Before("gorm:update").Register("timing:before_update", before),
+		cb.Update().After("gorm:update").Register("timing:after_update", after("update")),
+		cb.Delete().Before("gorm:delete").Register("timing:before_delete", before),
+		cb.Delete().After("gorm:delete").Register("timing:after_delete", after("delete")),
+		cb.Row().Before("gorm:row").Register("timing:before_row", before),
+		cb.Row().After("gorm:row").Register("timing:after_row", after("row")),
+		cb.Raw().Before("gorm:raw").Register("timing:before_raw", before),
+		cb.Raw().After("gorm:raw").Register("timing:after_raw", after("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// queryCount db_query_duration_seconds 中 operation 的样本数
+func queryCount(t *testing.T, operation string) uint64 {
+	t.Helper()
+
+	var m dto.Metric
+	observer := metrics.HistogramVec(MetricDBQueryDuration, queryLabels...).WithLabelValues(operation)
+	if err := observer.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestQueryMetricLabeledByOperation(t *testing.T) {
+	db := dbtest.Open(t, &testItem{})
+	if err := registerTimingCallbacks(db); err != nil {
+		t.Fatalf("registerTimingCallbacks: %v", err)
+	}
+	labeled, unlabeled := queryCount(t, "test.search"), queryCount(t, "query")
+
+	var items []testItem
+	ctx := WithOperation(context.Background(), "test.search")
+	if err := db.WithContext(ctx).Find(&items).Error; err != nil {
+		t.Fatalf("labeled query: %v", err)
+	}
+	if got := queryCount(t, "test.search"); got != labeled+1 {
+		t.Fatalf("test.search samples = %d, want %d", got, labeled+1)
+	}
+	if got := queryCount(t, "query"); got != unlabeled {
+		t.Fatalf("labeled query also recorded as query: %d samples, want %d", got, unlabeled)
+	}
+
+	// 未标记的查询使用 GORM 操作类型
+	if err := db.WithContext(context.Background()).Find(&items).Error; err != nil {
+		t.Fatalf("unlabeled query: %v", err)
+	}
+	if got := queryCount(t, "query"); got != unlabeled+1 {
+		t.Fatalf("query samples = %d, want %d", got, unlabeled+1)
+	}
+}
+
+func TestOperationFromContext(t *testing.T) {
+	if got := OperationFromContext(context.Background()); got != "" {
+		t.Fatalf("unlabeled = %q, want empty", got)
+	}
+	if got := OperationFromContext(WithOperation(context.Background(), "demo.search")); got != "demo.search" {
+		t.Fatalf("labeled = %q, want demo.search", got)
+	}
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey context 中存放事务的 Key
+type txKey struct{}
+
+// WithTx 将事务绑定到 context
+// 使用该 context 调用 BaseRepository 的方法时会自动在此事务中执行
+func WithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext 获取 context 中绑定的事务
+func TxFromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return tx, ok && tx != nil
+}
+
+// Conn 获取当前应使用的连接：context 中有事务时使用事务，否则使用连接池
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// Transactor 事务执行接口（Service 层依赖此接口，单元测试时可替换为直接执行 fn 的实现）
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// 编译时检查：TxManager 实现了 Transactor
+var _ Transactor = (*TxManager)(nil)
+
+// TxManager 事务管理器
+// Service 层通过它把多个 Repository 调用放进同一个事务，无需显式传递 *gorm.DB
+type TxManager struct {
+	db *gorm.DB
+}
+
+// NewTxManager 创建事务管理器
+func NewTxManager(db *gorm.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// Transaction 在事务中执行 fn
+// fn 中应使用传入的 ctx 调用 Repository；fn 返回错误或 panic 时回滚，否则提交
+// 嵌套调用时内层使用 SavePoint，内层回滚不影响外层
+func (m *TxManager) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return Conn(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(WithTx(ctx, tx))
+	})
+}
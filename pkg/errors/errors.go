@@ -16,6 +16,7 @@ var (
 	ErrInvalidToken  = errors.New("无效的 token")
 	ErrTokenNotFound = errors.New("token 不存在或已过期")
 	ErrTokenExpired  = errors.New("token 已过期")
+	ErrTokenReused   = errors.New("token 已被使用（疑似泄露）")
 
 	// CheckSum 鉴权错误
 	ErrInvalidCheckSum   = errors.New("签名错误")
@@ -25,18 +26,29 @@ var (
 	ErrAppRevoked        = errors.New("应用已注销")
 	ErrAppExpired        = errors.New("应用已过期")
 	ErrMissingAuthParams = errors.New("缺少必要的鉴权参数")
+	ErrSequenceReplayed  = errors.New("请求序号重复或乱序")
 
 	// 数据库错误
 	ErrDatabaseQuery  = errors.New("数据库查询失败")
 	ErrDatabaseUpdate = errors.New("数据库更新失败")
+	ErrNoRowsAffected = errors.New("没有记录被修改")
+	ErrDuplicate      = errors.New("记录已存在")
 
 	// 缓存错误
 	ErrCacheGet = errors.New("缓存获取失败")
 	ErrCacheSet = errors.New("缓存设置失败")
 
+	// 依赖错误
+	ErrCircuitOpen = errors.New("依赖暂时不可用（熔断中）")
+
 	// 参数错误
 	ErrInvalidParams = errors.New("参数无效")
 	ErrMissingParams = errors.New("缺少必要参数")
+	ErrBodyTooLarge  = errors.New("请求体过大")
+
+	// 文件上传错误
+	ErrFileTooLarge       = errors.New("文件过大")
+	ErrFileTypeNotAllowed = errors.New("不允许的文件类型")
 )
 
 // ========== 错误包装函数 ==========
@@ -94,6 +106,12 @@ func WithMessagef(err error, format string, args ...interface{}) error {
 	return errors.WithMessagef(err, format, args...)
 }
 
+// Join 合并多个错误（忽略 nil，全部为 nil 时返回 nil）
+// 消息按行拼接，errors.Is / errors.As 对其中任一错误成立
+func Join(errs ...error) error {
+	return errors.JoinWithDepth(1, errs...)
+}
+
 // ========== 错误判断函数 ==========
 
 // Is 判断错误是否匹配目标错误
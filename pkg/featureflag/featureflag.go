@@ -0,0 +1,101 @@
+// Package featureflag 请求级功能开关
+// Provider 提供开关的默认值（目前为配置中的静态开关，后续可替换为远程配置中心），
+// 中间件为每个请求生成 Flags 放入 context，业务代码通过 Enabled(ctx, name) 判断
+package featureflag
+
+import (
+	"context"
+	"strings"
+)
+
+// Provider 功能开关来源
+type Provider interface {
+	Enabled(ctx context.Context, name string) bool
+}
+
+// StaticProvider 静态开关（来自配置文件），未配置的开关视为关闭
+type StaticProvider map[string]bool
+
+// NewStaticProvider 创建静态开关
+func NewStaticProvider(flags map[string]bool) StaticProvider {
+	p := make(StaticProvider, len(flags))
+	for name, enabled := range flags {
+		p[name] = enabled
+	}
+	return p
+}
+
+// Enabled 实现 Provider
+func (p StaticProvider) Enabled(_ context.Context, name string) bool {
+	return p[name]
+}
+
+// Flags 单个请求的功能开关：覆盖值优先，否则使用 Provider 的值
+type Flags struct {
+	provider  Provider
+	overrides map[string]bool
+}
+
+// New 创建请求级功能开关，overrides 为该请求的覆盖值（如来自请求头），可为 nil
+func New(provider Provider, overrides map[string]bool) *Flags {
+	return &Flags{provider: provider, overrides: overrides}
+}
+
+// Enabled 判断开关是否开启
+func (f *Flags) Enabled(ctx context.Context, name string) bool {
+	if f == nil {
+		return false
+	}
+	if enabled, ok := f.overrides[name]; ok {
+		return enabled
+	}
+	if f.provider == nil {
+		return false
+	}
+	return f.provider.Enabled(ctx, name)
+}
+
+// flagsKey context 中 Flags 的 key
+type flagsKey struct{}
+
+// WithFlags 将请求级功能开关放入 ctx
+func WithFlags(ctx context.Context, flags *Flags) context.Context {
+	return context.WithValue(ctx, flagsKey{}, flags)
+}
+
+// Enabled 判断 ctx 中的功能开关是否开启，ctx 中没有 Flags 时视为关闭
+func Enabled(ctx context.Context, name string) bool {
+	flags, _ := ctx.Value(flagsKey{}).(*Flags)
+	return flags.Enabled(ctx, name)
+}
+
+// ParseOverrides 解析开关覆盖值，格式为逗号分隔的 name=on|off（也支持 true/false、1/0），
+// 只写 name 表示开启，无法识别的项忽略
+//
+//	ParseOverrides("demo_import=off,new_search") // map[demo_import:false new_search:true]
+func ParseOverrides(s string) map[string]bool {
+	var overrides map[string]bool
+	for _, item := range strings.Split(s, ",") {
+		name, value, hasValue := strings.Cut(strings.TrimSpace(item), "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		enabled := true
+		if hasValue {
+			switch strings.ToLower(strings.TrimSpace(value)) {
+			case "on", "true", "1":
+				enabled = true
+			case "off", "false", "0":
+				enabled = false
+			default:
+				continue
+			}
+		}
+		if overrides == nil {
+			overrides = make(map[string]bool)
+		}
+		overrides[name] = enabled
+	}
+	return overrides
+}
@@ -0,0 +1,45 @@
+package featureflag
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestEnabledPrefersOverrides(t *testing.T) {
+	provider := NewStaticProvider(map[string]bool{"on_flag": true, "off_flag": false})
+	flags := New(provider, map[string]bool{"on_flag": false, "new_flag": true})
+	ctx := WithFlags(context.Background(), flags)
+
+	tests := map[string]bool{
+		"on_flag":  false, // 覆盖为关闭
+		"off_flag": false,
+		"new_flag": true, // 只有覆盖值
+		"unknown":  false,
+	}
+	for name, want := range tests {
+		if got := Enabled(ctx, name); got != want {
+			t.Fatalf("Enabled(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestEnabledWithoutFlags(t *testing.T) {
+	if Enabled(context.Background(), "anything") {
+		t.Fatal("flag enabled without Flags in context")
+	}
+	if New(nil, nil).Enabled(context.Background(), "anything") {
+		t.Fatal("flag enabled without provider")
+	}
+}
+
+func TestParseOverrides(t *testing.T) {
+	got := ParseOverrides(" demo_import=off, new_search ,beta=TRUE,bad=maybe,=on,legacy=0")
+	want := map[string]bool{"demo_import": false, "new_search": true, "beta": true, "legacy": false}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseOverrides = %v, want %v", got, want)
+	}
+	if got := ParseOverrides(""); got != nil {
+		t.Fatalf("ParseOverrides(\"\") = %v, want nil", got)
+	}
+}
@@ -0,0 +1,121 @@
+// Package geoip 客户端 IP 归属信息（国家、ASN）
+// Resolver 负责查询（目前为 MaxMind GeoLite2 数据库文件，可替换为其他实现），
+// 中间件为每个请求查询一次并放入 context，业务代码通过 FromContext(ctx) 读取
+package geoip
+
+import (
+	"context"
+	"net"
+
+	"go-api-template/pkg/errors"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Location IP 归属信息，查不到的字段为零值（如内网 IP）
+type Location struct {
+	Country string `json:"country,omitempty"` // 国家代码（ISO 3166-1，如 CN、US）
+	ASN     uint   `json:"asn,omitempty"`     // 自治系统编号
+	ASOrg   string `json:"as_org,omitempty"`  // 自治系统所属组织
+}
+
+// Resolver IP 归属信息查询
+type Resolver interface {
+	Lookup(ip net.IP) (*Location, error)
+}
+
+// MaxMindResolver 基于 MaxMind GeoLite2 数据库文件（.mmdb）的查询
+// 国家库（GeoLite2-Country 或 GeoLite2-City）和 ASN 库（GeoLite2-ASN）是两个文件，可只配置其中一个
+type MaxMindResolver struct {
+	country *maxminddb.Reader
+	asn     *maxminddb.Reader
+}
+
+// 编译时检查：MaxMindResolver 实现了 Resolver
+var _ Resolver = (*MaxMindResolver)(nil)
+
+// NewMaxMindResolver 打开 GeoLite2 数据库文件，路径为空表示不查询该项
+func NewMaxMindResolver(countryPath, asnPath string) (*MaxMindResolver, error) {
+	if countryPath == "" && asnPath == "" {
+		return nil, errors.New("geoip: no database configured")
+	}
+
+	r := &MaxMindResolver{}
+	if countryPath != "" {
+		reader, err := maxminddb.Open(countryPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "open geoip country database %s failed", countryPath)
+		}
+		r.country = reader
+	}
+	if asnPath != "" {
+		reader, err := maxminddb.Open(asnPath)
+		if err != nil {
+			_ = r.Close()
+			return nil, errors.Wrapf(err, "open geoip asn database %s failed", asnPath)
+		}
+		r.asn = reader
+	}
+	return r, nil
+}
+
+// countryRecord GeoLite2-Country / GeoLite2-City 中用到的字段
+type countryRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+// asnRecord GeoLite2-ASN 中用到的字段
+type asnRecord struct {
+	Number       uint   `maxminddb:"autonomous_system_number"`
+	Organization string `maxminddb:"autonomous_system_organization"`
+}
+
+// Lookup 查询 IP 归属信息，数据库中没有该 IP 时返回零值 Location
+func (r *MaxMindResolver) Lookup(ip net.IP) (*Location, error) {
+	loc := &Location{}
+	if r.country != nil {
+		var rec countryRecord
+		if err := r.country.Lookup(ip, &rec); err != nil {
+			return nil, errors.Wrapf(err, "geoip country lookup %s failed", ip)
+		}
+		loc.Country = rec.Country.ISOCode
+	}
+	if r.asn != nil {
+		var rec asnRecord
+		if err := r.asn.Lookup(ip, &rec); err != nil {
+			return nil, errors.Wrapf(err, "geoip asn lookup %s failed", ip)
+		}
+		loc.ASN, loc.ASOrg = rec.Number, rec.Organization
+	}
+	return loc, nil
+}
+
+// Close 关闭数据库文件
+func (r *MaxMindResolver) Close() error {
+	var err error
+	if r.asn != nil {
+		err = r.asn.Close()
+	}
+	if r.country != nil {
+		if cerr := r.country.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// locationKey context 中 Location 的 key
+type locationKey struct{}
+
+// WithLocation 将 IP 归属信息放入 ctx
+func WithLocation(ctx context.Context, loc *Location) context.Context {
+	return context.WithValue(ctx, locationKey{}, loc)
+}
+
+// FromContext 从 ctx 中取出 IP 归属信息，未启用 geoip 或查询失败时返回 nil, false
+func FromContext(ctx context.Context) (*Location, bool) {
+	loc, ok := ctx.Value(locationKey{}).(*Location)
+	return loc, ok && loc != nil
+}
@@ -0,0 +1,89 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/logger"
+)
+
+// HookFunc 关闭钩子函数
+// ctx 携带该钩子的截止时间，钩子应在 ctx 结束前返回
+type HookFunc func(ctx context.Context) error
+
+// hook 已注册的关闭钩子
+type hook struct {
+	name string
+	fn   HookFunc
+}
+
+// Manager 生命周期管理器
+// 各组件在创建时注册关闭钩子，应用退出时按注册的逆序依次执行
+type Manager struct {
+	mu      sync.Mutex
+	hooks   []hook
+	timeout time.Duration
+	closed  bool
+}
+
+// NewManager 创建生命周期管理器
+func NewManager(cfg *config.Config) *Manager {
+	return &Manager{
+		timeout: time.Duration(cfg.Server.ShutdownTimeout) * time.Second,
+	}
+}
+
+// OnShutdown 注册关闭钩子
+// 后注册的钩子先执行（与依赖创建顺序相反）
+func (m *Manager) OnShutdown(name string, fn HookFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+}
+
+// Shutdown 按注册的逆序执行所有关闭钩子
+// 每个钩子最多执行 timeout 时长，超时的钩子会被记录并跳过，不阻塞后续钩子
+// 重复调用时只有第一次生效
+func (m *Manager) Shutdown() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		m.runHook(hooks[i])
+	}
+}
+
+// runHook 在超时控制下执行单个钩子
+func (m *Manager) runHook(h hook) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- h.fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			logger.Error("shutdown hook failed",
+				logger.String("hook", h.name),
+				logger.Err(err),
+			)
+		}
+	case <-ctx.Done():
+		logger.Warn("shutdown hook exceeded deadline",
+			logger.String("hook", h.name),
+			logger.Duration("timeout", m.timeout),
+		)
+	}
+}
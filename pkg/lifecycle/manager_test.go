@@ -0,0 +1,86 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/logger/logtest"
+)
+
+func newTestManager(timeout int) *Manager {
+	cfg := &config.Config{}
+	cfg.Server.ShutdownTimeout = timeout
+	return NewManager(cfg)
+}
+
+func TestShutdownRunsHooksInReverseOrder(t *testing.T) {
+	logtest.New(t)
+	m := newTestManager(1)
+
+	var order []string
+	for _, name := range []string{"database", "redis", "scheduler"} {
+		m.OnShutdown(name, func(context.Context) error {
+			order = append(order, name)
+			return nil
+		})
+	}
+	m.Shutdown()
+
+	want := []string{"scheduler", "redis", "database"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+}
+
+func TestShutdownOnlyOnce(t *testing.T) {
+	logtest.New(t)
+	m := newTestManager(1)
+
+	calls := 0
+	m.OnShutdown("db", func(context.Context) error {
+		calls++
+		return nil
+	})
+	m.Shutdown()
+	m.Shutdown()
+
+	if calls != 1 {
+		t.Fatalf("hook ran %d times, want 1", calls)
+	}
+}
+
+func TestShutdownContinuesAfterFailedAndSlowHooks(t *testing.T) {
+	logs := logtest.New(t)
+	m := newTestManager(1)
+	m.timeout = 50 * time.Millisecond // 阻塞的钩子很快被跳过
+
+	ran := false
+	m.OnShutdown("last", func(context.Context) error {
+		ran = true
+		return nil
+	})
+	m.OnShutdown("failing", func(context.Context) error {
+		return errors.New("boom")
+	})
+	block := make(chan struct{})
+	defer close(block)
+	m.OnShutdown("slow", func(context.Context) error {
+		<-block
+		return nil
+	})
+	m.Shutdown()
+
+	if !ran {
+		t.Fatal("hook after failed and slow hooks did not run")
+	}
+	if n := logs.FilterMessage("shutdown hook exceeded deadline").Len(); n != 1 {
+		t.Fatalf("deadline warnings = %d, want 1", n)
+	}
+	if n := logs.FilterMessage("shutdown hook failed").Len(); n != 1 {
+		t.Fatalf("failure logs = %d, want 1", n)
+	}
+}
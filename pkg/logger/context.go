@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// ctxFieldsKey context 中日志字段的 key
+type ctxFieldsKey struct{}
+
+// WithFields 将日志字段附加到 context 上
+// 之后通过 Ctx(ctx) 输出的日志都会带上这些字段（如 request_id、trace_id）
+func WithFields(ctx context.Context, fields ...Field) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	existing := fieldsFromContext(ctx)
+	merged := make([]Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, ctxFieldsKey{}, merged)
+}
+
+// Ctx 返回携带 context 中日志字段的 Logger
+// 用法：logger.Ctx(ctx).Info("demo created", logger.Uint("id", id))
+func Ctx(ctx context.Context) *zap.Logger {
+	if ctx == nil {
+		return Logger
+	}
+	return Logger.With(fieldsFromContext(ctx)...)
+}
+
+// fieldsFromContext 读取 context 中的日志字段
+func fieldsFromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(ctxFieldsKey{}).([]Field)
+	return fields
+}
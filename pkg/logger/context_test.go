@@ -0,0 +1,27 @@
+package logger_test
+
+import (
+	"context"
+	"testing"
+
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/logger/logtest"
+)
+
+func TestCtxCarriesFields(t *testing.T) {
+	logs := logtest.New(t)
+
+	ctx := logger.WithFields(context.Background(), logger.String("request_id", "req-1"))
+	ctx = logger.WithFields(ctx, logger.String("trace_id", "trace-1"))
+	logger.Ctx(ctx).Info("with fields", logger.Int("n", 1))
+	logger.Ctx(context.Background()).Info("without fields")
+
+	entry := logs.FilterMessage("with fields").All()[0]
+	fields := entry.ContextMap()
+	if fields["request_id"] != "req-1" || fields["trace_id"] != "trace-1" || fields["n"] != int64(1) {
+		t.Fatalf("fields = %v", fields)
+	}
+	if fields := logs.FilterMessage("without fields").All()[0].ContextMap(); len(fields) != 0 {
+		t.Fatalf("fields without context = %v, want none", fields)
+	}
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"os"
+	"runtime"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// fatalHandler Fatal 日志写出后的处理函数，未设置时立即退出进程
+var fatalHandler atomic.Pointer[func()]
+
+// SetFatalHandler 设置 Fatal / Fatalf 写出日志后的处理，如触发优雅关闭，让关闭钩子（关闭数据库、刷新日志等）得以执行
+// 设置后 Fatal 不再调用 os.Exit：执行 handler 后以 runtime.Goexit 结束当前 goroutine（已注册的 defer 照常执行），
+// 进程由 handler 触发的关闭流程负责退出。传入 nil 恢复默认行为（立即 os.Exit(1)）
+//
+// 只应在启动完成后设置：启动阶段（如 main goroutine 中）的 Fatal 没有可以接手的关闭流程，应立即退出
+func SetFatalHandler(handler func()) {
+	if handler == nil {
+		fatalHandler.Store(nil)
+		return
+	}
+	fatalHandler.Store(&handler)
+}
+
+// fatalHook Fatal 日志的写后钩子
+type fatalHook struct{}
+
+// OnWrite 日志写出后调用
+func (fatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	handler := fatalHandler.Load()
+	if handler == nil {
+		_ = Close() // 退出前刷新缓冲
+		os.Exit(1)
+	}
+	(*handler)()
+	runtime.Goexit() // Fatal 不返回，调用方后续代码不会执行
+}
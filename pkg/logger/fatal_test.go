@@ -0,0 +1,87 @@
+package logger_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/lifecycle"
+	"go-api-template/pkg/logger"
+)
+
+func TestFatalGracefulShutdownRunsHooks(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.log")
+	newTestLogger(t, &logger.Config{Level: "info", Filename: filename})
+	t.Cleanup(func() { logger.SetFatalHandler(nil) })
+
+	cfg := &config.Config{}
+	cfg.Server.ShutdownTimeout = 1
+	lc := lifecycle.NewManager(cfg)
+	var closed []string
+	lc.OnShutdown("database", func(context.Context) error {
+		closed = append(closed, "database")
+		return nil
+	})
+	lc.OnShutdown("logger", func(context.Context) error {
+		closed = append(closed, "logger")
+		return logger.Close()
+	})
+
+	// 与 main 相同：Fatal 通知主流程关闭
+	quit := make(chan struct{}, 1)
+	logger.SetFatalHandler(func() { quit <- struct{}{} })
+
+	deferred := make(chan bool, 1)
+	go func() {
+		returned := false
+		defer func() { deferred <- returned }()
+		logger.Fatal("background worker failed", logger.String("worker", "relay"))
+		returned = true
+	}()
+
+	select {
+	case <-quit:
+	case <-time.After(time.Second):
+		t.Fatal("fatal handler not called")
+	}
+	if returned := <-deferred; returned {
+		t.Fatal("Fatal returned to the caller")
+	}
+	lc.Shutdown()
+
+	if len(closed) != 2 || closed[0] != "logger" || closed[1] != "database" {
+		t.Fatalf("hooks run = %v, want [logger database]", closed)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil || !strings.Contains(string(data), "background worker failed") {
+		t.Fatalf("fatal entry not flushed to file: %q, %v", data, err)
+	}
+}
+
+// TestFatalExitsByDefault 默认（未设置处理函数）立即退出，在子进程中验证
+func TestFatalExitsByDefault(t *testing.T) {
+	if os.Getenv("LOGGER_FATAL_CHILD") == "1" {
+		if _, err := logger.NewLogger(&logger.Config{Level: "info", Console: true, ConsoleFormat: logger.FormatJSON}); err != nil {
+			os.Exit(2)
+		}
+		logger.Fatal("startup failed")
+		os.Exit(0) // 不应执行到这里
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFatalExitsByDefault$")
+	cmd.Env = append(os.Environ(), "LOGGER_FATAL_CHILD=1")
+	out, err := cmd.Output()
+
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok || exitErr.ExitCode() != 1 {
+		t.Fatalf("child exited with %v, want exit code 1", err)
+	}
+	if !strings.Contains(string(out), "startup failed") {
+		t.Fatalf("fatal entry not written before exit: %q", out)
+	}
+}
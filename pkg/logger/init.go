@@ -9,13 +9,17 @@ import (
 // InitLogger 从配置初始化日志
 func InitLogger(cfg *config.Config) (*zap.Logger, error) {
 	loggerConfig := &Config{
-		Level:      cfg.Logger.Level,
-		Filename:   cfg.Logger.Filename,
-		MaxSize:    cfg.Logger.MaxSize,
-		MaxBackups: cfg.Logger.MaxBackups,
-		MaxAge:     cfg.Logger.MaxAge,
-		Compress:   cfg.Logger.Compress,
-		Console:    cfg.Logger.Console,
+		Level:           cfg.Logger.Level,
+		Filename:        cfg.Logger.Filename,
+		MaxSize:         cfg.Logger.MaxSize,
+		MaxBackups:      cfg.Logger.MaxBackups,
+		MaxAge:          cfg.Logger.MaxAge,
+		Compress:        cfg.Logger.Compress,
+		Console:         cfg.Logger.Console,
+		ConsoleFormat:   cfg.Logger.ConsoleFormat,
+		FileFormat:      cfg.Logger.FileFormat,
+		DisableCaller:   cfg.Logger.DisableCaller,
+		StacktraceLevel: cfg.Logger.StacktraceLevel,
 	}
 
 	return NewLogger(loggerConfig)
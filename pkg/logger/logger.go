@@ -1,8 +1,10 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"time"
 
 	"go.uber.org/zap"
@@ -15,6 +17,11 @@ var (
 	Logger *zap.Logger
 	// Sugar 全局 SugaredLogger 实例（更方便的 API）
 	Sugar *zap.SugaredLogger
+
+	// wrapped / wrappedSugar 供包装函数（logger.Info 等）使用，多跳过一层调用栈，
+	// 使 caller 指向业务代码而不是 logger.go
+	wrapped      *zap.Logger
+	wrappedSugar *zap.SugaredLogger
 )
 
 // Field 日志字段类型（隔离 zap 依赖）
@@ -22,29 +29,30 @@ type Field = zapcore.Field
 
 // Config 日志配置
 type Config struct {
-	Level      string // 日志级别：debug, info, warn, error
-	Filename   string // 日志文件路径
-	MaxSize    int    // 单个日志文件最大大小（MB）
-	MaxBackups int    // 保留的旧日志文件数量
-	MaxAge     int    // 保留旧日志文件的最大天数
-	Compress   bool   // 是否压缩旧日志文件
-	Console    bool   // 是否同时输出到控制台
+	Level         string // 日志级别：debug, info, warn, error
+	Filename      string // 日志文件路径
+	MaxSize       int    // 单个日志文件最大大小（MB）
+	MaxBackups    int    // 保留的旧日志文件数量
+	MaxAge        int    // 保留旧日志文件的最大天数
+	Compress      bool   // 是否压缩旧日志文件
+	Console       bool   // 是否同时输出到控制台
+	ConsoleFormat string // 控制台输出格式：console（默认）, json
+	FileFormat    string // 文件输出格式：json（默认）, console
+
+	DisableCaller   bool   // 是否关闭 caller（调用位置）输出
+	StacktraceLevel string // 该级别及以上的日志附带调用栈：debug, info, warn, error；为空则不附带
 }
 
+// 日志输出格式
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
 // NewLogger 创建日志实例
 func NewLogger(cfg *Config) (*zap.Logger, error) {
 	// 设置日志级别
-	level := zapcore.InfoLevel
-	switch cfg.Level {
-	case "debug":
-		level = zapcore.DebugLevel
-	case "info":
-		level = zapcore.InfoLevel
-	case "warn":
-		level = zapcore.WarnLevel
-	case "error":
-		level = zapcore.ErrorLevel
-	}
+	level := parseLevel(cfg.Level)
 
 	// 创建日志目录
 	if cfg.Filename != "" {
@@ -83,8 +91,12 @@ func NewLogger(cfg *Config) (*zap.Logger, error) {
 			Compress:   cfg.Compress,
 		}
 
+		fileEncoder, err := newEncoder(cfg.FileFormat, FormatJSON, encoderConfig)
+		if err != nil {
+			return nil, err
+		}
 		fileCore := zapcore.NewCore(
-			zapcore.NewJSONEncoder(encoderConfig),
+			fileEncoder,
 			zapcore.AddSync(fileWriter),
 			level,
 		)
@@ -93,7 +105,10 @@ func NewLogger(cfg *Config) (*zap.Logger, error) {
 
 	// 控制台输出
 	if cfg.Console {
-		consoleEncoder := zapcore.NewConsoleEncoder(encoderConfig)
+		consoleEncoder, err := newEncoder(cfg.ConsoleFormat, FormatConsole, encoderConfig)
+		if err != nil {
+			return nil, err
+		}
 		consoleCore := zapcore.NewCore(
 			consoleEncoder,
 			zapcore.AddSync(os.Stdout),
@@ -104,15 +119,69 @@ func NewLogger(cfg *Config) (*zap.Logger, error) {
 
 	// 创建 logger
 	core := zapcore.NewTee(cores...)
-	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+	opts := []zap.Option{zap.WithFatalHook(fatalHook{})}
+	if !cfg.DisableCaller {
+		opts = append(opts, zap.AddCaller())
+	}
+	if cfg.StacktraceLevel != "" {
+		opts = append(opts, zap.AddStacktrace(parseLevel(cfg.StacktraceLevel)))
+	}
+	logger := zap.New(core, opts...)
 
 	// 设置全局实例
-	Logger = logger
-	Sugar = logger.Sugar()
+	setGlobal(logger)
 
 	return logger, nil
 }
 
+// Replace 替换全局日志实例，返回恢复之前实例的函数（用于测试，见 logtest 包）
+func Replace(l *zap.Logger) (restore func()) {
+	prev := Logger
+	setGlobal(l)
+	return func() { setGlobal(prev) }
+}
+
+// setGlobal 设置全局实例
+func setGlobal(l *zap.Logger) {
+	Logger = l
+	if l == nil {
+		Sugar, wrapped, wrappedSugar = nil, nil, nil
+		return
+	}
+	Sugar = l.Sugar()
+	wrapped = l.WithOptions(zap.AddCallerSkip(1))
+	wrappedSugar = wrapped.Sugar()
+}
+
+// parseLevel 解析日志级别，无法识别时使用 info
+func parseLevel(s string) zapcore.Level {
+	switch s {
+	case "debug":
+		return zapcore.DebugLevel
+	case "warn":
+		return zapcore.WarnLevel
+	case "error":
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// newEncoder 按输出格式创建编码器，未配置时使用 fallback
+func newEncoder(format, fallback string, encoderConfig zapcore.EncoderConfig) (zapcore.Encoder, error) {
+	if format == "" {
+		format = fallback
+	}
+	switch format {
+	case FormatJSON:
+		return zapcore.NewJSONEncoder(encoderConfig), nil
+	case FormatConsole:
+		return zapcore.NewConsoleEncoder(encoderConfig), nil
+	default:
+		return nil, fmt.Errorf("unsupported log format %q (expected json or console)", format)
+	}
+}
+
 // Close 关闭日志
 func Close() error {
 	if Logger != nil {
@@ -125,52 +194,52 @@ func Close() error {
 
 // Debug 调试日志
 func Debug(msg string, fields ...Field) {
-	Logger.Debug(msg, fields...)
+	wrapped.Debug(msg, fields...)
 }
 
 // Info 信息日志
 func Info(msg string, fields ...Field) {
-	Logger.Info(msg, fields...)
+	wrapped.Info(msg, fields...)
 }
 
 // Warn 警告日志
 func Warn(msg string, fields ...Field) {
-	Logger.Warn(msg, fields...)
+	wrapped.Warn(msg, fields...)
 }
 
 // Error 错误日志
 func Error(msg string, fields ...Field) {
-	Logger.Error(msg, fields...)
+	wrapped.Error(msg, fields...)
 }
 
 // Fatal 致命错误日志
 func Fatal(msg string, fields ...Field) {
-	Logger.Fatal(msg, fields...)
+	wrapped.Fatal(msg, fields...)
 }
 
 // Debugf 格式化调试日志
 func Debugf(format string, args ...interface{}) {
-	Sugar.Debugf(format, args...)
+	wrappedSugar.Debugf(format, args...)
 }
 
 // Infof 格式化信息日志
 func Infof(format string, args ...interface{}) {
-	Sugar.Infof(format, args...)
+	wrappedSugar.Infof(format, args...)
 }
 
 // Warnf 格式化警告日志
 func Warnf(format string, args ...interface{}) {
-	Sugar.Warnf(format, args...)
+	wrappedSugar.Warnf(format, args...)
 }
 
 // Errorf 格式化错误日志
 func Errorf(format string, args ...interface{}) {
-	Sugar.Errorf(format, args...)
+	wrappedSugar.Errorf(format, args...)
 }
 
 // Fatalf 格式化致命错误日志
 func Fatalf(format string, args ...interface{}) {
-	Sugar.Fatalf(format, args...)
+	wrappedSugar.Fatalf(format, args...)
 }
 
 // With 添加字段
@@ -256,3 +325,23 @@ func Ints(key string, val []int) Field {
 func Skip() Field {
 	return zap.Skip()
 }
+
+// Stack 当前 goroutine 的调用栈字段
+func Stack(key string) Field {
+	return zap.StackSkip(key, 1) // 跳过本函数
+}
+
+// AllStacks 所有 goroutine 的调用栈字段（runtime.Stack(buf, true)），内容可能很大，仅用于排查崩溃
+func AllStacks(key string) Field {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return zap.ByteString(key, buf[:n])
+		}
+		if len(buf) >= 64<<20 { // 上限 64MB，超出时截断
+			return zap.ByteString(key, buf)
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
@@ -0,0 +1,162 @@
+package logger_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-api-template/pkg/logger"
+)
+
+// captureStdout 在 fn 执行期间把 os.Stdout 重定向到管道，返回输出的内容
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+
+	fn()
+	_ = w.Close()
+	return <-out
+}
+
+// newTestLogger 用 cfg 创建全局日志，测试结束时恢复之前的实例
+func newTestLogger(t *testing.T, cfg *logger.Config) {
+	t.Helper()
+
+	t.Cleanup(logger.Replace(logger.Logger))
+	if _, err := logger.NewLogger(cfg); err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+}
+
+// decodeLines 按行解析 JSON 日志
+func decodeLines(t *testing.T, out string) []map[string]interface{} {
+	t.Helper()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestConsoleFormatJSON(t *testing.T) {
+	out := captureStdout(t, func() {
+		newTestLogger(t, &logger.Config{Level: "info", Console: true, ConsoleFormat: logger.FormatJSON})
+		logger.Info("to console", logger.String("k", "v"))
+		_ = logger.Close()
+	})
+
+	lines := decodeLines(t, out)
+	if len(lines) != 1 || lines[0]["msg"] != "to console" || lines[0]["k"] != "v" || lines[0]["level"] != "info" {
+		t.Fatalf("console output = %v", lines)
+	}
+}
+
+func TestConsoleFormatDefault(t *testing.T) {
+	out := captureStdout(t, func() {
+		newTestLogger(t, &logger.Config{Level: "info", Console: true})
+		logger.Info("to console")
+		_ = logger.Close()
+	})
+
+	// 默认 console 编码：制表符分隔，不是 JSON
+	if !strings.Contains(out, "\tinfo\t") || strings.HasPrefix(out, "{") {
+		t.Fatalf("console output = %q, want console encoding", out)
+	}
+}
+
+func TestFileFormatConsole(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.log")
+	newTestLogger(t, &logger.Config{Level: "info", Filename: filename, FileFormat: logger.FormatConsole})
+	logger.Info("to file")
+	_ = logger.Close()
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "\tinfo\t") || strings.HasPrefix(string(data), "{") {
+		t.Fatalf("file output = %q, want console encoding", data)
+	}
+}
+
+func TestInvalidFormat(t *testing.T) {
+	t.Cleanup(logger.Replace(logger.Logger))
+	if _, err := logger.NewLogger(&logger.Config{Console: true, ConsoleFormat: "xml"}); err == nil {
+		t.Fatal("unsupported format accepted")
+	}
+}
+
+func TestCallerPointsToCallSite(t *testing.T) {
+	out := captureStdout(t, func() {
+		newTestLogger(t, &logger.Config{Level: "debug", Console: true, ConsoleFormat: logger.FormatJSON})
+		ctx := logger.WithFields(context.Background(), logger.String("request_id", "req-1"))
+
+		logger.Info("wrapper")
+		logger.Warnf("sugar %d", 1)
+		logger.Ctx(ctx).Info("context logger")
+		logger.With(logger.String("k", "v")).Info("with")
+		_ = logger.Close()
+	})
+
+	lines := decodeLines(t, out)
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4: %v", len(lines), lines)
+	}
+	for _, line := range lines {
+		caller, _ := line["caller"].(string)
+		if !strings.HasPrefix(caller, "logger/logger_test.go:") {
+			t.Fatalf("%s: caller = %q, want logger/logger_test.go", line["msg"], caller)
+		}
+	}
+}
+
+func TestDisableCallerAndStacktraceLevel(t *testing.T) {
+	out := captureStdout(t, func() {
+		newTestLogger(t, &logger.Config{Level: "info", Console: true, ConsoleFormat: logger.FormatJSON, DisableCaller: true, StacktraceLevel: "error"})
+		logger.Warn("warn")
+		logger.Error("error")
+		_ = logger.Close()
+	})
+
+	lines := decodeLines(t, out)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %v", len(lines), lines)
+	}
+	for _, line := range lines {
+		if _, ok := line["caller"]; ok {
+			t.Fatalf("%s: caller present with DisableCaller", line["msg"])
+		}
+	}
+	// 只有 error 及以上附带调用栈，且调用栈从业务代码开始
+	if _, ok := lines[0]["stacktrace"]; ok {
+		t.Fatal("warn has stacktrace")
+	}
+	stack, _ := lines[1]["stacktrace"].(string)
+	if !strings.HasPrefix(stack, "go-api-template/pkg/logger_test.TestDisableCallerAndStacktraceLevel") {
+		t.Fatalf("error stacktrace = %q, want to start at the test", stack)
+	}
+}
@@ -0,0 +1,28 @@
+// Package logtest 在测试中捕获全局日志，用于断言某条日志被输出（及其字段）
+//
+//	logs := logtest.New(t)
+//	// ... 执行被测代码
+//	entries := logs.FilterMessage("demo created").All()
+//	if len(entries) != 1 || entries[0].ContextMap()["request_id"] != "abc" { ... }
+package logtest
+
+import (
+	"testing"
+
+	"go-api-template/pkg/logger"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// New 将全局日志替换为捕获所有级别的内存日志，测试结束时恢复
+// 全局日志是包级变量，使用 logtest 的测试不能并行执行
+func New(t testing.TB, opts ...zap.Option) *observer.ObservedLogs {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	restore := logger.Replace(zap.New(core, append([]zap.Option{zap.AddCaller()}, opts...)...))
+	t.Cleanup(restore)
+	return logs
+}
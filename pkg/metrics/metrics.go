@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// registry 全局指标注册表
+	registry = newRegistry()
+
+	mu          sync.Mutex
+	counters    = make(map[string]prometheus.Counter)
+	counterVecs = make(map[string]*prometheus.CounterVec)
+	gauges      = make(map[string]prometheus.Gauge)
+	histVecs    = make(map[string]*prometheus.HistogramVec)
+)
+
+// newRegistry 创建注册表，并注册 Go 运行时和进程指标
+func newRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return r
+}
+
+// Registry 获取全局指标注册表
+// 需要自定义指标类型（Histogram、带 Label 的 Vec 等）时直接注册到这里
+func Registry() *prometheus.Registry {
+	return registry
+}
+
+// Handler 指标导出 Handler（Prometheus 文本格式）
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ========== 计数器 ==========
+
+// Counter 获取计数器，首次使用时自动注册
+// 计数器只增不减，命名建议以 _total 结尾，如 demo_created_total
+func Counter(name string) prometheus.Counter {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if c, ok := counters[name]; ok {
+		return c
+	}
+	c := prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: name})
+	registry.MustRegister(c)
+	counters[name] = c
+	return c
+}
+
+// Inc 计数器加 1
+func Inc(name string) {
+	Counter(name).Inc()
+}
+
+// Add 计数器增加指定值（必须为非负数）
+func Add(name string, value float64) {
+	Counter(name).Add(value)
+}
+
+// CounterVec 获取带 Label 的计数器，首次使用时自动注册
+// 同名计数器的 labels 必须一致
+func CounterVec(name string, labels ...string) *prometheus.CounterVec {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if c, ok := counterVecs[name]; ok {
+		return c
+	}
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: name}, labels)
+	registry.MustRegister(c)
+	counterVecs[name] = c
+	return c
+}
+
+// IncWith 带 Label 的计数器加 1，labelValues 与注册时的 labels 一一对应
+func IncWith(name string, labels []string, labelValues ...string) {
+	CounterVec(name, labels...).WithLabelValues(labelValues...).Inc()
+}
+
+// AddWith 带 Label 的计数器增加指定值（必须为非负数），labelValues 与注册时的 labels 一一对应
+func AddWith(name string, labels []string, value float64, labelValues ...string) {
+	CounterVec(name, labels...).WithLabelValues(labelValues...).Add(value)
+}
+
+// ========== 仪表盘 ==========
+
+// Gauge 获取仪表盘，首次使用时自动注册
+// 仪表盘可增可减，适合记录当前状态，如队列长度
+func Gauge(name string) prometheus.Gauge {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if g, ok := gauges[name]; ok {
+		return g
+	}
+	g := prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: name})
+	registry.MustRegister(g)
+	gauges[name] = g
+	return g
+}
+
+// Set 设置仪表盘的值
+func Set(name string, value float64) {
+	Gauge(name).Set(value)
+}
+
+// IncGauge 仪表盘加 1
+func IncGauge(name string) {
+	Gauge(name).Inc()
+}
+
+// DecGauge 仪表盘减 1
+func DecGauge(name string) {
+	Gauge(name).Dec()
+}
+
+// ========== 直方图 ==========
+
+// HistogramVec 获取带 Label 的直方图（默认分桶），首次使用时自动注册
+// 适合记录耗时分布，命名建议以 _seconds 结尾；同名直方图的 labels 必须一致
+func HistogramVec(name string, labels ...string) *prometheus.HistogramVec {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if h, ok := histVecs[name]; ok {
+		return h
+	}
+	h := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: name, Buckets: prometheus.DefBuckets}, labels)
+	registry.MustRegister(h)
+	histVecs[name] = h
+	return h
+}
+
+// ObserveWith 带 Label 的直方图记录一次观测值，labelValues 与注册时的 labels 一一对应
+func ObserveWith(name string, labels []string, value float64, labelValues ...string) {
+	HistogramVec(name, labels...).WithLabelValues(labelValues...).Observe(value)
+}
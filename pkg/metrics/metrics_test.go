@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// scrape 通过 Handler 抓取指标，返回 series（含 label）对应的值，未导出时返回 false
+func scrape(t *testing.T, series string) (float64, bool) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d", w.Code)
+	}
+
+	sc := bufio.NewScanner(w.Body)
+	for sc.Scan() {
+		name, value, ok := strings.Cut(sc.Text(), " ")
+		if !ok || name != series {
+			continue
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			t.Fatalf("parse %q: %v", sc.Text(), err)
+		}
+		return v, true
+	}
+	return 0, false
+}
+
+func TestCounterScrape(t *testing.T) {
+	if _, ok := scrape(t, "test_events_total"); ok {
+		t.Fatal("counter exported before first use")
+	}
+
+	Inc("test_events_total")
+	Add("test_events_total", 2)
+	if v, _ := scrape(t, "test_events_total"); v != 3 {
+		t.Fatalf("test_events_total = %v, want 3", v)
+	}
+	// 同名计数器只注册一次
+	if Counter("test_events_total") != Counter("test_events_total") {
+		t.Fatal("Counter returned different instances")
+	}
+}
+
+func TestCounterVecScrape(t *testing.T) {
+	labels := []string{"result"}
+	IncWith("test_jobs_total", labels, "ok")
+	IncWith("test_jobs_total", labels, "ok")
+	AddWith("test_jobs_total", labels, 5, "error")
+
+	if v, _ := scrape(t, `test_jobs_total{result="ok"}`); v != 2 {
+		t.Fatalf(`test_jobs_total{result="ok"} = %v, want 2`, v)
+	}
+	if v, _ := scrape(t, `test_jobs_total{result="error"}`); v != 5 {
+		t.Fatalf(`test_jobs_total{result="error"} = %v, want 5`, v)
+	}
+}
+
+func TestGaugeScrape(t *testing.T) {
+	Set("test_queue_length", 4)
+	IncGauge("test_queue_length")
+	DecGauge("test_queue_length")
+	DecGauge("test_queue_length")
+	if v, _ := scrape(t, "test_queue_length"); v != 3 {
+		t.Fatalf("test_queue_length = %v, want 3", v)
+	}
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// StartSampler 立即执行一次 sample，之后每隔 interval 执行一次
+// 适合把外部状态（如连接池统计）定期同步到仪表盘；返回的 stop 函数可重复调用
+func StartSampler(interval time.Duration, sample func()) (stop func()) {
+	sample()
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				sample()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
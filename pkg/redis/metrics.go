@@ -0,0 +1,31 @@
+package redis
+
+import (
+	"time"
+
+	"go-api-template/pkg/metrics"
+)
+
+// Redis 连接池指标
+const (
+	MetricRedisTotalConns = "redis_pool_total_conns" // 连接总数
+	MetricRedisIdleConns  = "redis_pool_idle_conns"  // 空闲连接数
+	MetricRedisStaleConns = "redis_pool_stale_conns" // 累计被移除的过期连接数
+	MetricRedisHits       = "redis_pool_hits"        // 累计从池中取到空闲连接的次数
+	MetricRedisMisses     = "redis_pool_misses"      // 累计池中没有空闲连接的次数
+	MetricRedisTimeouts   = "redis_pool_timeouts"    // 累计等待连接超时的次数
+)
+
+// StartPoolMetrics 定期采集连接池统计并导出为 Prometheus 仪表盘
+// 集群模式下为所有节点连接池的汇总；返回停止采集的函数，应在关闭客户端前调用
+func (c *Client) StartPoolMetrics(interval time.Duration) (stop func()) {
+	return metrics.StartSampler(interval, func() {
+		stats := c.PoolStats()
+		metrics.Set(MetricRedisTotalConns, float64(stats.TotalConns))
+		metrics.Set(MetricRedisIdleConns, float64(stats.IdleConns))
+		metrics.Set(MetricRedisStaleConns, float64(stats.StaleConns))
+		metrics.Set(MetricRedisHits, float64(stats.Hits))
+		metrics.Set(MetricRedisMisses, float64(stats.Misses))
+		metrics.Set(MetricRedisTimeouts, float64(stats.Timeouts))
+	})
+}
@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/metrics"
+
+	"github.com/alicebob/miniredis/v2"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestStartPoolMetricsReportsNonNegative(t *testing.T) {
+	client := newTestClient(t, miniredis.RunT(t))
+	t.Cleanup(func() { _ = client.Close() })
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+
+	stop := client.StartPoolMetrics(time.Hour)
+	defer stop()
+
+	for _, name := range []string{MetricRedisTotalConns, MetricRedisIdleConns, MetricRedisStaleConns, MetricRedisHits, MetricRedisMisses, MetricRedisTimeouts} {
+		var m dto.Metric
+		if err := metrics.Gauge(name).Write(&m); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if v := m.GetGauge().GetValue(); v < 0 {
+			t.Fatalf("%s = %v, want >= 0", name, v)
+		}
+	}
+	var m dto.Metric
+	_ = metrics.Gauge(MetricRedisTotalConns).Write(&m)
+	if m.GetGauge().GetValue() < 1 {
+		t.Fatalf("%s = %v, want >= 1", MetricRedisTotalConns, m.GetGauge().GetValue())
+	}
+}
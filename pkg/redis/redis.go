@@ -10,30 +10,80 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// 部署模式
+const (
+	ModeSingle   = "single"   // 单节点
+	ModeCluster  = "cluster"  // Redis Cluster
+	ModeSentinel = "sentinel" // 哨兵（主从自动切换）
+)
+
+// Client Redis 客户端
+// 内嵌 redis.UniversalClient，单节点、集群、哨兵三种模式对上层提供相同的接口
 type Client struct {
-	*redis.Client
+	redis.UniversalClient
 }
 
 // NewRedisClient 创建 Redis 客户端
 func NewRedisClient(cfg *config.Config) (*Client, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
-		Password: cfg.Redis.Password,
-		DB:       cfg.Redis.DB,
-		PoolSize: cfg.Redis.PoolSize,
-	})
+	client, err := newUniversalClient(cfg.Redis)
+	if err != nil {
+		return nil, err
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
 		return nil, fmt.Errorf("连接 Redis 失败: %w", err)
 	}
 
-	return &Client{Client: client}, nil
+	return &Client{UniversalClient: client}, nil
+}
+
+// newUniversalClient 根据部署模式创建客户端
+func newUniversalClient(cfg config.RedisConfig) (redis.UniversalClient, error) {
+	switch cfg.Mode {
+	case ModeSingle:
+		return redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+			Password: cfg.Password,
+			DB:       cfg.DB,
+			PoolSize: cfg.PoolSize,
+		}), nil
+
+	case ModeCluster:
+		if len(cfg.Addrs) == 0 {
+			return nil, fmt.Errorf("cluster 模式需要配置 redis.addrs")
+		}
+		if cfg.DB != 0 {
+			return nil, fmt.Errorf("cluster 模式不支持选择数据库（redis.db 必须为 0）")
+		}
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    cfg.Addrs,
+			Password: cfg.Password,
+			PoolSize: cfg.PoolSize,
+		}), nil
+
+	case ModeSentinel:
+		if len(cfg.Addrs) == 0 || cfg.MasterName == "" {
+			return nil, fmt.Errorf("sentinel 模式需要配置 redis.addrs 和 redis.master_name")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.Addrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			PoolSize:         cfg.PoolSize,
+		}), nil
+
+	default:
+		return nil, fmt.Errorf("不支持的 Redis 模式: %s", cfg.Mode)
+	}
 }
 
 // Close 关闭 Redis 连接
 func (c *Client) Close() error {
-	return c.Client.Close()
+	return c.UniversalClient.Close()
 }
@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/lifecycle"
+	"go-api-template/pkg/logger/logtest"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestClient 连接到内存 Redis 的单节点客户端
+func newTestClient(t *testing.T, mr *miniredis.Miniredis) *Client {
+	t.Helper()
+
+	port, err := strconv.Atoi(mr.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewRedisClient(&config.Config{Redis: config.RedisConfig{
+		Mode:     ModeSingle,
+		Host:     mr.Host(),
+		Port:     port,
+		PoolSize: 4,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// 反复"启动、关闭"应用：关闭钩子关闭客户端后，服务端不再有该客户端的连接
+func TestCloseOnShutdownReleasesConnections(t *testing.T) {
+	logtest.New(t)
+	mr := miniredis.RunT(t)
+
+	for i := 0; i < 5; i++ {
+		client := newTestClient(t, mr)
+		lc := lifecycle.NewManager(&config.Config{Server: config.ServerConfig{ShutdownTimeout: 1}})
+		lc.OnShutdown("redis", func(ctx context.Context) error {
+			return client.Close()
+		})
+		if err := client.Set(context.Background(), "k", "v", 0).Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		lc.Shutdown()
+
+		if err := client.Ping(context.Background()).Err(); err == nil {
+			t.Fatalf("round %d: ping succeeded after shutdown", i)
+		}
+		if n := client.PoolStats().TotalConns; n != 0 {
+			t.Fatalf("round %d: %d connections still open after shutdown", i, n)
+		}
+	}
+}
+
+// 单节点模式仍然是 *redis.Client，按配置选择数据库，读写行为与引入多模式前一致
+func TestSingleModeClient(t *testing.T) {
+	mr := miniredis.RunT(t)
+	port, err := strconv.Atoi(mr.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewRedisClient(&config.Config{Redis: config.RedisConfig{
+		Mode:     ModeSingle,
+		Host:     mr.Host(),
+		Port:     port,
+		DB:       2,
+		PoolSize: 4,
+	}})
+	if err != nil {
+		t.Fatalf("NewRedisClient: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	if _, ok := client.UniversalClient.(*redis.Client); !ok {
+		t.Fatalf("single mode client is %T, want *redis.Client", client.UniversalClient)
+	}
+
+	ctx := context.Background()
+	if err := client.Set(ctx, "k", "v", 0).Err(); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got, err := client.Get(ctx, "k").Result(); err != nil || got != "v" {
+		t.Fatalf("Get = %q, %v, want v", got, err)
+	}
+	mr.Select(2)
+	if got, err := mr.Get("k"); err != nil || got != "v" {
+		t.Fatalf("key not written to db 2: %q, %v", got, err)
+	}
+	if _, err := client.Get(ctx, "missing").Result(); err != redis.Nil {
+		t.Fatalf("missing key: err = %v, want redis.Nil", err)
+	}
+}
+
+func TestNewRedisClientInvalidMode(t *testing.T) {
+	tests := map[string]config.RedisConfig{
+		"unknown mode":            {Mode: "ring"},
+		"cluster without addrs":   {Mode: ModeCluster},
+		"cluster with db":         {Mode: ModeCluster, Addrs: []string{"127.0.0.1:7000"}, DB: 1},
+		"sentinel without master": {Mode: ModeSentinel, Addrs: []string{"127.0.0.1:26379"}},
+	}
+	for name, cfg := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewRedisClient(&config.Config{Redis: cfg}); err == nil {
+				t.Fatal("NewRedisClient succeeded, want config error")
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/security"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// refreshKeyPrefix refresh token 家族的 Key 前缀
+// 每个家族一个 Hash：user 为用户 ID，current 为当前有效 token 的摘要
+const refreshKeyPrefix = "refresh:family:"
+
+// rotateScript 原子地校验并轮换 refresh token
+// 返回 0：家族不存在（过期或已吊销）；-1：旧 token 被重复使用（家族已吊销）；1：轮换成功
+var rotateScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'current')
+if not current then
+	return 0
+end
+if current ~= ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return -1
+end
+redis.call('HSET', KEYS[1], 'current', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// RefreshStore 基于 Redis 的 refresh token 存储
+type RefreshStore struct {
+	client *Client
+	ttl    time.Duration
+}
+
+var _ security.RefreshStore = (*RefreshStore)(nil)
+
+// NewRefreshStore 创建 refresh token 存储
+// ttl 为 refresh token 的有效期，每次轮换后重新计算
+func NewRefreshStore(client *Client, ttl time.Duration) *RefreshStore {
+	return &RefreshStore{client: client, ttl: ttl}
+}
+
+// Issue 为用户签发新的 refresh token
+func (s *RefreshStore) Issue(ctx context.Context, userID string) (string, error) {
+	token := security.NewRefreshToken("")
+	family, _ := security.SplitRefreshToken(token)
+	key := refreshKeyPrefix + family
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "user", userID, "current", security.HashRefreshToken(token))
+		pipe.PExpire(ctx, key, s.ttl)
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "issue refresh token failed")
+	}
+	return token, nil
+}
+
+// Rotate 轮换 refresh token
+// 旧 token 已被轮换过时吊销整个家族并返回 errors.ErrTokenReused
+func (s *RefreshStore) Rotate(ctx context.Context, old string) (string, error) {
+	family, err := security.SplitRefreshToken(old)
+	if err != nil {
+		return "", err
+	}
+
+	token := security.NewRefreshToken(family)
+	result, err := rotateScript.Run(ctx, s.client,
+		[]string{refreshKeyPrefix + family},
+		security.HashRefreshToken(old), security.HashRefreshToken(token), s.ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return "", errors.Wrap(err, "rotate refresh token failed")
+	}
+
+	switch result {
+	case 1:
+		return token, nil
+	case -1:
+		return "", errors.WithStack(errors.ErrTokenReused)
+	default:
+		return "", errors.WithStack(errors.ErrTokenNotFound)
+	}
+}
+
+// Revoke 吊销 token 所在的整个家族
+func (s *RefreshStore) Revoke(ctx context.Context, token string) error {
+	family, err := security.SplitRefreshToken(token)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Del(ctx, refreshKeyPrefix+family).Err(); err != nil {
+		return errors.Wrap(err, "revoke refresh token failed")
+	}
+	return nil
+}
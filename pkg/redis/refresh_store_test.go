@@ -0,0 +1,86 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/errors"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRefreshStore(t *testing.T) (*RefreshStore, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := newTestClient(t, mr)
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRefreshStore(client, time.Hour), mr
+}
+
+func TestRefreshRotateDetectsReuse(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestRefreshStore(t)
+
+	first, err := store.Issue(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	second, err := store.Rotate(ctx, first)
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if second == first {
+		t.Fatal("Rotate returned the same token")
+	}
+
+	// 已轮换的 token 再次使用：检测到重用并吊销整个家族
+	if _, err := store.Rotate(ctx, first); !errors.Is(err, errors.ErrTokenReused) {
+		t.Fatalf("reuse: err = %v, want ErrTokenReused", err)
+	}
+	// 家族已吊销，当前有效的 token 也随之失效
+	if _, err := store.Rotate(ctx, second); !errors.Is(err, errors.ErrTokenNotFound) {
+		t.Fatalf("after reuse: err = %v, want ErrTokenNotFound", err)
+	}
+}
+
+func TestRefreshRotateChain(t *testing.T) {
+	ctx := context.Background()
+	store, mr := newTestRefreshStore(t)
+
+	token, err := store.Issue(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if token, err = store.Rotate(ctx, token); err != nil {
+			t.Fatalf("rotate %d: %v", i+1, err)
+		}
+	}
+
+	// 过期后无法轮换
+	mr.FastForward(2 * time.Hour)
+	if _, err := store.Rotate(ctx, token); !errors.Is(err, errors.ErrTokenNotFound) {
+		t.Fatalf("expired: err = %v, want ErrTokenNotFound", err)
+	}
+}
+
+func TestRefreshRevoke(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestRefreshStore(t)
+
+	token, err := store.Issue(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if err := store.Revoke(ctx, token); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := store.Rotate(ctx, token); !errors.Is(err, errors.ErrTokenNotFound) {
+		t.Fatalf("after revoke: err = %v, want ErrTokenNotFound", err)
+	}
+	if _, err := store.Rotate(ctx, "malformed"); !errors.Is(err, errors.ErrInvalidToken) {
+		t.Fatalf("malformed: err = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/security"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// sequenceKeyPrefix 应用请求序号的 Key 前缀，值为最近接受的序号（不过期，每个应用一个 Key）
+const sequenceKeyPrefix = "checksum:sequence:"
+
+// advanceScript 原子地比较并记录请求序号
+// 序号以十进制字符串比较（先比长度再逐位比较），避免 Lua 数字精度不足以表示 uint64
+// 返回 1：序号大于已记录值，已记录；0：重复或乱序
+var advanceScript = redis.NewScript(`
+local last = redis.call('GET', KEYS[1])
+if last and (#ARGV[1] < #last or (#ARGV[1] == #last and ARGV[1] <= last)) then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1])
+return 1
+`)
+
+// SequenceStore 基于 Redis 的请求序号存储（多实例共享）
+type SequenceStore struct {
+	client *Client
+}
+
+var _ security.SequenceStore = (*SequenceStore)(nil)
+
+// NewSequenceStore 创建请求序号存储
+func NewSequenceStore(client *Client) *SequenceStore {
+	return &SequenceStore{client: client}
+}
+
+// Advance 记录请求序号，重复或乱序时返回 errors.ErrSequenceReplayed
+func (s *SequenceStore) Advance(ctx context.Context, appKey string, seq uint64) error {
+	result, err := advanceScript.Run(ctx, s.client,
+		[]string{sequenceKeyPrefix + appKey},
+		strconv.FormatUint(seq, 10),
+	).Int()
+	if err != nil {
+		return errors.Wrap(err, "advance request sequence failed")
+	}
+	if result == 0 {
+		return errors.Wrapf(errors.ErrSequenceReplayed, "app_key: %s, sequence: %d", appKey, seq)
+	}
+	return nil
+}
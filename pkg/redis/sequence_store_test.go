@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"go-api-template/pkg/errors"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestSequenceStoreInOrderAndOutOfOrder(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := newTestClient(t, mr)
+	t.Cleanup(func() { _ = client.Close() })
+	store := NewSequenceStore(client)
+
+	steps := []struct {
+		app string
+		seq uint64
+		ok  bool
+	}{
+		{"a", 1, true},
+		{"a", 2, true},
+		{"a", 9, true},
+		{"a", 10, true}, // 位数变多：按十进制字符串比较时先比长度
+		{"a", 10, false},
+		{"a", 3, false},
+		{"b", 1, true}, // 每个应用单独计数
+		{"a", math.MaxUint64 - 1, true},
+		{"a", math.MaxUint64, true},
+		{"a", math.MaxUint64, false},
+	}
+	for i, s := range steps {
+		err := store.Advance(ctx, s.app, s.seq)
+		if s.ok && err != nil {
+			t.Fatalf("step %d (%s, %d): %v", i, s.app, s.seq, err)
+		}
+		if !s.ok && !errors.Is(err, errors.ErrSequenceReplayed) {
+			t.Fatalf("step %d (%s, %d): err = %v, want ErrSequenceReplayed", i, s.app, s.seq, err)
+		}
+	}
+}
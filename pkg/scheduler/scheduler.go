@@ -0,0 +1,171 @@
+// Package scheduler 轻量级后台任务调度（按固定间隔执行）
+// 每次执行记录耗时和结果；单个任务 panic 不影响其他任务；同一任务上次未结束时跳过本次，不会重叠执行
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-api-template/pkg/logger"
+	"go-api-template/pkg/metrics"
+)
+
+// 任务指标
+const (
+	MetricJobRuns     = "scheduler_job_runs_total"       // 任务执行次数（result: success、error、panic、skipped）
+	MetricJobDuration = "scheduler_job_duration_seconds" // 任务执行耗时
+)
+
+var (
+	runLabels      = []string{"job", "result"}
+	durationLabels = []string{"job"}
+)
+
+// JobFunc 任务函数，ctx 在调度器停止时取消，任务应尽快返回
+type JobFunc func(ctx context.Context) error
+
+// job 已注册的任务
+type job struct {
+	name     string
+	interval time.Duration
+	fn       JobFunc
+	running  atomic.Bool
+}
+
+// Scheduler 任务调度器
+// 先通过 Every 注册任务，再调用 Start；Stop 等待执行中的任务结束（通常注册为 lifecycle 关闭钩子）
+type Scheduler struct {
+	mu      sync.Mutex
+	jobs    []*job
+	started bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New 创建调度器
+func New() *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{ctx: ctx, cancel: cancel}
+}
+
+// Every 注册按固定间隔执行的任务（首次在 Start 后经过一个间隔执行）
+// 名称重复、间隔不大于 0 或调度器已启动时返回错误
+func (s *Scheduler) Every(name string, interval time.Duration, fn JobFunc) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return fmt.Errorf("scheduler: 调度器已启动，无法注册任务 %s", name)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("scheduler: 任务 %s 的间隔必须大于 0", name)
+	}
+	for _, j := range s.jobs {
+		if j.name == name {
+			return fmt.Errorf("scheduler: 任务 %s 已注册", name)
+		}
+	}
+	s.jobs = append(s.jobs, &job{name: name, interval: interval, fn: fn})
+	return nil
+}
+
+// Start 启动所有任务，重复调用无效
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started {
+		return
+	}
+	s.started = true
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+	if len(s.jobs) > 0 {
+		logger.Info("scheduler started", logger.Int("jobs", len(s.jobs)))
+	}
+}
+
+// Stop 停止调度并取消任务的 ctx，等待执行中的任务结束，直到 ctx 结束
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduler: 等待任务结束超时: %w", ctx.Err())
+	}
+}
+
+// loop 按间隔触发任务
+func (s *Scheduler) loop(j *job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			// 上次执行尚未结束：跳过，避免同一任务重叠执行
+			if !j.running.CompareAndSwap(false, true) {
+				metrics.IncWith(MetricJobRuns, runLabels, j.name, "skipped")
+				logger.Warn("job skipped, previous run still in progress", logger.String("job", j.name))
+				continue
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer j.running.Store(false)
+				s.run(j)
+			}()
+		}
+	}
+}
+
+// run 执行一次任务，记录耗时和结果，panic 时记录调用栈
+func (s *Scheduler) run(j *job) {
+	start := time.Now()
+	result := "success"
+
+	defer func() {
+		elapsed := time.Since(start)
+		fields := []logger.Field{
+			logger.String("job", j.name),
+			logger.Duration("duration", elapsed),
+		}
+		if rec := recover(); rec != nil {
+			result = "panic"
+			logger.Error("job panicked", append(fields, logger.Any("panic", rec), logger.Stack("stack"))...)
+		}
+		metrics.IncWith(MetricJobRuns, runLabels, j.name, result)
+		metrics.ObserveWith(MetricJobDuration, durationLabels, elapsed.Seconds(), j.name)
+		if result == "success" {
+			logger.Info("job finished", fields...)
+		}
+	}()
+
+	if err := j.fn(s.ctx); err != nil {
+		result = "error"
+		logger.Error("job failed",
+			logger.String("job", j.name),
+			logger.Duration("duration", time.Since(start)),
+			logger.Err(err),
+		)
+	}
+}
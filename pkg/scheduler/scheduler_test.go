@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/logger/logtest"
+)
+
+func TestJobFiresAtInterval(t *testing.T) {
+	logtest.New(t)
+	s := New()
+
+	var runs atomic.Int32
+	if err := s.Every("tick", 20*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}); err != nil {
+		t.Fatalf("Every: %v", err)
+	}
+
+	s.Start()
+	time.Sleep(110 * time.Millisecond)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	// 110ms / 20ms 约 5 次，容忍调度抖动
+	if n := runs.Load(); n < 3 || n > 6 {
+		t.Fatalf("job ran %d times, want about 5", n)
+	}
+	// 停止后不再执行
+	n := runs.Load()
+	time.Sleep(50 * time.Millisecond)
+	if runs.Load() != n {
+		t.Fatal("job ran after Stop")
+	}
+}
+
+func TestJobPanicAndErrorDoNotStopScheduling(t *testing.T) {
+	logs := logtest.New(t)
+	s := New()
+
+	var runs atomic.Int32
+	_ = s.Every("flaky", 10*time.Millisecond, func(ctx context.Context) error {
+		switch runs.Add(1) {
+		case 1:
+			panic("boom")
+		case 2:
+			return errors.New("failed")
+		}
+		return nil
+	})
+
+	s.Start()
+	deadline := time.Now().Add(time.Second)
+	for runs.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	_ = s.Stop(context.Background())
+
+	if runs.Load() < 3 {
+		t.Fatalf("job ran %d times after panic/error, want at least 3", runs.Load())
+	}
+	if logs.FilterMessage("job panicked").Len() != 1 || logs.FilterMessage("job failed").Len() != 1 {
+		t.Fatalf("panicked = %d, failed = %d, want 1 each",
+			logs.FilterMessage("job panicked").Len(), logs.FilterMessage("job failed").Len())
+	}
+}
+
+func TestJobRunsDoNotOverlap(t *testing.T) {
+	logs := logtest.New(t)
+	s := New()
+
+	var active, maxActive atomic.Int32
+	_ = s.Every("slow", 10*time.Millisecond, func(ctx context.Context) error {
+		n := active.Add(1)
+		defer active.Add(-1)
+		if n > maxActive.Load() {
+			maxActive.Store(n)
+		}
+		select {
+		case <-time.After(50 * time.Millisecond):
+		case <-ctx.Done():
+		}
+		return nil
+	})
+
+	s.Start()
+	time.Sleep(120 * time.Millisecond)
+	_ = s.Stop(context.Background())
+
+	if maxActive.Load() != 1 {
+		t.Fatalf("max concurrent runs = %d, want 1", maxActive.Load())
+	}
+	if logs.FilterMessage("job skipped, previous run still in progress").Len() == 0 {
+		t.Fatal("overlapping ticks not skipped")
+	}
+}
+
+func TestStopTimesOutWaitingForJob(t *testing.T) {
+	logtest.New(t)
+	s := New()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	_ = s.Every("stuck", 5*time.Millisecond, func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release // 忽略 ctx 的任务
+		return nil
+	})
+	s.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Stop = %v, want deadline exceeded", err)
+	}
+	close(release)
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop after release: %v", err)
+	}
+}
+
+func TestEveryValidation(t *testing.T) {
+	s := New()
+	noop := func(ctx context.Context) error { return nil }
+
+	if err := s.Every("a", 0, noop); err == nil {
+		t.Fatal("zero interval accepted")
+	}
+	if err := s.Every("a", time.Second, noop); err != nil {
+		t.Fatalf("Every: %v", err)
+	}
+	if err := s.Every("a", time.Second, noop); err == nil {
+		t.Fatal("duplicate name accepted")
+	}
+
+	logtest.New(t)
+	s.Start()
+	defer s.Stop(context.Background())
+	if err := s.Every("b", time.Second, noop); err == nil {
+		t.Fatal("registration after Start accepted")
+	}
+}
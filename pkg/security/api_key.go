@@ -0,0 +1,128 @@
+package security
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"go-api-template/pkg/cache"
+	"go-api-template/pkg/errors"
+)
+
+// APIKeyIdentity API Key 对应的应用身份
+type APIKeyIdentity struct {
+	AppID     string     `json:"app_id,omitempty"`
+	AppKey    string     `json:"app_key"`
+	AppName   string     `json:"app_name,omitempty"`
+	Revoked   bool       `json:"revoked"`              // 已注销
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // 过期时间，为空表示长期有效
+}
+
+// APIKeyProvider 按 API Key 查询应用身份
+// API Key 不存在时返回 errors.ErrAppNotFound；注销、过期由 ResolveAPIKey 统一判断
+type APIKeyProvider interface {
+	APIKeyIdentity(ctx context.Context, apiKey string) (*APIKeyIdentity, error)
+}
+
+// ResolveAPIKey 查询 API Key 对应的应用并检查应用状态
+// 应用已注销返回 errors.ErrAppRevoked，已过期返回 errors.ErrAppExpired
+func ResolveAPIKey(ctx context.Context, p APIKeyProvider, apiKey string) (*APIKeyIdentity, error) {
+	identity, err := p.APIKeyIdentity(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if identity.Revoked {
+		return nil, errors.Wrapf(errors.ErrAppRevoked, "app_key: %s", identity.AppKey)
+	}
+	if identity.ExpiresAt != nil && !time.Now().Before(*identity.ExpiresAt) {
+		return nil, errors.Wrapf(errors.ErrAppExpired, "app_key: %s", identity.AppKey)
+	}
+	return identity, nil
+}
+
+// HashAPIKey API Key 的 SHA-256（十六进制小写），数据库中只保存哈希，缓存 Key 也使用哈希
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// StaticAPIKeyProvider 固定配置的 API Key，用于配置文件中的少量应用
+type StaticAPIKeyProvider struct {
+	apps map[string]string // API Key 哈希 -> app_key
+}
+
+// 编译时检查：StaticAPIKeyProvider 实现了 APIKeyProvider
+var _ APIKeyProvider = (*StaticAPIKeyProvider)(nil)
+
+// NewStaticAPIKeyProvider 创建固定配置的 API Key 查询，apps 为 app_key -> API Key（与 checksum.apps 的方向一致）
+func NewStaticAPIKeyProvider(apps map[string]string) *StaticAPIKeyProvider {
+	p := &StaticAPIKeyProvider{apps: make(map[string]string, len(apps))}
+	for appKey, apiKey := range apps {
+		if apiKey != "" {
+			p.apps[HashAPIKey(apiKey)] = appKey
+		}
+	}
+	return p
+}
+
+// APIKeyIdentity 查询应用身份
+func (p *StaticAPIKeyProvider) APIKeyIdentity(_ context.Context, apiKey string) (*APIKeyIdentity, error) {
+	appKey, ok := p.apps[HashAPIKey(apiKey)]
+	if !ok {
+		return nil, errors.Wrap(errors.ErrAppNotFound, "unknown api key")
+	}
+	return &APIKeyIdentity{AppKey: appKey, AppName: appKey}, nil
+}
+
+// CachedAPIKeyProvider 带缓存的 API Key 查询（装饰 APIKeyProvider，如数据库查询）
+// 与 CachedAppSecretProvider 相同：身份（含注销状态和过期时间）缓存 ttl，过期时间在每次校验时判断；
+// 注销应用或更换 API Key 后调用 Invalidate 立即生效，否则最迟 ttl 后生效。查询失败（含 API Key 不存在）不缓存
+type CachedAPIKeyProvider struct {
+	next  APIKeyProvider
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// 编译时检查：CachedAPIKeyProvider 实现了 APIKeyProvider
+var _ APIKeyProvider = (*CachedAPIKeyProvider)(nil)
+
+// NewCachedAPIKeyProvider 创建带缓存的 API Key 查询
+func NewCachedAPIKeyProvider(next APIKeyProvider, c cache.Cache, ttl time.Duration) *CachedAPIKeyProvider {
+	return &CachedAPIKeyProvider{next: next, cache: c, ttl: ttl}
+}
+
+// APIKeyIdentity 查询应用身份（优先读缓存）
+func (p *CachedAPIKeyProvider) APIKeyIdentity(ctx context.Context, apiKey string) (*APIKeyIdentity, error) {
+	value, err := p.cache.Remember(ctx, apiKeyCacheKey(apiKey), p.ttl, func() (string, error) {
+		identity, err := p.next.APIKeyIdentity(ctx, apiKey)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(identity)
+		if err != nil {
+			return "", errors.Wrapf(err, "encode api key identity failed, app_key: %s", identity.AppKey)
+		}
+		return string(data), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var identity APIKeyIdentity
+	if err := json.Unmarshal([]byte(value), &identity); err != nil {
+		return nil, errors.Wrap(err, "decode cached api key identity failed")
+	}
+	return &identity, nil
+}
+
+// Invalidate 清除 API Key 的缓存（注销应用、更换 API Key 后调用，传入旧的 API Key）
+func (p *CachedAPIKeyProvider) Invalidate(ctx context.Context, apiKey string) error {
+	return p.cache.Delete(ctx, apiKeyCacheKey(apiKey))
+}
+
+// apiKeyCacheKey API Key 的缓存 Key（使用哈希，缓存中不出现 API Key 原文）
+func apiKeyCacheKey(apiKey string) string {
+	return "app:apikey:" + HashAPIKey(apiKey)
+}
@@ -0,0 +1,141 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go-api-template/pkg/cache"
+	"go-api-template/pkg/errors"
+)
+
+// AppCredential 应用凭证（CheckSum 签名使用）
+type AppCredential struct {
+	AppKey    string     `json:"app_key"`
+	Secret    string     `json:"secret"`
+	Revoked   bool       `json:"revoked"`              // 已注销
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // 过期时间，为空表示长期有效
+
+	RequireSequence bool `json:"require_sequence"` // 要求请求带严格递增的序号（见 SequenceStore）
+}
+
+// AppSecretProvider 按 app_key 查询应用凭证
+// 应用不存在时返回 errors.ErrAppNotFound；注销、过期由 ResolveAppCredential 统一判断
+type AppSecretProvider interface {
+	AppCredential(ctx context.Context, appKey string) (*AppCredential, error)
+}
+
+// ResolveAppCredential 查询应用凭证并检查应用状态
+// 应用已注销返回 errors.ErrAppRevoked，已过期返回 errors.ErrAppExpired
+func ResolveAppCredential(ctx context.Context, p AppSecretProvider, appKey string) (*AppCredential, error) {
+	cred, err := p.AppCredential(ctx, appKey)
+	if err != nil {
+		return nil, err
+	}
+	if cred.Revoked {
+		return nil, errors.Wrapf(errors.ErrAppRevoked, "app_key: %s", appKey)
+	}
+	if cred.ExpiresAt != nil && !time.Now().Before(*cred.ExpiresAt) {
+		return nil, errors.Wrapf(errors.ErrAppExpired, "app_key: %s", appKey)
+	}
+	return cred, nil
+}
+
+// ResolveAppSecret 查询应用密钥并检查应用状态（同 ResolveAppCredential，只返回密钥）
+func ResolveAppSecret(ctx context.Context, p AppSecretProvider, appKey string) (string, error) {
+	cred, err := ResolveAppCredential(ctx, p, appKey)
+	if err != nil {
+		return "", err
+	}
+	return cred.Secret, nil
+}
+
+// StaticAppSecretProvider 固定配置的应用密钥（app_key -> secret），用于配置文件中的少量应用
+type StaticAppSecretProvider map[string]string
+
+// AppCredential 查询应用凭证
+func (p StaticAppSecretProvider) AppCredential(_ context.Context, appKey string) (*AppCredential, error) {
+	secret, ok := p[appKey]
+	if !ok {
+		return nil, errors.Wrapf(errors.ErrAppNotFound, "app_key: %s", appKey)
+	}
+	return &AppCredential{AppKey: appKey, Secret: secret}, nil
+}
+
+// SequencedAppSecretProvider 为指定应用开启请求序号（装饰 AppSecretProvider，用于配置文件中的应用）
+type SequencedAppSecretProvider struct {
+	next    AppSecretProvider
+	appKeys map[string]struct{}
+}
+
+// NewSequencedAppSecretProvider 创建为 appKeys 开启请求序号的应用凭证查询，其余应用保持 next 返回的设置
+func NewSequencedAppSecretProvider(next AppSecretProvider, appKeys []string) *SequencedAppSecretProvider {
+	set := make(map[string]struct{}, len(appKeys))
+	for _, appKey := range appKeys {
+		set[appKey] = struct{}{}
+	}
+	return &SequencedAppSecretProvider{next: next, appKeys: set}
+}
+
+// AppCredential 查询应用凭证
+func (p *SequencedAppSecretProvider) AppCredential(ctx context.Context, appKey string) (*AppCredential, error) {
+	cred, err := p.next.AppCredential(ctx, appKey)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.appKeys[appKey]; ok {
+		cred.RequireSequence = true
+	}
+	return cred, nil
+}
+
+// CachedAppSecretProvider 带缓存的应用凭证查询（装饰 AppSecretProvider，如数据库查询）
+// 凭证（含注销状态和过期时间）缓存 ttl，过期时间在每次校验时判断，不受缓存影响；
+// 注销应用或更换密钥后调用 Invalidate 立即生效，否则最迟 ttl 后生效。查询失败（含应用不存在）不缓存
+type CachedAppSecretProvider struct {
+	next  AppSecretProvider
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// 编译时检查：CachedAppSecretProvider 实现了 AppSecretProvider
+var _ AppSecretProvider = (*CachedAppSecretProvider)(nil)
+
+// NewCachedAppSecretProvider 创建带缓存的应用凭证查询
+func NewCachedAppSecretProvider(next AppSecretProvider, c cache.Cache, ttl time.Duration) *CachedAppSecretProvider {
+	return &CachedAppSecretProvider{next: next, cache: c, ttl: ttl}
+}
+
+// AppCredential 查询应用凭证（优先读缓存）
+func (p *CachedAppSecretProvider) AppCredential(ctx context.Context, appKey string) (*AppCredential, error) {
+	value, err := p.cache.Remember(ctx, appCredentialCacheKey(appKey), p.ttl, func() (string, error) {
+		cred, err := p.next.AppCredential(ctx, appKey)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(cred)
+		if err != nil {
+			return "", errors.Wrapf(err, "encode app credential failed, app_key: %s", appKey)
+		}
+		return string(data), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var cred AppCredential
+	if err := json.Unmarshal([]byte(value), &cred); err != nil {
+		return nil, errors.Wrapf(err, "decode cached app credential failed, app_key: %s", appKey)
+	}
+	return &cred, nil
+}
+
+// Invalidate 清除应用凭证缓存（注销应用、更换密钥后调用）
+func (p *CachedAppSecretProvider) Invalidate(ctx context.Context, appKey string) error {
+	return p.cache.Delete(ctx, appCredentialCacheKey(appKey))
+}
+
+// appCredentialCacheKey 应用凭证的缓存 Key
+func appCredentialCacheKey(appKey string) string {
+	return "app:credential:" + appKey
+}
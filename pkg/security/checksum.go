@@ -2,6 +2,7 @@ package security
 
 import (
 	"crypto/sha1"
+	"crypto/subtle"
 	"fmt"
 )
 
@@ -16,5 +17,22 @@ func Sha1(data string) string {
 // checksum = SHA1(secret + nonce + timestamp)
 func ValidateCheckSum(checksum, timestamp, nonce, secret string) bool {
 	calculatedSum := Sha1(secret + nonce + timestamp)
-	return calculatedSum == checksum
+	return SecureCompare(calculatedSum, checksum)
+}
+
+// ValidateSequencedCheckSum 验证带请求序号的 checksum 是否有效（开启请求序号的应用使用）
+// checksum = SHA1(secret + nonce + timestamp + sequence)，序号参与签名，无法被篡改
+func ValidateSequencedCheckSum(checksum, timestamp, nonce, sequence, secret string) bool {
+	calculatedSum := Sha1(secret + nonce + timestamp + sequence)
+	return SecureCompare(calculatedSum, checksum)
+}
+
+// SecureCompare 以常量时间比较两个字符串（用于签名、HMAC 等校验）
+// 普通的 == 在遇到第一个不同字节时就返回，攻击者可以通过响应时间差逐字节猜出正确的签名；
+// 常量时间比较的耗时与内容无关，只会暴露长度（签名长度本身是公开的）
+func SecureCompare(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
 }
@@ -0,0 +1,62 @@
+package security
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCheckSum(t *testing.T) {
+	const (
+		secret    = "app-secret"
+		nonce     = "n0nce"
+		timestamp = "1700000000"
+	)
+	sum := Sha1(secret + nonce + timestamp)
+
+	if !ValidateCheckSum(sum, timestamp, nonce, secret) {
+		t.Fatal("matching checksum rejected")
+	}
+	// 大写的十六进制同样视为不匹配（签名按小写计算）
+	mismatches := []string{
+		"",
+		sum[:len(sum)-1],
+		sum + "0",
+		strings.ToUpper(sum),
+		Sha1("other-secret" + nonce + timestamp),
+		Sha1(secret + nonce + "1700000001"),
+	}
+	for _, m := range mismatches {
+		if ValidateCheckSum(m, timestamp, nonce, secret) {
+			t.Fatalf("non-matching checksum %q accepted", m)
+		}
+	}
+}
+
+func TestValidateSequencedCheckSum(t *testing.T) {
+	sum := Sha1("secret" + "nonce" + "1700000000" + "42")
+	if !ValidateSequencedCheckSum(sum, "1700000000", "nonce", "42", "secret") {
+		t.Fatal("matching checksum rejected")
+	}
+	// 序号参与签名，修改序号后校验失败
+	if ValidateSequencedCheckSum(sum, "1700000000", "nonce", "43", "secret") {
+		t.Fatal("checksum accepted with a tampered sequence")
+	}
+}
+
+func TestSecureCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"abc", "abc", true},
+		{"", "", true},
+		{"abc", "abd", false},
+		{"abc", "ab", false},
+		{"ab", "abc", false},
+	}
+	for _, tt := range tests {
+		if got := SecureCompare(tt.a, tt.b); got != tt.want {
+			t.Fatalf("SecureCompare(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
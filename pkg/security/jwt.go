@@ -0,0 +1,68 @@
+package security
+
+import (
+	"crypto/rsa"
+	"time"
+
+	"go-api-template/pkg/errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims JWT 声明（隔离 jwt 依赖）
+// 标准声明可通过 GetSubject()、GetIssuer()、GetExpirationTime() 等方法读取
+type Claims = jwt.MapClaims
+
+// NewJWT 使用 HS256 签发 JWT
+// claims 中可包含 iss、sub 等标准声明；exp、iat 根据 ttl 自动设置
+func NewJWT(claims map[string]interface{}, ttl time.Duration, secret string) (string, error) {
+	return signJWT(jwt.SigningMethodHS256, claims, ttl, []byte(secret))
+}
+
+// ParseJWT 解析并校验 HS256 签名的 JWT
+// 过期返回 errors.ErrTokenExpired，签名错误或格式错误返回 errors.ErrInvalidToken
+func ParseJWT(token, secret string) (Claims, error) {
+	return parseJWT(token, jwt.SigningMethodHS256, []byte(secret))
+}
+
+// NewJWTRS256 使用 RS256（RSA 私钥）签发 JWT
+func NewJWTRS256(claims map[string]interface{}, ttl time.Duration, key *rsa.PrivateKey) (string, error) {
+	return signJWT(jwt.SigningMethodRS256, claims, ttl, key)
+}
+
+// ParseJWTRS256 使用 RSA 公钥解析并校验 RS256 签名的 JWT
+func ParseJWTRS256(token string, key *rsa.PublicKey) (Claims, error) {
+	return parseJWT(token, jwt.SigningMethodRS256, key)
+}
+
+// signJWT 签发 JWT
+func signJWT(method jwt.SigningMethod, claims map[string]interface{}, ttl time.Duration, key interface{}) (string, error) {
+	now := time.Now()
+	mapClaims := make(jwt.MapClaims, len(claims)+2)
+	for k, v := range claims {
+		mapClaims[k] = v
+	}
+	mapClaims["iat"] = now.Unix()
+	mapClaims["exp"] = now.Add(ttl).Unix()
+
+	token, err := jwt.NewWithClaims(method, mapClaims).SignedString(key)
+	if err != nil {
+		return "", errors.Wrap(err, "sign jwt failed")
+	}
+	return token, nil
+}
+
+// parseJWT 解析 JWT，只接受指定的签名算法（防止算法替换攻击）
+func parseJWT(token string, method jwt.SigningMethod, key interface{}) (Claims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return key, nil
+	}, jwt.WithValidMethods([]string{method.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errors.WithStack(errors.ErrTokenExpired)
+		}
+		return nil, errors.WithDetail(errors.WithStack(errors.ErrInvalidToken), err.Error())
+	}
+	return claims, nil
+}
@@ -0,0 +1,91 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/errors"
+)
+
+func TestJWTRoundTrip(t *testing.T) {
+	token, err := NewJWT(map[string]interface{}{"sub": "user-1", "iss": "api"}, time.Minute, "secret")
+	if err != nil {
+		t.Fatalf("NewJWT: %v", err)
+	}
+	claims, err := ParseJWT(token, "secret")
+	if err != nil {
+		t.Fatalf("ParseJWT: %v", err)
+	}
+	if sub, _ := claims.GetSubject(); sub != "user-1" {
+		t.Fatalf("sub = %q, want user-1", sub)
+	}
+	if iss, _ := claims.GetIssuer(); iss != "api" {
+		t.Fatalf("iss = %q, want api", iss)
+	}
+	exp, _ := claims.GetExpirationTime()
+	if exp == nil || time.Until(exp.Time) <= 0 || time.Until(exp.Time) > time.Minute {
+		t.Fatalf("exp = %v, want within a minute", exp)
+	}
+}
+
+func TestJWTExpired(t *testing.T) {
+	token, err := NewJWT(map[string]interface{}{"sub": "user-1"}, -time.Minute, "secret")
+	if err != nil {
+		t.Fatalf("NewJWT: %v", err)
+	}
+	if _, err := ParseJWT(token, "secret"); !errors.Is(err, errors.ErrTokenExpired) {
+		t.Fatalf("err = %v, want ErrTokenExpired", err)
+	}
+}
+
+func TestJWTSignatureMismatch(t *testing.T) {
+	token, err := NewJWT(map[string]interface{}{"sub": "user-1"}, time.Minute, "secret")
+	if err != nil {
+		t.Fatalf("NewJWT: %v", err)
+	}
+
+	if _, err := ParseJWT(token, "other-secret"); !errors.Is(err, errors.ErrInvalidToken) {
+		t.Fatalf("wrong secret: err = %v, want ErrInvalidToken", err)
+	}
+
+	// 篡改载荷后签名不再匹配
+	parts := strings.Split(token, ".")
+	forged, _ := NewJWT(map[string]interface{}{"sub": "admin"}, time.Minute, "attacker")
+	parts[1] = strings.Split(forged, ".")[1]
+	if _, err := ParseJWT(strings.Join(parts, "."), "secret"); !errors.Is(err, errors.ErrInvalidToken) {
+		t.Fatalf("tampered payload: err = %v, want ErrInvalidToken", err)
+	}
+
+	if _, err := ParseJWT("not-a-jwt", "secret"); !errors.Is(err, errors.ErrInvalidToken) {
+		t.Fatalf("malformed: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestJWTRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	token, err := NewJWTRS256(map[string]interface{}{"sub": "user-1"}, time.Minute, key)
+	if err != nil {
+		t.Fatalf("NewJWTRS256: %v", err)
+	}
+	if _, err := ParseJWTRS256(token, &key.PublicKey); err != nil {
+		t.Fatalf("ParseJWTRS256: %v", err)
+	}
+	if _, err := ParseJWTRS256(token, &other.PublicKey); !errors.Is(err, errors.ErrInvalidToken) {
+		t.Fatalf("wrong key: err = %v, want ErrInvalidToken", err)
+	}
+	// 只接受指定算法：RS256 的 token 不能按 HS256 校验
+	if _, err := ParseJWT(token, "secret"); !errors.Is(err, errors.ErrInvalidToken) {
+		t.Fatalf("algorithm mismatch: err = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,61 @@
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"go-api-template/pkg/errors"
+)
+
+// RefreshStore refresh token 存储（支持轮换）
+//
+// 同一次登录签发的 refresh token 属于同一个“家族”：
+//   - Rotate 用旧 token 换取新 token，旧 token 随即失效
+//   - 已轮换的旧 token 再次被使用，说明 token 可能已泄露，
+//     此时整个家族被吊销并返回 errors.ErrTokenReused，用户需要重新登录
+type RefreshStore interface {
+	// Issue 为用户签发新的 refresh token（开启新的家族）
+	Issue(ctx context.Context, userID string) (string, error)
+	// Rotate 轮换 refresh token，返回新 token
+	Rotate(ctx context.Context, old string) (string, error)
+	// Revoke 吊销 token 所在的整个家族（如用户登出）
+	Revoke(ctx context.Context, token string) error
+}
+
+// NewRefreshToken 生成属于 family 的 refresh token
+// 格式：<family>.<随机串>，family 为空时生成新的家族 ID
+func NewRefreshToken(family string) string {
+	if family == "" {
+		family = randomToken(16)
+	}
+	return family + "." + randomToken(32)
+}
+
+// SplitRefreshToken 解析 refresh token 的家族 ID
+func SplitRefreshToken(token string) (family string, err error) {
+	family, secret, ok := strings.Cut(token, ".")
+	if !ok || family == "" || secret == "" {
+		return "", errors.WithStack(errors.ErrInvalidToken)
+	}
+	return family, nil
+}
+
+// HashRefreshToken 计算 refresh token 的摘要（存储端只保存摘要，不保存明文）
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// randomToken 生成 n 字节随机数的 URL 安全编码
+func randomToken(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("generate refresh token failed: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
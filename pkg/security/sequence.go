@@ -0,0 +1,45 @@
+package security
+
+import (
+	"context"
+	"sync"
+
+	"go-api-template/pkg/errors"
+)
+
+// SequenceStore 应用请求序号存储（CheckSum 的重放保护）
+//
+// 开启请求序号的应用每个请求带一个严格递增的序号（如毫秒时间戳或自增计数），
+// 只记录每个应用最近接受的序号：小于或等于该值的请求一律拒绝，
+// 即使仍在 timestamp 允许的偏差内、nonce 也未记录过，存储量也只与应用数量有关
+type SequenceStore interface {
+	// Advance 序号大于已记录值时记录并返回 nil，否则返回 errors.ErrSequenceReplayed
+	Advance(ctx context.Context, appKey string, seq uint64) error
+}
+
+// MemorySequenceStore 进程内存中的请求序号存储（单实例部署或未使用 Redis 时）
+// 重启后记录丢失，多实例部署时应使用 Redis 实现（见 redis.SequenceStore）
+type MemorySequenceStore struct {
+	mu   sync.Mutex
+	last map[string]uint64
+}
+
+// 编译时检查：MemorySequenceStore 实现了 SequenceStore
+var _ SequenceStore = (*MemorySequenceStore)(nil)
+
+// NewMemorySequenceStore 创建进程内存中的请求序号存储
+func NewMemorySequenceStore() *MemorySequenceStore {
+	return &MemorySequenceStore{last: make(map[string]uint64)}
+}
+
+// Advance 记录请求序号
+func (s *MemorySequenceStore) Advance(_ context.Context, appKey string, seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.last[appKey]; ok && seq <= last {
+		return errors.Wrapf(errors.ErrSequenceReplayed, "app_key: %s, sequence: %d, last: %d", appKey, seq, last)
+	}
+	s.last[appKey] = seq
+	return nil
+}
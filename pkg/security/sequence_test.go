@@ -0,0 +1,27 @@
+package security
+
+import (
+	"context"
+	"testing"
+
+	"go-api-template/pkg/errors"
+)
+
+func TestMemorySequenceStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySequenceStore()
+
+	for _, seq := range []uint64{1, 2, 5} {
+		if err := store.Advance(ctx, "a", seq); err != nil {
+			t.Fatalf("Advance(%d): %v", seq, err)
+		}
+	}
+	for _, seq := range []uint64{5, 3} {
+		if err := store.Advance(ctx, "a", seq); !errors.Is(err, errors.ErrSequenceReplayed) {
+			t.Fatalf("Advance(%d) = %v, want ErrSequenceReplayed", seq, err)
+		}
+	}
+	if err := store.Advance(ctx, "b", 1); err != nil {
+		t.Fatalf("other app: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-api-template/pkg/errors"
+)
+
+// totpEncoding TOTP 密钥编码（Base32，无填充，兼容 Google Authenticator 等应用）
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTP 基于时间的一次性密码（RFC 6238，HMAC-SHA1）
+type TOTP struct {
+	Period int // 时间步长（秒），0 使用默认值 30
+	Digits int // 验证码位数，只允许 6~8，0 使用默认值 6
+}
+
+// TOTP 默认配置与验证码位数范围
+const (
+	defaultTOTPPeriod = 30
+	defaultTOTPDigits = 6
+	minTOTPDigits     = 6
+	maxTOTPDigits     = 8
+)
+
+// DefaultTOTP 默认配置：30 秒步长，6 位验证码
+var DefaultTOTP = TOTP{Period: defaultTOTPPeriod, Digits: defaultTOTPDigits}
+
+// GenerateTOTPSecret 生成 TOTP 密钥（160 位，Base32 编码）
+func GenerateTOTPSecret() string {
+	key := make([]byte, 20)
+	if _, err := rand.Read(key); err != nil {
+		panic(errors.Wrap(err, "generate totp secret failed"))
+	}
+	return totpEncoding.EncodeToString(key)
+}
+
+// GenerateTOTP 使用默认配置生成当前时间的验证码
+func GenerateTOTP(secret string) (string, error) {
+	return DefaultTOTP.Generate(secret, time.Now())
+}
+
+// ValidateTOTP 使用默认配置校验验证码
+// window 为允许的时钟偏差步数，如 1 表示同时接受前后各一个时间步的验证码
+func ValidateTOTP(secret, code string, window int) bool {
+	return DefaultTOTP.Validate(secret, code, window, time.Now())
+}
+
+// TOTPProvisioningURI 生成用于二维码绑定的 otpauth:// URI（默认配置）
+func TOTPProvisioningURI(secret, account, issuer string) (string, error) {
+	return DefaultTOTP.ProvisioningURI(secret, account, issuer)
+}
+
+// Generate 生成指定时间的验证码
+// 配置无效（Period 为负数、Digits 不在 6~8）时返回 errors.ErrInvalidParams
+func (t TOTP) Generate(secret string, at time.Time) (string, error) {
+	t, err := t.normalize()
+	if err != nil {
+		return "", err
+	}
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return t.code(key, t.counter(at)), nil
+}
+
+// Validate 校验指定时间的验证码，允许前后 window 个时间步的偏差
+// 配置无效时始终返回 false
+func (t TOTP) Validate(secret, code string, window int, at time.Time) bool {
+	t, err := t.normalize()
+	if err != nil || len(code) != t.Digits {
+		return false
+	}
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return false
+	}
+
+	counter := t.counter(at)
+	for offset := -window; offset <= window; offset++ {
+		expected := t.code(key, uint64(int64(counter)+int64(offset)))
+		if SecureCompare(expected, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProvisioningURI 生成用于二维码绑定的 otpauth:// URI
+// 配置无效时返回 errors.ErrInvalidParams
+func (t TOTP) ProvisioningURI(secret, account, issuer string) (string, error) {
+	t, err := t.normalize()
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(t.Digits))
+	query.Set("period", fmt.Sprint(t.Period))
+
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + query.Encode(), nil
+}
+
+// normalize 零值字段使用默认值，并校验配置
+// Period 为负数会导致计数错误，Digits 超过 9 时 10^Digits 溢出 uint32
+func (t TOTP) normalize() (TOTP, error) {
+	if t.Period == 0 {
+		t.Period = defaultTOTPPeriod
+	}
+	if t.Digits == 0 {
+		t.Digits = defaultTOTPDigits
+	}
+	if t.Period < 0 {
+		return t, errors.Wrapf(errors.ErrInvalidParams, "invalid totp period: %d", t.Period)
+	}
+	if t.Digits < minTOTPDigits || t.Digits > maxTOTPDigits {
+		return t, errors.Wrapf(errors.ErrInvalidParams, "invalid totp digits: %d, must be %d~%d", t.Digits, minTOTPDigits, maxTOTPDigits)
+	}
+	return t, nil
+}
+
+// counter 计算时间步计数
+func (t TOTP) counter(at time.Time) uint64 {
+	return uint64(at.Unix() / int64(t.Period))
+}
+
+// code 计算 HOTP 验证码（RFC 4226）
+func (t TOTP) code(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// 动态截断
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < t.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", t.Digits, value%mod)
+}
+
+// decodeTOTPSecret 解码 Base32 密钥（忽略空格和大小写）
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid totp secret")
+	}
+	return key, nil
+}
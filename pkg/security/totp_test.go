@@ -0,0 +1,116 @@
+package security
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/errors"
+)
+
+// rfc6238Secret RFC 6238 附录 B 的 SHA1 测试密钥 "12345678901234567890"
+var rfc6238Secret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPRFC6238Vectors(t *testing.T) {
+	totp := TOTP{Period: 30, Digits: 8}
+	vectors := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, v := range vectors {
+		at := time.Unix(v.unix, 0)
+		got, err := totp.Generate(rfc6238Secret, at)
+		if err != nil {
+			t.Fatalf("Generate(%d): %v", v.unix, err)
+		}
+		if got != v.code {
+			t.Fatalf("Generate(%d) = %s, want %s", v.unix, got, v.code)
+		}
+		if !totp.Validate(rfc6238Secret, v.code, 0, at) {
+			t.Fatalf("Validate(%d, %s) = false", v.unix, v.code)
+		}
+	}
+}
+
+func TestTOTPValidateWindow(t *testing.T) {
+	totp := DefaultTOTP
+	secret := GenerateTOTPSecret()
+	now := time.Unix(1700000000, 0)
+
+	prev, err := totp.Generate(secret, now.Add(-30*time.Second))
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if totp.Validate(secret, prev, 0, now) {
+		t.Fatal("previous step accepted with window 0")
+	}
+	if !totp.Validate(secret, prev, 1, now) {
+		t.Fatal("previous step rejected with window 1")
+	}
+
+	far, _ := totp.Generate(secret, now.Add(-90*time.Second))
+	if totp.Validate(secret, far, 1, now) {
+		t.Fatal("code three steps old accepted with window 1")
+	}
+	if totp.Validate(secret, "12345", 1, now) || totp.Validate("not base32!", prev, 1, now) {
+		t.Fatal("malformed code or secret accepted")
+	}
+}
+
+func TestTOTPInvalidConfig(t *testing.T) {
+	for _, totp := range []TOTP{
+		{Period: -30, Digits: 6},
+		{Period: 30, Digits: 5},
+		{Period: 30, Digits: 9},
+		{Period: 30, Digits: 10},
+	} {
+		if _, err := totp.Generate(rfc6238Secret, time.Now()); !errors.Is(err, errors.ErrInvalidParams) {
+			t.Fatalf("%+v: Generate err = %v, want ErrInvalidParams", totp, err)
+		}
+		if totp.Validate(rfc6238Secret, strings.Repeat("0", totp.Digits), 1, time.Now()) {
+			t.Fatalf("%+v: Validate = true, want false", totp)
+		}
+		if _, err := totp.ProvisioningURI(rfc6238Secret, "a", "b"); !errors.Is(err, errors.ErrInvalidParams) {
+			t.Fatalf("%+v: ProvisioningURI err = %v, want ErrInvalidParams", totp, err)
+		}
+	}
+}
+
+func TestTOTPZeroValueUsesDefaults(t *testing.T) {
+	at := time.Unix(59, 0)
+	got, err := TOTP{}.Generate(rfc6238Secret, at)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	want, _ := DefaultTOTP.Generate(rfc6238Secret, at)
+	if got != want || len(got) != 6 {
+		t.Fatalf("zero-value code = %s, want %s", got, want)
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	raw, err := TOTPProvisioningURI("JBSWY3DPEHPK3PXP", "alice@example.com", "Example Co")
+	if err != nil {
+		t.Fatalf("TOTPProvisioningURI: %v", err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse %s: %v", raw, err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/Example Co:alice@example.com" {
+		t.Fatalf("uri = %s", raw)
+	}
+	q := u.Query()
+	if q.Get("secret") != "JBSWY3DPEHPK3PXP" || q.Get("issuer") != "Example Co" || q.Get("digits") != "6" || q.Get("period") != "30" {
+		t.Fatalf("query = %v", q)
+	}
+}
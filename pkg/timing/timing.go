@@ -0,0 +1,85 @@
+package timing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// recorderKey context 中 Recorder 的 key
+type recorderKey struct{}
+
+// Recorder 请求内各阶段（db、cache 等）的耗时统计
+// 由计时中间件放入请求 context，组件通过 Record/Track 上报，最终输出为 Server-Timing 头
+type Recorder struct {
+	mu     sync.Mutex
+	order  []string
+	phases map[string]time.Duration
+}
+
+// NewRecorder 创建耗时统计
+func NewRecorder() *Recorder {
+	return &Recorder{phases: make(map[string]time.Duration)}
+}
+
+// WithRecorder 将 Recorder 放入 context
+func WithRecorder(ctx context.Context, r *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// FromContext 从 context 获取 Recorder（不存在时返回 nil）
+func FromContext(ctx context.Context) *Recorder {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return r
+}
+
+// Record 累加某个阶段的耗时
+// context 中没有 Recorder 时（如非 HTTP 请求）直接忽略
+func Record(ctx context.Context, phase string, d time.Duration) {
+	if r := FromContext(ctx); r != nil {
+		r.Add(phase, d)
+	}
+}
+
+// Track 开始计时，返回结束函数，配合 defer 使用
+//
+//	defer timing.Track(ctx, "cache")()
+func Track(ctx context.Context, phase string) func() {
+	start := time.Now()
+	return func() {
+		Record(ctx, phase, time.Since(start))
+	}
+}
+
+// Add 累加某个阶段的耗时
+func (r *Recorder) Add(phase string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.phases[phase]; !ok {
+		r.order = append(r.order, phase)
+	}
+	r.phases[phase] += d
+}
+
+// ServerTiming 格式化为 Server-Timing 头的值，如 "db;dur=12.30, cache;dur=0.40"
+func (r *Recorder) ServerTiming() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	parts := make([]string, 0, len(r.order))
+	for _, phase := range r.order {
+		parts = append(parts, fmt.Sprintf("%s;dur=%.2f", phase, Milliseconds(r.phases[phase])))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Milliseconds 将时长转换为毫秒（保留小数）
+func Milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"sync"
+	"time"
+
+	"go-api-template/pkg/errors"
+)
+
+// CircuitState 熔断器状态
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // 关闭：正常放行，统计连续失败
+	CircuitOpen                         // 打开：直接返回 ErrCircuitOpen
+	CircuitHalfOpen                     // 半开：放行少量探测请求，全部成功则关闭，任一失败则重新打开
+)
+
+// String 状态名称
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig 熔断器配置
+type CircuitBreakerConfig struct {
+	FailureThreshold int                         // 连续失败多少次后打开，<=0 时为 5
+	OpenTimeout      time.Duration               // 打开后多久进入半开，<=0 时为 30s
+	HalfOpenProbes   int                         // 半开状态放行的探测请求数，<=0 时为 1
+	OnStateChange    func(from, to CircuitState) // 状态变化回调（可选，在锁外调用）
+	IsFailure        func(err error) bool        // 判断错误是否计为失败（可选，默认所有非 nil 错误）
+}
+
+// CircuitBreaker 熔断器
+// 依赖（Redis、上游 API）持续失败时快速失败，避免请求堆积拖垮整个服务
+type CircuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int       // 关闭状态下的连续失败次数
+	openedAt  time.Time // 进入打开状态的时间
+	probes    int       // 半开状态已放行的探测请求数
+	successes int       // 半开状态成功的探测请求数
+}
+
+// NewCircuitBreaker 创建熔断器
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = 1
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = func(err error) bool { return err != nil }
+	}
+	return &CircuitBreaker{cfg: cfg}
+}
+
+// Execute 通过熔断器执行 fn
+// 熔断器打开（或半开且探测名额已满）时不执行 fn，直接返回 errors.ErrCircuitOpen；否则返回 fn 的结果
+func (b *CircuitBreaker) Execute(fn func() error) error {
+	if err := b.before(); err != nil {
+		return err
+	}
+
+	err := fn()
+	b.after(b.cfg.IsFailure(err))
+	return err
+}
+
+// State 当前状态（打开超时后视为半开）
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.cfg.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// before 判断是否放行
+func (b *CircuitBreaker) before() error {
+	b.mu.Lock()
+	from := b.state
+
+	if b.state == CircuitOpen {
+		if time.Since(b.openedAt) < b.cfg.OpenTimeout {
+			b.mu.Unlock()
+			return errors.ErrCircuitOpen
+		}
+		b.setState(CircuitHalfOpen)
+	}
+
+	if b.state == CircuitHalfOpen {
+		if b.probes >= b.cfg.HalfOpenProbes {
+			b.mu.Unlock()
+			b.notify(from, CircuitHalfOpen)
+			return errors.ErrCircuitOpen
+		}
+		b.probes++
+	}
+
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+	return nil
+}
+
+// after 记录执行结果
+func (b *CircuitBreaker) after(failed bool) {
+	b.mu.Lock()
+	from := b.state
+
+	switch b.state {
+	case CircuitClosed:
+		if !failed {
+			b.failures = 0
+			break
+		}
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.setState(CircuitOpen)
+		}
+
+	case CircuitHalfOpen:
+		if failed {
+			b.setState(CircuitOpen)
+			break
+		}
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenProbes {
+			b.setState(CircuitClosed)
+		}
+
+	case CircuitOpen:
+		// 其他探测请求已将熔断器重新打开，忽略本次结果
+	}
+
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+// setState 切换状态并重置计数（需持有锁）
+func (b *CircuitBreaker) setState(state CircuitState) {
+	b.state = state
+	b.failures = 0
+	b.probes = 0
+	b.successes = 0
+	if state == CircuitOpen {
+		b.openedAt = time.Now()
+	}
+}
+
+// notify 状态发生变化时调用回调
+func (b *CircuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(from, to)
+	}
+}
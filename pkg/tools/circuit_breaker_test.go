@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"go-api-template/pkg/errors"
+)
+
+var errDependency = errors.New("dependency down")
+
+func fail() error    { return errDependency }
+func succeed() error { return nil }
+
+// newRecordingBreaker 创建记录状态变化的熔断器
+func newRecordingBreaker(cfg CircuitBreakerConfig) (*CircuitBreaker, *[]string) {
+	var transitions []string
+	cfg.OnStateChange = func(from, to CircuitState) {
+		transitions = append(transitions, from.String()+"->"+to.String())
+	}
+	return NewCircuitBreaker(cfg), &transitions
+}
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	b, transitions := newRecordingBreaker(CircuitBreakerConfig{FailureThreshold: 3, OpenTimeout: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		if b.State() != CircuitClosed {
+			t.Fatalf("after %d failures: state = %v, want closed", i, b.State())
+		}
+		if err := b.Execute(fail); err != errDependency {
+			t.Fatalf("failure %d: err = %v", i+1, err)
+		}
+	}
+	if b.State() != CircuitOpen {
+		t.Fatalf("state = %v, want open", b.State())
+	}
+
+	// 打开状态下不执行 fn
+	called := false
+	err := b.Execute(func() error { called = true; return nil })
+	if !errors.Is(err, errors.ErrCircuitOpen) || called {
+		t.Fatalf("open: err = %v, called = %v", err, called)
+	}
+	if len(*transitions) != 1 || (*transitions)[0] != "closed->open" {
+		t.Fatalf("transitions = %v", *transitions)
+	}
+}
+
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Hour})
+
+	_ = b.Execute(fail)
+	_ = b.Execute(succeed)
+	_ = b.Execute(fail)
+	if b.State() != CircuitClosed {
+		t.Fatalf("non-consecutive failures opened the breaker: state = %v", b.State())
+	}
+}
+
+func TestCircuitBreakerHalfOpenCloses(t *testing.T) {
+	b, transitions := newRecordingBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: 20 * time.Millisecond, HalfOpenProbes: 2})
+
+	_ = b.Execute(fail)
+	time.Sleep(30 * time.Millisecond)
+	if b.State() != CircuitHalfOpen {
+		t.Fatalf("after open timeout: state = %v, want half-open", b.State())
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := b.Execute(succeed); err != nil {
+			t.Fatalf("probe %d: %v", i+1, err)
+		}
+	}
+	if b.State() != CircuitClosed {
+		t.Fatalf("after successful probes: state = %v, want closed", b.State())
+	}
+	want := []string{"closed->open", "open->half-open", "half-open->closed"}
+	if len(*transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", *transitions, want)
+	}
+	for i := range want {
+		if (*transitions)[i] != want[i] {
+			t.Fatalf("transitions = %v, want %v", *transitions, want)
+		}
+	}
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: 20 * time.Millisecond})
+
+	_ = b.Execute(fail)
+	time.Sleep(30 * time.Millisecond)
+	if err := b.Execute(fail); err != errDependency {
+		t.Fatalf("probe err = %v", err)
+	}
+	if b.State() != CircuitOpen {
+		t.Fatalf("after failed probe: state = %v, want open", b.State())
+	}
+	if err := b.Execute(succeed); !errors.Is(err, errors.ErrCircuitOpen) {
+		t.Fatalf("reopened: err = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerHalfOpenLimitsProbes(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: 20 * time.Millisecond, HalfOpenProbes: 1})
+
+	_ = b.Execute(fail)
+	time.Sleep(30 * time.Millisecond)
+
+	// 探测请求执行期间，其他请求被拒绝
+	err := b.Execute(func() error {
+		if err := b.Execute(succeed); !errors.Is(err, errors.ErrCircuitOpen) {
+			t.Errorf("second probe: err = %v, want ErrCircuitOpen", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("first probe: %v", err)
+	}
+	if b.State() != CircuitClosed {
+		t.Fatalf("state = %v, want closed", b.State())
+	}
+}
+
+func TestCircuitBreakerIsFailure(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Hour,
+		IsFailure:        func(err error) bool { return err != nil && !errors.Is(err, errors.ErrNotFound) },
+	})
+
+	if err := b.Execute(func() error { return errors.ErrNotFound }); !errors.Is(err, errors.ErrNotFound) {
+		t.Fatalf("err = %v", err)
+	}
+	if b.State() != CircuitClosed {
+		t.Fatalf("ignored error opened the breaker: state = %v", b.State())
+	}
+}
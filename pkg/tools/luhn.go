@@ -0,0 +1,45 @@
+package tools
+
+// RandNumberWithLuhn 生成带 Luhn 校验位的数字串（如邀请码、验证码）
+// 前 length-1 位为随机数字，最后一位为校验位；length 小于 2 时返回空字符串
+func RandNumberWithLuhn(length int) string {
+	if length < 2 {
+		return ""
+	}
+	payload := RandNumber(length - 1)
+	return payload + string(rune('0'+luhnCheckDigit(payload)))
+}
+
+// ValidateLuhn 校验数字串的 Luhn 校验位
+// 包含非数字字符或长度小于 2 时返回 false
+func ValidateLuhn(code string) bool {
+	if len(code) < 2 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+	}
+	payload, check := code[:len(code)-1], int(code[len(code)-1]-'0')
+	return luhnCheckDigit(payload) == check
+}
+
+// luhnCheckDigit 计算数字串的 Luhn 校验位（payload 只能包含数字）
+func luhnCheckDigit(payload string) int {
+	sum := 0
+	// 从右往左，校验位左侧第一位开始每隔一位乘 2
+	double := true
+	for i := len(payload) - 1; i >= 0; i-- {
+		d := int(payload[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
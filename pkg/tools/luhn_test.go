@@ -0,0 +1,63 @@
+package tools
+
+import "testing"
+
+func TestValidateLuhnKnownNumbers(t *testing.T) {
+	valid := []string{"79927398713", "4111111111111111", "18", "00"}
+	for _, code := range valid {
+		if !ValidateLuhn(code) {
+			t.Fatalf("ValidateLuhn(%q) = false, want true", code)
+		}
+	}
+	invalid := []string{"79927398710", "4111111111111112", "", "0", "1234a", " 18"}
+	for _, code := range invalid {
+		if ValidateLuhn(code) {
+			t.Fatalf("ValidateLuhn(%q) = true, want false", code)
+		}
+	}
+}
+
+func TestRandNumberWithLuhn(t *testing.T) {
+	for _, length := range []int{2, 6, 8, 16} {
+		for i := 0; i < 50; i++ {
+			code := RandNumberWithLuhn(length)
+			if len(code) != length {
+				t.Fatalf("len(%q) = %d, want %d", code, len(code), length)
+			}
+			if !ValidateLuhn(code) {
+				t.Fatalf("generated code %q does not validate", code)
+			}
+		}
+	}
+	if got := RandNumberWithLuhn(1); got != "" {
+		t.Fatalf("RandNumberWithLuhn(1) = %q, want empty", got)
+	}
+}
+
+func TestLuhnDetectsTampering(t *testing.T) {
+	useGenerator(t, NewSeededGenerator(7))
+
+	for i := 0; i < 20; i++ {
+		code := RandNumberWithLuhn(8)
+		// 修改任意一位数字都会被发现
+		for pos := 0; pos < len(code); pos++ {
+			tampered := []byte(code)
+			tampered[pos] = '0' + (tampered[pos]-'0'+1)%10
+			if ValidateLuhn(string(tampered)) {
+				t.Fatalf("tampered code %q (from %q) validates", tampered, code)
+			}
+		}
+		// 交换相邻的两位不同数字（0/9 交换除外，Luhn 无法发现）
+		for pos := 0; pos+1 < len(code); pos++ {
+			a, b := code[pos], code[pos+1]
+			if a == b || (a == '0' && b == '9') || (a == '9' && b == '0') {
+				continue
+			}
+			swapped := []byte(code)
+			swapped[pos], swapped[pos+1] = b, a
+			if ValidateLuhn(string(swapped)) {
+				t.Fatalf("transposed code %q (from %q) validates", swapped, code)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"go-api-template/pkg/errors"
+)
+
+// Pool 有并发上限的任务池，用于扇出调用（如批量调用外部接口补全 Demo 数据）
+//   - 同时执行的任务不超过 limit 个，达到上限时 Submit 阻塞，直到有任务完成或 ctx 结束
+//   - 任务收到 NewPool 传入的 ctx；ctx 结束后尚未开始的任务不再执行，错误为 ctx.Err()
+//   - 单个任务失败或 panic 不影响其他任务，Wait 返回全部任务的错误
+//
+// 一个 Pool 只使用一次：提交全部任务后调用 Wait，Wait 之后不能再 Submit
+//
+//	pool := tools.NewPool[*Profile](ctx, 8)
+//	for _, id := range ids {
+//		pool.Submit(func(ctx context.Context) (*Profile, error) {
+//			return client.Profile(ctx, id)
+//		})
+//	}
+//	profiles, err := pool.Wait() // profiles 与提交顺序一致，失败的任务为零值
+type Pool[T any] struct {
+	ctx context.Context
+	sem chan struct{}
+	wg  sync.WaitGroup
+
+	mu      sync.Mutex
+	results []T
+	errs    []error
+}
+
+// NewPool 创建任务池，limit 为最大并发数（<=0 时为 GOMAXPROCS）
+func NewPool[T any](ctx context.Context, limit int) *Pool[T] {
+	if limit <= 0 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+	return &Pool[T]{ctx: ctx, sem: make(chan struct{}, limit)}
+}
+
+// Submit 提交任务，并发数达到上限时阻塞
+// ctx 已结束时不再执行任务，该任务的错误为 ctx.Err()
+func (p *Pool[T]) Submit(fn func(ctx context.Context) (T, error)) {
+	p.mu.Lock()
+	i := len(p.results)
+	var zero T
+	p.results = append(p.results, zero)
+	p.errs = append(p.errs, nil)
+	p.mu.Unlock()
+
+	select {
+	case p.sem <- struct{}{}:
+	case <-p.ctx.Done():
+		p.set(i, zero, p.ctx.Err())
+		return
+	}
+	// 空闲名额和 ctx 结束同时就绪时 select 随机选择，这里再检查一次
+	if err := p.ctx.Err(); err != nil {
+		<-p.sem
+		p.set(i, zero, err)
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer func() { <-p.sem }()
+		result, err := p.run(fn)
+		if err != nil {
+			result = zero // 失败的任务即使返回了值也按零值处理
+		}
+		p.set(i, result, err)
+	}()
+}
+
+// Wait 等待全部任务结束，返回按提交顺序排列的结果（失败的任务为零值）
+// 有任务失败时返回合并后的错误，每个错误带有任务序号（从 0 开始），errors.Is 可以判断其中任一错误
+func (p *Pool[T]) Wait() ([]T, error) {
+	p.wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var errs []error
+	for i, err := range p.errs {
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "task %d", i))
+		}
+	}
+	return p.results, errors.Join(errs...)
+}
+
+// run 执行单个任务，panic 转换为错误（带调用栈）
+func (p *Pool[T]) run(fn func(ctx context.Context) (T, error)) (result T, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			var zero T
+			result, err = zero, errors.Newf("task panicked: %v", rec)
+		}
+	}()
+	return fn(p.ctx)
+}
+
+// set 记录任务结果
+func (p *Pool[T]) set(i int, result T, err error) {
+	p.mu.Lock()
+	p.results[i] = result
+	p.errs[i] = err
+	p.mu.Unlock()
+}
@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/errors"
+)
+
+func TestPoolConcurrencyLimit(t *testing.T) {
+	const limit, tasks = 3, 20
+	pool := NewPool[int](context.Background(), limit)
+
+	var active, peak atomic.Int32
+	for i := 0; i < tasks; i++ {
+		pool.Submit(func(context.Context) (int, error) {
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			active.Add(-1)
+			return i * i, nil
+		})
+	}
+
+	results, err := pool.Wait()
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if p := peak.Load(); p > limit || p < 2 {
+		t.Fatalf("peak concurrency = %d, want at most %d (and tasks running in parallel)", p, limit)
+	}
+	// 结果与提交顺序一致
+	for i, v := range results {
+		if v != i*i {
+			t.Fatalf("results[%d] = %d, want %d", i, v, i*i)
+		}
+	}
+}
+
+func TestPoolAggregatesErrorsAndPanics(t *testing.T) {
+	errBoom := errors.New("boom")
+	pool := NewPool[string](context.Background(), 4)
+
+	for i := 0; i < 6; i++ {
+		pool.Submit(func(context.Context) (string, error) {
+			switch i {
+			case 2:
+				return "ignored", errBoom
+			case 4:
+				panic("bad input")
+			}
+			return "ok", nil
+		})
+	}
+
+	results, err := pool.Wait()
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("err = %v, want it to wrap errBoom", err)
+	}
+	for _, want := range []string{"task 2", "task 4", "task panicked: bad input"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("err = %q, want it to contain %q", err.Error(), want)
+		}
+	}
+	// 单个任务失败不影响其他任务，失败的任务为零值
+	want := []string{"ok", "ok", "", "ok", "", "ok"}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Fatalf("results = %q, want %q", results, want)
+		}
+	}
+}
+
+func TestPoolCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool := NewPool[int](ctx, 1)
+
+	var ran atomic.Int32
+	for i := 0; i < 5; i++ {
+		pool.Submit(func(context.Context) (int, error) {
+			ran.Add(1)
+			if i == 0 {
+				cancel()
+			}
+			return i + 1, nil
+		})
+	}
+
+	results, err := pool.Wait()
+	if n := ran.Load(); n != 1 {
+		t.Fatalf("%d tasks ran, want only the first (the rest start after cancel)", n)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if results[0] != 1 || results[4] != 0 {
+		t.Fatalf("results = %v, want [1 0 0 0 0]", results)
+	}
+}
+
+// 正在执行的任务通过 ctx 感知取消
+func TestPoolPropagatesContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	pool := NewPool[int](ctx, 2)
+
+	for i := 0; i < 2; i++ {
+		pool.Submit(func(ctx context.Context) (int, error) {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return 1, nil
+			}
+		})
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.Wait()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Wait did not return after the parent context ended")
+	}
+}
@@ -3,6 +3,9 @@ package tools
 import (
 	"crypto/rand"
 	"math/big"
+	mrand "math/rand/v2"
+	"sync"
+	"time"
 )
 
 const (
@@ -44,18 +47,98 @@ func randStringFromCharset(length int, charset string) string {
 		return ""
 	}
 
+	gen := currentGenerator()
 	result := make([]byte, length)
-	charsetLen := big.NewInt(int64(len(charset)))
-
 	for i := 0; i < length; i++ {
-		randomInt, err := rand.Int(rand.Reader, charsetLen)
-		if err != nil {
-			// 如果随机数生成失败，使用字符集的第一个字符
-			result[i] = charset[0]
-			continue
-		}
-		result[i] = charset[randomInt.Int64()]
+		result[i] = charset[gen.Intn(len(charset))]
 	}
 
 	return string(result)
 }
+
+// Jitter 在 d 的基础上随机浮动 ±percent%（精度为毫秒），用于错开同时设置的过期时间、重试间隔等
+// 结果始终为正：d 不为正、percent 不为正或浮动范围不足 1 毫秒时原样返回，percent 超过 100 按 100 计算
+func Jitter(d time.Duration, percent int) time.Duration {
+	if d <= 0 || percent <= 0 {
+		return d
+	}
+	percent = min(percent, 100)
+
+	delta := d.Milliseconds() * int64(percent) / 100
+	if delta <= 0 {
+		return d
+	}
+	offset := int64(currentGenerator().Intn(int(2*delta+1))) - delta
+	if result := d + time.Duration(offset)*time.Millisecond; result > 0 {
+		return result
+	}
+	return d
+}
+
+// ========== 随机数生成器 ==========
+
+// Generator 随机数生成器
+// 默认使用 crypto/rand，测试中可通过 SetGenerator 注入确定性的实现
+type Generator interface {
+	// Intn 返回 [0, n) 范围内的随机整数，n 必须大于 0
+	Intn(n int) int
+}
+
+var (
+	generatorMu sync.RWMutex
+	generator   Generator = cryptoGenerator{}
+)
+
+// SetGenerator 替换全局随机数生成器，返回之前的生成器（便于测试结束后恢复）
+// 传入 nil 时恢复为默认的 crypto/rand 实现
+func SetGenerator(g Generator) Generator {
+	if g == nil {
+		g = cryptoGenerator{}
+	}
+
+	generatorMu.Lock()
+	defer generatorMu.Unlock()
+
+	prev := generator
+	generator = g
+	return prev
+}
+
+// currentGenerator 获取当前随机数生成器
+func currentGenerator() Generator {
+	generatorMu.RLock()
+	defer generatorMu.RUnlock()
+	return generator
+}
+
+// cryptoGenerator 基于 crypto/rand 的生成器（默认）
+type cryptoGenerator struct{}
+
+// Intn 返回 [0, n) 范围内的随机整数
+func (cryptoGenerator) Intn(n int) int {
+	randomInt, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		// 如果随机数生成失败，返回 0（即字符集的第一个字符）
+		return 0
+	}
+	return int(randomInt.Int64())
+}
+
+// seededGenerator 固定种子的伪随机生成器（结果可复现，仅用于测试）
+type seededGenerator struct {
+	mu  sync.Mutex
+	rnd *mrand.Rand
+}
+
+// NewSeededGenerator 创建固定种子的生成器，相同种子产生相同序列
+// 不具备密码学安全性，只能用于测试
+func NewSeededGenerator(seed uint64) Generator {
+	return &seededGenerator{rnd: mrand.New(mrand.NewPCG(seed, seed))}
+}
+
+// Intn 返回 [0, n) 范围内的随机整数
+func (g *seededGenerator) Intn(n int) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.rnd.IntN(n)
+}
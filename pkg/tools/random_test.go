@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// sequenceGenerator 按顺序循环返回固定值（对 n 取模）
+type sequenceGenerator struct {
+	values []int
+	next   int
+}
+
+func (g *sequenceGenerator) Intn(n int) int {
+	v := g.values[g.next%len(g.values)]
+	g.next++
+	return v % n
+}
+
+// useGenerator 在测试期间替换全局生成器
+func useGenerator(t *testing.T, g Generator) {
+	t.Helper()
+
+	prev := SetGenerator(g)
+	t.Cleanup(func() { SetGenerator(prev) })
+}
+
+func TestFixedGenerator(t *testing.T) {
+	useGenerator(t, &sequenceGenerator{values: []int{0, 1, 2, 25, 26, 61}})
+
+	if got := RandString(6); got != "abczA9" {
+		t.Fatalf("RandString = %q, want %q", got, "abczA9")
+	}
+	if got := RandNumber(4); got != "0125" {
+		t.Fatalf("RandNumber = %q, want %q", got, "0125")
+	}
+	if got := RandStringCustom(3, "xy"); got != "xyx" {
+		t.Fatalf("RandStringCustom = %q, want %q", got, "xyx")
+	}
+}
+
+func TestSeededGeneratorReproducible(t *testing.T) {
+	generate := func(seed uint64) string {
+		useGenerator(t, NewSeededGenerator(seed))
+		return RandString(16) + RandStringLower(8) + RandStringUpper(8) + RandNumber(8)
+	}
+
+	first, second := generate(42), generate(42)
+	if first != second {
+		t.Fatalf("same seed produced %q and %q", first, second)
+	}
+	if other := generate(43); other == first {
+		t.Fatalf("different seeds produced the same output %q", first)
+	}
+}
+
+func TestSetGeneratorNilRestoresDefault(t *testing.T) {
+	useGenerator(t, NewSeededGenerator(1))
+	SetGenerator(nil)
+
+	if _, ok := currentGenerator().(cryptoGenerator); !ok {
+		t.Fatalf("generator = %T, want cryptoGenerator", currentGenerator())
+	}
+	if got := RandStringLower(32); len(got) != 32 || strings.Trim(got, alphaLower) != "" {
+		t.Fatalf("RandStringLower = %q", got)
+	}
+}
+
+func TestJitterWithinBand(t *testing.T) {
+	const d = 300 * time.Second
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 1000; i++ {
+		got := Jitter(d, 10)
+		if got < 270*time.Second || got > 330*time.Second {
+			t.Fatalf("Jitter(%v, 10) = %v, want within ±10%%", d, got)
+		}
+		seen[got] = true
+	}
+	if len(seen) < 100 {
+		t.Fatalf("only %d distinct values in 1000 draws", len(seen))
+	}
+}
+
+func TestJitterBounds(t *testing.T) {
+	// 生成器返回最小值和最大值：正好落在区间两端
+	useGenerator(t, &sequenceGenerator{values: []int{0, 60000}})
+	if got := Jitter(300*time.Second, 10); got != 270*time.Second {
+		t.Fatalf("lowest = %v, want 270s", got)
+	}
+	if got := Jitter(300*time.Second, 10); got != 330*time.Second {
+		t.Fatalf("highest = %v, want 330s", got)
+	}
+}
+
+func TestJitterNeverNonPositive(t *testing.T) {
+	// 总是取最小值：浮动 100% 及以上时结果为 0，应退回原值
+	useGenerator(t, &sequenceGenerator{values: []int{0}})
+
+	tests := []struct {
+		d       time.Duration
+		percent int
+		want    time.Duration
+	}{
+		{time.Second, 100, time.Second},
+		{time.Second, 500, time.Second},          // 超过 100 按 100 计算
+		{time.Millisecond, 50, time.Millisecond}, // 浮动不足 1 毫秒
+		{time.Second, 0, time.Second},
+		{0, 10, 0},
+		{-time.Second, 10, -time.Second},
+		{time.Second, 50, 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := Jitter(tt.d, tt.percent); got != tt.want {
+			t.Fatalf("Jitter(%v, %d) = %v, want %v", tt.d, tt.percent, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"math"
+	mrand "math/rand/v2"
+	"time"
+
+	"go-api-template/pkg/errors"
+)
+
+// RetryPolicy 重试策略
+// 第 n 次重试前等待 BaseDelay * Factor^(n-1)，不超过 MaxDelay，再按 Jitter 随机缩短
+type RetryPolicy struct {
+	MaxAttempts int           // 最大尝试次数（含第一次），<=0 时为 3
+	BaseDelay   time.Duration // 首次重试前的等待时间，<=0 时为 100ms
+	MaxDelay    time.Duration // 单次等待上限，<=0 时不限制
+	Factor      float64       // 指数因子，<1 时为 2
+	Jitter      float64       // 抖动比例 [0, 1]，实际等待在 d*(1-Jitter) 到 d 之间，避免多个调用方同时重试
+}
+
+// DefaultRetryPolicy 默认重试策略：最多 3 次，100ms 起指数退避，上限 2s，50% 抖动
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+		Factor:      2,
+		Jitter:      0.5,
+	}
+}
+
+// permanentError 不可重试的错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 将错误标记为不可重试，Retry 遇到后立即返回原错误
+// 例如参数错误、记录不存在等重试也不会成功的情况
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Retry 按策略执行 fn，直到成功、返回 Permanent 错误、达到最大次数或 ctx 结束
+//   - 达到最大次数：返回最后一次的错误
+//   - Permanent 错误：返回被包装的原错误
+//   - ctx 结束：返回包装了 ctx.Err() 的错误（errors.Is(err, context.Canceled) 成立）
+func Retry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	policy = policy.normalize()
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return retryAborted(err, attempt-1, lastErr)
+		}
+
+		lastErr = fn()
+		if lastErr == nil {
+			return nil
+		}
+
+		var perm *permanentError
+		if errors.As(lastErr, &perm) {
+			return perm.err
+		}
+		if attempt >= policy.MaxAttempts {
+			return lastErr
+		}
+
+		timer := time.NewTimer(policy.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return retryAborted(ctx.Err(), attempt, lastErr)
+		case <-timer.C:
+		}
+	}
+}
+
+// normalize 补全策略默认值
+func (p RetryPolicy) normalize() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = 100 * time.Millisecond
+	}
+	if p.Factor < 1 {
+		p.Factor = 2
+	}
+	p.Jitter = math.Min(math.Max(p.Jitter, 0), 1)
+	return p
+}
+
+// delay 第 attempt 次失败后的等待时间
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := float64(p.BaseDelay) * math.Pow(p.Factor, float64(attempt-1))
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * mrand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// retryAborted ctx 结束时的错误，附带已尝试次数和最后一次错误
+func retryAborted(ctxErr error, attempts int, lastErr error) error {
+	if lastErr == nil {
+		return errors.Wrap(ctxErr, "retry aborted")
+	}
+	return errors.Wrapf(ctxErr, "retry aborted after %d attempts, last error: %v", attempts, lastErr)
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/errors"
+)
+
+// fastPolicy 测试用的短等待策略
+func fastPolicy(maxAttempts int) RetryPolicy {
+	return RetryPolicy{MaxAttempts: maxAttempts, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Factor: 2}
+}
+
+func TestRetrySucceedsAfterFailures(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), fastPolicy(5), func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("temporary")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("err = %v, calls = %d, want nil, 3", err, calls)
+	}
+}
+
+func TestRetryReturnsLastErrorAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	errLast := errors.New("still failing")
+	err := Retry(context.Background(), fastPolicy(4), func() error {
+		calls++
+		return errLast
+	})
+	if err != errLast || calls != 4 {
+		t.Fatalf("err = %v, calls = %d, want errLast, 4", err, calls)
+	}
+}
+
+func TestRetryStopsOnPermanentError(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), fastPolicy(5), func() error {
+		calls++
+		return Permanent(errors.ErrNotFound)
+	})
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+	if err != errors.ErrNotFound {
+		t.Fatalf("err = %v, want the unwrapped ErrNotFound", err)
+	}
+	if Permanent(nil) != nil {
+		t.Fatal("Permanent(nil) != nil")
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Hour}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Retry(ctx, policy, func() error {
+			calls++
+			return errors.New("temporary")
+		})
+	}()
+
+	// 第一次失败后进入一小时的等待，取消应立即返回
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("err = %v, want context.Canceled", err)
+		}
+		if calls != 1 {
+			t.Fatalf("calls = %d, want 1", calls)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Retry did not return after cancel")
+	}
+}
+
+func TestRetryCanceledBeforeFirstAttempt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Retry(ctx, fastPolicy(3), func() error {
+		t.Fatal("fn called with canceled context")
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestRetryDelayBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond, Factor: 2}.normalize()
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 300 * time.Millisecond, 6: 300 * time.Millisecond} {
+		if got := p.delay(attempt); got != want {
+			t.Fatalf("delay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.delay(2); got < 100*time.Millisecond || got > 200*time.Millisecond {
+			t.Fatalf("jittered delay(2) = %v, want in [100ms, 200ms]", got)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"crypto/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"go-api-template/pkg/errors"
+)
+
+// crockford ULID 使用的 Crockford Base32 字符集（字典序与数值序一致）
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidLen ULID 字符串长度
+const ulidLen = 26
+
+var (
+	ulidMu      sync.Mutex
+	ulidLastMs  uint64
+	ulidEntropy [10]byte
+)
+
+// ULID 生成 ULID（26 位，48 位毫秒时间戳 + 80 位 crypto/rand 随机数）
+// 字符串按字典序排序即按生成时间排序，适合日志类资源的 ID 或分页游标；
+// 同一毫秒内生成的 ULID 在上一个随机部分的基础上加 1，保证进程内严格递增
+func ULID() string {
+	ulidMu.Lock()
+	defer ulidMu.Unlock()
+
+	ms := uint64(time.Now().UnixMilli())
+	if ms <= ulidLastMs {
+		// 同一毫秒（或时钟回拨）：沿用上一个时间戳，随机部分加 1
+		ms = ulidLastMs
+		if !incrementEntropy(&ulidEntropy) {
+			// 随机部分溢出，借用下一毫秒
+			ms++
+			readEntropy(&ulidEntropy)
+		}
+	} else {
+		readEntropy(&ulidEntropy)
+	}
+	ulidLastMs = ms
+
+	return encodeULID(ms, ulidEntropy)
+}
+
+// ULIDTime 解析 ULID 中的时间戳（毫秒精度）
+func ULIDTime(id string) (time.Time, error) {
+	if len(id) != ulidLen {
+		return time.Time{}, errors.Newf("invalid ulid length: %d", len(id))
+	}
+	// 首字符只有 3 位有效，超过 7 说明溢出
+	if id[0] > '7' {
+		return time.Time{}, errors.Newf("invalid ulid: %s", id)
+	}
+
+	var ms uint64
+	for i := 0; i < 10; i++ {
+		v := strings.IndexByte(crockford, upper(id[i]))
+		if v < 0 {
+			return time.Time{}, errors.Newf("invalid ulid character: %q", id[i])
+		}
+		ms = ms<<5 | uint64(v)
+	}
+	return time.UnixMilli(int64(ms)), nil
+}
+
+// readEntropy 填充随机部分，crypto/rand 失败时退化为当前生成器
+func readEntropy(entropy *[10]byte) {
+	if _, err := rand.Read(entropy[:]); err != nil {
+		gen := currentGenerator()
+		for i := range entropy {
+			entropy[i] = byte(gen.Intn(256))
+		}
+	}
+}
+
+// incrementEntropy 随机部分按大端整数加 1，溢出时返回 false
+func incrementEntropy(entropy *[10]byte) bool {
+	for i := len(entropy) - 1; i >= 0; i-- {
+		entropy[i]++
+		if entropy[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID 将时间戳和随机部分编码为 26 位 Crockford Base32
+func encodeULID(ms uint64, entropy [10]byte) string {
+	// 128 位拆为高低两个 64 位：高 48 位时间戳 + 16 位随机，低 64 位随机
+	hi := ms<<16 | uint64(entropy[0])<<8 | uint64(entropy[1])
+	var lo uint64
+	for _, b := range entropy[2:] {
+		lo = lo<<8 | uint64(b)
+	}
+
+	var out [ulidLen]byte
+	for i := ulidLen - 1; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// upper 将小写字母转为大写（ULID 解析不区分大小写）
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestULIDSortsByCreationTime(t *testing.T) {
+	const n = 10000 // 大量生成，覆盖同一毫秒内的多个 ULID
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = ULID()
+	}
+
+	if !sort.StringsAreSorted(ids) {
+		t.Fatal("ULIDs generated in order are not lexicographically sorted")
+	}
+	seen := make(map[string]struct{}, n)
+	for _, id := range ids {
+		if len(id) != ulidLen {
+			t.Fatalf("len(%q) = %d, want %d", id, len(id), ulidLen)
+		}
+		if _, dup := seen[id]; dup {
+			t.Fatalf("duplicate ULID %q", id)
+		}
+		seen[id] = struct{}{}
+	}
+}
+
+func TestULIDSortsAcrossMilliseconds(t *testing.T) {
+	first := ULID()
+	time.Sleep(2 * time.Millisecond)
+	second := ULID()
+	if first >= second {
+		t.Fatalf("%q generated before %q but does not sort first", first, second)
+	}
+}
+
+func TestULIDTime(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	id := ULID()
+	after := time.Now()
+
+	got, err := ULIDTime(id)
+	if err != nil {
+		t.Fatalf("ULIDTime(%q): %v", id, err)
+	}
+	// 同一毫秒内的溢出最多借用后续毫秒，这里只生成一个，时间戳应落在生成前后之间
+	if got.Before(before.Add(-time.Millisecond)) || got.After(after.Add(time.Millisecond)) {
+		t.Fatalf("ULIDTime = %v, want between %v and %v", got, before, after)
+	}
+	if lower, err := ULIDTime(strings.ToLower(id)); err != nil || !lower.Equal(got) {
+		t.Fatalf("lowercase ULIDTime = %v, %v", lower, err)
+	}
+
+	for _, invalid := range []string{"", "01ARZ3NDEKTSV4RRFFQ69G5FA", "81ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEUTSV4RRFFQ69G5FAV"} {
+		if _, err := ULIDTime(invalid); err == nil {
+			t.Fatalf("ULIDTime(%q) succeeded, want error", invalid)
+		}
+	}
+}
+
+func TestEncodeULIDKnownValue(t *testing.T) {
+	var entropy [10]byte
+	if got := encodeULID(0, entropy); got != "00000000000000000000000000" {
+		t.Fatalf("encodeULID(0) = %q", got)
+	}
+	for i := range entropy {
+		entropy[i] = 0xff
+	}
+	if got := encodeULID(1<<48-1, entropy); got != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Fatalf("encodeULID(max) = %q", got)
+	}
+}
+
+func TestIncrementEntropyOverflow(t *testing.T) {
+	entropy := [10]byte{9: 0xfe}
+	if !incrementEntropy(&entropy) || entropy[9] != 0xff {
+		t.Fatalf("increment = %v", entropy)
+	}
+	for i := range entropy {
+		entropy[i] = 0xff
+	}
+	if incrementEntropy(&entropy) {
+		t.Fatal("incrementing max entropy did not report overflow")
+	}
+}
@@ -0,0 +1,172 @@
+package web
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"go-api-template/pkg/errors"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Bind 根据 Content-Type 选择绑定方式，并执行 binding 标签校验
+//   - application/json（或未指定）：JSON 请求体（先按 JSONLimits 检查大小、嵌套深度和重复 key）
+//   - application/x-www-form-urlencoded：表单
+//   - multipart/form-data：multipart 表单
+//
+// 表单字段名取自 form 标签，校验规则与 JSON 一致，错误信息格式相同
+func Bind(c *Context, obj interface{}) error {
+	b, err := bindingFor(c.GetHeader("Content-Type"))
+	if err != nil {
+		return err
+	}
+	if b == binding.JSON {
+		return bindJSON(c, obj)
+	}
+	return c.ShouldBindWith(obj, b)
+}
+
+// MustBind 按 Content-Type 绑定并校验请求（同 Bind），失败时写入统一的校验错误响应并返回 false
+// 用法：if !ctx.MustBind(&req) { return }
+func (c *Context) MustBind(obj interface{}) bool {
+	if err := Bind(c, obj); err != nil {
+		InvalidRequest(c, err)
+		return false
+	}
+	return true
+}
+
+// MustBindJSON 按 JSON 绑定并校验请求体（忽略 Content-Type），失败时写入统一的校验错误响应并返回 false
+func (c *Context) MustBindJSON(obj interface{}) bool {
+	if err := bindJSON(c, obj); err != nil {
+		InvalidRequest(c, err)
+		return false
+	}
+	return true
+}
+
+// multipartMemory multipart 表单解析时的内存上限（与 Gin 默认一致），超出部分写入临时文件
+const multipartMemory = 32 << 20
+
+// BindAll 在一次调用中从多个来源绑定请求，全部填充后再统一按 binding 标签校验：
+//   - uri 标签：路径参数（如 :id）
+//   - header 标签：请求头（不区分大小写）
+//   - form 标签：查询参数，以及表单/multipart 请求体
+//   - json 标签：JSON 请求体（请求体为空时跳过）
+//
+// 按以上顺序绑定，后面的来源覆盖前面的同名字段（请求体优先于查询参数）。只来自路径或请求头的字段应设置 json:"-" form:"-"。
+// multipart 中的文件字段请使用 Bind 或 ctx.FormFile
+//
+//	type UpdateRequest struct {
+//	    ID    uint   `uri:"id" json:"-" form:"-" binding:"required"`
+//	    Title string `json:"title" form:"title" binding:"required"`
+//	}
+func BindAll(c *Context, obj interface{}) error {
+	params := make(map[string][]string, len(c.Params))
+	for _, p := range c.Params {
+		params[p.Key] = []string{p.Value}
+	}
+	if err := binding.MapFormWithTag(obj, params, "uri"); err != nil {
+		return errors.Wrap(err, "uri")
+	}
+
+	if err := binding.MapFormWithTag(obj, headerValues(c.Request.Header, obj), "header"); err != nil {
+		return errors.Wrap(err, "header")
+	}
+
+	if err := binding.MapFormWithTag(obj, c.Request.URL.Query(), "form"); err != nil {
+		return errors.Wrap(err, "query")
+	}
+
+	if err := bindBody(c, obj); err != nil {
+		return err
+	}
+
+	return Validate(obj)
+}
+
+// MustBindAll 同 BindAll，失败时写入统一的校验错误响应并返回 false
+func (c *Context) MustBindAll(obj interface{}) bool {
+	if err := BindAll(c, obj); err != nil {
+		InvalidRequest(c, err)
+		return false
+	}
+	return true
+}
+
+// bindBody 按 Content-Type 解码请求体（不校验）
+func bindBody(c *Context, obj interface{}) error {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody || c.Request.ContentLength == 0 {
+		return nil
+	}
+
+	b, err := bindingFor(c.GetHeader("Content-Type"))
+	if err != nil {
+		return err
+	}
+	switch b {
+	case binding.Form:
+		if err := c.Request.ParseForm(); err != nil {
+			return errors.Wrap(err, "body")
+		}
+		return errors.Wrap(binding.MapFormWithTag(obj, c.Request.PostForm, "form"), "body")
+	case binding.FormMultipart:
+		if err := c.Request.ParseMultipartForm(multipartMemory); err != nil {
+			return errors.Wrap(err, "body")
+		}
+		return errors.Wrap(binding.MapFormWithTag(obj, c.Request.MultipartForm.Value, "form"), "body")
+	default:
+		if err := decodeJSON(c, obj); err != nil && err != io.EOF { // 分块传输的空请求体
+			return errors.Wrap(err, "body")
+		}
+		return nil
+	}
+}
+
+// headerValues 取出结构体 header 标签对应的请求头（按规范化名称查找，标签不区分大小写）
+func headerValues(header http.Header, obj interface{}) map[string][]string {
+	values := make(map[string][]string)
+	t := reflect.TypeOf(obj)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return values
+	}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("header"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		if v := header.Values(name); len(v) > 0 {
+			values[name] = v
+		}
+	}
+	return values
+}
+
+// bindingFor 返回 Content-Type 对应的绑定器
+func bindingFor(contentType string) (binding.Binding, error) {
+	if contentType == "" {
+		return binding.JSON, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid content type: %s", contentType)
+	}
+
+	switch mediaType {
+	case binding.MIMEJSON:
+		return binding.JSON, nil
+	case binding.MIMEPOSTForm:
+		return binding.Form, nil
+	case binding.MIMEMultipartPOSTForm:
+		return binding.FormMultipart, nil
+	default:
+		return nil, errors.Newf("unsupported content type: %s", mediaType)
+	}
+}
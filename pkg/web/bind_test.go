@@ -0,0 +1,289 @@
+package web_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+type bindRequest struct {
+	Title  string `json:"title" form:"title" binding:"required,max=5"`
+	Status int    `json:"status" form:"status" binding:"oneof=0 1"`
+}
+
+func newBindServer(t *testing.T) *webtest.Server {
+	return webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").POST("/bind", func(ctx *web.Context) {
+			var req bindRequest
+			if !ctx.MustBind(&req) {
+				return
+			}
+			web.Success(ctx, req)
+		})
+	})
+}
+
+// encodeBody 按 Content-Type 编码表单字段，返回请求体和完整的 Content-Type
+func encodeBody(t *testing.T, contentType string, fields map[string]string) (string, string) {
+	t.Helper()
+
+	switch contentType {
+	case "application/json":
+		data, err := json.Marshal(fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data), contentType
+	case "application/x-www-form-urlencoded":
+		values := url.Values{}
+		for k, v := range fields {
+			values.Set(k, v)
+		}
+		return values.Encode(), contentType
+	default:
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		for k, v := range fields {
+			if err := w.WriteField(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String(), w.FormDataContentType()
+	}
+}
+
+var bindContentTypes = []string{"application/json", "application/x-www-form-urlencoded", "multipart/form-data"}
+
+func TestBindSameStructFromEachContentType(t *testing.T) {
+	for _, ct := range bindContentTypes {
+		t.Run(ct, func(t *testing.T) {
+			s := newBindServer(t)
+			// JSON 中 status 为字符串无法解码到 int，单独构造
+			body, contentType := encodeBody(t, ct, map[string]string{"title": "hello", "status": "1"})
+			if ct == "application/json" {
+				body = `{"title":"hello","status":1}`
+			}
+			s.Header.Set("Content-Type", contentType)
+
+			w := s.Do(http.MethodPost, "/bind", body)
+			s.AssertCode(w, http.StatusOK)
+			var got bindRequest
+			s.DecodeData(w, &got)
+			if got != (bindRequest{Title: "hello", Status: 1}) {
+				t.Fatalf("bound %+v", got)
+			}
+		})
+	}
+}
+
+func TestBindValidationErrorsMatchAcrossContentTypes(t *testing.T) {
+	var first string
+	for _, ct := range bindContentTypes {
+		s := newBindServer(t)
+		body, contentType := encodeBody(t, ct, map[string]string{"title": "too long"})
+		s.Header.Set("Content-Type", contentType)
+
+		w := s.Do(http.MethodPost, "/bind", body)
+		s.AssertCode(w, http.StatusBadRequest)
+		var data struct {
+			Errors []web.FieldError `json:"errors"`
+		}
+		s.DecodeData(w, &data)
+		if len(data.Errors) != 1 || data.Errors[0].Field != "title" || data.Errors[0].Rule != "max" {
+			t.Fatalf("%s: errors = %+v", ct, data.Errors)
+		}
+
+		if first == "" {
+			first = w.Body.String()
+		} else if w.Body.String() != first {
+			t.Fatalf("%s: body %s differs from JSON body %s", ct, w.Body.String(), first)
+		}
+	}
+}
+
+func TestBindUnsupportedContentType(t *testing.T) {
+	s := newBindServer(t)
+	s.Header.Set("Content-Type", "text/plain")
+	s.AssertCode(s.Do(http.MethodPost, "/bind", "title=hello"), http.StatusBadRequest)
+}
+
+func TestMustBindJSONAbortsOnInvalidBody(t *testing.T) {
+	ran := 0
+	s := webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").POST("/bind", func(ctx *web.Context) {
+			var req bindRequest
+			if !ctx.MustBindJSON(&req) {
+				return
+			}
+			ran++
+			web.Success(ctx, req)
+		})
+	})
+
+	// 校验失败：逐字段错误
+	w := s.Do(http.MethodPost, "/bind", json.RawMessage(`{"title":"too long","status":3}`))
+	s.AssertCode(w, http.StatusBadRequest)
+	var data struct {
+		Errors []web.FieldError `json:"errors"`
+	}
+	s.DecodeData(w, &data)
+	if len(data.Errors) != 2 || data.Errors[0].Field != "title" || data.Errors[1].Field != "status" {
+		t.Fatalf("errors = %+v", data.Errors)
+	}
+
+	// 无法解析的请求体
+	w = s.Do(http.MethodPost, "/bind", `{"title":`)
+	s.AssertCode(w, http.StatusBadRequest)
+
+	if ran != 0 {
+		t.Fatalf("handler body ran %d times after bind failures", ran)
+	}
+
+	// 忽略 Content-Type，始终按 JSON 解析
+	s.Header.Set("Content-Type", "text/plain")
+	s.AssertCode(s.Do(http.MethodPost, "/bind", `{"title":"ok","status":1}`), http.StatusOK)
+	if ran != 1 {
+		t.Fatalf("handler body ran %d times, want 1", ran)
+	}
+}
+
+type bindAllRequest struct {
+	ID     uint   `uri:"id" json:"-" form:"-" binding:"required"`
+	Tenant string `header:"x-tenant-id" json:"-" form:"-" binding:"required"`
+	Page   int    `form:"page" json:"-"`
+	Title  string `json:"title" form:"title" binding:"required"`
+	Status int    `json:"status" form:"status" binding:"oneof=0 1"`
+}
+
+func newBindAllServer(t *testing.T) *webtest.Server {
+	return webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").PUT("/items/:id", func(ctx *web.Context) {
+			var req bindAllRequest
+			if !ctx.MustBindAll(&req) {
+				return
+			}
+			web.Success(ctx, web.Map{"id": req.ID, "tenant": req.Tenant, "page": req.Page, "title": req.Title, "status": req.Status})
+		})
+	})
+}
+
+func TestBindAllEachSource(t *testing.T) {
+	for _, ct := range bindContentTypes {
+		t.Run(ct, func(t *testing.T) {
+			s := newBindAllServer(t)
+			s.Header.Set("X-Tenant-ID", "acme")
+			body, contentType := encodeBody(t, ct, map[string]string{"title": "hello", "status": "1"})
+			if ct == "application/json" {
+				body = `{"title":"hello","status":1,"id":99}` // 请求体中的 id 被忽略（json:"-"）
+			}
+			s.Header.Set("Content-Type", contentType)
+
+			w := s.Do(http.MethodPut, "/items/7?page=3&status=0", body)
+			s.AssertCode(w, http.StatusOK)
+			var got struct {
+				ID     uint   `json:"id"`
+				Tenant string `json:"tenant"`
+				Page   int    `json:"page"`
+				Title  string `json:"title"`
+				Status int    `json:"status"`
+			}
+			s.DecodeData(w, &got)
+			// 路径、请求头、查询参数、请求体各取一个字段；请求体覆盖查询参数中的 status
+			if got.ID != 7 || got.Tenant != "acme" || got.Page != 3 || got.Title != "hello" || got.Status != 1 {
+				t.Fatalf("bound %+v", got)
+			}
+		})
+	}
+}
+
+func TestBindAllValidatesMergedStruct(t *testing.T) {
+	s := newBindAllServer(t)
+
+	// 缺少请求头、路径参数无法解析
+	w := s.Do(http.MethodPut, "/items/7", json.RawMessage(`{"title":"hello"}`))
+	s.AssertCode(w, http.StatusBadRequest)
+	var data struct {
+		Errors []web.FieldError `json:"errors"`
+	}
+	s.DecodeData(w, &data)
+	if len(data.Errors) != 1 || data.Errors[0].Field != "x-tenant-id" || data.Errors[0].Rule != "required" {
+		t.Fatalf("errors = %+v", data.Errors)
+	}
+
+	s.Header.Set("X-Tenant-ID", "acme")
+	s.AssertCode(s.Do(http.MethodPut, "/items/abc", json.RawMessage(`{"title":"hello"}`)), http.StatusBadRequest)
+
+	// 没有请求体时跳过请求体，form 字段取自查询参数
+	w = s.Do(http.MethodPut, "/items/7?title=from-query", nil)
+	s.AssertCode(w, http.StatusOK)
+	var got struct {
+		Title string `json:"title"`
+	}
+	s.DecodeData(w, &got)
+	if got.Title != "from-query" {
+		t.Fatalf("title = %q, want from-query", got.Title)
+	}
+}
+
+type bulkItem struct {
+	Title  string `json:"title" binding:"required"`
+	Status int    `json:"status" binding:"oneof=0 1"`
+}
+
+type bulkRequest struct {
+	Items []bulkItem `json:"items" binding:"required,dive"`
+}
+
+// assertFieldErrors 断言 400 响应 data.errors 中的字段路径
+func assertFieldErrors(t *testing.T, s *webtest.Server, w *httptest.ResponseRecorder, want ...string) {
+	t.Helper()
+
+	s.AssertCode(w, http.StatusBadRequest)
+	var data struct {
+		Errors []web.FieldError `json:"errors"`
+	}
+	s.DecodeData(w, &data)
+	if len(data.Errors) != len(want) {
+		t.Fatalf("errors = %+v, want fields %v", data.Errors, want)
+	}
+	for i, field := range want {
+		if data.Errors[i].Field != field || !strings.HasPrefix(data.Errors[i].Message, field+" ") {
+			t.Fatalf("errors[%d] = %+v, want field %s", i, data.Errors[i], field)
+		}
+	}
+}
+
+func TestBindArrayElementErrorHasIndexedPath(t *testing.T) {
+	s := webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "")
+		g.POST("/bulk", func(ctx *web.Context) {
+			var req bulkRequest
+			if ctx.MustBind(&req) {
+				web.Success(ctx, nil)
+			}
+		})
+		g.POST("/array", func(ctx *web.Context) {
+			var req []bulkItem
+			if ctx.MustBind(&req) {
+				web.Success(ctx, nil)
+			}
+		})
+	})
+
+	body := `[{"title":"a","status":1},{"title":"b","status":0},{"title":"c","status":7},{"status":1}]`
+	assertFieldErrors(t, s, s.Do(http.MethodPost, "/bulk", `{"items":`+body+`}`), "items[2].status", "items[3].title")
+	assertFieldErrors(t, s, s.Do(http.MethodPost, "/array", body), "[2].status", "[3].title")
+}
@@ -0,0 +1,85 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheControl 设置响应缓存头（Cache-Control / Expires）的中间件
+// 仅对 GET/HEAD 请求生效；携带 Authorization 的请求视为已认证请求，不设置缓存头，
+// 避免 CDN 或共享缓存把用户私有数据返回给其他人。
+// 只有 2xx 和 304 响应才带缓存头：在响应头发送前按状态码决定，错误响应（4xx/5xx）不会被缓存
+func CacheControl(maxAge time.Duration, public bool) HandlerFunc {
+	scope := "private"
+	if public {
+		scope = "public"
+	}
+	value := fmt.Sprintf("%s, max-age=%d", scope, int(maxAge.Seconds()))
+
+	return func(ctx *Context) {
+		method := ctx.Request.Method
+		if (method != http.MethodGet && method != http.MethodHead) || ctx.GetHeader("Authorization") != "" {
+			ctx.Next()
+			return
+		}
+
+		// 包装 ResponseWriter，在响应头发送前按状态码写入缓存头
+		w := &cacheControlWriter{ResponseWriter: ctx.Writer, value: value, maxAge: maxAge}
+		ctx.Writer = w
+
+		ctx.Next()
+
+		// Handler 没有写响应体（如 304）时，在这里补上
+		w.writeCacheHeaders()
+	}
+}
+
+// cacheControlWriter 在响应头发送前写入缓存头
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	value  string
+	maxAge time.Duration
+	done   bool
+}
+
+// writeCacheHeaders 成功响应写入缓存头（只写一次，响应头已发送后不再写）
+func (w *cacheControlWriter) writeCacheHeaders() {
+	if w.done || w.ResponseWriter.Written() {
+		return
+	}
+	w.done = true
+
+	status := w.ResponseWriter.Status()
+	if (status < 200 || status > 299) && status != http.StatusNotModified {
+		return
+	}
+	w.Header().Set("Cache-Control", w.value)
+	w.Header().Set("Expires", time.Now().Add(w.maxAge).UTC().Format(http.TimeFormat))
+}
+
+// WriteHeaderNow 发送响应头
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.writeCacheHeaders()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write 写入响应体
+func (w *cacheControlWriter) Write(data []byte) (int, error) {
+	w.writeCacheHeaders()
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString 写入字符串响应体
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.writeCacheHeaders()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Flush 刷新缓冲区
+func (w *cacheControlWriter) Flush() {
+	w.writeCacheHeaders()
+	w.ResponseWriter.Flush()
+}
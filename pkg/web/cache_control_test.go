@@ -0,0 +1,76 @@
+package web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCacheControlServer(t *testing.T) *webtest.Server {
+	return webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "")
+		cc := web.CacheControl(30*time.Second, true)
+		g.GET("/ok", cc, func(ctx *web.Context) { web.Success(ctx, nil) })
+		g.GET("/not-modified", cc, func(ctx *web.Context) { ctx.AbortWithStatus(http.StatusNotModified) })
+		g.GET("/bad", cc, func(ctx *web.Context) { web.BadRequest(ctx, "bad") })
+		g.GET("/fail", cc, func(ctx *web.Context) { web.InternalError(ctx, "fail") })
+		g.POST("/ok", cc, func(ctx *web.Context) { web.Success(ctx, nil) })
+	})
+}
+
+func TestCacheControl(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		auth   bool
+		want   string
+	}{
+		{name: "success", method: http.MethodGet, path: "/ok", want: "public, max-age=30"},
+		{name: "head", method: http.MethodHead, path: "/ok", want: "public, max-age=30"},
+		{name: "not modified", method: http.MethodGet, path: "/not-modified", want: "public, max-age=30"},
+		{name: "client error", method: http.MethodGet, path: "/bad"},
+		{name: "server error", method: http.MethodGet, path: "/fail"},
+		{name: "mutating", method: http.MethodPost, path: "/ok"},
+		{name: "authenticated", method: http.MethodGet, path: "/ok", auth: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newCacheControlServer(t)
+			if tt.auth {
+				s.Header.Set("Authorization", "Bearer token")
+			}
+			w := s.Do(tt.method, tt.path, nil)
+
+			if got := w.Header().Get("Cache-Control"); got != tt.want {
+				t.Fatalf("Cache-Control = %q, want %q (status %d)", got, tt.want, w.Code)
+			}
+			checkExpires(t, w, tt.want != "")
+		})
+	}
+}
+
+func checkExpires(t *testing.T, w *httptest.ResponseRecorder, want bool) {
+	t.Helper()
+
+	expires := w.Header().Get("Expires")
+	if !want {
+		if expires != "" {
+			t.Fatalf("Expires = %q, want none", expires)
+		}
+		return
+	}
+	at, err := http.ParseTime(expires)
+	if err != nil {
+		t.Fatalf("Expires = %q: %v", expires, err)
+	}
+	if d := time.Until(at); d < 28*time.Second || d > 31*time.Second {
+		t.Fatalf("Expires in %v, want ~30s", d)
+	}
+}
@@ -19,3 +19,23 @@ func (c *Context) GetRequestID() string {
 	reqID := c.GetString(constants.CtxKeyRequestID)
 	return reqID
 }
+
+// AppID 获取当前请求的应用 ID（未设置或类型不符时返回空字符串）
+func (c *Context) AppID() string {
+	return c.GetString(constants.CtxKeyAppID)
+}
+
+// AppKey 获取当前请求的应用 KEY（未设置或类型不符时返回空字符串）
+func (c *Context) AppKey() string {
+	return c.GetString(constants.CtxKeyAppKey)
+}
+
+// AppName 获取当前请求的应用名称（未设置或类型不符时返回空字符串）
+func (c *Context) AppName() string {
+	return c.GetString(constants.CtxKeyAppName)
+}
+
+// UserID 获取当前登录用户 ID（未设置或类型不符时返回空字符串）
+func (c *Context) UserID() string {
+	return c.GetString(constants.CtxKeyUserID)
+}
@@ -0,0 +1,46 @@
+package web
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"go-api-template/internal/constants"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestContext() *Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	return &Context{Context: c}
+}
+
+func TestContextStoreHelpers(t *testing.T) {
+	accessors := []struct {
+		key string
+		get func(*Context) string
+	}{
+		{constants.CtxKeyAppID, (*Context).AppID},
+		{constants.CtxKeyAppKey, (*Context).AppKey},
+		{constants.CtxKeyAppName, (*Context).AppName},
+		{constants.CtxKeyUserID, (*Context).UserID},
+		{constants.CtxKeyRequestID, (*Context).GetRequestID},
+	}
+	for _, a := range accessors {
+		t.Run(a.key, func(t *testing.T) {
+			c := newTestContext()
+			if got := a.get(c); got != "" {
+				t.Fatalf("missing value = %q, want empty", got)
+			}
+
+			c.Set(a.key, 42)
+			if got := a.get(c); got != "" {
+				t.Fatalf("wrong-type value = %q, want empty", got)
+			}
+
+			c.Set(a.key, "value")
+			if got := a.get(c); got != "value" {
+				t.Fatalf("value = %q, want %q", got, "value")
+			}
+		})
+	}
+}
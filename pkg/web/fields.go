@@ -0,0 +1,125 @@
+package web
+
+import (
+	"reflect"
+	"strings"
+
+	"go-api-template/pkg/errors"
+)
+
+// FieldsQuery 字段筛选的查询参数名
+const FieldsQuery = "fields"
+
+// SelectFields 按 fields 查询参数（如 ?fields=id,title）裁剪响应数据，只保留请求的 JSON 字段
+// 支持结构体、map 及其切片；字段名与响应中的 JSON 字段名一致（开启 camelCase 时使用 camelCase），
+// 按 data 的元素类型校验，未知字段返回 errors.ErrInvalidParams；未传 fields 时原样返回
+//
+//	data, err := web.SelectFields(ctx, demos)
+//	if err != nil {
+//		web.BadRequest(ctx, err.Error())
+//		return
+//	}
+//	web.Success(ctx, data)
+func SelectFields(c *Context, data interface{}) (interface{}, error) {
+	raw := strings.TrimSpace(c.Query(FieldsQuery))
+	if raw == "" {
+		return data, nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			wanted[name] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return data, nil
+	}
+
+	// 校验字段名（元素类型为结构体时）
+	if known := jsonOptions.fieldNames(reflect.TypeOf(data)); known != nil {
+		for name := range wanted {
+			if !known[name] {
+				return nil, errors.Wrapf(errors.ErrInvalidParams, "unknown field: %s", name)
+			}
+		}
+	}
+
+	// nil 切片保持原样，由 Success 输出 []
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+		return data, nil
+	}
+	return pruneFields(jsonOptions.normalize(reflect.ValueOf(data)), wanted), nil
+}
+
+// pruneFields 只保留对象中 wanted 内的字段，数组逐个处理
+func pruneFields(v interface{}, wanted map[string]bool) interface{} {
+	switch val := v.(type) {
+	case orderedObject:
+		obj := make(orderedObject, 0, len(wanted))
+		for _, f := range val {
+			if wanted[f.key] {
+				obj = append(obj, f)
+			}
+		}
+		return obj
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(wanted))
+		for k, fv := range val {
+			if wanted[k] {
+				m[k] = fv
+			}
+		}
+		return m
+	case []interface{}:
+		for i := range val {
+			val[i] = pruneFields(val[i], wanted)
+		}
+		return val
+	}
+	return v
+}
+
+// fieldNames 返回 t（或其元素类型）为结构体时可输出的 JSON 字段名，其余类型返回 nil（不校验）
+func (o JSONOptions) fieldNames(t reflect.Type) map[string]bool {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || t == timeType || t.Implements(marshalerType) {
+		return nil
+	}
+	names := make(map[string]bool)
+	o.collectFieldNames(t, names)
+	return names
+}
+
+// collectFieldNames 按 json 标签规则收集字段名（与 normalizeStruct 一致，匿名嵌入的结构体字段提升到外层）
+func (o JSONOptions) collectFieldNames(t reflect.Type, names map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && ft != timeType && !ft.Implements(marshalerType) {
+				o.collectFieldNames(ft, names)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[o.fieldName(name)] = true
+	}
+}
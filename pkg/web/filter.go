@@ -0,0 +1,114 @@
+package web
+
+import (
+	"slices"
+	"strings"
+
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/errors"
+)
+
+// FilterQuery 筛选的查询参数前缀
+const FilterQuery = "filter"
+
+// ParseFilter 解析筛选查询参数，格式：
+//
+//	filter[status]=1                      等于（省略操作符时为 eq）
+//	filter[created_at][gte]=2026-01-01    大于等于（还有 lte）
+//	filter[title][like]=go                包含
+//	filter[status][in]=0,1                在列表中
+//
+// 字段必须在 fields 白名单内，且操作符在该字段允许的范围内，值按字段的 Parse 转换，
+// 否则返回 errors.ErrInvalidParams；格式错误的 filter 参数（如 filter[name）同样返回错误；多个条件之间为 AND
+func ParseFilter(c *Context, fields map[string]database.FilterField) (database.FilterSet, error) {
+	var filters database.FilterSet
+	for key, values := range c.Request.URL.Query() {
+		name, op, ok, err := parseFilterKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		field, exists := fields[name]
+		if !exists {
+			return nil, errors.Wrapf(errors.ErrInvalidParams, "invalid filter field: %s", name)
+		}
+		if !slices.Contains(field.Ops, op) {
+			return nil, errors.Wrapf(errors.ErrInvalidParams, "invalid filter operator: %s[%s]", name, op)
+		}
+
+		for _, raw := range values {
+			value, err := parseFilterValue(field, op, raw)
+			if err != nil {
+				return nil, errors.Wrapf(errors.ErrInvalidParams, "invalid filter value: %s[%s]=%s", name, op, raw)
+			}
+			filters = append(filters, database.Filter{Column: field.Column, Op: op, Value: value})
+		}
+	}
+
+	// map 遍历无序，按列名排序使生成的 SQL 稳定
+	slices.SortStableFunc(filters, func(a, b database.Filter) int {
+		return strings.Compare(a.Column, b.Column)
+	})
+	return filters, nil
+}
+
+// parseFilterKey 解析 filter[name] 或 filter[name][op]
+// 不是筛选参数时 ok 为 false；以 filter 开头但格式错误（如 filter[name、filter[]、filter[name][]）时
+// 返回 errors.ErrInvalidParams，避免拼写错误的条件被静默忽略而返回未筛选的数据
+func parseFilterKey(key string) (name string, op database.FilterOp, ok bool, err error) {
+	if key != FilterQuery && !strings.HasPrefix(key, FilterQuery+"[") {
+		return "", "", false, nil
+	}
+	malformed := errors.Wrapf(errors.ErrInvalidParams, "malformed filter parameter: %s", key)
+
+	rest, _ := strings.CutPrefix(key, FilterQuery+"[")
+	name, rest, found := strings.Cut(rest, "]")
+	if !found || name == "" {
+		return "", "", false, malformed
+	}
+	if rest == "" {
+		return name, database.FilterEq, true, nil
+	}
+	opName, found := strings.CutPrefix(rest, "[")
+	if !found || !strings.HasSuffix(opName, "]") {
+		return "", "", false, malformed
+	}
+	opName = strings.TrimSuffix(opName, "]")
+	if opName == "" || strings.ContainsAny(opName, "[]") {
+		return "", "", false, malformed
+	}
+	return name, database.FilterOp(opName), true, nil
+}
+
+// parseFilterValue 按字段配置转换筛选值，in 操作符按逗号拆分为列表
+func parseFilterValue(field database.FilterField, op database.FilterOp, raw string) (interface{}, error) {
+	convert := func(s string) (interface{}, error) {
+		if field.Parse == nil {
+			return s, nil
+		}
+		return field.Parse(s)
+	}
+
+	if op != database.FilterIn {
+		return convert(strings.TrimSpace(raw))
+	}
+
+	var list []interface{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		v, err := convert(item)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	if len(list) == 0 {
+		return nil, errors.New("empty list")
+	}
+	return list, nil
+}
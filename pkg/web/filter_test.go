@@ -0,0 +1,89 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/errors"
+)
+
+var testFilterFields = map[string]database.FilterField{
+	"id":         {Column: "id", Ops: []database.FilterOp{database.FilterEq, database.FilterIn}, Parse: database.ParseFilterInt},
+	"title":      {Column: "title", Ops: []database.FilterOp{database.FilterEq, database.FilterLike}},
+	"created_at": {Column: "created_at", Ops: []database.FilterOp{database.FilterGte, database.FilterLte}, Parse: database.ParseFilterTime},
+}
+
+// parseFilterQuery 使用 query 调用 ParseFilter
+func parseFilterQuery(t *testing.T, query url.Values) (database.FilterSet, error) {
+	t.Helper()
+
+	c := newTestContext()
+	c.Request = httptest.NewRequest(http.MethodGet, "/?"+query.Encode(), nil)
+	return ParseFilter(c, testFilterFields)
+}
+
+func TestParseFilterOperators(t *testing.T) {
+	day := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		key, value string
+		want       database.Filter
+	}{
+		{"filter[id]", "7", database.Filter{Column: "id", Op: database.FilterEq, Value: int64(7)}},
+		{"filter[id][eq]", "7", database.Filter{Column: "id", Op: database.FilterEq, Value: int64(7)}},
+		{"filter[id][in]", "1, 2,,3", database.Filter{Column: "id", Op: database.FilterIn, Value: []interface{}{int64(1), int64(2), int64(3)}}},
+		{"filter[title][like]", " go ", database.Filter{Column: "title", Op: database.FilterLike, Value: "go"}},
+		{"filter[created_at][gte]", day.Format(time.RFC3339), database.Filter{Column: "created_at", Op: database.FilterGte, Value: day}},
+		{"filter[created_at][lte]", day.Format(time.RFC3339), database.Filter{Column: "created_at", Op: database.FilterLte, Value: day}},
+	}
+	for _, tt := range tests {
+		filters, err := parseFilterQuery(t, url.Values{tt.key: {tt.value}})
+		if err != nil {
+			t.Fatalf("%s=%s: %v", tt.key, tt.value, err)
+		}
+		if len(filters) != 1 || !reflect.DeepEqual(filters[0], tt.want) {
+			t.Fatalf("%s=%s: filters = %+v, want %+v", tt.key, tt.value, filters, tt.want)
+		}
+	}
+}
+
+func TestParseFilterIgnoresOtherParams(t *testing.T) {
+	filters, err := parseFilterQuery(t, url.Values{"sort": {"id"}, "filters": {"x"}, "page": {"1"}})
+	if err != nil || len(filters) != 0 {
+		t.Fatalf("filters = %+v, err = %v, want none", filters, err)
+	}
+}
+
+func TestParseFilterRejects(t *testing.T) {
+	tests := map[string]string{
+		"filter[password]":        "invalid filter field: password",
+		"filter[title][gte]":      "invalid filter operator: title[gte]",
+		"filter[id]":              "invalid filter value",
+		"filter[id][in]":          "invalid filter value",
+		"filter[created_at][lte]": "invalid filter value",
+	}
+	values := map[string]string{"filter[id]": "abc", "filter[id][in]": ",", "filter[created_at][lte]": "yesterday"}
+	for key, want := range tests {
+		_, err := parseFilterQuery(t, url.Values{key: {values[key]}})
+		if !errors.Is(err, errors.ErrInvalidParams) || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: err = %v, want ErrInvalidParams containing %q", key, err, want)
+		}
+	}
+}
+
+func TestParseFilterMalformedKey(t *testing.T) {
+	for _, key := range []string{"filter", "filter[", "filter[name", "filter[]", "filter[]x", "filter[title]x", "filter[title][", "filter[title][]", "filter[title][like", "filter[title][like]]"} {
+		_, err := parseFilterQuery(t, url.Values{key: {"go"}})
+		if !errors.Is(err, errors.ErrInvalidParams) {
+			t.Fatalf("%q: err = %v, want ErrInvalidParams", key, err)
+		}
+		if !strings.Contains(err.Error(), "malformed filter parameter: "+key) {
+			t.Fatalf("%q: err = %v, want the offending key", key, err)
+		}
+	}
+}
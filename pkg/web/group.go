@@ -0,0 +1,52 @@
+package web
+
+import "github.com/gin-gonic/gin"
+
+// RouterGroup 路由组，使用 web.HandlerFunc 注册路由和组中间件
+// 组中间件只作用于该组（及子组）下注册的路由，如只对写接口启用认证
+type RouterGroup struct {
+	group *gin.RouterGroup
+}
+
+// Group 在 parent（*gin.Engine 或 *gin.RouterGroup）下创建路由组，middlewares 按顺序在 Handler 之前执行
+func Group(parent gin.IRouter, path string, middlewares ...HandlerFunc) *RouterGroup {
+	return &RouterGroup{group: parent.Group(path, ToGinHandlers(middlewares...)...)}
+}
+
+// Group 创建子路由组，继承当前组的中间件
+func (g *RouterGroup) Group(path string, middlewares ...HandlerFunc) *RouterGroup {
+	return Group(g.group, path, middlewares...)
+}
+
+// Use 为当前组追加中间件（只影响之后注册的路由）
+func (g *RouterGroup) Use(middlewares ...HandlerFunc) *RouterGroup {
+	g.group.Use(ToGinHandlers(middlewares...)...)
+	return g
+}
+
+// GET 注册 GET 路由，handlers 中最后一个为业务 Handler，之前的为路由级中间件
+// 同时注册 HEAD：执行相同的 Handler，返回相同的响应头（含 Content-Length），不返回响应体
+func (g *RouterGroup) GET(path string, handlers ...HandlerFunc) {
+	g.group.GET(path, ToGinHandlers(handlers...)...)
+	g.group.HEAD(path, ToGinHandlers(append([]HandlerFunc{headHandler()}, handlers...)...)...)
+}
+
+// POST 注册 POST 路由
+func (g *RouterGroup) POST(path string, handlers ...HandlerFunc) {
+	g.group.POST(path, ToGinHandlers(handlers...)...)
+}
+
+// PUT 注册 PUT 路由
+func (g *RouterGroup) PUT(path string, handlers ...HandlerFunc) {
+	g.group.PUT(path, ToGinHandlers(handlers...)...)
+}
+
+// PATCH 注册 PATCH 路由
+func (g *RouterGroup) PATCH(path string, handlers ...HandlerFunc) {
+	g.group.PATCH(path, ToGinHandlers(handlers...)...)
+}
+
+// DELETE 注册 DELETE 路由
+func (g *RouterGroup) DELETE(path string, handlers ...HandlerFunc) {
+	g.group.DELETE(path, ToGinHandlers(handlers...)...)
+}
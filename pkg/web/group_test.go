@@ -0,0 +1,67 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordMiddleware 记录经过的请求路径
+func recordMiddleware(paths *[]string) web.HandlerFunc {
+	return func(ctx *web.Context) {
+		*paths = append(*paths, ctx.Request.Method+" "+ctx.FullPath())
+		ctx.Next()
+	}
+}
+
+func TestGroupMiddlewareOnlyOnProtectedRoutes(t *testing.T) {
+	var groupHits, routeHits []string
+	ok := func(ctx *web.Context) { web.Success(ctx, nil) }
+
+	s := webtest.New(t, func(r *gin.Engine) {
+		items := web.Group(r, "/items")
+		items.GET("", ok)
+		items.GET("/:id", ok)
+
+		writes := items.Group("", recordMiddleware(&groupHits))
+		writes.POST("", ok)
+		writes.DELETE("/:id", recordMiddleware(&routeHits), ok)
+	})
+
+	s.AssertCode(s.Do(http.MethodGet, "/items", nil), http.StatusOK)
+	s.AssertCode(s.Do(http.MethodGet, "/items/1", nil), http.StatusOK)
+	if len(groupHits) != 0 {
+		t.Fatalf("group middleware ran on open routes: %v", groupHits)
+	}
+
+	s.AssertCode(s.Do(http.MethodPost, "/items", nil), http.StatusOK)
+	s.AssertCode(s.Do(http.MethodDelete, "/items/1", nil), http.StatusOK)
+	if len(groupHits) != 2 || groupHits[0] != "POST /items" || groupHits[1] != "DELETE /items/:id" {
+		t.Fatalf("group middleware hits = %v", groupHits)
+	}
+	if len(routeHits) != 1 || routeHits[0] != "DELETE /items/:id" {
+		t.Fatalf("route middleware hits = %v", routeHits)
+	}
+}
+
+func TestGroupMiddlewareAborts(t *testing.T) {
+	deny := func(ctx *web.Context) {
+		web.Unauthorized(ctx, "denied")
+		ctx.Abort()
+	}
+	called := false
+
+	s := webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "/secure", deny)
+		g.POST("", func(ctx *web.Context) { called = true })
+	})
+
+	s.AssertCode(s.Do(http.MethodPost, "/secure", nil), http.StatusUnauthorized)
+	if called {
+		t.Fatal("handler ran after group middleware aborted")
+	}
+}
@@ -1,6 +1,9 @@
 package web
 
 import (
+	"net/http"
+	"strings"
+
 	"go-api-template/internal/constants"
 )
 
@@ -17,17 +20,39 @@ func HealthHandler() HandlerFunc {
 }
 
 // NotFoundHandler 404 错误 Handler
-// 返回统一的 JSON 格式 404 响应
+// 返回统一的 JSON 格式 404 响应，data 中包含请求的方法和路径
 func NotFoundHandler() HandlerFunc {
 	return func(ctx *Context) {
-		NotFound(ctx, constants.MsgInterfaceNotFound)
+		renderJSON(ctx, http.StatusNotFound, Response{
+			Code:    http.StatusNotFound,
+			Message: constants.MsgInterfaceNotFound,
+			Data: Map{
+				"method": ctx.Request.Method,
+				"path":   ctx.Request.URL.Path,
+			},
+		})
 	}
 }
 
-// MethodNotAllowedHandler 405 错误 Handler
-// 返回统一的 JSON 格式 405 响应
+// MethodNotAllowedHandler 405 错误 Handler（需开启 gin.Engine.HandleMethodNotAllowed）
+// 返回统一的 JSON 格式 405 响应，data 中包含请求的方法、路径以及该路径允许的方法
 func MethodNotAllowedHandler() HandlerFunc {
 	return func(ctx *Context) {
-		Error(ctx, 405, 405, constants.MsgMethodNotAllowed)
+		// Gin 在调用此 Handler 前已根据路由树写入 Allow 响应头（RFC 7231 6.5.5），按方法注册顺序列出，
+		// 不含当前请求的方法；通过 web.Group 注册的 GET 路由同时注册了 HEAD，因此包含 HEAD
+		allowed := []string{}
+		if allow := ctx.Writer.Header().Get("Allow"); allow != "" {
+			allowed = strings.Split(allow, ", ")
+		}
+
+		renderJSON(ctx, http.StatusMethodNotAllowed, Response{
+			Code:    http.StatusMethodNotAllowed,
+			Message: constants.MsgMethodNotAllowed,
+			Data: Map{
+				"method":  ctx.Request.Method,
+				"path":    ctx.Request.URL.Path,
+				"allowed": allowed,
+			},
+		})
 	}
 }
@@ -0,0 +1,48 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMethodNotAllowedSetsAllowHeader(t *testing.T) {
+	ok := func(ctx *web.Context) { web.Success(ctx, nil) }
+	s := webtest.New(t, func(r *gin.Engine) {
+		r.HandleMethodNotAllowed = true
+		r.NoMethod(web.ToGinHandler(web.MethodNotAllowedHandler()))
+
+		items := web.Group(r, "/items")
+		items.GET("", ok)
+		items.POST("", ok)
+		items.GET("/:id", ok)
+		items.PUT("/:id", ok)
+		items.DELETE("/:id", ok)
+	})
+
+	tests := []struct {
+		method, path, allow string
+	}{
+		{http.MethodPatch, "/items/1", "GET, HEAD, PUT, DELETE"},
+		{http.MethodDelete, "/items", "GET, HEAD, POST"},
+	}
+	for _, tt := range tests {
+		w := s.Do(tt.method, tt.path, nil)
+		s.AssertCode(w, http.StatusMethodNotAllowed)
+		if got := w.Header().Get("Allow"); got != tt.allow {
+			t.Fatalf("%s %s: Allow = %q, want %q", tt.method, tt.path, got, tt.allow)
+		}
+
+		var data struct {
+			Allowed []string `json:"allowed"`
+		}
+		s.DecodeData(w, &data)
+		if len(data.Allowed) == 0 {
+			t.Fatalf("%s %s: data.allowed empty", tt.method, tt.path)
+		}
+	}
+}
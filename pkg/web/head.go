@@ -0,0 +1,73 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// headHandler HEAD 请求的前置中间件：照常执行 GET 的 Handler，丢弃响应体，
+// 并按丢弃的字节数设置 Content-Length（与 GET 响应的响应头一致）
+func headHandler() HandlerFunc {
+	return func(ctx *Context) {
+		w := &headWriter{ResponseWriter: ctx.Writer, status: http.StatusOK}
+		ctx.Writer = w
+
+		ctx.Next()
+
+		ctx.Writer = w.ResponseWriter
+		if w.size > 0 && w.Header().Get("Content-Length") == "" {
+			w.Header().Set("Content-Length", strconv.Itoa(w.size))
+		}
+		w.ResponseWriter.WriteHeader(w.status)
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// headWriter 只记录状态码和响应体长度，响应头在 Handler 结束后统一发送
+type headWriter struct {
+	gin.ResponseWriter
+	status int
+	size   int
+}
+
+// WriteHeader 记录状态码
+func (w *headWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow 延迟到 Handler 结束后发送
+func (w *headWriter) WriteHeaderNow() {}
+
+// Write 丢弃响应体，只统计长度
+func (w *headWriter) Write(data []byte) (int, error) {
+	w.size += len(data)
+	return len(data), nil
+}
+
+// WriteString 丢弃响应体，只统计长度
+func (w *headWriter) WriteString(s string) (int, error) {
+	w.size += len(s)
+	return len(s), nil
+}
+
+// Flush 响应头尚未发送，忽略
+func (w *headWriter) Flush() {}
+
+// Status 当前状态码
+func (w *headWriter) Status() int {
+	return w.status
+}
+
+// Size 已丢弃的响应体长度
+func (w *headWriter) Size() int {
+	return w.size
+}
+
+// Written Handler 执行期间视为未发送
+func (w *headWriter) Written() bool {
+	return false
+}
@@ -0,0 +1,122 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"go-api-template/internal/constants"
+)
+
+// HealthStatus 健康检查状态
+type HealthStatus string
+
+const (
+	HealthUp      HealthStatus = "up"      // 正常
+	HealthTimeout HealthStatus = "timeout" // 检查超时
+	HealthDown    HealthStatus = "down"    // 检查失败
+)
+
+// severity 状态严重程度，用于取所有检查中最差的状态作为整体状态
+func (s HealthStatus) severity() int {
+	switch s {
+	case HealthUp:
+		return 0
+	case HealthTimeout:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// HealthCheck 依赖健康检查（如数据库、Redis）
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthResult 单个检查的结果
+type HealthResult struct {
+	Name     string       `json:"name"`
+	Status   HealthStatus `json:"status"`
+	Error    string       `json:"error,omitempty"`
+	Duration int64        `json:"duration_ms"`
+}
+
+// ReadinessHandler 就绪检查 Handler
+// 所有检查并行执行，每个检查最多等待 timeout，超时的检查报告为 timeout 而不阻塞整个响应
+// 整体状态取所有检查中最差的状态：全部正常返回 200，否则返回 503
+func ReadinessHandler(timeout time.Duration, checks ...HealthCheck) HandlerFunc {
+	return func(ctx *Context) {
+		results := RunHealthChecks(ctx.Request.Context(), timeout, checks...)
+
+		status := HealthUp
+		for _, r := range results {
+			if r.Status.severity() > status.severity() {
+				status = r.Status
+			}
+		}
+
+		data := Map{
+			"status": status,
+			"checks": results,
+		}
+		if status != HealthUp {
+			renderJSON(ctx, http.StatusServiceUnavailable, Response{
+				Code:    http.StatusServiceUnavailable,
+				Message: constants.MsgServiceUnavailable,
+				Data:    data,
+			})
+			return
+		}
+		Success(ctx, data)
+	}
+}
+
+// RunHealthChecks 并行执行健康检查，结果顺序与 checks 一致
+func RunHealthChecks(ctx context.Context, timeout time.Duration, checks ...HealthCheck) []HealthResult {
+	results := make([]HealthResult, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runHealthCheck(ctx, timeout, check)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// runHealthCheck 在超时控制下执行单个检查
+// 检查函数不响应 ctx 取消时，不再等待其返回
+func runHealthCheck(ctx context.Context, timeout time.Duration, check HealthCheck) HealthResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Check(ctx)
+	}()
+
+	result := HealthResult{Name: check.Name, Status: HealthUp}
+	select {
+	case err := <-done:
+		if err != nil {
+			result.Status = HealthDown
+			result.Error = err.Error()
+			if ctx.Err() != nil {
+				result.Status = HealthTimeout
+			}
+		}
+	case <-ctx.Done():
+		result.Status = HealthTimeout
+		result.Error = ctx.Err().Error()
+	}
+	result.Duration = time.Since(start).Milliseconds()
+	return result
+}
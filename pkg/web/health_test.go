@@ -0,0 +1,96 @@
+package web_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+type readiness struct {
+	Status web.HealthStatus   `json:"status"`
+	Checks []web.HealthResult `json:"checks"`
+}
+
+func newReadinessServer(t *testing.T, timeout time.Duration, checks ...web.HealthCheck) *webtest.Server {
+	return webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").GET("/ready", web.ReadinessHandler(timeout, checks...))
+	})
+}
+
+func up(context.Context) error { return nil }
+
+func TestReadinessSlowCheckTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	s := newReadinessServer(t, 50*time.Millisecond,
+		web.HealthCheck{Name: "database", Check: up},
+		// 不响应 ctx 取消的检查也不能阻塞响应
+		web.HealthCheck{Name: "stuck", Check: func(context.Context) error { <-release; return nil }},
+		web.HealthCheck{Name: "slow", Check: func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() }},
+	)
+
+	start := time.Now()
+	w := s.Do(http.MethodGet, "/ready", nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("readiness took %v, want about the 50ms timeout", elapsed)
+	}
+
+	s.AssertCode(w, http.StatusServiceUnavailable)
+	var got readiness
+	s.DecodeData(w, &got)
+	if got.Status != web.HealthTimeout {
+		t.Fatalf("status = %s, want timeout", got.Status)
+	}
+	want := []web.HealthStatus{web.HealthUp, web.HealthTimeout, web.HealthTimeout}
+	for i, r := range got.Checks {
+		if r.Status != want[i] {
+			t.Fatalf("check %s = %s, want %s", r.Name, r.Status, want[i])
+		}
+	}
+}
+
+func TestReadinessWorstStatus(t *testing.T) {
+	down := func(context.Context) error { return errors.New("connection refused") }
+	slow := func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() }
+
+	tests := []struct {
+		name   string
+		checks []web.HealthCheck
+		code   int
+		want   web.HealthStatus
+	}{
+		{"all up", []web.HealthCheck{{Name: "a", Check: up}, {Name: "b", Check: up}}, http.StatusOK, web.HealthUp},
+		{"timeout", []web.HealthCheck{{Name: "a", Check: up}, {Name: "b", Check: slow}}, http.StatusServiceUnavailable, web.HealthTimeout},
+		{"down beats timeout", []web.HealthCheck{{Name: "a", Check: down}, {Name: "b", Check: slow}}, http.StatusServiceUnavailable, web.HealthDown},
+		{"no checks", nil, http.StatusOK, web.HealthUp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newReadinessServer(t, 20*time.Millisecond, tt.checks...)
+			w := s.Do(http.MethodGet, "/ready", nil)
+			s.AssertCode(w, tt.code)
+			var got readiness
+			s.DecodeData(w, &got)
+			if got.Status != tt.want {
+				t.Fatalf("status = %s, want %s", got.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadinessDownReportsError(t *testing.T) {
+	results := web.RunHealthChecks(context.Background(), time.Second,
+		web.HealthCheck{Name: "redis", Check: func(context.Context) error { return errors.New("connection refused") }},
+	)
+	if len(results) != 1 || results[0].Status != web.HealthDown || results[0].Error != "connection refused" {
+		t.Fatalf("results = %+v", results)
+	}
+}
@@ -0,0 +1,282 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 时间格式预设值
+const (
+	TimeFormatRFC3339   = ""           // 默认：RFC3339（与 encoding/json 一致）
+	TimeFormatUnixMilli = "unix_milli" // 毫秒时间戳（数字）
+)
+
+// 字段命名风格
+const (
+	FieldNamingAsIs  = ""      // 默认：与 json 标签一致（项目中为 snake_case）
+	FieldNamingCamel = "camel" // 转换为 camelCase，如 created_at -> createdAt
+)
+
+// JSONOptions 响应 JSON 编码选项
+type JSONOptions struct {
+	// Int64AsString 将 64 位整数字段（int64/uint64 以及 int/uint/uintptr，如 BaseModel.ID）编码为字符串
+	// 避免 JS 客户端解析大整数（如雪花 ID）时丢失精度
+	Int64AsString bool
+	// TimeFormat 时间格式：空为 RFC3339，unix_milli 为毫秒时间戳，其余视为 Go 时间布局
+	TimeFormat string
+	// FieldNaming 字段命名风格：空为 json 标签原样，camel 将结构体字段名和 map 的 key 转换为 camelCase
+	// 只影响响应，请求体仍按 json 标签解析
+	FieldNaming string
+}
+
+// jsonOptions 全局响应编码选项（启动时通过 SetJSONOptions 设置）
+var jsonOptions JSONOptions
+
+// SetJSONOptions 设置全局响应编码选项，返回之前的选项（便于测试结束后恢复）
+// 应在路由注册前调用
+func SetJSONOptions(opts JSONOptions) JSONOptions {
+	prev := jsonOptions
+	jsonOptions = opts
+	return prev
+}
+
+// renderJSON 使用全局编码选项输出 JSON 响应
+func renderJSON(c *Context, httpStatus int, obj interface{}) {
+	c.JSON(httpStatus, encodable(obj))
+}
+
+// encodable 按全局编码选项转换待编码的值（未开启任何选项时原样返回）
+func encodable(obj interface{}) interface{} {
+	if !jsonOptions.Int64AsString && jsonOptions.TimeFormat == TimeFormatRFC3339 && jsonOptions.FieldNaming == FieldNamingAsIs {
+		return obj
+	}
+	// 统一响应结构只转换 data 和 meta，code 始终为数字
+	if resp, ok := obj.(Response); ok {
+		resp.Data = jsonOptions.normalize(reflect.ValueOf(resp.Data))
+		resp.Meta = jsonOptions.normalize(reflect.ValueOf(resp.Meta))
+		return resp
+	}
+	return jsonOptions.normalize(reflect.ValueOf(obj))
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// timeConverter 可转换为 time.Time 的自定义时间类型（如 model.Time），同样按 TimeFormat 输出
+type timeConverter interface {
+	Time() time.Time
+}
+
+// normalize 按编码选项把任意值转换为可直接编码的结构
+// 结构体字段遵循 json 标签（名称、omitempty、"-"、匿名嵌入），并保持字段顺序
+func (o JSONOptions) normalize(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Type() == timeType {
+		return o.formatTime(v.Interface().(time.Time))
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return o.normalize(v.Elem())
+	}
+
+	if tc, ok := v.Interface().(timeConverter); ok {
+		return o.formatTime(tc.Time())
+	}
+
+	// 自定义序列化的类型交给 encoding/json 处理
+	if v.Type().Implements(marshalerType) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64:
+		if o.Int64AsString {
+			return strconv.FormatInt(v.Int(), 10)
+		}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		if o.Int64AsString {
+			return strconv.FormatUint(v.Uint(), 10)
+		}
+	case reflect.Struct:
+		return o.normalizeStruct(v)
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[o.fieldName(iter.Key().String())] = o.normalize(iter.Value())
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		// []byte 保持 base64 编码
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = o.normalize(v.Index(i))
+		}
+		return list
+	}
+
+	return v.Interface()
+}
+
+// normalizeStruct 转换结构体，保留字段顺序
+func (o JSONOptions) normalizeStruct(v reflect.Value) orderedObject {
+	var obj orderedObject
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := v.Field(i)
+
+		// 匿名嵌入且无名称的结构体：字段提升到外层
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				ft, fv = ft.Elem(), fv.Elem()
+			}
+			if ft.Kind() == reflect.Struct && ft != timeType && !ft.Implements(marshalerType) {
+				obj = append(obj, o.normalizeStruct(fv)...)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && isEmptyValue(fv) {
+			continue
+		}
+		obj = append(obj, orderedField{key: o.fieldName(name), value: o.normalize(fv)})
+	}
+	return obj
+}
+
+// fieldName 按命名风格转换字段名
+func (o JSONOptions) fieldName(name string) string {
+	if o.FieldNaming == FieldNamingCamel {
+		return toCamel(name)
+	}
+	return name
+}
+
+// toCamel snake_case 转 camelCase，不含下划线时原样返回
+// 开头的下划线保留，连续下划线视为一个
+func toCamel(name string) string {
+	if !strings.Contains(strings.TrimLeft(name, "_"), "_") {
+		return name
+	}
+
+	var b strings.Builder
+	b.Grow(len(name))
+	i := 0
+	for i < len(name) && name[i] == '_' {
+		b.WriteByte('_')
+		i++
+	}
+	upper := false
+	for ; i < len(name); i++ {
+		c := name[i]
+		if c == '_' {
+			upper = b.Len() > 0
+			continue
+		}
+		if upper && c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		upper = false
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// formatTime 按配置格式化时间
+func (o JSONOptions) formatTime(t time.Time) interface{} {
+	switch o.TimeFormat {
+	case TimeFormatRFC3339:
+		return t
+	case TimeFormatUnixMilli:
+		return t.UnixMilli()
+	default:
+		return t.Format(o.TimeFormat)
+	}
+}
+
+// isEmptyValue 与 encoding/json 的 omitempty 判定保持一致
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// orderedField 有序对象的单个字段
+type orderedField struct {
+	key   string
+	value interface{}
+}
+
+// orderedObject 保持字段顺序的 JSON 对象
+type orderedObject []orderedField
+
+// MarshalJSON 按字段顺序编码
+func (obj orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range obj {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
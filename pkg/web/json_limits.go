@@ -0,0 +1,152 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"go-api-template/pkg/errors"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// JSONLimits 请求体 JSON 的大小和结构限制，在解码到结构体之前检查
+// 防止超大或深度嵌套的请求体在解码时消耗大量内存、CPU / 栈空间
+type JSONLimits struct {
+	// MaxBytes 请求体大小上限（字节），超出时返回 errors.ErrBodyTooLarge（413），0 表示不限制
+	MaxBytes int64
+	// MaxDepth 最大嵌套深度（对象和数组各算一层），0 表示不限制
+	MaxDepth int
+	// RejectDuplicateKeys 拒绝同一对象中的重复 key（encoding/json 默认静默取最后一个，容易被用来绕过前置检查）
+	RejectDuplicateKeys bool
+}
+
+// jsonLimits 全局请求体 JSON 限制（启动时通过 SetJSONLimits 设置）
+var jsonLimits JSONLimits
+
+// SetJSONLimits 设置全局请求体 JSON 限制，返回之前的设置（便于测试结束后恢复）
+// 应在路由注册前调用
+func SetJSONLimits(limits JSONLimits) JSONLimits {
+	prev := jsonLimits
+	jsonLimits = limits
+	return prev
+}
+
+// bindJSON 解码 JSON 请求体（同 decodeJSON）并按 binding 标签校验，Bind / MustBindJSON 使用
+func bindJSON(c *Context, obj interface{}) error {
+	if err := decodeJSON(c, obj); err != nil {
+		return err
+	}
+	return Validate(obj)
+}
+
+// decodeJSON 读取请求体（见 readJSONBody）后按 Gin 的解码选项解码到 obj
+// 请求体为空时返回 io.EOF（与 Gin 一致）
+func decodeJSON(c *Context, obj interface{}) error {
+	data, err := readJSONBody(c)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if binding.EnableDecoderUseNumber {
+		decoder.UseNumber()
+	}
+	if binding.EnableDecoderDisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(obj)
+}
+
+// readJSONBody 读取 JSON 请求体并检查 JSONLimits，所有一次性读取 JSON 请求体的入口（Bind、BindAll、MustBindJSON、BindPatch）共用
+// 最多读取 MaxBytes 字节，超出时返回 errors.ErrBodyTooLarge；结构不符合限制时返回错误（对应 400）
+func readJSONBody(c *Context) ([]byte, error) {
+	if c.Request.Body == nil {
+		return nil, nil
+	}
+	body := io.Reader(c.Request.Body)
+	if jsonLimits.MaxBytes > 0 {
+		body = http.MaxBytesReader(c.Writer, c.Request.Body, jsonLimits.MaxBytes)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, errors.Wrapf(errors.ErrBodyTooLarge, "request body exceeds %d bytes", maxBytesErr.Limit)
+		}
+		return nil, errors.Wrap(err, "read request body failed")
+	}
+	if err := checkJSON(data, jsonLimits, 0); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// jsonFrame checkJSON 中正在解析的对象或数组
+type jsonFrame struct {
+	object    bool
+	expectKey bool                // 对象中下一个字符串 token 是 key
+	keys      map[string]struct{} // 对象中已出现的 key（RejectDuplicateKeys 时记录）
+}
+
+// checkJSON 按 token 扫描 JSON，检查嵌套深度和重复 key，不构造任何值
+// depth 为 data 所在的嵌套深度（完整请求体为 0，JSON 数组中的元素为 1）；语法错误不在这里处理，留给后续解码返回
+func checkJSON(data []byte, limits JSONLimits, depth int) error {
+	if limits.MaxDepth <= 0 && !limits.RejectDuplicateKeys {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // 数字按原文返回，不做浮点转换
+	var stack []*jsonFrame
+
+	// valueDone 一个值结束后，所在对象的下一个 token 是 key
+	valueDone := func() {
+		if n := len(stack); n > 0 && stack[n-1].object {
+			stack[n-1].expectKey = true
+		}
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil // io.EOF 或语法错误
+		}
+
+		switch v := tok.(type) {
+		case json.Delim:
+			switch v {
+			case '{', '[':
+				if limits.MaxDepth > 0 && depth+len(stack) >= limits.MaxDepth {
+					return errors.Newf("JSON nesting depth exceeds %d", limits.MaxDepth)
+				}
+				frame := &jsonFrame{object: v == '{', expectKey: v == '{'}
+				if frame.object && limits.RejectDuplicateKeys {
+					frame.keys = make(map[string]struct{})
+				}
+				stack = append(stack, frame)
+			default: // '}' 或 ']'
+				stack = stack[:len(stack)-1]
+				valueDone()
+			}
+		case string:
+			top := len(stack) - 1
+			if top < 0 || !stack[top].object || !stack[top].expectKey {
+				valueDone()
+				continue
+			}
+			stack[top].expectKey = false
+			if stack[top].keys == nil {
+				continue
+			}
+			if _, ok := stack[top].keys[v]; ok {
+				return errors.Newf("duplicate JSON key %q", v)
+			}
+			stack[top].keys[v] = struct{}{}
+		default:
+			valueDone()
+		}
+	}
+}
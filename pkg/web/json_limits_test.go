@@ -0,0 +1,170 @@
+package web_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+type limitsRequest struct {
+	ID    uint        `uri:"id" json:"-" form:"-"`
+	Title string      `json:"title" form:"title"`
+	Meta  interface{} `json:"meta" form:"-"`
+}
+
+// jsonEntryPoints 所有读取 JSON 请求体的入口，路径 -> 请求体（DecodeJSONArray 需要数组）
+var jsonEntryPoints = []string{"/bind", "/bind-json", "/bind-all/1", "/patch", "/array"}
+
+// newLimitsServer 注册每个 JSON 请求体入口，并设置 limits（测试结束时恢复）
+func newLimitsServer(t *testing.T, limits web.JSONLimits) *webtest.Server {
+	t.Helper()
+
+	prev := web.SetJSONLimits(limits)
+	t.Cleanup(func() { web.SetJSONLimits(prev) })
+
+	return webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "")
+		g.POST("/bind", func(ctx *web.Context) {
+			var req limitsRequest
+			if ctx.MustBind(&req) {
+				web.Success(ctx, nil)
+			}
+		})
+		g.POST("/bind-json", func(ctx *web.Context) {
+			var req limitsRequest
+			if ctx.MustBindJSON(&req) {
+				web.Success(ctx, nil)
+			}
+		})
+		g.POST("/bind-all/:id", func(ctx *web.Context) {
+			var req limitsRequest
+			if ctx.MustBindAll(&req) {
+				web.Success(ctx, nil)
+			}
+		})
+		g.POST("/patch", func(ctx *web.Context) {
+			var patch web.Patch
+			if err := web.BindPatch(ctx, &patch); err != nil {
+				web.InvalidRequest(ctx, err)
+				return
+			}
+			web.Success(ctx, nil)
+		})
+		g.POST("/array", func(ctx *web.Context) {
+			err := web.DecodeJSONArray(ctx, 0, func(int, json.RawMessage) error { return nil })
+			if err != nil {
+				web.InvalidRequest(ctx, err)
+				return
+			}
+			web.Success(ctx, nil)
+		})
+	})
+}
+
+// bodyFor 数组入口把对象包在数组中
+func bodyFor(path, object string) json.RawMessage {
+	if path == "/array" {
+		return json.RawMessage("[" + object + "]")
+	}
+	return json.RawMessage(object)
+}
+
+func TestJSONLimitsRejectDeepNesting(t *testing.T) {
+	// 对象 4 层（数组入口 5 层）
+	const nested = `{"title":"x","meta":{"a":{"b":{"c":1}}}}`
+
+	s := newLimitsServer(t, web.JSONLimits{MaxDepth: 4})
+	for _, path := range jsonEntryPoints {
+		w := s.Do(http.MethodPost, path, bodyFor(path, nested))
+		if path == "/array" {
+			s.AssertCode(w, http.StatusBadRequest)
+			if !strings.Contains(s.Decode(w).Message, "JSON nesting depth exceeds 4") {
+				t.Fatalf("%s: message = %q", path, s.Decode(w).Message)
+			}
+			continue
+		}
+		s.AssertCode(w, http.StatusOK)
+	}
+
+	s = newLimitsServer(t, web.JSONLimits{MaxDepth: 3})
+	for _, path := range jsonEntryPoints {
+		w := s.Do(http.MethodPost, path, bodyFor(path, nested))
+		s.AssertCode(w, http.StatusBadRequest)
+		if msg := s.Decode(w).Message; !strings.Contains(msg, "JSON nesting depth exceeds 3") {
+			t.Fatalf("%s: message = %q", path, msg)
+		}
+	}
+}
+
+func TestJSONLimitsRejectPathologicalNesting(t *testing.T) {
+	s := newLimitsServer(t, web.JSONLimits{MaxDepth: 32})
+	deep := `{"meta":` + strings.Repeat("[", 100000) + strings.Repeat("]", 100000) + `}`
+
+	for _, path := range jsonEntryPoints {
+		w := s.Do(http.MethodPost, path, string(bodyFor(path, deep))) // json.RawMessage 编码时会检查深度，原样发送
+		s.AssertCode(w, http.StatusBadRequest)
+	}
+}
+
+func TestJSONLimitsRejectDuplicateKeys(t *testing.T) {
+	s := newLimitsServer(t, web.JSONLimits{RejectDuplicateKeys: true})
+
+	for _, path := range jsonEntryPoints {
+		w := s.Do(http.MethodPost, path, bodyFor(path, `{"title":"a","meta":{"x":1},"title":"b"}`))
+		s.AssertCode(w, http.StatusBadRequest)
+		if msg := s.Decode(w).Message; !strings.Contains(msg, `duplicate JSON key "title"`) {
+			t.Fatalf("%s: message = %q", path, msg)
+		}
+
+		// 不同对象中的同名 key 不算重复
+		w = s.Do(http.MethodPost, path, bodyFor(path, `{"title":"a","meta":{"title":"b"}}`))
+		s.AssertCode(w, http.StatusOK)
+	}
+
+	// 关闭时与 encoding/json 一致取最后一个
+	s = newLimitsServer(t, web.JSONLimits{})
+	s.AssertCode(s.Do(http.MethodPost, "/bind", json.RawMessage(`{"title":"a","title":"b"}`)), http.StatusOK)
+}
+
+func TestJSONLimitsBodyTooLarge(t *testing.T) {
+	s := newLimitsServer(t, web.JSONLimits{MaxBytes: 64})
+	large := `{"title":"` + strings.Repeat("x", 100) + `"}`
+
+	for _, path := range jsonEntryPoints {
+		w := s.Do(http.MethodPost, path, bodyFor(path, large))
+		s.AssertCode(w, http.StatusRequestEntityTooLarge)
+		s.AssertMessage(w, constants.MsgRequestEntityTooLarge)
+
+		s.AssertCode(s.Do(http.MethodPost, path, bodyFor(path, `{"title":"small"}`)), http.StatusOK)
+	}
+}
+
+func TestDecodeJSONArrayExplicitLimit(t *testing.T) {
+	prev := web.SetJSONLimits(web.JSONLimits{MaxBytes: 16})
+	t.Cleanup(func() { web.SetJSONLimits(prev) })
+
+	// 显式传入的上限优先于 JSONLimits.MaxBytes（如批量导入）
+	var got error
+	s := webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").POST("/import", func(ctx *web.Context) {
+			got = web.DecodeJSONArray(ctx, 1024, func(int, json.RawMessage) error { return nil })
+			web.Success(ctx, nil)
+		})
+	})
+	s.Do(http.MethodPost, "/import", json.RawMessage(`[{"title":"longer than sixteen bytes"}]`))
+	if got != nil {
+		t.Fatalf("explicit limit: %v", got)
+	}
+	s.Do(http.MethodPost, "/import", json.RawMessage(`[{"title":"`+strings.Repeat("x", 2048)+`"}]`))
+	if !errors.Is(got, errors.ErrBodyTooLarge) {
+		t.Fatalf("err = %v, want ErrBodyTooLarge", got)
+	}
+}
@@ -0,0 +1,163 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-api-template/pkg/errors"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// schemaPrinter 未单独翻译的 JSON Schema 规则使用库自带的英文提示
+var schemaPrinter = message.NewPrinter(language.English)
+
+// ValidateJSONSchema 按 JSON Schema 文件校验原始请求体的中间件
+// 用于以 JSON Schema 定义请求契约、或结构不固定（无法用结构体 binding 标签描述）的接口；
+// 请求体先按 JSONLimits 检查，不符合 Schema 时返回 400，逐字段错误放在 data.errors 中（与 InvalidRequest 一致），
+// 校验通过后请求体原样交给后续 Handler，仍可正常 Bind。
+// Schema 在注册路由时加载并编译，文件不存在或 Schema 无效时 panic
+func ValidateJSONSchema(schemaPath string) HandlerFunc {
+	schema, err := jsonschema.NewCompiler().Compile(schemaPath)
+	if err != nil {
+		panic(errors.Wrapf(err, "compile json schema %s failed", schemaPath))
+	}
+
+	return func(ctx *Context) {
+		// 与 Bind 相同检查大小、嵌套深度和重复 key，读出的请求体放回，后续 Handler 仍可绑定
+		data, err := readJSONBody(ctx)
+		if err != nil {
+			InvalidRequest(ctx, err)
+			ctx.Abort()
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(data))
+
+		if len(bytes.TrimSpace(data)) == 0 {
+			BadRequest(ctx, "invalid request: empty body")
+			ctx.Abort()
+			return
+		}
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+		if err != nil {
+			BadRequest(ctx, "invalid request: "+err.Error())
+			ctx.Abort()
+			return
+		}
+
+		if err := schema.Validate(doc); err != nil {
+			var verr *jsonschema.ValidationError
+			if !errors.As(err, &verr) {
+				BadRequest(ctx, "invalid request: "+err.Error())
+				ctx.Abort()
+				return
+			}
+			invalidFields(ctx, schemaFieldErrors(verr))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// schemaFieldErrors 将 Schema 校验错误展开为逐字段的错误（只取最底层的错误，按字段路径排序）
+func schemaFieldErrors(err *jsonschema.ValidationError) []FieldError {
+	var fields []FieldError
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, cause := range e.Causes {
+				walk(cause)
+			}
+			return
+		}
+
+		path := schemaFieldPath(e.InstanceLocation)
+		switch k := e.ErrorKind.(type) {
+		case *kind.Required:
+			// 缺少的字段逐个报告，字段路径指向缺少的字段本身
+			for _, name := range k.Missing {
+				field := joinFieldPath(path, name)
+				fields = append(fields, FieldError{Field: field, Rule: "required", Message: field + " 不能为空"})
+			}
+		case *kind.AdditionalProperties:
+			for _, name := range k.Properties {
+				field := joinFieldPath(path, name)
+				fields = append(fields, FieldError{Field: field, Rule: "additionalProperties", Message: field + " 是未定义的字段"})
+			}
+		default:
+			keywords := e.ErrorKind.KeywordPath()
+			rule := ""
+			if len(keywords) > 0 {
+				rule = keywords[len(keywords)-1]
+			}
+			fields = append(fields, FieldError{Field: path, Rule: rule, Message: schemaMessage(e.ErrorKind, path)})
+		}
+	}
+	walk(err)
+
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	return fields
+}
+
+// schemaFieldPath 将 JSON Pointer 各段转换为与 TranslateValidationErrors 一致的字段路径，如 items[2].status
+// 纯数字的段视为数组下标
+func schemaFieldPath(location []string) string {
+	path := ""
+	for _, token := range location {
+		if _, err := strconv.Atoi(token); err == nil {
+			path += "[" + token + "]"
+			continue
+		}
+		path = joinFieldPath(path, token)
+	}
+	return path
+}
+
+// schemaMessage 生成单个 Schema 规则的友好提示，field 为字段路径（为空表示整个请求体）
+func schemaMessage(k jsonschema.ErrorKind, field string) string {
+	if field == "" {
+		field = "请求体"
+	}
+
+	switch k := k.(type) {
+	case *kind.Type:
+		return fmt.Sprintf("%s 类型必须为 %s", field, strings.Join(k.Want, " 或 "))
+	case *kind.MinLength:
+		return fmt.Sprintf("%s 长度不能少于 %d 个字符", field, k.Want)
+	case *kind.MaxLength:
+		return fmt.Sprintf("%s 长度不能超过 %d 个字符", field, k.Want)
+	case *kind.MinItems:
+		return fmt.Sprintf("%s 元素个数不能少于 %d 个", field, k.Want)
+	case *kind.MaxItems:
+		return fmt.Sprintf("%s 元素个数不能超过 %d 个", field, k.Want)
+	case *kind.Minimum:
+		return fmt.Sprintf("%s 不能小于 %s", field, k.Want.RatString())
+	case *kind.Maximum:
+		return fmt.Sprintf("%s 不能大于 %s", field, k.Want.RatString())
+	case *kind.ExclusiveMinimum:
+		return fmt.Sprintf("%s 必须大于 %s", field, k.Want.RatString())
+	case *kind.ExclusiveMaximum:
+		return fmt.Sprintf("%s 必须小于 %s", field, k.Want.RatString())
+	case *kind.Enum:
+		want := make([]string, len(k.Want))
+		for i, v := range k.Want {
+			want[i] = fmt.Sprint(v)
+		}
+		return fmt.Sprintf("%s 必须是以下值之一：%s", field, strings.Join(want, ", "))
+	case *kind.Pattern:
+		return fmt.Sprintf("%s 格式不正确", field)
+	case *kind.Format:
+		return fmt.Sprintf("%s 必须是有效的 %s", field, k.Want)
+	default:
+		return fmt.Sprintf("%s %s", field, k.LocalizedString(schemaPrinter))
+	}
+}
@@ -0,0 +1,115 @@
+package web_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newSchemaServer(t *testing.T) (*webtest.Server, *string) {
+	var received string
+	s := webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").POST("/orders", web.ValidateJSONSchema("testdata/schema/order.json"), func(ctx *web.Context) {
+			data, _ := io.ReadAll(ctx.Request.Body)
+			received = string(data)
+			web.Success(ctx, nil)
+		})
+	})
+	return s, &received
+}
+
+func TestValidateJSONSchemaPasses(t *testing.T) {
+	s, received := newSchemaServer(t)
+
+	body := `{"customer":"alice","channel":"web","items":[{"sku":"a","qty":2}]}`
+	s.AssertCode(s.Do(http.MethodPost, "/orders", body), http.StatusOK)
+	// 校验通过后 Handler 仍能读到完整的请求体
+	if *received != body {
+		t.Fatalf("handler body = %q, want %q", *received, body)
+	}
+}
+
+func TestValidateJSONSchemaFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		fields []web.FieldError
+	}{
+		{
+			name: "missing and unknown",
+			body: `{"extra":1}`,
+			fields: []web.FieldError{
+				{Field: "customer", Rule: "required", Message: "customer 不能为空"},
+				{Field: "extra", Rule: "additionalProperties", Message: "extra 是未定义的字段"},
+				{Field: "items", Rule: "required", Message: "items 不能为空"},
+			},
+		},
+		{
+			name: "nested array element",
+			body: `{"customer":"alice","items":[{"sku":"a","qty":1},{"sku":"b","qty":0},{"qty":"3"}]}`,
+			fields: []web.FieldError{
+				{Field: "items[1].qty", Rule: "minimum", Message: "items[1].qty 不能小于 1"},
+				{Field: "items[2].qty", Rule: "type", Message: "items[2].qty 类型必须为 integer"},
+				{Field: "items[2].sku", Rule: "required", Message: "items[2].sku 不能为空"},
+			},
+		},
+		{
+			name: "enum and length",
+			body: `{"customer":"abcdefghijk","channel":"fax","items":[{"sku":"a","qty":1}]}`,
+			fields: []web.FieldError{
+				{Field: "channel", Rule: "enum", Message: "channel 必须是以下值之一：web, app"},
+				{Field: "customer", Rule: "maxLength", Message: "customer 长度不能超过 10 个字符"},
+			},
+		},
+		{
+			name:   "root type",
+			body:   `[1, 2]`,
+			fields: []web.FieldError{{Field: "", Rule: "type", Message: "请求体 类型必须为 object"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, received := newSchemaServer(t)
+
+			w := s.Do(http.MethodPost, "/orders", tt.body)
+			s.AssertCode(w, http.StatusBadRequest)
+			var data struct {
+				Errors []web.FieldError `json:"errors"`
+			}
+			s.DecodeData(w, &data)
+			if len(data.Errors) != len(tt.fields) {
+				t.Fatalf("errors = %+v, want %+v", data.Errors, tt.fields)
+			}
+			for i, want := range tt.fields {
+				if data.Errors[i] != want {
+					t.Fatalf("errors[%d] = %+v, want %+v", i, data.Errors[i], want)
+				}
+			}
+			if *received != "" {
+				t.Fatal("handler ran for an invalid body")
+			}
+		})
+	}
+}
+
+func TestValidateJSONSchemaMalformedBody(t *testing.T) {
+	for _, body := range []string{"", `{"customer":`} {
+		s, _ := newSchemaServer(t)
+		w := s.Do(http.MethodPost, "/orders", body)
+		s.AssertCode(w, http.StatusBadRequest)
+	}
+}
+
+func TestValidateJSONSchemaMissingFilePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("missing schema did not panic")
+		}
+	}()
+	web.ValidateJSONSchema("testdata/schema/missing.json")
+}
@@ -0,0 +1,149 @@
+package web
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go-api-template/internal/model"
+)
+
+// snowflakeID 超过 2^53 的雪花 ID，JS 的 Number 无法精确表示
+const snowflakeID = 1759237845123456789
+
+type testSnowflakeModel struct {
+	model.BaseModel
+	OwnerID  int64   `json:"owner_id"`
+	ParentID *uint64 `json:"parent_id,omitempty"`
+	Count    int     `json:"count"`
+	Ratio    uint32  `json:"ratio"`
+}
+
+func encodeWith(t *testing.T, opts JSONOptions, v interface{}) map[string]interface{} {
+	t.Helper()
+
+	prev := SetJSONOptions(opts)
+	t.Cleanup(func() { SetJSONOptions(prev) })
+
+	data, err := json.Marshal(encodable(v))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unmarshal %s: %v", data, err)
+	}
+	return out
+}
+
+func TestInt64AsStringSnowflakeID(t *testing.T) {
+	parent := uint64(snowflakeID + 1)
+	v := testSnowflakeModel{
+		BaseModel: model.BaseModel{ID: snowflakeID},
+		OwnerID:   -snowflakeID,
+		ParentID:  &parent,
+		Count:     snowflakeID,
+		Ratio:     7,
+	}
+
+	out := encodeWith(t, JSONOptions{Int64AsString: true}, v)
+	want := map[string]interface{}{
+		"id":        "1759237845123456789", // BaseModel.ID 为 uint
+		"owner_id":  "-1759237845123456789",
+		"parent_id": "1759237845123456790",
+		"count":     "1759237845123456789",
+	}
+	for key, w := range want {
+		if out[key] != w {
+			t.Fatalf("%s = %#v, want %q", key, out[key], w)
+		}
+	}
+	// 32 位及以下的整数不受影响
+	if out["ratio"] != float64(7) {
+		t.Fatalf("ratio = %#v, want 7", out["ratio"])
+	}
+}
+
+func TestInt64AsStringDisabled(t *testing.T) {
+	out := encodeWith(t, JSONOptions{TimeFormat: TimeFormatUnixMilli}, testSnowflakeModel{BaseModel: model.BaseModel{ID: 42}})
+	if out["id"] != float64(42) {
+		t.Fatalf("id = %#v, want number 42", out["id"])
+	}
+}
+
+func TestTimeFormat(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	v := map[string]interface{}{"at": ts}
+
+	if out := encodeWith(t, JSONOptions{TimeFormat: TimeFormatUnixMilli}, v); out["at"] != float64(ts.UnixMilli()) {
+		t.Fatalf("unix_milli: at = %#v", out["at"])
+	}
+	if out := encodeWith(t, JSONOptions{TimeFormat: "2006-01-02 15:04:05"}, v); out["at"] != "2024-05-06 07:08:09" {
+		t.Fatalf("layout: at = %#v", out["at"])
+	}
+}
+
+func TestFieldNamingCamel(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	v := map[string]interface{}{
+		"demo":      testSnowflakeModel{BaseModel: model.BaseModel{ID: 1, CreatedAt: model.Time(ts)}, OwnerID: 2},
+		"page_size": 10,
+	}
+
+	out := encodeWith(t, JSONOptions{FieldNaming: FieldNamingCamel}, v)
+	if _, ok := out["pageSize"]; !ok {
+		t.Fatalf("map key not converted: %v", out)
+	}
+	demo, ok := out["demo"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("demo = %#v", out["demo"])
+	}
+	for _, key := range []string{"createdAt", "updatedAt", "ownerId", "id"} {
+		if _, ok := demo[key]; !ok {
+			t.Fatalf("missing %s in %v", key, demo)
+		}
+	}
+	for _, key := range []string{"created_at", "owner_id"} {
+		if _, ok := demo[key]; ok {
+			t.Fatalf("snake_case key %s still present in %v", key, demo)
+		}
+	}
+}
+
+func TestFieldNamingDefaultSnakeCase(t *testing.T) {
+	out := encodeWith(t, JSONOptions{TimeFormat: TimeFormatUnixMilli}, testSnowflakeModel{OwnerID: 2})
+	if _, ok := out["created_at"]; !ok {
+		t.Fatalf("created_at missing with default naming: %v", out)
+	}
+	if _, ok := out["createdAt"]; ok {
+		t.Fatalf("createdAt present with default naming: %v", out)
+	}
+}
+
+func TestToCamel(t *testing.T) {
+	tests := map[string]string{
+		"created_at":  "createdAt",
+		"id":          "id",
+		"api_key_id":  "apiKeyId",
+		"_private":    "_private",
+		"_meta_data":  "_metaData",
+		"double__sep": "doubleSep",
+		"trailing_":   "trailing",
+		"alreadyCase": "alreadyCase",
+	}
+	for in, want := range tests {
+		if got := toCamel(in); got != want {
+			t.Fatalf("toCamel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestInt64AsStringKeepsEnvelopeCodeNumeric(t *testing.T) {
+	out := encodeWith(t, JSONOptions{Int64AsString: true}, Response{Code: 200, Message: "success", Data: testSnowflakeModel{OwnerID: 7}})
+	if out["code"] != float64(200) {
+		t.Fatalf("code = %#v, want number 200", out["code"])
+	}
+	if data := out["data"].(map[string]interface{}); data["owner_id"] != "7" {
+		t.Fatalf("data.owner_id = %#v, want \"7\"", data["owner_id"])
+	}
+}
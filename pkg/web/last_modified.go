@@ -0,0 +1,51 @@
+package web
+
+import (
+	"net/http"
+	"time"
+)
+
+// LastModified 设置 Last-Modified 响应头并处理 If-Modified-Since 条件请求
+// 资源在 If-Modified-Since 之后没有修改时返回 304（不含响应体）并返回 true，调用方应直接返回：
+//
+//	if web.LastModified(ctx, demo.UpdatedAt.Time()) {
+//		return
+//	}
+//	web.Success(ctx, demo)
+//
+// HTTP 日期只精确到秒，比较前 modTime 截断到秒；modTime 晚于服务器当前时间（数据库与应用服务器时钟偏差）时按当前时间处理。
+// 以下情况忽略 If-Modified-Since，正常返回：非 GET/HEAD 请求、带 If-None-Match（以 ETag 为准）、
+// 日期格式无效、日期晚于服务器当前时间（客户端时钟偏差）、modTime 为零值
+func LastModified(c *Context, modTime time.Time) bool {
+	if modTime.IsZero() || modTime.Unix() == 0 {
+		return false
+	}
+
+	now := time.Now()
+	if modTime.After(now) {
+		modTime = now
+	}
+	modTime = modTime.UTC().Truncate(time.Second)
+	c.Header("Last-Modified", modTime.Format(http.TimeFormat))
+
+	method := c.Request.Method
+	if method != http.MethodGet && method != http.MethodHead {
+		return false
+	}
+	if c.GetHeader("If-None-Match") != "" {
+		return false
+	}
+	ims, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || ims.After(now) {
+		return false
+	}
+	if modTime.After(ims) {
+		return false
+	}
+
+	// 304 不应携带描述响应体的头
+	c.Writer.Header().Del("Content-Type")
+	c.Writer.Header().Del("Content-Length")
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
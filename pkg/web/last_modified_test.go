@@ -0,0 +1,93 @@
+package web_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newLastModifiedServer(t *testing.T, modTime time.Time) *webtest.Server {
+	return webtest.New(t, func(r *gin.Engine) {
+		handler := func(ctx *web.Context) {
+			if web.LastModified(ctx, modTime) {
+				return
+			}
+			web.Success(ctx, web.Map{"ok": true})
+		}
+		g := web.Group(r, "")
+		g.GET("/item", handler)
+		g.PUT("/item", handler)
+	})
+}
+
+func TestLastModifiedNotModified(t *testing.T) {
+	// 带亚秒部分：HTTP 日期只精确到秒
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second).Add(300 * time.Millisecond)
+	s := newLastModifiedServer(t, modTime)
+
+	w := s.Do(http.MethodGet, "/item", nil)
+	s.AssertCode(w, http.StatusOK)
+	lastModified := w.Header().Get("Last-Modified")
+	if want := modTime.UTC().Format(http.TimeFormat); lastModified != want {
+		t.Fatalf("Last-Modified = %q, want %q", lastModified, want)
+	}
+
+	// If-Modified-Since 等于 Last-Modified（UpdatedAt 截断到秒）：304
+	s.Header.Set("If-Modified-Since", lastModified)
+	w = s.Do(http.MethodGet, "/item", nil)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Fatalf("status = %d, body = %q, want 304 without body", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Content-Type") != "" {
+		t.Fatalf("304 has Content-Type %q", w.Header().Get("Content-Type"))
+	}
+
+	// 之后的日期同样 304
+	s.Header.Set("If-Modified-Since", modTime.Add(time.Minute).UTC().Format(http.TimeFormat))
+	if w := s.Do(http.MethodGet, "/item", nil); w.Code != http.StatusNotModified {
+		t.Fatalf("later If-Modified-Since: status = %d, want 304", w.Code)
+	}
+}
+
+func TestLastModifiedModified(t *testing.T) {
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	s := newLastModifiedServer(t, modTime)
+
+	cases := map[string]string{
+		"earlier date":       modTime.Add(-time.Second).UTC().Format(http.TimeFormat),
+		"future client date": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+		"invalid date":       "yesterday",
+	}
+	for name, ims := range cases {
+		s.Header.Set("If-Modified-Since", ims)
+		if w := s.Do(http.MethodGet, "/item", nil); w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", name, w.Code)
+		}
+	}
+
+	// If-None-Match 优先，非 GET/HEAD 忽略
+	s.Header.Set("If-Modified-Since", modTime.UTC().Format(http.TimeFormat))
+	if w := s.Do(http.MethodPut, "/item", nil); w.Code != http.StatusOK {
+		t.Fatalf("PUT: status = %d, want 200", w.Code)
+	}
+	s.Header.Set("If-None-Match", `"abc"`)
+	if w := s.Do(http.MethodGet, "/item", nil); w.Code != http.StatusOK {
+		t.Fatalf("with If-None-Match: status = %d, want 200", w.Code)
+	}
+}
+
+func TestLastModifiedFutureModTimeClamped(t *testing.T) {
+	// 数据库时钟快于应用服务器：按当前时间输出
+	s := newLastModifiedServer(t, time.Now().Add(time.Hour))
+
+	w := s.Do(http.MethodGet, "/item", nil)
+	got, err := http.ParseTime(w.Header().Get("Last-Modified"))
+	if err != nil || got.After(time.Now()) {
+		t.Fatalf("Last-Modified = %v, %v, want not after now", got, err)
+	}
+}
@@ -0,0 +1,86 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-api-template/pkg/database"
+)
+
+// MetaQuery 回显查询条件的查询参数名，?meta=true 时列表响应带 meta
+const MetaQuery = "meta"
+
+// ListMeta 列表接口回显的查询条件：服务端实际解析并应用的筛选、排序、返回字段和分页
+// 用于客户端确认查询参数被按预期理解（如拼错的 filter 值、被忽略的参数）
+type ListMeta struct {
+	Filters []FilterMeta `json:"filters"`
+	Sort    []string     `json:"sort"`             // 如 -created_at（- 前缀为降序）
+	Fields  []string     `json:"fields,omitempty"` // fields 参数指定的返回字段
+	Page    *PageMeta    `json:"page,omitempty"`   // 分页接口才有
+}
+
+// FilterMeta 单个筛选条件，Value 为转换后的值（如时间、整数），in 时为数组
+type FilterMeta struct {
+	Field string            `json:"field"`
+	Op    database.FilterOp `json:"op"`
+	Value interface{}       `json:"value"`
+}
+
+// PageMeta 分页信息
+type PageMeta struct {
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+	Total    int64 `json:"total"`
+}
+
+// NewListMeta 由解析后的筛选、排序条件（见 ParseFilter、ParseSort）和 fields 参数生成回显的查询条件
+func NewListMeta(c *Context, filters database.FilterSet, sort database.Sort) *ListMeta {
+	meta := &ListMeta{
+		Filters: make([]FilterMeta, 0, len(filters)),
+		Sort:    make([]string, 0, len(sort)),
+	}
+	for _, f := range filters {
+		meta.Filters = append(meta.Filters, FilterMeta{Field: f.Column, Op: f.Op, Value: f.Value})
+	}
+	for _, s := range sort {
+		if s.Desc {
+			meta.Sort = append(meta.Sort, "-"+s.Column)
+		} else {
+			meta.Sort = append(meta.Sort, s.Column)
+		}
+	}
+	for _, name := range strings.Split(c.Query(FieldsQuery), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			meta.Fields = append(meta.Fields, name)
+		}
+	}
+	return meta
+}
+
+// WithPage 设置分页信息
+func (m *ListMeta) WithPage(page, pageSize int, total int64) *ListMeta {
+	m.Page = &PageMeta{Page: page, PageSize: pageSize, Total: total}
+	return m
+}
+
+// SuccessList 列表成功响应（200），请求带 ?meta=true 时在 meta 中回显查询条件，否则与 Success 相同
+//
+//	web.SuccessList(ctx, data, web.NewListMeta(ctx, filters, sort))
+func SuccessList(c *Context, data interface{}, meta *ListMeta) {
+	resp := Response{
+		Code:    200,
+		Message: "success",
+		Data:    nonNilSlice(data),
+	}
+	if wantMeta(c) && meta != nil {
+		resp.Meta = meta
+	}
+	renderJSON(c, http.StatusOK, resp)
+}
+
+// wantMeta 请求是否要求回显查询条件（meta 参数为 true、1 等），无法解析时视为不要求
+func wantMeta(c *Context) bool {
+	want, _ := strconv.ParseBool(c.Query(MetaQuery))
+	return want
+}
@@ -0,0 +1,65 @@
+package web_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newListMetaServer(t *testing.T) *webtest.Server {
+	filters := database.FilterSet{
+		{Column: "status", Op: database.FilterIn, Value: []interface{}{0, 1}},
+		{Column: "title", Op: database.FilterLike, Value: "go"},
+	}
+	sort := database.Sort{{Column: "created_at", Desc: true}, {Column: "id"}}
+	return webtest.New(t, func(r *gin.Engine) {
+		r.GET("/items", web.ToGinHandler(func(c *web.Context) {
+			web.SuccessList(c, []string{"a"}, web.NewListMeta(c, filters, sort).WithPage(2, 20, 41))
+		}))
+	})
+}
+
+func TestSuccessListMeta(t *testing.T) {
+	s := newListMetaServer(t)
+
+	env := s.Decode(s.Do(http.MethodGet, "/items?meta=true&fields=id,+title", nil))
+	var meta web.ListMeta
+	if err := json.Unmarshal(env.Meta, &meta); err != nil {
+		t.Fatalf("decode meta %s: %v", env.Meta, err)
+	}
+	want := web.ListMeta{
+		Filters: []web.FilterMeta{
+			{Field: "status", Op: database.FilterIn, Value: []interface{}{float64(0), float64(1)}},
+			{Field: "title", Op: database.FilterLike, Value: "go"},
+		},
+		Sort:   []string{"-created_at", "id"},
+		Fields: []string{"id", "title"},
+		Page:   &web.PageMeta{Page: 2, PageSize: 20, Total: 41},
+	}
+	if !reflect.DeepEqual(meta, want) {
+		t.Fatalf("meta = %+v, want %+v", meta, want)
+	}
+	if string(env.Data) != `["a"]` {
+		t.Fatalf("data = %s, want [\"a\"]", env.Data)
+	}
+}
+
+// 没有 meta 参数或无法解析时与 Success 相同
+func TestSuccessListWithoutMeta(t *testing.T) {
+	s := newListMetaServer(t)
+
+	for _, target := range []string{"/items", "/items?meta=false", "/items?meta=yes"} {
+		w := s.Do(http.MethodGet, target, nil)
+		s.AssertCode(w, http.StatusOK)
+		if body := decodeRaw(t, w.Body.Bytes()); body["meta"] != nil {
+			t.Fatalf("GET %s: meta = %v, want omitted", target, body["meta"])
+		}
+	}
+}
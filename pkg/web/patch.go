@@ -0,0 +1,59 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"go-api-template/pkg/errors"
+)
+
+// Patch 部分更新（PATCH）请求体
+// 保留每个字段的原始 JSON，用于区分三种情况：
+//   - 字段未出现：不修改
+//   - 字段为 null：显式置空（是否允许由业务决定）
+//   - 字段有值：更新为该值（包括空字符串、0 等零值）
+type Patch map[string]json.RawMessage
+
+// BindPatch 将请求体绑定为 Patch
+// 请求体必须是 JSON 对象，空请求体视为 {}；与 Bind 相同按 JSONLimits 检查（超出大小上限时返回 errors.ErrBodyTooLarge）
+func BindPatch(c *Context, patch *Patch) error {
+	body, err := readJSONBody(c)
+	if err != nil {
+		return err
+	}
+
+	raw := make(Patch)
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return errors.Wrap(err, "request body must be a JSON object")
+		}
+	}
+
+	*patch = raw
+	return nil
+}
+
+// Has 判断客户端是否传入了该字段（包括 null）
+func (p Patch) Has(key string) bool {
+	_, ok := p[key]
+	return ok
+}
+
+// IsNull 判断字段是否被显式设置为 null
+func (p Patch) IsNull(key string) bool {
+	value, ok := p[key]
+	return ok && bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
+
+// Decode 解码单个字段到 dest
+// 返回字段是否存在；字段不存在时不修改 dest
+func (p Patch) Decode(key string, dest interface{}) (bool, error) {
+	value, ok := p[key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(value, dest); err != nil {
+		return true, errors.Wrapf(err, "invalid field: %s", key)
+	}
+	return true, nil
+}
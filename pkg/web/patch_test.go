@@ -0,0 +1,75 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func bindTestPatch(t *testing.T, body string) Patch {
+	t.Helper()
+
+	c := newTestContext()
+	c.Request = httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+	var patch Patch
+	if err := BindPatch(c, &patch); err != nil {
+		t.Fatalf("BindPatch(%s): %v", body, err)
+	}
+	return patch
+}
+
+func TestBindPatchAbsentEmptyNull(t *testing.T) {
+	tests := []struct {
+		body     string
+		has      bool
+		isNull   bool
+		decoded  bool
+		wantText string
+	}{
+		{body: `{}`},
+		{body: ``},
+		{body: `{"content":""}`, has: true, decoded: true},
+		{body: `{"content":"text"}`, has: true, decoded: true, wantText: "text"},
+		{body: `{"content":null}`, has: true, isNull: true, decoded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			patch := bindTestPatch(t, tt.body)
+			if got := patch.Has("content"); got != tt.has {
+				t.Fatalf("Has = %v, want %v", got, tt.has)
+			}
+			if got := patch.IsNull("content"); got != tt.isNull {
+				t.Fatalf("IsNull = %v, want %v", got, tt.isNull)
+			}
+
+			content := "unchanged"
+			ok, err := patch.Decode("content", &content)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if ok != tt.decoded {
+				t.Fatalf("Decode ok = %v, want %v", ok, tt.decoded)
+			}
+			want := tt.wantText
+			if !tt.decoded || tt.isNull {
+				// 未出现的字段不修改 dest；null 解码到 string 同样保持原值
+				want = "unchanged"
+			}
+			if content != want {
+				t.Fatalf("content = %q, want %q", content, want)
+			}
+		})
+	}
+}
+
+func TestBindPatchRejectsNonObject(t *testing.T) {
+	for _, body := range []string{`[]`, `"content"`, `{"content":`} {
+		c := newTestContext()
+		c.Request = httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(body))
+		var patch Patch
+		if err := BindPatch(c, &patch); err == nil {
+			t.Fatalf("BindPatch(%s) succeeded, want error", body)
+		}
+	}
+}
@@ -2,38 +2,44 @@ package web
 
 import (
 	"net/http"
+	"reflect"
+
+	"go-api-template/pkg/errors"
 
 	"github.com/gin-gonic/gin"
 )
 
 // Response 统一响应结构
+// Data 为 nil 时省略（如错误响应）；nil 切片会转换为空切片，列表为空时始终输出 "data": []
+// Meta 为列表接口回显的查询条件（见 SuccessList），为 nil 时省略
 type Response struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	Meta    interface{} `json:"meta,omitempty"`
 }
 
 // Success 成功响应（200）
 func Success(c *Context, data interface{}) {
-	c.JSON(http.StatusOK, Response{
+	renderJSON(c, http.StatusOK, Response{
 		Code:    200,
 		Message: "success",
-		Data:    data,
+		Data:    nonNilSlice(data),
 	})
 }
 
 // SuccessWithMessage 成功响应（自定义消息）
 func SuccessWithMessage(c *Context, message string, data interface{}) {
-	c.JSON(http.StatusOK, Response{
+	renderJSON(c, http.StatusOK, Response{
 		Code:    200,
 		Message: message,
-		Data:    data,
+		Data:    nonNilSlice(data),
 	})
 }
 
 // Error 错误响应（自定义状态码和消息）
 func Error(c *Context, httpStatus int, code int, message string) {
-	c.JSON(httpStatus, Response{
+	renderJSON(c, httpStatus, Response{
 		Code:    code,
 		Message: message,
 	})
@@ -41,7 +47,7 @@ func Error(c *Context, httpStatus int, code int, message string) {
 
 // BadRequest 请求参数错误（400）
 func BadRequest(c *Context, message string) {
-	c.JSON(http.StatusBadRequest, Response{
+	renderJSON(c, http.StatusBadRequest, Response{
 		Code:    400,
 		Message: message,
 	})
@@ -49,7 +55,7 @@ func BadRequest(c *Context, message string) {
 
 // Unauthorized 未授权（401）
 func Unauthorized(c *Context, message string) {
-	c.JSON(http.StatusUnauthorized, Response{
+	renderJSON(c, http.StatusUnauthorized, Response{
 		Code:    401,
 		Message: message,
 	})
@@ -57,7 +63,7 @@ func Unauthorized(c *Context, message string) {
 
 // Forbidden 禁止访问（403）
 func Forbidden(c *Context, message string) {
-	c.JSON(http.StatusForbidden, Response{
+	renderJSON(c, http.StatusForbidden, Response{
 		Code:    403,
 		Message: message,
 	})
@@ -65,34 +71,99 @@ func Forbidden(c *Context, message string) {
 
 // NotFound 资源不存在（404）
 func NotFound(c *Context, message string) {
-	c.JSON(http.StatusNotFound, Response{
+	renderJSON(c, http.StatusNotFound, Response{
 		Code:    404,
 		Message: message,
 	})
 }
 
+// Conflict 资源冲突（409），如唯一字段重复
+func Conflict(c *Context, message string) {
+	renderJSON(c, http.StatusConflict, Response{
+		Code:    409,
+		Message: message,
+	})
+}
+
 // InternalError 服务器内部错误（500）
 func InternalError(c *Context, message string) {
-	c.JSON(http.StatusInternalServerError, Response{
+	renderJSON(c, http.StatusInternalServerError, Response{
 		Code:    500,
 		Message: message,
 	})
 }
 
+// RespondError 按 Service 返回的错误响应：参数错误（errors.ErrInvalidParams、errors.ErrMissingParams）返回 400，
+// 消息为错误信息；其他错误返回 500，消息为 message（不暴露内部错误）。
+// debug 模式下错误链中的提示（errors.WithHint）放在 data.hints 中，便于定位是哪个字段不符合业务规则
+func RespondError(c *Context, err error, message string) {
+	if errors.Is(err, errors.ErrInvalidParams) || errors.Is(err, errors.ErrMissingParams) {
+		renderJSON(c, http.StatusBadRequest, Response{
+			Code:    400,
+			Message: err.Error(),
+			Data:    errorHints(err),
+		})
+		return
+	}
+	InternalError(c, message)
+}
+
+// errorHints debug 模式下返回错误链中的提示，其他模式或没有提示时返回 nil（响应中省略 data）
+func errorHints(err error) interface{} {
+	if !gin.IsDebugging() {
+		return nil
+	}
+	hints := errors.GetAllHints(err)
+	if len(hints) == 0 {
+		return nil
+	}
+	return Map{"hints": hints}
+}
+
 // Created 创建成功（201）
 func Created(c *Context, data interface{}) {
-	c.JSON(http.StatusCreated, Response{
+	renderJSON(c, http.StatusCreated, Response{
 		Code:    201,
 		Message: "创建成功",
-		Data:    data,
+		Data:    nonNilSlice(data),
 	})
 }
 
+// CreatedAt 创建成功（201），Location 响应头指向新资源的地址（如 /api/v1/demos/1）
+func CreatedAt(c *Context, location string, data interface{}) {
+	c.Header("Location", location)
+	Created(c, data)
+}
+
 // NoContent 无内容（204）
 func NoContent(c *Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// nonNilSlice nil 切片转换为同类型的空切片（编码为 [] 而不是 null），其他值原样返回
+func nonNilSlice(data interface{}) interface{} {
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return data
+}
+
+// ========== 原始响应（不使用统一响应结构）==========
+
+// Raw 原样输出 JSON，不包装为 {code, message, data}，也不应用全局编码选项（字段命名、时间格式等）
+// 用于对方要求固定格式的接口，如 Webhook、第三方回调；payload 为 json.RawMessage 时按原字节输出
+// 普通业务接口应使用 Success 等方法
+func Raw(c *Context, httpStatus int, payload interface{}) {
+	c.JSON(httpStatus, payload)
+}
+
+// RawError 原始格式的错误响应：{"error": message}
+// 与 Raw 搭配使用；调用方对错误格式没有要求时也可以直接使用 BadRequest 等方法
+func RawError(c *Context, httpStatus int, message string) {
+	c.JSON(httpStatus, Map{"error": message})
+}
+
 // ========== 兼容性方法（用于非 web.HandlerFunc 的场景）==========
 
 // SuccessGin 成功响应（兼容 gin.Context）
@@ -0,0 +1,203 @@
+package web_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func decodeRaw(t *testing.T, body []byte) map[string]interface{} {
+	t.Helper()
+
+	var out map[string]interface{}
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("decode %s: %v", body, err)
+	}
+	return out
+}
+
+func TestRawHasNoEnvelope(t *testing.T) {
+	s := webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "")
+		g.POST("/webhook", func(ctx *web.Context) {
+			web.Raw(ctx, http.StatusOK, web.Map{"ok": true, "created_at": "2024-01-01"})
+		})
+		g.POST("/webhook/raw", func(ctx *web.Context) {
+			web.Raw(ctx, http.StatusAccepted, json.RawMessage(`{"accepted":1}`))
+		})
+		g.POST("/webhook/error", func(ctx *web.Context) {
+			web.RawError(ctx, http.StatusBadRequest, "bad signature")
+		})
+	})
+	// 全局编码选项不影响 Raw
+	prev := web.SetJSONOptions(web.JSONOptions{FieldNaming: web.FieldNamingCamel})
+	t.Cleanup(func() { web.SetJSONOptions(prev) })
+
+	w := s.Do(http.MethodPost, "/webhook", nil)
+	s.AssertStatus(w, http.StatusOK)
+	out := decodeRaw(t, w.Body.Bytes())
+	for _, key := range []string{"code", "message", "data"} {
+		if _, ok := out[key]; ok {
+			t.Fatalf("raw body has envelope key %q: %s", key, w.Body.String())
+		}
+	}
+	if out["ok"] != true || out["created_at"] != "2024-01-01" {
+		t.Fatalf("raw body = %s", w.Body.String())
+	}
+
+	w = s.Do(http.MethodPost, "/webhook/raw", nil)
+	s.AssertStatus(w, http.StatusAccepted)
+	if got := w.Body.String(); got != `{"accepted":1}` {
+		t.Fatalf("raw message body = %s", got)
+	}
+
+	w = s.Do(http.MethodPost, "/webhook/error", nil)
+	s.AssertStatus(w, http.StatusBadRequest)
+	if out := decodeRaw(t, w.Body.Bytes()); len(out) != 1 || out["error"] != "bad signature" {
+		t.Fatalf("raw error body = %s", w.Body.String())
+	}
+}
+
+func TestSuccessEmptyListEncodesArray(t *testing.T) {
+	s := webtest.New(t, func(r *gin.Engine) {
+		g := web.Group(r, "")
+		g.GET("/nil", func(ctx *web.Context) {
+			var items []string
+			web.Success(ctx, items)
+		})
+		g.GET("/empty", func(ctx *web.Context) { web.Success(ctx, []int{}) })
+		g.GET("/none", func(ctx *web.Context) { web.Success(ctx, nil) })
+	})
+
+	for _, opts := range []web.JSONOptions{{}, {Int64AsString: true, FieldNaming: web.FieldNamingCamel}} {
+		prev := web.SetJSONOptions(opts)
+		for _, path := range []string{"/nil", "/empty"} {
+			w := s.Do(http.MethodGet, path, nil)
+			s.AssertCode(w, http.StatusOK)
+			if data := string(s.Decode(w).Data); data != "[]" {
+				t.Fatalf("%s with %+v: data = %s, want []", path, opts, data)
+			}
+		}
+		web.SetJSONOptions(prev)
+	}
+
+	// 没有数据（非列表）时省略 data
+	w := s.Do(http.MethodGet, "/none", nil)
+	if _, ok := decodeRaw(t, w.Body.Bytes())["data"]; ok {
+		t.Fatalf("nil data encoded: %s", w.Body.String())
+	}
+}
+
+type fieldsItem struct {
+	ID        uint   `json:"id"`
+	Title     string `json:"title"`
+	CreatedAt string `json:"created_at"`
+	Secret    string `json:"-"`
+}
+
+func TestSelectFields(t *testing.T) {
+	items := []fieldsItem{{ID: 1, Title: "a", CreatedAt: "x"}, {ID: 2, Title: "b", CreatedAt: "y"}}
+	s := webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").GET("/items", func(ctx *web.Context) {
+			data, err := web.SelectFields(ctx, items)
+			if err != nil {
+				web.BadRequest(ctx, err.Error())
+				return
+			}
+			web.Success(ctx, data)
+		})
+	})
+
+	w := s.Do(http.MethodGet, "/items?fields=title,+id", nil)
+	s.AssertCode(w, http.StatusOK)
+	if got := string(s.Decode(w).Data); got != `[{"id":1,"title":"a"},{"id":2,"title":"b"}]` {
+		t.Fatalf("data = %s", got)
+	}
+
+	// 未传 fields 时返回全部字段
+	w = s.Do(http.MethodGet, "/items", nil)
+	if got := string(s.Decode(w).Data); !strings.Contains(got, `"created_at":"x"`) {
+		t.Fatalf("data without fields = %s", got)
+	}
+
+	// json:"-" 的字段不可选
+	s.AssertCode(s.Do(http.MethodGet, "/items?fields=Secret", nil), http.StatusBadRequest)
+
+	// 开启 camelCase 时使用 camelCase 字段名
+	prev := web.SetJSONOptions(web.JSONOptions{FieldNaming: web.FieldNamingCamel})
+	defer web.SetJSONOptions(prev)
+	w = s.Do(http.MethodGet, "/items?fields=createdAt", nil)
+	s.AssertCode(w, http.StatusOK)
+	if got := string(s.Decode(w).Data); got != `[{"createdAt":"x"},{"createdAt":"y"}]` {
+		t.Fatalf("camel data = %s", got)
+	}
+	s.AssertCode(s.Do(http.MethodGet, "/items?fields=created_at", nil), http.StatusBadRequest)
+}
+
+func TestCreatedAtSetsLocation(t *testing.T) {
+	s := webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").POST("/items", func(ctx *web.Context) {
+			web.CreatedAt(ctx, "/items/7", web.Map{"id": 7})
+		})
+	})
+
+	w := s.Do(http.MethodPost, "/items", nil)
+	s.AssertStatus(w, http.StatusCreated)
+	s.AssertCode(w, http.StatusCreated)
+	if got := w.Header().Get("Location"); got != "/items/7" {
+		t.Fatalf("Location = %q, want /items/7", got)
+	}
+	var data struct {
+		ID int `json:"id"`
+	}
+	s.DecodeData(w, &data)
+	if data.ID != 7 {
+		t.Fatalf("data.id = %d, want 7", data.ID)
+	}
+}
+
+func TestRespondError(t *testing.T) {
+	invalid := errors.WithHint(errors.Wrap(errors.ErrInvalidParams, "title cannot be empty"), "title is required")
+	s := webtest.New(t, func(r *gin.Engine) {
+		r.GET("/invalid", web.ToGinHandler(func(c *web.Context) { web.RespondError(c, invalid, "create failed") }))
+		r.GET("/internal", web.ToGinHandler(func(c *web.Context) {
+			web.RespondError(c, errors.WithHint(errors.New("db down"), "secret detail"), "create failed")
+		}))
+	})
+
+	// 测试模式下不返回提示
+	w := s.Do(http.MethodGet, "/invalid", nil)
+	s.AssertCode(w, http.StatusBadRequest)
+	s.AssertMessage(w, invalid.Error())
+	if body := decodeRaw(t, w.Body.Bytes()); body["data"] != nil {
+		t.Fatalf("data = %v outside debug mode, want omitted", body["data"])
+	}
+
+	w = s.Do(http.MethodGet, "/internal", nil)
+	s.AssertCode(w, http.StatusInternalServerError)
+	s.AssertMessage(w, "create failed")
+
+	// debug 模式下返回错误链中的提示
+	gin.SetMode(gin.DebugMode)
+	defer gin.SetMode(gin.TestMode)
+	w = s.Do(http.MethodGet, "/invalid", nil)
+	s.AssertCode(w, http.StatusBadRequest)
+	var data struct {
+		Hints []string `json:"hints"`
+	}
+	s.DecodeData(w, &data)
+	if len(data.Hints) != 1 || data.Hints[0] != "title is required" {
+		t.Fatalf("hints = %v, want [title is required]", data.Hints)
+	}
+	// 500 不返回内部错误的提示
+	if w := s.Do(http.MethodGet, "/internal", nil); strings.Contains(w.Body.String(), "secret detail") {
+		t.Fatalf("internal error leaked its hint: %s", w.Body.String())
+	}
+}
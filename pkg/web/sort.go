@@ -0,0 +1,54 @@
+package web
+
+import (
+	"slices"
+	"strings"
+
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/errors"
+)
+
+// SortQuery 排序的查询参数名
+const SortQuery = "sort"
+
+// ParseSort 解析 sort 查询参数（如 ?sort=-created_at,title），"-" 前缀表示降序，"+" 或无前缀表示升序
+// 列名必须在 allowed 白名单内，否则返回 errors.ErrInvalidParams（防止任意列名注入 ORDER BY）；
+// 同一列重复出现同样视为参数错误；未传 sort 时返回空排序，由 Repository 使用默认排序
+func ParseSort(c *Context, allowed []string) (database.Sort, error) {
+	return parseSort(c.Query(SortQuery), allowed)
+}
+
+// parseSort 解析排序表达式
+func parseSort(raw string, allowed []string) (database.Sort, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	var sort database.Sort
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		desc := false
+		switch item[0] {
+		case '-':
+			desc, item = true, item[1:]
+		case '+':
+			item = item[1:]
+		}
+
+		if !slices.Contains(allowed, item) {
+			return nil, errors.Wrapf(errors.ErrInvalidParams, "invalid sort field: %s", item)
+		}
+		if seen[item] {
+			return nil, errors.Wrapf(errors.ErrInvalidParams, "duplicate sort field: %s", item)
+		}
+		seen[item] = true
+		sort = append(sort, database.SortField{Column: item, Desc: desc})
+	}
+	return sort, nil
+}
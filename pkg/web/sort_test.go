@@ -0,0 +1,38 @@
+package web
+
+import (
+	"reflect"
+	"testing"
+
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/errors"
+)
+
+var testSortFields = []string{"id", "title", "created_at"}
+
+func TestParseSortValid(t *testing.T) {
+	tests := map[string]database.Sort{
+		"":                         nil,
+		"title":                    {{Column: "title"}},
+		"-created_at":              {{Column: "created_at", Desc: true}},
+		"+id":                      {{Column: "id"}},
+		"-created_at, title,,+id ": {{Column: "created_at", Desc: true}, {Column: "title"}, {Column: "id"}},
+	}
+	for raw, want := range tests {
+		got, err := parseSort(raw, testSortFields)
+		if err != nil {
+			t.Fatalf("parseSort(%q): %v", raw, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("parseSort(%q) = %+v, want %+v", raw, got, want)
+		}
+	}
+}
+
+func TestParseSortInvalid(t *testing.T) {
+	for _, raw := range []string{"password", "-title,secret", "title,-title", "id;drop table", "-"} {
+		if _, err := parseSort(raw, testSortFields); !errors.Is(err, errors.ErrInvalidParams) {
+			t.Fatalf("parseSort(%q) err = %v, want ErrInvalidParams", raw, err)
+		}
+	}
+}
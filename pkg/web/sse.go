@@ -0,0 +1,122 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// SSEEventShutdown 服务关闭时发送给客户端的最后一个事件，客户端收到后应稍后重连（连到其他实例）
+const SSEEventShutdown = "shutdown"
+
+// SSEEvent Server-Sent Events 事件
+type SSEEvent struct {
+	Event string      // 事件类型，为空时客户端按 message 处理
+	ID    string      // 事件 ID（客户端重连时通过 Last-Event-ID 带回）
+	Data  interface{} // 事件数据：string 原样输出，其他值编码为 JSON
+}
+
+// StreamTracker 跟踪在途的流式连接（SSE 等）
+// 服务关闭时（http.Server.RegisterOnShutdown）调用 Shutdown，通知所有流发送关闭事件并结束，
+// 避免 http.Server.Shutdown 一直等到超时后被强制断开
+type StreamTracker struct {
+	active   atomic.Int64
+	closing  chan struct{}
+	shutdown sync.Once
+}
+
+// NewStreamTracker 创建流式连接跟踪器
+func NewStreamTracker() *StreamTracker {
+	return &StreamTracker{closing: make(chan struct{})}
+}
+
+// Active 当前在途的流式连接数
+func (t *StreamTracker) Active() int64 {
+	return t.active.Load()
+}
+
+// Shutdown 通知所有流式连接结束，重复调用只有第一次生效
+func (t *StreamTracker) Shutdown() {
+	t.shutdown.Do(func() { close(t.closing) })
+}
+
+// Closing 服务关闭时关闭的 channel
+func (t *StreamTracker) Closing() <-chan struct{} {
+	return t.closing
+}
+
+// StreamSSE 以 Server-Sent Events 格式输出 events 中的事件
+// 直到 events 被关闭、客户端断开、写入失败或服务关闭；服务关闭时先发送 event: shutdown 再结束
+// tracker 为 nil 时不参与关闭通知
+func StreamSSE(c *Context, tracker *StreamTracker, events <-chan SSEEvent) {
+	var closing <-chan struct{}
+	if tracker != nil {
+		tracker.active.Add(1)
+		defer tracker.active.Add(-1)
+		closing = tracker.Closing()
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 关闭 Nginx 缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	done := c.Request.Context().Done()
+	for {
+		select {
+		case <-done:
+			return
+		case <-closing:
+			_ = writeSSE(c.Writer, SSEEvent{Event: SSEEventShutdown, Data: ""})
+			c.Writer.Flush()
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeSSE(c.Writer, event); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeSSE 按 SSE 格式写出单个事件（多行数据拆成多个 data 行）
+func writeSSE(w io.Writer, event SSEEvent) error {
+	var data string
+	switch v := event.Data.(type) {
+	case string:
+		data = v
+	default:
+		b, err := json.Marshal(encodable(v))
+		if err != nil {
+			return err
+		}
+		data = string(b)
+	}
+
+	// id、event 中的换行会破坏事件边界
+	oneLine := strings.NewReplacer("\r", " ", "\n", " ")
+	var sb strings.Builder
+	if event.ID != "" {
+		fmt.Fprintf(&sb, "id: %s\n", oneLine.Replace(event.ID))
+	}
+	if event.Event != "" {
+		fmt.Fprintf(&sb, "event: %s\n", oneLine.Replace(event.Event))
+	}
+	data = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(data)
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(&sb, "data: %s\n", line)
+	}
+	sb.WriteString("\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
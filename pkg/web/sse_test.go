@@ -0,0 +1,112 @@
+package web_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStreamSSEReceivesShutdownEventOnDrain(t *testing.T) {
+	tracker := web.NewStreamTracker()
+	events := make(chan web.SSEEvent, 1)
+	events <- web.SSEEvent{Event: "tick", ID: "1", Data: web.Map{"n": 1}}
+
+	s := webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").GET("/events", func(ctx *web.Context) {
+			web.StreamSSE(ctx, tracker, events)
+		})
+	})
+	srv := httptest.NewUnstartedServer(s.Engine)
+	srv.Config.RegisterOnShutdown(tracker.Shutdown) // 与 startServer 相同
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + "/events")
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// 读到第一个事件后开始关闭
+	reader := bufio.NewReader(resp.Body)
+	first := readSSEEvent(t, reader)
+	if first != "id: 1\nevent: tick\ndata: {\"n\":1}\n" {
+		t.Fatalf("first event = %q", first)
+	}
+	if tracker.Active() != 1 {
+		t.Fatalf("active streams = %d, want 1", tracker.Active())
+	}
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownErr <- srv.Config.Shutdown(ctx)
+	}()
+
+	if last := readSSEEvent(t, reader); last != "event: shutdown\ndata: \n" {
+		t.Fatalf("last event = %q, want shutdown", last)
+	}
+	if rest, _ := io.ReadAll(reader); len(rest) != 0 {
+		t.Fatalf("data after shutdown event: %q", rest)
+	}
+
+	// 流结束后 Shutdown 很快返回，不会等到超时
+	select {
+	case err := <-shutdownErr:
+		if err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Shutdown blocked by the stream")
+	}
+	if tracker.Active() != 0 {
+		t.Fatalf("active streams after drain = %d, want 0", tracker.Active())
+	}
+}
+
+// readSSEEvent 读取一个事件（到空行为止），返回不含结尾空行的内容
+func readSSEEvent(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+
+	var sb strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v (read %q)", err, sb.String())
+		}
+		if line == "\n" {
+			return sb.String()
+		}
+		sb.WriteString(line)
+	}
+}
+
+func TestStreamSSEMultilineData(t *testing.T) {
+	events := make(chan web.SSEEvent, 1)
+	events <- web.SSEEvent{Event: "multi\nline", Data: "a\r\nb"}
+	close(events)
+
+	s := webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").GET("/events", func(ctx *web.Context) {
+			web.StreamSSE(ctx, nil, events)
+		})
+	})
+	w := s.Do(http.MethodGet, "/events", nil)
+	if want := "event: multi line\ndata: a\ndata: b\n\n"; w.Body.String() != want {
+		t.Fatalf("body = %q, want %q", w.Body.String(), want)
+	}
+}
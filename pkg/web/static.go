@@ -0,0 +1,185 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// staticIndex SPA 入口文件
+	staticIndex = "index.html"
+	// staticMaxAge 静态资源（非 HTML）的缓存时间；HTML 每次都需要重新验证，保证发布后立即生效
+	staticMaxAge = 24 * time.Hour
+)
+
+// 静态文件压缩指标（content_type、encoding 区分，未压缩返回的 encoding 为 identity）
+// 压缩率 = sum(rate(out)) / sum(rate(in))，用于判断哪些类型的文件值得预压缩
+const (
+	MetricStaticBytesIn  = "static_response_bytes_in_total"  // 完整返回的文件压缩前的大小
+	MetricStaticBytesOut = "static_response_bytes_out_total" // 实际返回的大小（压缩后）
+)
+
+// staticMetricLabels 静态文件压缩指标的 Label
+var staticMetricLabels = []string{"content_type", "encoding"}
+
+// staticEncodings 支持的预压缩格式（按优先级），文件名为原文件名加后缀，如 app.js.br / app.js.gz
+var staticEncodings = []struct {
+	name   string
+	suffix string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// ServeEmbedded 在 prefix（如 "/app"，不能为根路径）下提供嵌入文件系统中的静态文件（前端构建产物、文档等）
+//   - Content-Type 按扩展名设置；HTML 使用 Cache-Control: no-cache，其他文件缓存 staticMaxAge，均带 ETag
+//   - 存在预压缩文件（.br / .gz）且 Accept-Encoding 接受时直接返回压缩文件，并设置 Content-Encoding 和 Vary
+//   - 不存在的路径：带扩展名的按 404 处理，其余返回 index.html（SPA 前端路由）
+//
+// fsys 通常为 embed.FS，文件位于子目录时用 fs.Sub 取出：
+//
+//	//go:embed dist
+//	var dist embed.FS
+//	sub, _ := fs.Sub(dist, "dist")
+//	web.ServeEmbedded(r, "/app", sub)
+func ServeEmbedded(r gin.IRouter, prefix string, fsys fs.FS) {
+	s := &staticServer{fsys: fsys}
+	route := strings.TrimSuffix(prefix, "/") + "/*filepath"
+	r.GET(route, ToGinHandler(s.serve))
+	r.HEAD(route, ToGinHandler(s.serve))
+}
+
+// staticServer 嵌入文件服务
+type staticServer struct {
+	fsys  fs.FS
+	etags sync.Map // 文件名 -> ETag（嵌入文件不会变化，计算一次即可）
+}
+
+// serve 处理静态文件请求
+func (s *staticServer) serve(ctx *Context) {
+	name := strings.TrimPrefix(path.Clean("/"+ctx.Param("filepath")), "/")
+	if name == "" {
+		name = staticIndex
+	}
+
+	if info, err := fs.Stat(s.fsys, name); err == nil && info.IsDir() {
+		name = path.Join(name, staticIndex)
+	}
+	if !s.exists(name) {
+		if path.Ext(name) != "" || !s.exists(staticIndex) {
+			NotFound(ctx, constants.MsgNotFound)
+			return
+		}
+		name = staticIndex // SPA 前端路由
+	}
+
+	// 预压缩文件
+	ctx.Header("Vary", "Accept-Encoding")
+	served, encoding := name, "identity"
+	accept := ctx.GetHeader("Accept-Encoding")
+	for _, enc := range staticEncodings {
+		if acceptsEncoding(accept, enc.name) && s.exists(name+enc.suffix) {
+			served, encoding = name+enc.suffix, enc.name
+			ctx.Header("Content-Encoding", enc.name)
+			break
+		}
+	}
+
+	data, err := fs.ReadFile(s.fsys, served)
+	if err != nil {
+		InternalError(ctx, constants.MsgInternalError)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType != "" {
+		ctx.Header("Content-Type", contentType)
+	}
+	if path.Ext(name) == ".html" {
+		ctx.Header("Cache-Control", "no-cache")
+	} else {
+		ctx.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(staticMaxAge.Seconds())))
+	}
+	ctx.Header("ETag", s.etag(served, data))
+
+	// 嵌入文件没有修改时间，由 ETag 处理 If-None-Match（304）
+	http.ServeContent(ctx.Writer, ctx.Request, name, time.Time{}, bytes.NewReader(data))
+
+	// 只统计完整返回的文件（不含 304、HEAD 和 Range 请求）
+	if ctx.Writer.Status() == http.StatusOK && ctx.Writer.Size() == len(data) {
+		s.recordBytes(name, contentType, encoding, len(data))
+	}
+}
+
+// recordBytes 记录一次完整返回的压缩前后大小
+func (s *staticServer) recordBytes(name, contentType, encoding string, out int) {
+	in := out
+	if encoding != "identity" {
+		info, err := fs.Stat(s.fsys, name)
+		if err != nil {
+			return
+		}
+		in = int(info.Size())
+	}
+
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	if mediaType == "" {
+		mediaType = "unknown"
+	}
+	metrics.AddWith(MetricStaticBytesIn, staticMetricLabels, float64(in), mediaType, encoding)
+	metrics.AddWith(MetricStaticBytesOut, staticMetricLabels, float64(out), mediaType, encoding)
+}
+
+// exists 判断文件（非目录）是否存在
+func (s *staticServer) exists(name string) bool {
+	info, err := fs.Stat(s.fsys, name)
+	return err == nil && !info.IsDir()
+}
+
+// etag 文件内容的 ETag（强校验）
+func (s *staticServer) etag(name string, data []byte) string {
+	if v, ok := s.etags.Load(name); ok {
+		return v.(string)
+	}
+	sum := sha256.Sum256(data)
+	tag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	s.etags.Store(name, tag)
+	return tag
+}
+
+// acceptsEncoding 判断 Accept-Encoding 是否接受 encoding（q=0 表示拒绝，支持 *）
+func acceptsEncoding(header, encoding string) bool {
+	accepted := false
+	for _, part := range strings.Split(header, ",") {
+		token, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		token = strings.ToLower(strings.TrimSpace(token))
+		if token != encoding && token != "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if token == encoding {
+			return q > 0 // 明确列出的优先于 *
+		}
+		accepted = q > 0
+	}
+	return accepted
+}
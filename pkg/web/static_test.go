@@ -0,0 +1,190 @@
+package web_test
+
+import (
+	"compress/gzip"
+	"embed"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go-api-template/pkg/metrics"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed testdata/static
+var staticFiles embed.FS
+
+func newStaticServer(t *testing.T) (*webtest.Server, fs.FS) {
+	t.Helper()
+
+	sub, err := fs.Sub(staticFiles, "testdata/static")
+	if err != nil {
+		t.Fatalf("fs.Sub: %v", err)
+	}
+	return webtest.New(t, func(r *gin.Engine) {
+		web.ServeEmbedded(r, "/app", sub)
+	}), sub
+}
+
+func TestServeEmbeddedFile(t *testing.T) {
+	s, sub := newStaticServer(t)
+	want, _ := fs.ReadFile(sub, "assets/app.js")
+
+	w := s.Do(http.MethodGet, "/app/assets/app.js", nil)
+	s.AssertStatus(w, http.StatusOK)
+	if w.Body.String() != string(want) {
+		t.Fatalf("body = %q, want %q", w.Body.String(), want)
+	}
+	headers := map[string]string{
+		"Cache-Control":    "public, max-age=86400",
+		"Vary":             "Accept-Encoding",
+		"Content-Encoding": "",
+	}
+	for name, value := range headers {
+		if got := w.Header().Get(name); got != value {
+			t.Fatalf("%s = %q, want %q", name, got, value)
+		}
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.Contains(ct, "javascript") {
+		t.Fatalf("Content-Type = %q, want javascript", ct)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag")
+	}
+
+	w = s.Do(http.MethodGet, "/app/assets/style.css", nil)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/css") {
+		t.Fatalf("css Content-Type = %q", ct)
+	}
+
+	// ETag 匹配时返回 304
+	s.Header.Set("If-None-Match", etag)
+	if w := s.Do(http.MethodGet, "/app/assets/app.js", nil); w.Code != http.StatusNotModified {
+		t.Fatalf("If-None-Match: status = %d, want 304", w.Code)
+	}
+}
+
+func TestServeEmbeddedPrecompressed(t *testing.T) {
+	s, sub := newStaticServer(t)
+	want, _ := fs.ReadFile(sub, "assets/app.js")
+
+	s.Header.Set("Accept-Encoding", "br, gzip")
+	w := s.Do(http.MethodGet, "/app/assets/app.js", nil)
+	s.AssertStatus(w, http.StatusOK)
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip (no .br variant)", got)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.Contains(ct, "javascript") {
+		t.Fatalf("Content-Type = %q, want type of the original file", ct)
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	if data, _ := io.ReadAll(zr); string(data) != string(want) {
+		t.Fatalf("decompressed body = %q, want %q", data, want)
+	}
+
+	// q=0 拒绝 gzip
+	s.Header.Set("Accept-Encoding", "*, gzip;q=0")
+	if w := s.Do(http.MethodGet, "/app/assets/app.js", nil); w.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("gzip;q=0: Content-Encoding = %q", w.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestServeEmbeddedSPAFallback(t *testing.T) {
+	s, sub := newStaticServer(t)
+	index, _ := fs.ReadFile(sub, "index.html")
+
+	for _, path := range []string{"/app/", "/app/orders/42", "/app/../index.html"} {
+		w := s.Do(http.MethodGet, path, nil)
+		s.AssertStatus(w, http.StatusOK)
+		if w.Body.String() != string(index) {
+			t.Fatalf("%s: body = %q, want index.html", path, w.Body.String())
+		}
+		if got := w.Header().Get("Cache-Control"); got != "no-cache" {
+			t.Fatalf("%s: Cache-Control = %q, want no-cache", path, got)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+			t.Fatalf("%s: Content-Type = %q", path, ct)
+		}
+	}
+
+	// 带扩展名的不存在文件不回退到 index.html
+	s.AssertCode(s.Do(http.MethodGet, "/app/assets/missing.css", nil), http.StatusNotFound)
+}
+
+// scrapeMetric 从 /metrics 读取 series（含 label）的值，未导出时为 0
+func scrapeMetric(t *testing.T, series string) float64 {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, line := range strings.Split(w.Body.String(), "\n") {
+		name, value, ok := strings.Cut(line, " ")
+		if !ok || name != series {
+			continue
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			t.Fatalf("parse %q: %v", line, err)
+		}
+		return v
+	}
+	return 0
+}
+
+func TestServeEmbeddedCompressionMetrics(t *testing.T) {
+	s, sub := newStaticServer(t)
+	plain, _ := fs.ReadFile(sub, "assets/app.js")
+	gz, _ := fs.ReadFile(sub, "assets/app.js.gz")
+
+	series := func(name, encoding string) string {
+		return name + `{content_type="text/javascript",encoding="` + encoding + `"}`
+	}
+	read := func() [4]float64 {
+		return [4]float64{
+			scrapeMetric(t, series(web.MetricStaticBytesIn, "gzip")),
+			scrapeMetric(t, series(web.MetricStaticBytesOut, "gzip")),
+			scrapeMetric(t, series(web.MetricStaticBytesIn, "identity")),
+			scrapeMetric(t, series(web.MetricStaticBytesOut, "identity")),
+		}
+	}
+	before := read()
+
+	s.Header.Set("Accept-Encoding", "gzip")
+	s.AssertStatus(s.Do(http.MethodGet, "/app/assets/app.js", nil), http.StatusOK)
+	s.AssertStatus(s.Do(http.MethodGet, "/app/assets/app.js", nil), http.StatusOK)
+	s.Header.Del("Accept-Encoding")
+	w := s.Do(http.MethodGet, "/app/assets/app.js", nil)
+	s.AssertStatus(w, http.StatusOK)
+
+	after := read()
+	want := [4]float64{
+		float64(2 * len(plain)), float64(2 * len(gz)),
+		float64(len(plain)), float64(len(plain)),
+	}
+	for i := range want {
+		if got := after[i] - before[i]; got != want[i] {
+			t.Fatalf("counter deltas = %v, want %v", [4]float64{
+				after[0] - before[0], after[1] - before[1], after[2] - before[2], after[3] - before[3],
+			}, want)
+		}
+	}
+
+	// HEAD、304 不计入
+	s.AssertStatus(s.Do(http.MethodHead, "/app/assets/app.js", nil), http.StatusOK)
+	s.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	s.AssertStatus(s.Do(http.MethodGet, "/app/assets/app.js", nil), http.StatusNotModified)
+	if got := read(); got != after {
+		t.Fatalf("counters = %v after HEAD and 304, want unchanged %v", got, after)
+	}
+}
@@ -0,0 +1,108 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go-api-template/pkg/errors"
+)
+
+// ndjsonFlushEvery 每写入多少行刷新一次缓冲区
+const ndjsonFlushEvery = 100
+
+// StreamNDJSON 以 NDJSON（每行一个 JSON）格式流式输出 rows 中的数据
+// 直到 rows 被关闭、客户端断开或写入失败；调用方应在返回后停止向 rows 写入（如取消 context）
+// 响应头在第一行写出前已发送，中途出错无法再修改状态码
+func StreamNDJSON(c *Context, rows <-chan interface{}) {
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+
+	enc := json.NewEncoder(c.Writer)
+	done := c.Request.Context().Done()
+	written := 0
+
+	defer c.Writer.Flush()
+
+	for {
+		select {
+		case <-done:
+			return
+		case row, ok := <-rows:
+			if !ok {
+				return
+			}
+			if err := enc.Encode(encodable(row)); err != nil {
+				return
+			}
+			written++
+			if written%ndjsonFlushEvery == 0 {
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// DecodeJSONArray 流式解析请求体中的 JSON 数组，每解析出一个元素调用一次 fn
+// 不会把整个请求体读入内存；maxBytes 为请求体大小上限，不大于 0 时使用 JSONLimits.MaxBytes。
+// 每个元素同样按 JSONLimits 检查嵌套深度（数组本身算一层）和重复 key
+//
+// 错误：
+//   - 请求体超过大小上限：errors.ErrBodyTooLarge（对应 413）
+//   - 请求体不是 JSON 数组、格式错误或元素不符合 JSONLimits：errors.ErrInvalidParams（对应 400）
+//   - 请求 context 结束：ctx.Err()
+//   - fn 返回的错误原样返回，并停止解析
+func DecodeJSONArray(c *Context, maxBytes int64, fn func(index int, item json.RawMessage) error) error {
+	if maxBytes <= 0 {
+		maxBytes = jsonLimits.MaxBytes
+	}
+	if maxBytes > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+	}
+	ctx := c.Request.Context()
+	dec := json.NewDecoder(c.Request.Body)
+
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for index := 0; dec.More(); index++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var item json.RawMessage
+		if err := dec.Decode(&item); err != nil {
+			return decodeError(err, index)
+		}
+		if err := checkJSON(item, jsonLimits, 1); err != nil {
+			return errors.Wrapf(errors.ErrInvalidParams, "invalid json array item %d: %v", index, err)
+		}
+		if err := fn(index, item); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+// expectDelim 读取下一个 token 并确认是指定的分隔符
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return decodeError(err, -1)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return errors.Wrapf(errors.ErrInvalidParams, "invalid json array: expected %q, got %v", delim, tok)
+	}
+	return nil
+}
+
+// decodeError 转换解析错误：请求体超限时返回 ErrBodyTooLarge，其余为 ErrInvalidParams
+func decodeError(err error, index int) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return errors.WithStack(errors.ErrBodyTooLarge)
+	}
+	if index < 0 {
+		return errors.Wrapf(errors.ErrInvalidParams, "invalid json array: %v", err)
+	}
+	return errors.Wrapf(errors.ErrInvalidParams, "invalid json array item %d: %v", index, err)
+}
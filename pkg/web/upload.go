@@ -0,0 +1,104 @@
+package web
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/tools"
+)
+
+// multipartOverhead multipart 请求中除文件内容外的额外开销（边界、字段头、其他表单字段）
+const multipartOverhead = 1 << 20
+
+// UploadOptions 文件上传选项
+type UploadOptions struct {
+	MaxSize      int64    // 文件大小上限（字节），0 表示不限制
+	AllowedExts  []string // 允许的扩展名（如 .png，不区分大小写），为空表示不限制
+	AllowedTypes []string // 允许的内容类型（如 image/png），为空表示不限制
+}
+
+// SaveUploadedFile 保存 multipart 表单中的上传文件
+// 文件以随机文件名保存到 dstDir（保留原扩展名），返回保存后的路径
+// 内容类型根据文件头部字节检测，不信任客户端声明的 Content-Type
+//
+// 错误：
+//   - 文件超过 MaxSize：errors.ErrFileTooLarge（对应 413）
+//   - 扩展名或内容类型不在白名单：errors.ErrFileTypeNotAllowed（对应 400）
+func SaveUploadedFile(c *Context, field string, dstDir string, opts UploadOptions) (savedPath string, err error) {
+	if opts.MaxSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, opts.MaxSize+multipartOverhead)
+	}
+
+	fileHeader, err := c.FormFile(field)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return "", errors.WithStack(errors.ErrFileTooLarge)
+		}
+		return "", errors.Wrapf(err, "read upload field failed: %s", field)
+	}
+	if opts.MaxSize > 0 && fileHeader.Size > opts.MaxSize {
+		return "", errors.WithStack(errors.ErrFileTooLarge)
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	if len(opts.AllowedExts) > 0 && !slices.ContainsFunc(opts.AllowedExts, func(allowed string) bool {
+		return strings.EqualFold(allowed, ext)
+	}) {
+		return "", errors.WithDetail(errors.WithStack(errors.ErrFileTypeNotAllowed), "extension: "+ext)
+	}
+
+	src, err := fileHeader.Open()
+	if err != nil {
+		return "", errors.Wrap(err, "open upload file failed")
+	}
+	defer src.Close()
+
+	if len(opts.AllowedTypes) > 0 {
+		contentType, err := detectContentType(src)
+		if err != nil {
+			return "", err
+		}
+		if !slices.Contains(opts.AllowedTypes, contentType) {
+			return "", errors.WithDetail(errors.WithStack(errors.ErrFileTypeNotAllowed), "content type: "+contentType)
+		}
+	}
+
+	if err := os.MkdirAll(dstDir, 0o755); err != nil {
+		return "", errors.Wrap(err, "create upload dir failed")
+	}
+
+	savedPath = filepath.Join(dstDir, tools.RandStringLower(32)+ext)
+	dst, err := os.OpenFile(savedPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", errors.Wrap(err, "create upload file failed")
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(savedPath)
+		return "", errors.Wrap(err, "save upload file failed")
+	}
+	return savedPath, nil
+}
+
+// detectContentType 根据文件头部字节检测内容类型（不含参数），并将读取位置重置到开头
+func detectContentType(f io.ReadSeeker) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", errors.Wrap(err, "read upload file failed")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", errors.Wrap(err, "seek upload file failed")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	return mediaType, nil
+}
@@ -0,0 +1,113 @@
+package web_test
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-api-template/pkg/errors"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pngHeader PNG 文件头（用于内容类型检测）
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// newUploadServer 上传接口：成功时返回保存的路径，失败时返回错误对应的状态码
+func newUploadServer(t *testing.T, dir string, opts web.UploadOptions) *webtest.Server {
+	return webtest.New(t, func(r *gin.Engine) {
+		web.Group(r, "").POST("/upload", func(ctx *web.Context) {
+			saved, err := web.SaveUploadedFile(ctx, "file", dir, opts)
+			switch {
+			case err == nil:
+				web.Success(ctx, saved)
+			case errors.Is(err, errors.ErrFileTooLarge):
+				web.Error(ctx, http.StatusRequestEntityTooLarge, http.StatusRequestEntityTooLarge, "file too large")
+			case errors.Is(err, errors.ErrFileTypeNotAllowed):
+				web.BadRequest(ctx, "file type not allowed")
+			default:
+				web.BadRequest(ctx, err.Error())
+			}
+		})
+	})
+}
+
+// uploadFile 以 multipart 上传一个文件
+func uploadFile(t *testing.T, s *webtest.Server, filename string, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	s.Header.Set("Content-Type", w.FormDataContentType())
+	return s.Do(http.MethodPost, "/upload", buf.Bytes())
+}
+
+var imageOnly = web.UploadOptions{
+	MaxSize:      1024,
+	AllowedExts:  []string{".png", ".jpg"},
+	AllowedTypes: []string{"image/png", "image/jpeg"},
+}
+
+func TestUploadRejectsDisallowedType(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  []byte
+	}{
+		{name: "extension", filename: "run.exe", content: pngHeader},
+		{name: "content sniffed", filename: "fake.png", content: []byte("#!/bin/sh\necho hi\n")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s := newUploadServer(t, dir, imageOnly)
+
+			w := uploadFile(t, s, tt.filename, tt.content)
+			s.AssertCode(w, http.StatusBadRequest)
+			s.AssertMessage(w, "file type not allowed")
+			if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+				t.Fatalf("rejected file was saved: %v", entries)
+			}
+		})
+	}
+}
+
+func TestUploadRejectsOversize(t *testing.T) {
+	s := newUploadServer(t, t.TempDir(), imageOnly)
+	content := append(append([]byte(nil), pngHeader...), make([]byte, 2048)...)
+	s.AssertCode(uploadFile(t, s, "big.png", content), http.StatusRequestEntityTooLarge)
+}
+
+func TestUploadSavesWithRandomName(t *testing.T) {
+	dir := t.TempDir()
+	s := newUploadServer(t, dir, imageOnly)
+
+	w := uploadFile(t, s, "../../avatar.PNG", pngHeader)
+	s.AssertCode(w, http.StatusOK)
+	var saved string
+	s.DecodeData(w, &saved)
+
+	if filepath.Dir(saved) != dir || filepath.Ext(saved) != ".png" || filepath.Base(saved) == "avatar.png" {
+		t.Fatalf("saved path = %s", saved)
+	}
+	data, err := os.ReadFile(saved)
+	if err != nil || !bytes.Equal(data, pngHeader) {
+		t.Fatalf("saved content = %q, err = %v", data, err)
+	}
+}
@@ -0,0 +1,216 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go-api-template/internal/constants"
+	"go-api-template/pkg/errors"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`   // 字段路径（字段名依次取自 json、form、uri、header 标签），如 items[2].status
+	Rule    string `json:"rule"`    // 未通过的规则，如 required、max
+	Message string `json:"message"` // 友好的错误提示
+}
+
+func init() {
+	// 校验错误中使用 json/form/uri/header 标签名，与客户端提交的字段名一致
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldName)
+	}
+}
+
+// fieldName 返回字段对外的名称
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri", "header"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// InvalidRequest 请求参数错误响应（400）
+// 校验错误（binding 标签）转换为逐字段的友好提示，放在 data.errors 中；
+// 请求体超过大小上限（errors.ErrBodyTooLarge）返回 413；其他错误（如 JSON 格式错误）直接返回错误信息
+func InvalidRequest(c *Context, err error) {
+	if errors.Is(err, errors.ErrBodyTooLarge) {
+		Error(c, http.StatusRequestEntityTooLarge, http.StatusRequestEntityTooLarge, constants.MsgRequestEntityTooLarge)
+		return
+	}
+	fields, ok := TranslateValidationErrors(err)
+	if !ok {
+		BadRequest(c, "invalid request: "+err.Error())
+		return
+	}
+	invalidFields(c, fields)
+}
+
+// invalidFields 逐字段校验错误响应（400），错误放在 data.errors 中
+func invalidFields(c *Context, fields []FieldError) {
+	renderJSON(c, http.StatusBadRequest, Response{
+		Code:    http.StatusBadRequest,
+		Message: constants.MsgBadRequest,
+		Data:    Map{"errors": fields},
+	})
+}
+
+// Validate 按 binding 标签校验结构体（与 Bind 使用同一个校验器）
+// 用于不经过 Bind 解析的数据，如流式导入中的每个元素。
+// obj 为数组时逐个元素校验，错误中保留元素下标（Gin 的 SliceValidationError 只保留失败元素，丢失了下标）
+func Validate(obj interface{}) error {
+	value := reflect.Indirect(reflect.ValueOf(obj))
+	if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+		return binding.Validator.ValidateStruct(obj)
+	}
+
+	var errs elementValidationErrors
+	for i := 0; i < value.Len(); i++ {
+		if err := Validate(value.Index(i).Interface()); err != nil {
+			errs = append(errs, elementValidationError{index: i, err: err})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// elementValidationError 数组中单个元素的校验错误
+type elementValidationError struct {
+	index int
+	err   error
+}
+
+// elementValidationErrors 数组请求体的校验错误
+type elementValidationErrors []elementValidationError
+
+// Error 实现 error 接口
+func (errs elementValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = "[" + strconv.Itoa(e.index) + "]: " + e.err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// TranslateValidationErrors 将校验错误转换为逐字段的友好提示
+// 字段为完整路径：嵌套结构体用 . 连接，数组元素带下标，如 items[2].status、[0].title（请求体为数组时）
+// err 不是校验错误时返回 false
+func TranslateValidationErrors(err error) ([]FieldError, bool) {
+	return translateValidationErrors(err, "")
+}
+
+// translateValidationErrors 转换校验错误，字段路径加上 prefix（数组元素的下标）
+func translateValidationErrors(err error, prefix string) ([]FieldError, bool) {
+	var elementErrs elementValidationErrors
+	if errors.As(err, &elementErrs) {
+		var fields []FieldError
+		for _, e := range elementErrs {
+			sub, ok := translateValidationErrors(e.err, prefix+"["+strconv.Itoa(e.index)+"]")
+			if !ok {
+				return nil, false
+			}
+			fields = append(fields, sub...)
+		}
+		return fields, true
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil, false
+	}
+
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		field := joinFieldPath(prefix, fieldPath(fe))
+		fields = append(fields, FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Message: validationMessage(fe, field),
+		})
+	}
+	return fields, true
+}
+
+// fieldPath 字段在请求中的路径
+// Namespace 形如 CreateRequest.items[2].status（字段名已由 fieldName 转换），去掉开头的结构体类型名
+func fieldPath(fe validator.FieldError) string {
+	if _, path, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return path
+	}
+	return fe.Field()
+}
+
+// joinFieldPath 拼接下标前缀和字段路径
+func joinFieldPath(prefix, path string) string {
+	if prefix == "" || strings.HasPrefix(path, "[") {
+		return prefix + path
+	}
+	return prefix + "." + path
+}
+
+// validationMessage 生成单个规则的友好提示，field 为字段路径
+func validationMessage(fe validator.FieldError, field string) string {
+	param := fe.Param()
+
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s 不能为空", field)
+	case "min":
+		return fmt.Sprintf("%s %s不能少于 %s%s", field, measure(fe.Kind()), param, unit(fe.Kind()))
+	case "max":
+		return fmt.Sprintf("%s %s不能超过 %s%s", field, measure(fe.Kind()), param, unit(fe.Kind()))
+	case "len":
+		return fmt.Sprintf("%s %s必须为 %s%s", field, measure(fe.Kind()), param, unit(fe.Kind()))
+	case "gt":
+		return fmt.Sprintf("%s 必须大于 %s", field, param)
+	case "gte":
+		return fmt.Sprintf("%s 不能小于 %s", field, param)
+	case "lt":
+		return fmt.Sprintf("%s 必须小于 %s", field, param)
+	case "lte":
+		return fmt.Sprintf("%s 不能大于 %s", field, param)
+	case "oneof":
+		return fmt.Sprintf("%s 必须是以下值之一：%s", field, strings.Join(strings.Fields(param), ", "))
+	case "email":
+		return fmt.Sprintf("%s 必须是有效的邮箱地址", field)
+	case "url":
+		return fmt.Sprintf("%s 必须是有效的 URL", field)
+	default:
+		return fmt.Sprintf("%s 未通过 %s 校验", field, fe.Tag())
+	}
+}
+
+// measure 长度类规则的度量对象（字符串为长度，集合为元素个数，数字为值本身）
+func measure(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "长度"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "元素个数"
+	default:
+		return ""
+	}
+}
+
+// unit 长度类规则的单位
+func unit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return " 个字符"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " 个"
+	default:
+		return ""
+	}
+}
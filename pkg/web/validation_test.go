@@ -0,0 +1,79 @@
+package web
+
+import (
+	"strings"
+	"testing"
+)
+
+type validationItem struct {
+	Status int `json:"status" binding:"oneof=0 1"`
+}
+
+type validationRequest struct {
+	Title   string           `json:"title" binding:"required,min=1,max=10"`
+	Code    string           `json:"code" binding:"omitempty,len=4"`
+	Email   string           `json:"email" binding:"omitempty,email"`
+	Website string           `json:"website" binding:"omitempty,url"`
+	Age     int              `json:"age" binding:"gte=0,lte=150"`
+	Tags    []string         `json:"tags" binding:"max=2"`
+	Status  int              `json:"status" binding:"oneof=0 1"`
+	Items   []validationItem `json:"items" binding:"dive"`
+}
+
+func validRequest() validationRequest {
+	return validationRequest{Title: "ok", Email: "a@example.com", Website: "https://example.com"}
+}
+
+func TestValidationViolations(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(r *validationRequest)
+		field   string
+		rule    string
+		message string
+	}{
+		{"required", func(r *validationRequest) { r.Title = "" }, "title", "required", "title 不能为空"},
+		{"max length", func(r *validationRequest) { r.Title = strings.Repeat("字", 11) }, "title", "max", "title 长度不能超过 10 个字符"},
+		{"len", func(r *validationRequest) { r.Code = "123" }, "code", "len", "code 长度必须为 4 个字符"},
+		{"email", func(r *validationRequest) { r.Email = "not-an-email" }, "email", "email", "email 必须是有效的邮箱地址"},
+		{"url", func(r *validationRequest) { r.Website = "example" }, "website", "url", "website 必须是有效的 URL"},
+		{"gte", func(r *validationRequest) { r.Age = -1 }, "age", "gte", "age 不能小于 0"},
+		{"lte", func(r *validationRequest) { r.Age = 151 }, "age", "lte", "age 不能大于 150"},
+		{"max items", func(r *validationRequest) { r.Tags = []string{"a", "b", "c"} }, "tags", "max", "tags 元素个数不能超过 2 个"},
+		{"oneof", func(r *validationRequest) { r.Status = 2 }, "status", "oneof", "status 必须是以下值之一：0, 1"},
+		{"nested", func(r *validationRequest) { r.Items = []validationItem{{}, {Status: 3}} }, "items[1].status", "oneof", "items[1].status 必须是以下值之一：0, 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validRequest()
+			tt.mutate(&req)
+
+			fields, ok := TranslateValidationErrors(Validate(&req))
+			if !ok {
+				t.Fatal("not a validation error")
+			}
+			if len(fields) != 1 {
+				t.Fatalf("fields = %+v, want one", fields)
+			}
+			want := FieldError{Field: tt.field, Rule: tt.rule, Message: tt.message}
+			if fields[0] != want {
+				t.Fatalf("field error = %+v, want %+v", fields[0], want)
+			}
+		})
+	}
+}
+
+func TestValidationValidRequest(t *testing.T) {
+	req := validRequest()
+	if err := Validate(&req); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+}
+
+func TestValidationSliceKeepsIndex(t *testing.T) {
+	items := []validationItem{{Status: 1}, {Status: 5}, {Status: 0}, {Status: 9}}
+	fields, ok := TranslateValidationErrors(Validate(items))
+	if !ok || len(fields) != 2 || fields[0].Field != "[1].status" || fields[1].Field != "[3].status" {
+		t.Fatalf("fields = %+v", fields)
+	}
+}
@@ -0,0 +1,152 @@
+// Package webtest 内存中的 HTTP 测试服务器，用于 Controller / 中间件测试
+//
+//	s := webtest.New(t, func(r *gin.Engine) {
+//	    api := web.Group(r, "/api/v1/demos")
+//	    api.GET("/:id", ctrl.GetByID)
+//	})
+//	w := s.Do(http.MethodGet, "/api/v1/demos/1", nil)
+//	s.AssertCode(w, http.StatusOK)
+//	var demo model.Demo
+//	s.DecodeData(w, &demo)
+package webtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-template/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Server 测试服务器，不监听端口，请求直接交给路由处理
+// 每个测试单独创建，测试结束时恢复 web 包的全局状态（JSON 编码选项）
+type Server struct {
+	t testing.TB
+
+	// Engine 底层路由，可继续注册路由或中间件
+	Engine *gin.Engine
+	// Header 每个请求默认携带的请求头（如 Authorization）
+	Header http.Header
+}
+
+// New 创建测试服务器：middlewares 按顺序注册为全局中间件，routes 注册路由（可为 nil）
+func New(t testing.TB, routes func(r *gin.Engine), middlewares ...web.HandlerFunc) *Server {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	prev := web.SetJSONOptions(web.JSONOptions{})
+	t.Cleanup(func() { web.SetJSONOptions(prev) })
+
+	r := gin.New()
+	r.Use(web.ToGinHandlers(middlewares...)...)
+	r.NoRoute(web.ToGinHandler(web.NotFoundHandler()))
+	if routes != nil {
+		routes(r)
+	}
+
+	return &Server{t: t, Engine: r, Header: make(http.Header)}
+}
+
+// Do 发送请求并返回响应
+// body 为 nil 时没有请求体；string、[]byte、io.Reader 原样发送；其他值编码为 JSON（未指定 Content-Type 时设置为 application/json）
+func (s *Server) Do(method, path string, body interface{}) *httptest.ResponseRecorder {
+	s.t.Helper()
+
+	var (
+		reader io.Reader
+		isJSON bool
+	)
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewBufferString(b)
+	case []byte:
+		reader = bytes.NewReader(b)
+	case io.Reader:
+		reader = b
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			s.t.Fatalf("webtest: encode request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+		isJSON = true
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	for key, values := range s.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if isJSON && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, req)
+	return w
+}
+
+// Envelope 统一响应结构（web.Response），Data、Meta 保留原始 JSON 以便按需解码
+type Envelope struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Meta    json.RawMessage `json:"meta"`
+}
+
+// Decode 解析统一响应结构，响应体不是合法 JSON 时测试失败
+func (s *Server) Decode(w *httptest.ResponseRecorder) Envelope {
+	s.t.Helper()
+
+	var env Envelope
+	if err := json.Unmarshal(w.Body.Bytes(), &env); err != nil {
+		s.t.Fatalf("webtest: decode response (status %d): %v\nbody: %s", w.Code, err, w.Body.String())
+	}
+	return env
+}
+
+// AssertStatus 断言 HTTP 状态码
+func (s *Server) AssertStatus(w *httptest.ResponseRecorder, status int) {
+	s.t.Helper()
+
+	if w.Code != status {
+		s.t.Fatalf("webtest: status = %d, want %d\nbody: %s", w.Code, status, w.Body.String())
+	}
+}
+
+// AssertCode 断言 HTTP 状态码和响应中的 code 均为 code
+func (s *Server) AssertCode(w *httptest.ResponseRecorder, code int) {
+	s.t.Helper()
+
+	s.AssertStatus(w, code)
+	if env := s.Decode(w); env.Code != code {
+		s.t.Fatalf("webtest: code = %d, want %d\nbody: %s", env.Code, code, w.Body.String())
+	}
+}
+
+// AssertMessage 断言响应中的 message
+func (s *Server) AssertMessage(w *httptest.ResponseRecorder, message string) {
+	s.t.Helper()
+
+	if env := s.Decode(w); env.Message != message {
+		s.t.Fatalf("webtest: message = %q, want %q", env.Message, message)
+	}
+}
+
+// DecodeData 将响应中的 data 解码到 dest，响应没有 data 时测试失败
+func (s *Server) DecodeData(w *httptest.ResponseRecorder, dest interface{}) {
+	s.t.Helper()
+
+	env := s.Decode(w)
+	if len(env.Data) == 0 || string(env.Data) == "null" {
+		s.t.Fatalf("webtest: response has no data\nbody: %s", w.Body.String())
+	}
+	if err := json.Unmarshal(env.Data, dest); err != nil {
+		s.t.Fatalf("webtest: decode data: %v\ndata: %s", err, env.Data)
+	}
+}
@@ -0,0 +1,134 @@
+package webtest_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"go-api-template/internal/controller"
+	"go-api-template/internal/model"
+	"go-api-template/internal/repository"
+	"go-api-template/internal/service"
+	"go-api-template/pkg/cache"
+	"go-api-template/pkg/config"
+	"go-api-template/pkg/database"
+	"go-api-template/pkg/database/dbtest"
+	"go-api-template/pkg/logger/logtest"
+	"go-api-template/pkg/web"
+	"go-api-template/pkg/web/webtest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newDemoServer 注册 Demo 的 CRUD 路由；数据库（内存 SQLite）和缓存每个测试独立创建
+func newDemoServer(t *testing.T) *webtest.Server {
+	t.Helper()
+
+	logtest.New(t)
+	cfg, err := config.LoadConfig("../../../config/config.yaml")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	cfg.Cache.Driver = string(cache.DriverMemory)
+	manager, err := cache.NewCacheManager(cfg, nil)
+	if err != nil {
+		t.Fatalf("create cache: %v", err)
+	}
+
+	db := dbtest.Open(t, &model.Demo{})
+	svc := service.NewDemoService(repository.NewDemoRepository(db), database.NewTxManager(db), cache.NewCacheFacade(manager), nil, cfg)
+	ctrl := controller.NewDemoController(svc, cfg)
+
+	return webtest.New(t, func(r *gin.Engine) {
+		demos := web.Group(r, "/api/v1/demos")
+		demos.GET("", ctrl.GetAll)
+		demos.GET("/:id", ctrl.GetByID)
+		demos.POST("", ctrl.Create)
+		demos.PUT("/:id", ctrl.Update)
+		demos.DELETE("/:id", ctrl.Delete)
+	})
+}
+
+func TestDemoCRUD(t *testing.T) {
+	s := newDemoServer(t)
+
+	// 创建
+	w := s.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": "first", "content": "hello"})
+	s.AssertStatus(w, http.StatusCreated)
+	var created model.Demo
+	s.DecodeData(w, &created)
+	if created.ID == 0 || created.Title != "first" {
+		t.Fatalf("created = %+v", created)
+	}
+	path := fmt.Sprintf("/api/v1/demos/%d", created.ID)
+
+	// 列表
+	w = s.Do(http.MethodGet, "/api/v1/demos", nil)
+	s.AssertCode(w, http.StatusOK)
+	var list []model.Demo
+	s.DecodeData(w, &list)
+	if len(list) != 1 || list[0].ID != created.ID {
+		t.Fatalf("list = %+v", list)
+	}
+
+	// 更新后查询
+	w = s.Do(http.MethodPut, path, map[string]interface{}{"title": "renamed", "content": "hello", "status": 0})
+	s.AssertCode(w, http.StatusOK)
+	s.AssertMessage(w, "demo updated successfully")
+
+	w = s.Do(http.MethodGet, path, nil)
+	s.AssertCode(w, http.StatusOK)
+	var got model.Demo
+	s.DecodeData(w, &got)
+	if got.Title != "renamed" || got.Status != 0 {
+		t.Fatalf("after update = %+v", got)
+	}
+
+	// 删除后查询不到
+	w = s.Do(http.MethodDelete, path, nil)
+	s.AssertCode(w, http.StatusOK)
+	s.AssertCode(s.Do(http.MethodGet, path, nil), http.StatusNotFound)
+	s.AssertCode(s.Do(http.MethodDelete, path, nil), http.StatusNotFound)
+}
+
+func TestDemoCreateValidation(t *testing.T) {
+	s := newDemoServer(t)
+
+	w := s.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": ""})
+	s.AssertCode(w, http.StatusBadRequest)
+	var data struct {
+		Errors []web.FieldError `json:"errors"`
+	}
+	s.DecodeData(w, &data)
+	if len(data.Errors) != 1 || data.Errors[0].Field != "title" {
+		t.Fatalf("errors = %+v", data.Errors)
+	}
+}
+
+// TestStateResetBetweenTests 每个测试的数据库和 JSON 编码选项互不影响：
+// 前一个测试创建的数据和修改的选项在这里都不可见
+func TestStateResetBetweenTests(t *testing.T) {
+	t.Run("modify", func(t *testing.T) {
+		s := newDemoServer(t)
+		web.SetJSONOptions(web.JSONOptions{FieldNaming: web.FieldNamingCamel})
+		s.AssertStatus(s.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": "leak"}), http.StatusCreated)
+	})
+	t.Run("fresh", func(t *testing.T) {
+		s := newDemoServer(t)
+		w := s.Do(http.MethodGet, "/api/v1/demos", nil)
+		s.AssertCode(w, http.StatusOK)
+		var list []map[string]interface{}
+		s.DecodeData(w, &list)
+		if len(list) != 0 {
+			t.Fatalf("list = %v, want empty", list)
+		}
+
+		s.AssertStatus(s.Do(http.MethodPost, "/api/v1/demos", map[string]interface{}{"title": "fresh"}), http.StatusCreated)
+		w = s.Do(http.MethodGet, "/api/v1/demos", nil)
+		list = nil
+		s.DecodeData(w, &list)
+		if _, ok := list[0]["created_at"]; !ok {
+			t.Fatalf("JSON options leaked from the previous test: %v", list[0])
+		}
+	})
+}